@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/sillen102/simba/middleware"
 	"github.com/sillen102/simba/settings"
@@ -31,8 +32,17 @@ type Application struct {
 	// telemetryProvider manages tracing and metrics via a pluggable interface
 	telemetryProvider TelemetryProvider `exhaustruct:"optional"`
 
+	// startHooks are invoked by Start before the server starts listening
+	startHooks []func(context.Context) error `exhaustruct:"optional"`
+
 	// shutdownHooks are invoked during Stop to let optional modules clean up
 	shutdownHooks []func(context.Context) error `exhaustruct:"optional"`
+
+	// accessLogStats counts the requests logged by the access log of the default application
+	accessLogStats *middleware.AccessLogStats `exhaustruct:"optional"`
+
+	// concurrencyLimiter caps the number of requests handled at once, if configured
+	concurrencyLimiter *middleware.ConcurrencyLimiter `exhaustruct:"optional"`
 }
 
 // Default returns a new [Application] application with default Simba.
@@ -51,18 +61,40 @@ func New(opts ...settings.Option) *Application {
 	}
 
 	router := newRouter(cfg.Request, cfg.Docs)
+	router.hotReload = cfg.HotReload
 	router.Use(func(next http.Handler) http.Handler {
 		return injectRequestSettings(next, &cfg.Request)
 	})
+	if cfg.MaxURILength > 0 || cfg.MaxQueryLength > 0 {
+		router.Use(middleware.URILength{MaxURILength: cfg.MaxURILength, MaxQueryLength: cfg.MaxQueryLength}.Limit)
+	}
+	if cfg.Capture != nil {
+		router.Use(middleware.RequestCapture{Sink: cfg.Capture.Sink, Options: cfg.Capture.Options}.Capture)
+	}
+
+	var concurrencyLimiter *middleware.ConcurrencyLimiter
+	if cfg.MaxConcurrentRequests > 0 {
+		concurrencyLimiter = middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{
+			MaxConcurrent: cfg.MaxConcurrentRequests,
+			MaxQueued:     cfg.MaxQueuedRequests,
+			QueueTimeout:  time.Duration(cfg.QueueTimeoutMillis) * time.Millisecond,
+		})
+		router.UsePreRouting(concurrencyLimiter.Limit)
+	}
 
 	// Support modular telemetry config if provided; fallback for legacy settings
 	telemetryProvider := NoOpTelemetryProvider{}
 
 	return &Application{
-		Server:            &http.Server{Addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port), Handler: router},
-		Router:            router,
-		Settings:          cfg,
-		telemetryProvider: telemetryProvider,
+		Server: &http.Server{
+			Addr:           fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Handler:        router,
+			MaxHeaderBytes: cfg.MaxHeaderBytes,
+		},
+		Router:             router,
+		Settings:           cfg,
+		telemetryProvider:  telemetryProvider,
+		concurrencyLimiter: concurrencyLimiter,
 	}
 }
 
@@ -71,6 +103,25 @@ func (a *Application) SetTelemetryProvider(tp TelemetryProvider) {
 	a.telemetryProvider = tp
 }
 
+// OnStart adds a callback invoked by Start before the server starts listening, e.g. to open database pools.
+// Hooks are executed in registration order with a context that is cancelled when the server is stopped.
+// An error aborts the startup: later hooks are not run, the hooks registered with [Application.OnStop]
+// are invoked and Start panics with the error.
+func (a *Application) OnStart(hook func(ctx context.Context) error) {
+	if hook != nil {
+		a.startHooks = append(a.startHooks, hook)
+	}
+}
+
+// OnStop adds a callback invoked during Stop, after the server has stopped accepting requests,
+// e.g. to close database pools. Hooks are executed in registration order, together with the hooks
+// registered with [Application.RegisterShutdownHook], and their errors are returned by Stop.
+func (a *Application) OnStop(hook func(ctx context.Context) error) {
+	if hook != nil {
+		a.shutdownHooks = append(a.shutdownHooks, hook)
+	}
+}
+
 // RegisterShutdownHook adds a callback invoked during Stop.
 // Hooks are executed in registration order.
 //
@@ -127,8 +178,32 @@ func (a *Application) defaultMiddleware() []func(http.Handler) http.Handler {
 		middleware.TraceID,
 		middleware.Logger{Logger: a.Settings.Logger}.ContextLogger,
 		middleware.PanicRecovery,
-		middleware.LogRequests,
 	}
 
-	return middlewares
+	if !a.Settings.AccessLog {
+		return middlewares
+	}
+
+	level, _ := a.Settings.AccessLogSlogLevel()
+	a.accessLogStats = middleware.NewAccessLogStats()
+	accessLog := middleware.AccessLog{
+		Level:  level,
+		Fields: a.Settings.AccessLogFieldList(),
+		Stats:  a.accessLogStats,
+	}
+	return append(middlewares, accessLog.Log)
+}
+
+// AccessLogStats returns the number of requests logged by the access log of the default application
+// by status class and latency bucket. It returns nil if the access log is disabled or the application
+// was not created with [Default].
+func (a *Application) AccessLogStats() *middleware.AccessLogStats {
+	return a.accessLogStats
+}
+
+// ConcurrencyLimiter returns the limiter capping the number of requests handled at once, which reports the
+// number of requests in flight, queued and rejected, e.g. to export them as metrics. It returns nil if
+// [settings.Server.MaxConcurrentRequests] is not set.
+func (a *Application) ConcurrencyLimiter() *middleware.ConcurrencyLimiter {
+	return a.concurrencyLimiter
 }
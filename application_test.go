@@ -1,10 +1,14 @@
 package simba_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/sillen102/simba"
@@ -70,6 +74,12 @@ func TestNewApplication(t *testing.T) {
 		assert.Assert(t, app.Settings != nil)
 		assert.Equal(t, "localhost:8080", app.Server.Addr)
 	})
+
+	t.Run("sets max header bytes", func(t *testing.T) {
+		app := simba.New(settings.WithMaxHeaderBytes(16 << 10))
+
+		assert.Equal(t, 16<<10, app.Server.MaxHeaderBytes)
+	})
 }
 
 func TestApplicationRegisterShutdownHook(t *testing.T) {
@@ -157,3 +167,231 @@ func TestApplicationRegisterShutdownHook(t *testing.T) {
 		app.RegisterShutdownHook("invalid")
 	})
 }
+
+func TestApplicationLifecycleHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs stop hooks during stop", func(t *testing.T) {
+		app := simba.New()
+
+		var order []string
+		app.OnStop(func(ctx context.Context) error {
+			order = append(order, "close pool")
+			return nil
+		})
+		app.RegisterShutdownHook(func() {
+			order = append(order, "shutdown hook")
+		})
+
+		err := app.Stop()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"close pool", "shutdown hook"}, order)
+	})
+
+	t.Run("returns stop hook errors", func(t *testing.T) {
+		app := simba.New()
+		expectedErr := errors.New("close failed")
+
+		app.OnStop(func(ctx context.Context) error {
+			return expectedErr
+		})
+
+		err := app.Stop()
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+
+	t.Run("start hook error aborts startup", func(t *testing.T) {
+		app := simba.New(settings.WithServerPort(0))
+		expectedErr := errors.New("database unavailable")
+
+		var order []string
+		app.OnStart(func(ctx context.Context) error {
+			order = append(order, "open cache")
+			return nil
+		})
+		app.OnStart(func(ctx context.Context) error {
+			order = append(order, "open pool")
+			return expectedErr
+		})
+		app.OnStart(func(ctx context.Context) error {
+			order = append(order, "warm up")
+			return nil
+		})
+		app.OnStop(func(ctx context.Context) error {
+			order = append(order, "cleanup")
+			return nil
+		})
+
+		defer func() {
+			recovered := recover()
+			err, ok := recovered.(error)
+			assert.True(t, ok)
+			assert.True(t, errors.Is(err, expectedErr))
+			assert.Equal(t, []string{"open cache", "open pool", "cleanup"}, order)
+		}()
+
+		app.Start()
+	})
+}
+
+type captureSinkFunc func(ctx context.Context, req models.CapturedRequest) error
+
+func (f captureSinkFunc) Capture(ctx context.Context, req models.CapturedRequest) error {
+	return f(ctx, req)
+}
+
+func TestApplicationRequestCapture(t *testing.T) {
+	t.Parallel()
+
+	var captured []models.CapturedRequest
+	sink := captureSinkFunc(func(ctx context.Context, req models.CapturedRequest) error {
+		captured = append(captured, req)
+		return nil
+	})
+
+	handler := func(ctx context.Context, req *models.Request[map[string]string, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{Status: http.StatusAccepted}, nil
+	}
+
+	app := simba.Default(settings.WithRequestCapture(sink, models.CaptureOptions{}))
+	app.Router.POST("/test", simba.JsonHandler(handler))
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Len(t, captured, 1)
+	assert.Equal(t, http.StatusAccepted, captured[0].Status)
+	assert.Equal(t, `{"name":"John"}`, string(captured[0].Body))
+	assert.True(t, captured[0].TraceID != "")
+	assert.Equal(t, w.Header().Get(simbaContext.TraceIDHeader), captured[0].TraceID)
+}
+
+func TestApplicationAccessLog(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	t.Run("logs and counts requests", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		app := simba.Default(settings.WithLogger(logger))
+		app.Router.GET("/users/{id}", simba.JsonHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		app.Router.ServeHTTP(httptest.NewRecorder(), req)
+
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal[any](t, "request processed", line["msg"])
+		assert.Equal[any](t, "/users/{id}", line["path"])
+		assert.Equal[any](t, float64(http.StatusNoContent), line["status"])
+		assert.Equal(t, map[string]int64{"2xx": 1}, app.AccessLogStats().StatusClasses())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		app := simba.Default(settings.WithLogger(logger), settings.WithAccessLog(false))
+		app.Router.GET("/users/{id}", simba.JsonHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		app.Router.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "", buf.String())
+		assert.Nil(t, app.AccessLogStats())
+	})
+}
+
+func TestApplicationMaxURILength(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	app := simba.Default(settings.WithMaxURILength(32), settings.WithMaxQueryLength(8))
+	app.Router.GET("/test", simba.JsonHandler(handler))
+
+	t.Run("within limits", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/test?a=1", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("query too long", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/test?name=john", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestURITooLong, w.Code)
+		assert.True(t, strings.Contains(w.Body.String(), "query string exceeds the limit of 8 bytes"))
+	})
+
+	t.Run("uri too long", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/test?a=1&"+strings.Repeat("b", 30), nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestURITooLong, w.Code)
+	})
+}
+
+func TestApplicationMaxConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects requests beyond the limit", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+			close(started)
+			<-release
+			return &models.Response[models.NoBody]{}, nil
+		}
+
+		app := simba.Default(settings.WithMaxConcurrentRequests(1))
+		app.Router.GET("/slow", simba.JsonHandler(handler))
+
+		done := make(chan int)
+		go func() {
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+			done <- w.Code
+		}()
+		<-started
+
+		// The limit applies across routes, including unknown ones
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/other", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+		close(release)
+		assert.Equal(t, http.StatusNoContent, <-done)
+		assert.Equal(t, int64(1), app.ConcurrencyLimiter().Rejected())
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New()
+		assert.Nil(t, app.ConcurrencyLimiter())
+	})
+}
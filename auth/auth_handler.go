@@ -366,6 +366,12 @@ func (t SessionCookieAuthType[AuthModel]) GetHandler() AuthHandlerFunc[AuthModel
 // AuthHandlerFunc is a function that handles authentication for a route.
 type AuthHandlerFunc[AuthModel any] func(r *http.Request) (AuthModel, error)
 
+// RoleProvider is implemented by auth models that carry the roles of the authenticated principal.
+// The roles are added to the request context after authentication, see [simbaContext.GetRoles].
+type RoleProvider interface {
+	Roles() []string
+}
+
 // HandleAuthRequest is a helper function that parses the parameters and calls the authentication
 // function with the parsed parameters.
 func HandleAuthRequest[AuthModel any](
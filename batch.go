@@ -0,0 +1,204 @@
+package simba
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// defaultMaxBatchRequests is the number of sub-requests allowed in a batch if [BatchConfig.MaxRequests] is not set.
+const defaultMaxBatchRequests = 20
+
+// batchContextKey marks the context of sub-requests so batches can't be nested.
+type batchContextKey struct{}
+
+// BatchConfig configures a [BatchHandler].
+type BatchConfig struct {
+	// MaxRequests is the maximum number of sub-requests in a batch, defaults to 20.
+	MaxRequests int `exhaustruct:"optional"`
+}
+
+type batchHandler struct {
+	router *Router
+	config BatchConfig
+}
+
+// BatchHandler handles a batch of sub-requests in a single round-trip. The request body is a JSON
+// array of [models.BatchRequest] and the response body is a JSON array of [models.BatchResponse]
+// in the same order.
+//
+// The sub-requests are executed one at a time through the router, so each sub-request goes through
+// the same route middleware, auth and validation as a regular request. Pre-routing middleware registered
+// with [Router.UsePreRouting] only runs for the batch request, so a concurrency limiter counts a batch as
+// a single request. The sub-requests inherit the headers of the batch request, such as Authorization and
+// Cookie, which can be overridden per sub-request.
+// A sub-request that fails is reported through its status and doesn't stop the batch.
+//
+//	Example usage:
+//
+//	app.Router.POST("/batch", simba.BatchHandler(app.Router, simba.BatchConfig{MaxRequests: 10}))
+//
+// A batch request could look like:
+//
+//	[
+//		{"id": "user", "method": "GET", "path": "/users/1"},
+//		{"id": "update", "method": "PATCH", "path": "/users/1", "body": {"name": "John"}}
+//	]
+func BatchHandler(router *Router, config BatchConfig) Handler {
+	if config.MaxRequests <= 0 {
+		config.MaxRequests = defaultMaxBatchRequests
+	}
+	return batchHandler{router: router, config: config}
+}
+
+// ServeHTTP implements the http.Handler interface for batchHandler.
+func (h batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveBatch(h, w, r)
+}
+
+// serveBatch executes a batch of sub-requests.
+// The handler is documented through this function.
+// @ID batch
+// @Tag Batch
+// @Summary Batch requests
+// @Description Executes a batch of sub-requests and returns their responses in the same order.
+func serveBatch(h batchHandler, w http.ResponseWriter, r *http.Request) {
+	if r.Context().Value(batchContextKey{}) != nil {
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(http.StatusBadRequest, "nested batch requests are not allowed", nil))
+		return
+	}
+
+	req, err := handleJsonRequest[[]models.BatchRequest, models.NoParams](r)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	if len(req.Body) > h.config.MaxRequests {
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
+			http.StatusBadRequest,
+			fmt.Sprintf("batch contains %d requests, the maximum is %d", len(req.Body), h.config.MaxRequests),
+			nil,
+		))
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), batchContextKey{}, true)
+	responses := make([]models.BatchResponse, 0, len(req.Body))
+	for _, subRequest := range req.Body {
+		responses = append(responses, h.execute(ctx, r, subRequest))
+	}
+
+	writeResponse(w, r, &models.Response[[]models.BatchResponse]{Body: responses}, nil)
+}
+
+// execute dispatches a single sub-request through the routes of the router and records its response.
+// The pre-routing middleware already ran for the batch request, and running it again would have the
+// sub-requests wait for a concurrency limiter slot held by the batch itself.
+func (h batchHandler) execute(ctx context.Context, parent *http.Request, subRequest models.BatchRequest) models.BatchResponse {
+	recorder := newResponseRecorder()
+
+	req, err := newBatchSubRequest(ctx, parent, subRequest)
+	if err != nil {
+		simbaErrors.WriteError(recorder, parent, err)
+	} else {
+		h.router.serveMux(recorder, req)
+	}
+
+	return models.BatchResponse{
+		ID:      subRequest.ID,
+		Status:  recorder.status,
+		Headers: recorder.header,
+		Body:    batchResponseBody(recorder.body.Bytes()),
+	}
+}
+
+// newBatchSubRequest builds the request for a sub-request, inheriting the headers, host and
+// remote address of the batch request.
+func newBatchSubRequest(ctx context.Context, parent *http.Request, subRequest models.BatchRequest) (*http.Request, error) {
+	if subRequest.Method == "" || !strings.HasPrefix(subRequest.Path, "/") {
+		return nil, simbaErrors.NewSimbaError(
+			http.StatusBadRequest,
+			"batch request must have a method and a path starting with /",
+			nil,
+		)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(subRequest.Method), subRequest.Path, bytes.NewReader(subRequest.Body))
+	if err != nil {
+		return nil, simbaErrors.NewSimbaError(http.StatusBadRequest, "invalid batch request", err)
+	}
+
+	req.Header = parent.Header.Clone()
+	req.Header.Del("Content-Length")
+	req.Header.Del("Content-Type")
+	if len(subRequest.Body) > 0 {
+		req.Header.Set("Content-Type", mimetypes.ApplicationJSON)
+	}
+	for name, value := range subRequest.Headers {
+		req.Header.Set(name, value)
+	}
+
+	req.Host = parent.Host
+	req.RemoteAddr = parent.RemoteAddr
+	req.Proto, req.ProtoMajor, req.ProtoMinor = parent.Proto, parent.ProtoMajor, parent.ProtoMinor
+	req.TLS = parent.TLS
+
+	return req, nil
+}
+
+// batchResponseBody returns the body as JSON, encoding it as a JSON string if it's not valid JSON.
+func batchResponseBody(body []byte) json.RawMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+
+	encoded, err := json.Marshal(string(body))
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
+
+func (h batchHandler) GetRequestBody() any {
+	return []models.BatchRequest{}
+}
+
+func (h batchHandler) GetParams() any {
+	return models.NoParams{}
+}
+
+func (h batchHandler) GetResponseBody() any {
+	return []models.BatchResponse{}
+}
+
+func (h batchHandler) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h batchHandler) GetProduces() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h batchHandler) GetHandler() any {
+	return serveBatch
+}
+
+func (h batchHandler) GetAuthModel() any {
+	return nil
+}
+
+func (h batchHandler) GetAuthHandler() any {
+	return nil
+}
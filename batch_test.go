@@ -0,0 +1,172 @@
+package simba_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestBatchHandler(t *testing.T) {
+	t.Parallel()
+
+	type userParams struct {
+		ID int `path:"id" validate:"required"`
+	}
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	app := simba.New()
+	app.Router.GET("/users/{id}", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, userParams]) (*models.Response[user], error) {
+		return &models.Response[user]{
+			Headers: http.Header{"X-User": {"found"}},
+			Body:    user{ID: req.Params.ID, Name: "John"},
+		}, nil
+	}))
+	app.Router.POST("/users", simba.JsonHandler(func(ctx context.Context, req *models.Request[simbaTest.RequestBody, models.NoParams]) (*models.Response[user], error) {
+		return &models.Response[user]{Body: user{ID: 2, Name: req.Body.Name}, Status: http.StatusCreated}, nil
+	}))
+	app.Router.GET("/text", simba.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})))
+	app.Router.GET("/me", simba.AuthJsonHandler(simbaTest.BearerTokenAuthHandler, simbaTest.BearerAuthAuthenticationHandler))
+	app.Router.POST("/batch", simba.BatchHandler(app.Router, simba.BatchConfig{MaxRequests: 3}))
+
+	batch := func(t *testing.T, body string, headers map[string]string) (*httptest.ResponseRecorder, []models.BatchResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		var responses []models.BatchResponse
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &responses))
+		}
+		return w, responses
+	}
+
+	t.Run("executes sub-requests in order", func(t *testing.T) {
+		t.Parallel()
+
+		w, responses := batch(t, `[
+			{"id": "get", "method": "GET", "path": "/users/1"},
+			{"id": "create", "method": "POST", "path": "/users", "body": {"name": "Jane"}},
+			{"method": "GET", "path": "/text"}
+		]`, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, responses, 3)
+
+		assert.Equal(t, "get", responses[0].ID)
+		assert.Equal(t, http.StatusOK, responses[0].Status)
+		assert.Equal(t, "found", responses[0].Headers.Get("X-User"))
+		assert.Equal(t, `{"id":1,"name":"John"}`, string(responses[0].Body))
+
+		assert.Equal(t, "create", responses[1].ID)
+		assert.Equal(t, http.StatusCreated, responses[1].Status)
+		assert.Equal(t, `{"id":2,"name":"Jane"}`, string(responses[1].Body))
+
+		assert.Equal(t, http.StatusOK, responses[2].Status)
+		assert.Equal(t, `"hello"`, string(responses[2].Body))
+	})
+
+	t.Run("reports sub-request errors without stopping the batch", func(t *testing.T) {
+		t.Parallel()
+
+		w, responses := batch(t, `[
+			{"method": "POST", "path": "/users", "body": {"age": 30}},
+			{"method": "GET", "path": "/missing"},
+			{"method": "GET", "path": "users/1"}
+		]`, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, responses, 3)
+		assert.Equal(t, http.StatusBadRequest, responses[0].Status)
+		assert.True(t, strings.Contains(string(responses[0].Body), "request validation failed"))
+		assert.Equal(t, http.StatusNotFound, responses[1].Status)
+		assert.Equal(t, http.StatusBadRequest, responses[2].Status)
+	})
+
+	t.Run("sub-requests inherit and override headers", func(t *testing.T) {
+		t.Parallel()
+
+		w, responses := batch(t, `[
+			{"method": "GET", "path": "/me"},
+			{"method": "GET", "path": "/me", "headers": {"Authorization": "Bearer invalid"}}
+		]`, map[string]string{"Authorization": "Bearer token"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, responses, 2)
+		assert.Equal(t, http.StatusAccepted, responses[0].Status)
+		assert.Equal(t, http.StatusUnauthorized, responses[1].Status)
+	})
+
+	t.Run("too many sub-requests", func(t *testing.T) {
+		t.Parallel()
+
+		w, _ := batch(t, `[
+			{"method": "GET", "path": "/users/1"},
+			{"method": "GET", "path": "/users/2"},
+			{"method": "GET", "path": "/users/3"},
+			{"method": "GET", "path": "/users/4"}
+		]`, nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("nested batch is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		w, responses := batch(t, `[{"method": "POST", "path": "/batch", "body": []}]`, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, responses, 1)
+		assert.Equal(t, http.StatusBadRequest, responses[0].Status)
+		assert.True(t, strings.Contains(string(responses[0].Body), "nested batch requests are not allowed"))
+	})
+}
+
+func TestBatchHandler_ConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{MaxConcurrent: 1})
+
+	app := simba.New()
+	app.Router.UsePreRouting(limiter.Limit)
+	app.Router.GET("/text", simba.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})))
+	app.Router.POST("/batch", simba.BatchHandler(app.Router, simba.BatchConfig{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(`[
+		{"method": "GET", "path": "/text"},
+		{"method": "GET", "path": "/text"}
+	]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var responses []models.BatchResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &responses))
+	assert.Len(t, responses, 2)
+	for _, response := range responses {
+		assert.Equal(t, http.StatusOK, response.Status)
+	}
+	assert.Equal(t, int64(0), limiter.Rejected())
+	assert.Equal(t, int64(0), limiter.InFlight())
+}
@@ -0,0 +1,152 @@
+package simba
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// ClientIP returns the IP address of the client that sent the request.
+// The client IP header configured in [settings.Request] is only used for requests that came through
+// one of the trusted proxies, otherwise the address of the connection is returned.
+// Returns an empty string if the context doesn't belong to a request handled by a simba router.
+func ClientIP(ctx context.Context) string {
+	return simbaContext.GetClientIP(ctx)
+}
+
+// clientIPResolver determines the client IP of a request.
+type clientIPResolver struct {
+	trustedProxies []netip.Prefix
+	header         models.ClientIPHeader
+}
+
+func newClientIPResolver(requestSettings *settings.Request) clientIPResolver {
+	// Invalid proxies are rejected when the settings are loaded
+	trustedProxies, _ := requestSettings.TrustedProxyPrefixes()
+
+	header := requestSettings.ClientIPHeader
+	if header == "" {
+		header = models.XForwardedFor
+	}
+
+	return clientIPResolver{
+		trustedProxies: trustedProxies,
+		header:         header,
+	}
+}
+
+// resolve returns the client IP of the request. Headers are never trusted unless the
+// connection comes from a trusted proxy.
+func (c clientIPResolver) resolve(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	remote, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	remote = remote.Unmap()
+
+	if !c.isTrusted(remote) {
+		return remote.String()
+	}
+
+	switch c.header {
+	case models.XRealIP:
+		values := r.Header.Values("X-Real-IP")
+		if len(values) != 1 {
+			return remote.String()
+		}
+		if addr, ok := parseClientIP(values[0]); ok {
+			return addr.String()
+		}
+		return remote.String()
+	case models.Forwarded:
+		return c.fromChain(remote, forwardedFor(r.Header.Values("Forwarded"))).String()
+	default:
+		return c.fromChain(remote, splitHeaderList(r.Header.Values("X-Forwarded-For"))).String()
+	}
+}
+
+// fromChain returns the client IP from a chain of addresses appended by proxies. The chain is walked
+// from the closest proxy towards the client and the first address that isn't a trusted proxy is returned,
+// so a client can't spoof its IP by sending the header itself. The walk stops at invalid addresses,
+// returning the last trusted proxy.
+func (c clientIPResolver) fromChain(remote netip.Addr, chain []string) netip.Addr {
+	client := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseClientIP(chain[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !c.isTrusted(addr) {
+			break
+		}
+	}
+	return client
+}
+
+func (c clientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range c.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitHeaderList splits comma separated header values into a single list.
+func splitHeaderList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			list = append(list, strings.TrimSpace(item))
+		}
+	}
+	return list
+}
+
+// forwardedFor returns the "for" parameters of the elements of Forwarded headers (RFC 7239).
+// Elements without a "for" parameter are returned as empty strings so they end the chain.
+func forwardedFor(values []string) []string {
+	elements := splitHeaderList(values)
+	nodes := make([]string, 0, len(elements))
+	for _, element := range elements {
+		node := ""
+		for _, pair := range strings.Split(element, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if found && strings.EqualFold(key, "for") {
+				node = strings.Trim(value, `"`)
+				break
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// parseClientIP parses an IP address that may include a port or be enclosed in brackets.
+func parseClientIP(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return addr.Unmap(), true
+	}
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		if addr, err := netip.ParseAddr(value[1 : len(value)-1]); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
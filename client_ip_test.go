@@ -0,0 +1,115 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		opts       []settings.Option
+		remoteAddr string
+		headers    map[string][]string
+		expected   string
+	}{
+		{
+			name:       "no trusted proxies ignores headers",
+			remoteAddr: "203.0.113.10:1234",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "untrusted remote ignores headers",
+			opts:       []settings.Option{settings.WithTrustedProxies("10.0.0.0/8")},
+			remoteAddr: "203.0.113.10:1234",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "forwarded for from trusted proxy",
+			opts:       []settings.Option{settings.WithTrustedProxies("10.0.0.0/8")},
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "spoofed forwarded for entries are skipped",
+			opts:       []settings.Option{settings.WithTrustedProxies("10.0.0.0/8")},
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.1", "10.0.0.2"}},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "invalid forwarded for entry stops at last trusted proxy",
+			opts:       []settings.Option{settings.WithTrustedProxies("10.0.0.0/8")},
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1, garbage, 10.0.0.2"}},
+			expected:   "10.0.0.2",
+		},
+		{
+			name:       "missing header from trusted proxy",
+			opts:       []settings.Option{settings.WithTrustedProxies("10.0.0.1")},
+			remoteAddr: "10.0.0.1:1234",
+			expected:   "10.0.0.1",
+		},
+		{
+			name: "x real ip",
+			opts: []settings.Option{
+				settings.WithTrustedProxies("10.0.0.0/8"),
+				settings.WithClientIPHeader(models.XRealIP),
+			},
+			remoteAddr: "10.0.0.1:1234",
+			headers: map[string][]string{
+				"X-Real-IP":       {"198.51.100.1"},
+				"X-Forwarded-For": {"1.2.3.4"},
+			},
+			expected: "198.51.100.1",
+		},
+		{
+			name: "forwarded",
+			opts: []settings.Option{
+				settings.WithTrustedProxies("10.0.0.0/8", "2001:db8::1"),
+				settings.WithClientIPHeader(models.Forwarded),
+			},
+			remoteAddr: "[2001:db8::1]:1234",
+			headers:    map[string][]string{"Forwarded": {`for=1.2.3.4, for="[2001:db8:cafe::17]:4711";proto=https, for=10.0.0.2`}},
+			expected:   "2001:db8:cafe::17",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var clientIP string
+			app := simba.New(tc.opts...)
+			app.Router.GET("/ip", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+				clientIP = simba.ClientIP(ctx)
+				return &models.Response[models.NoBody]{}, nil
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for key, values := range tc.headers {
+				for _, value := range values {
+					req.Header.Add(key, value)
+				}
+			}
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Equal(t, tc.expected, clientIP)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package simba
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// coalescedCall is a handler execution whose response is shared with the requests waiting for it.
+type coalescedCall struct {
+	done     chan struct{}
+	response *responseRecorder
+}
+
+// coalesce returns middleware that runs the handler once for concurrent GET and HEAD requests with
+// the same key and writes the response of that execution to all of them.
+func coalesce(keyFunc func(r *http.Request) string) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	calls := make(map[string]*coalescedCall)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key = r.Method + " " + key
+
+			mu.Lock()
+			if call, ok := calls[key]; ok {
+				mu.Unlock()
+				select {
+				case <-call.done:
+				case <-r.Context().Done():
+					return
+				}
+				if call.response == nil {
+					// The handler panicked, handle the request on its own
+					next.ServeHTTP(w, r)
+					return
+				}
+				call.response.writeTo(w)
+				return
+			}
+			call := &coalescedCall{done: make(chan struct{}), response: nil}
+			calls[key] = call
+			mu.Unlock()
+
+			func() {
+				defer func() {
+					mu.Lock()
+					delete(calls, key)
+					mu.Unlock()
+					close(call.done)
+				}()
+
+				// The waiters depend on the response, so the handler isn't cancelled if this client disconnects
+				recorder := newResponseRecorder()
+				next.ServeHTTP(recorder, r.WithContext(context.WithoutCancel(r.Context())))
+				call.response = recorder
+			}()
+
+			call.response.writeTo(w)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestWithCoalescing(t *testing.T) {
+	t.Parallel()
+
+	type productParams struct {
+		ID int `path:"id"`
+	}
+
+	type product struct {
+		ID int `json:"id"`
+	}
+
+	var executions, keys atomic.Int32
+	release := make(chan struct{})
+
+	app := simba.New()
+	app.Router.GET("/products/{id}", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, productParams]) (*models.Response[product], error) {
+		executions.Add(1)
+		<-release
+		return &models.Response[product]{
+			Headers: http.Header{"X-Product": {"found"}},
+			Body:    product{ID: req.Params.ID},
+		}, nil
+	}), simba.WithCoalescing(func(r *http.Request) string {
+		keys.Add(1)
+		return r.URL.RequestURI()
+	}))
+
+	const requests = 5
+	recorders := make([]*httptest.ResponseRecorder, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		recorders[i] = httptest.NewRecorder()
+		wg.Go(func() {
+			app.Router.ServeHTTP(recorders[i], httptest.NewRequest(http.MethodGet, "/products/1", nil))
+		})
+	}
+
+	// Wait for all requests to arrive before letting the handler finish
+	for keys.Load() < requests || executions.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), executions.Load())
+	for _, w := range recorders {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "found", w.Header().Get("X-Product"))
+		assert.Equal(t, "{\"id\":1}\n", w.Body.String())
+	}
+
+	// Later requests run the handler again
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"id\":2}\n", w.Body.String())
+	assert.Equal(t, int32(2), executions.Load())
+}
@@ -0,0 +1,35 @@
+package simba
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// enforceContentType rejects Requests whose body does not match the media type accepted by the
+// handler with a 415 Unsupported Media Type if content type enforcement is enabled in the settings.
+func enforceContentType(handler Handler) http.Handler {
+	accepts := handler.GetAccepts()
+	if accepts == "" || handler.GetRequestBody() == (models.NoBody{}) {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !getConfigurationFromContext(r.Context()).EnforceContentType || r.ContentLength == 0 {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != accepts {
+			simbaErrors.WriteError(w, r, simbaErrors.ErrUnsupportedMediaType.
+				WithDetails("expected "+accepts+", got: "+contentType))
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
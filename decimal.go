@@ -0,0 +1,70 @@
+package simba
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/sillen102/simba/simbaOpenapi"
+	"github.com/sillen102/simba/validation"
+)
+
+// DecimalAdapter adapts an exact decimal number type, such as shopspring/decimal.Decimal, so Simba can
+// bind, validate and document it without depending on the package that provides it.
+type DecimalAdapter[T any] struct {
+	// Parse parses a decimal from a parameter or default value
+	Parse func(value string) (T, error)
+	// String formats a decimal, used to validate it with numeric rules such as min and max
+	String func(value T) string
+}
+
+var (
+	decimalParsersMu sync.RWMutex
+	decimalParsers   = map[reflect.Type]func(string) (any, error){}
+)
+
+// RegisterDecimal registers an exact decimal number type. Params and defaults of the type are parsed with
+// the adapter and rejected with 400 Bad Request if invalid, numeric validation rules such as
+// required, min and max compare the value as a float64, and the type is documented in OpenAPI as a string
+// in the decimal format. JSON bodies are decoded with the JSON methods of the type itself.
+// Register decimal types before the application is started.
+//
+//	Example usage:
+//
+//	simba.RegisterDecimal(simba.DecimalAdapter[decimal.Decimal]{
+//		Parse:  decimal.NewFromString,
+//		String: decimal.Decimal.String,
+//	})
+//
+//	type Params struct {
+//		MinPrice decimal.Decimal `query:"minPrice" validate:"min=0"`
+//	}
+func RegisterDecimal[T any](adapter DecimalAdapter[T]) {
+	decimalParsersMu.Lock()
+	decimalParsers[reflect.TypeFor[T]()] = func(value string) (any, error) {
+		return adapter.Parse(value)
+	}
+	decimalParsersMu.Unlock()
+
+	validation.Validator().RegisterCustomTypeFunc(func(field reflect.Value) any {
+		value, ok := field.Interface().(T)
+		if !ok {
+			return nil
+		}
+		number, err := strconv.ParseFloat(adapter.String(value), 64)
+		if err != nil {
+			return nil
+		}
+		return number
+	}, *new(T))
+
+	simbaOpenapi.RegisterDecimal(*new(T))
+}
+
+// decimalParser returns the parser of a registered decimal type.
+func DecimalParser(t reflect.Type) (func(string) (any, error), bool) {
+	decimalParsersMu.RLock()
+	defer decimalParsersMu.RUnlock()
+	parse, ok := decimalParsers[t]
+	return parse, ok
+}
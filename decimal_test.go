@@ -0,0 +1,152 @@
+package simba_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+// amount is an exact decimal type that doesn't implement encoding.TextUnmarshaler,
+// so params are only bound through the registered adapter.
+type amount struct {
+	rat *big.Rat
+}
+
+func parseAmount(value string) (amount, error) {
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount{rat: rat}, nil
+}
+
+func (a amount) String() string {
+	if a.rat == nil {
+		return "0"
+	}
+	return a.rat.FloatString(2)
+}
+
+func (a amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *amount) UnmarshalJSON(data []byte) error {
+	parsed, err := parseAmount(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+
+	simba.RegisterDecimal(simba.DecimalAdapter[amount]{
+		Parse:  parseAmount,
+		String: amount.String,
+	})
+
+	type params struct {
+		Min amount  `query:"min" validate:"min=0" default:"0.50"`
+		Max *amount `query:"max"`
+	}
+
+	type body struct {
+		Price    amount `json:"price" validate:"required,max=1000000000000"`
+		Discount amount `json:"discount" default:"0.10"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[body, params]) (*models.Response[map[string]any], error) {
+		total := new(big.Rat).Sub(req.Body.Price.rat, req.Body.Discount.rat)
+		response := map[string]any{
+			"min":   req.Params.Min,
+			"total": amount{rat: total},
+		}
+		if req.Params.Max != nil {
+			response["max"] = req.Params.Max
+		}
+		return &models.Response[map[string]any]{Body: response}, nil
+	}
+
+	app := simba.New()
+	app.Router.POST("/prices", simba.JsonHandler(handler))
+
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "exact decimals",
+			target:   "/prices?min=0.10&max=99.99",
+			body:     `{"price": 100000000000.30, "discount": "0.20"}`,
+			status:   http.StatusOK,
+			expected: `{"max":"99.99","min":"0.10","total":"100000000000.10"}`,
+		},
+		{
+			name:     "default values",
+			target:   "/prices",
+			body:     `{"price": "1.00"}`,
+			status:   http.StatusOK,
+			expected: `{"min":"0.50","total":"0.90"}`,
+		},
+		{
+			name:     "invalid param",
+			target:   "/prices?min=cheap",
+			body:     `{"price": "1.00"}`,
+			status:   http.StatusBadRequest,
+			expected: `"error":"invalid decimal parameter value: cheap"`,
+		},
+		{
+			name:     "param validated as a number",
+			target:   "/prices?min=-0.01",
+			body:     `{"price": "1.00"}`,
+			status:   http.StatusBadRequest,
+			expected: `"field":"Min"`,
+		},
+		{
+			name:     "body validated as a number",
+			target:   "/prices",
+			body:     `{"price": "1000000000000.01"}`,
+			status:   http.StatusBadRequest,
+			expected: `"field":"price"`,
+		},
+		{
+			name:     "invalid body",
+			target:   "/prices",
+			body:     `{"price": "free"}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `"invalid request body"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.True(t, strings.Contains(w.Body.String(), tt.expected), w.Body.String())
+		})
+	}
+
+	simbaTest.FuzzParams[params](t, 42, 5)
+}
@@ -0,0 +1,321 @@
+package simba
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+	"github.com/sillen102/simba/validation"
+)
+
+// DiscriminatedJsonHandlerFunc handles routes with a request body that is one of several types,
+// selected by a discriminator field.
+type DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody any] struct {
+	handler JsonHandlerFunc[RequestBody, Params, ResponseBody]
+	body    discriminatedBody[RequestBody]
+}
+
+// AuthenticatedDiscriminatedJsonHandlerFunc handles authenticated routes with a request body that is
+// one of several types, selected by a discriminator field.
+type AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody any] struct {
+	handler     func(ctx context.Context, req *models.Request[RequestBody, Params], authModel AuthModel) (*models.Response[ResponseBody], error)
+	authHandler auth.Handler[AuthModel]
+	body        discriminatedBody[RequestBody]
+}
+
+// discriminatedBody decodes request bodies into the variant selected by the discriminator field.
+type discriminatedBody[RequestBody any] struct {
+	discriminator string
+	variants      map[string]RequestBody
+}
+
+// DiscriminatedJsonHandler handles a Request with a polymorphic body. The value of the discriminator
+// field selects the variant the body is decoded into, after which defaults and validation are applied
+// as for the variant type. A missing or unknown discriminator value is rejected with 400 Bad Request.
+// The request body is documented in OpenAPI as oneOf the variants with a discriminator.
+//
+// RequestBody is usually an interface implemented by the variants, and each variant should declare
+// the discriminator field, so it's part of its schema and accepted when unknown fields are disallowed.
+//
+//	Example usage:
+//
+//	type Event interface{ isEvent() }
+//
+//	type UserCreated struct {
+//		Event string `json:"event"`
+//		Name  string `json:"name" validate:"required"`
+//	}
+//
+//	type UserDeleted struct {
+//		Event string `json:"event"`
+//		ID    int    `json:"id" validate:"required"`
+//	}
+//
+//	func handler(ctx context.Context, req *simba.Request[Event, simba.NoParams]) (*simba.Response[simba.NoBody], error) {
+//		switch event := req.Body.(type) {
+//		case UserCreated:
+//			// ...
+//		case UserDeleted:
+//			// ...
+//		}
+//	}
+//
+// Register the handler:
+//
+//	Mux.POST("/events", simba.DiscriminatedJsonHandler(handler, "event", map[string]Event{
+//		"user.created": UserCreated{},
+//		"user.deleted": UserDeleted{},
+//	}))
+func DiscriminatedJsonHandler[RequestBody, Params, ResponseBody any](
+	h JsonHandlerFunc[RequestBody, Params, ResponseBody],
+	discriminator string,
+	variants map[string]RequestBody,
+) Handler {
+	return DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]{
+		handler: h,
+		body:    discriminatedBody[RequestBody]{discriminator: discriminator, variants: variants},
+	}
+}
+
+// ServeHTTP implements the http.Handler interface for DiscriminatedJsonHandlerFunc.
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := handleDiscriminatedRequest[RequestBody, Params](r, h.body)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	resp, err := h.handler(ctx, req)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, resp, nil)
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetRequestBody() any {
+	return h.body.openAPIBody()
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetResponseBody() any {
+	var resb ResponseBody
+	return resb
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetParams() any {
+	var p Params
+	return p
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetProduces() string {
+	return responseMediaType[ResponseBody]()
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetHandler() any {
+	return h.handler
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetAuthModel() any {
+	return nil
+}
+
+func (h DiscriminatedJsonHandlerFunc[RequestBody, Params, ResponseBody]) GetAuthHandler() any {
+	return nil
+}
+
+// AuthDiscriminatedJsonHandler handles an authenticated Request with a polymorphic body.
+// See [DiscriminatedJsonHandler] for how the body is decoded.
+//
+// Register the handler:
+//
+//	Mux.POST("/events", simba.AuthDiscriminatedJsonHandler(handler, authHandler, "event", variants))
+func AuthDiscriminatedJsonHandler[RequestBody, Params, AuthModel, ResponseBody any](
+	handler func(ctx context.Context, req *models.Request[RequestBody, Params], authModel AuthModel) (*models.Response[ResponseBody], error),
+	authHandler auth.Handler[AuthModel],
+	discriminator string,
+	variants map[string]RequestBody,
+) Handler {
+	return AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]{
+		handler:     handler,
+		authHandler: authHandler,
+		body:        discriminatedBody[RequestBody]{discriminator: discriminator, variants: variants},
+	}
+}
+
+// ServeHTTP implements the http.Handler interface for AuthenticatedDiscriminatedJsonHandlerFunc.
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authModel, err := auth.HandleAuthRequest[AuthModel](h.authHandler, r)
+	if err != nil {
+		statusCode := http.StatusUnauthorized // Default status code for unauthorized access
+		if statusCoder, ok := err.(simbaErrors.StatusCodeProvider); ok {
+			statusCode = statusCoder.StatusCode()
+		}
+
+		errorMessage := "unauthorized" // Default error message for unauthorized access
+		if msgProvider, ok := err.(simbaErrors.PublicMessageProvider); ok {
+			errorMessage = msgProvider.PublicMessage()
+		}
+
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(statusCode, errorMessage, err))
+		return
+	}
+
+	r = withPrincipal(r, authModel)
+	ctx = r.Context()
+
+	req, err := handleDiscriminatedRequest[RequestBody, Params](r, h.body)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	resp, err := h.handler(ctx, req, authModel)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, resp, nil)
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetRequestBody() any {
+	return h.body.openAPIBody()
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetParams() any {
+	var p Params
+	return p
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetResponseBody() any {
+	var resb ResponseBody
+	return resb
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetProduces() string {
+	return responseMediaType[ResponseBody]()
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetHandler() any {
+	return h.handler
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetAuthModel() any {
+	var am AuthModel
+	return am
+}
+
+func (h AuthenticatedDiscriminatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetAuthHandler() any {
+	return h.authHandler
+}
+
+// handleDiscriminatedRequest handles extracting the polymorphic body and params from the Request.
+func handleDiscriminatedRequest[RequestBody any, Params any](r *http.Request, body discriminatedBody[RequestBody]) (*models.Request[RequestBody, Params], error) {
+	params, err := ParseAndValidateParams[Params](r)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody, err := body.decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Request[RequestBody, Params]{
+		Body:   reqBody,
+		Params: params,
+	}, nil
+}
+
+// decode reads the discriminator field of the request body and decodes the body into the selected variant.
+func (b discriminatedBody[RequestBody]) decode(r *http.Request) (RequestBody, error) {
+	var reqBody RequestBody
+
+	if err := checkJsonContentType(r); err != nil {
+		return reqBody, err
+	}
+
+	requestSettings := getConfigurationFromContext(r.Context())
+	if requestSettings.LogRequestBody {
+		logging.From(r.Context()).Info("request body", "body", r.Body)
+	}
+
+	// Keep the bytes read while looking up the discriminator to decode them again into the variant
+	var buf bytes.Buffer
+	var fields map[string]json.RawMessage
+	if err := readJson(io.NopCloser(io.TeeReader(r.Body, &buf)), requestSettings, &fields); err != nil {
+		return reqBody, err
+	}
+
+	var value string
+	if raw, ok := fields[b.discriminator]; ok {
+		_ = json.Unmarshal(raw, &value)
+	}
+	variant, ok := b.variants[value]
+	if !ok {
+		return reqBody, simbaErrors.NewSimbaError(
+			http.StatusBadRequest,
+			"request validation failed",
+			nil,
+		).WithDetails([]validation.ValidationError{{
+			Field:   b.discriminator,
+			Err:     fmt.Sprintf("%s must be one of %s", b.discriminator, strings.Join(b.values(), ", ")),
+			Code:    "oneof",
+			Pointer: validation.JSONPointer(b.discriminator),
+		}})
+	}
+
+	target := reflect.New(reflect.TypeOf(variant))
+	if err := decodeJsonBody(r, io.NopCloser(&buf), requestSettings, target.Interface()); err != nil {
+		return reqBody, err
+	}
+
+	reqBody, ok = target.Elem().Interface().(RequestBody)
+	if !ok {
+		return reqBody, fmt.Errorf("variant %T is not a %T", variant, reqBody)
+	}
+	return reqBody, nil
+}
+
+// values returns the discriminator values in sorted order.
+func (b discriminatedBody[RequestBody]) values() []string {
+	return slices.Sorted(maps.Keys(b.variants))
+}
+
+// openAPIBody describes the body for the OpenAPI documentation.
+func (b discriminatedBody[RequestBody]) openAPIBody() openapiModels.DiscriminatedBody {
+	variants := make(map[string]any, len(b.variants))
+	for value, variant := range b.variants {
+		variants[value] = variant
+	}
+	return openapiModels.DiscriminatedBody{
+		Discriminator: b.discriminator,
+		Variants:      variants,
+	}
+}
@@ -0,0 +1,144 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type userEvent interface {
+	userID() int
+}
+
+type userCreated struct {
+	Event string `json:"event"`
+	ID    int    `json:"id" validate:"required"`
+	Name  string `json:"name" validate:"required"`
+	Role  string `json:"role" default:"member"`
+}
+
+func (e userCreated) userID() int { return e.ID }
+
+type userDeleted struct {
+	Event  string `json:"event"`
+	ID     int    `json:"id" validate:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (e *userDeleted) userID() int { return e.ID }
+
+func TestDiscriminatedJsonHandler(t *testing.T) {
+	t.Parallel()
+
+	variants := map[string]userEvent{
+		"user.created": userCreated{},
+		"user.deleted": &userDeleted{},
+	}
+
+	handler := func(ctx context.Context, req *models.Request[userEvent, models.NoParams]) (*models.Response[map[string]any], error) {
+		body := map[string]any{"id": req.Body.userID()}
+		switch event := req.Body.(type) {
+		case userCreated:
+			body["type"], body["role"] = "created", event.Role
+		case *userDeleted:
+			body["type"], body["reason"] = "deleted", event.Reason
+		}
+		return &models.Response[map[string]any]{Body: body}, nil
+	}
+
+	authHandler := func(ctx context.Context, req *models.Request[userEvent, models.NoParams], user *simbaTest.User) (*models.Response[map[string]any], error) {
+		return &models.Response[map[string]any]{Body: map[string]any{"id": req.Body.userID(), "user": user.Name}}, nil
+	}
+
+	app := simba.New(settings.WithAllowUnknownFields(false))
+	app.Router.POST("/events", simba.DiscriminatedJsonHandler(handler, "event", variants))
+	app.Router.POST("/auth/events", simba.AuthDiscriminatedJsonHandler(authHandler, simbaTest.BearerAuthAuthenticationHandler, "event", variants))
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "first variant with defaults",
+			path:     "/events",
+			body:     `{"event": "user.created", "id": 1, "name": "John"}`,
+			status:   http.StatusOK,
+			expected: `{"id":1,"role":"member","type":"created"}`,
+		},
+		{
+			name:     "pointer variant",
+			path:     "/events",
+			body:     `{"event": "user.deleted", "id": 2, "reason": "spam"}`,
+			status:   http.StatusOK,
+			expected: `{"id":2,"reason":"spam","type":"deleted"}`,
+		},
+		{
+			name:     "variant is validated",
+			path:     "/events",
+			body:     `{"event": "user.created", "id": 1}`,
+			status:   http.StatusBadRequest,
+			expected: `"field":"name"`,
+		},
+		{
+			name:     "fields of other variants are unknown",
+			path:     "/events",
+			body:     `{"event": "user.deleted", "id": 2, "name": "John"}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `"invalid request body"`,
+		},
+		{
+			name:     "unknown discriminator",
+			path:     "/events",
+			body:     `{"event": "user.updated", "id": 1}`,
+			status:   http.StatusBadRequest,
+			expected: `"error":"event must be one of user.created, user.deleted"`,
+		},
+		{
+			name:     "missing discriminator",
+			path:     "/events",
+			body:     `{"id": 1}`,
+			status:   http.StatusBadRequest,
+			expected: `"field":"event"`,
+		},
+		{
+			name:     "invalid JSON",
+			path:     "/events",
+			body:     `{"event": `,
+			status:   http.StatusUnprocessableEntity,
+			expected: `"invalid request body"`,
+		},
+		{
+			name:     "authenticated",
+			path:     "/auth/events",
+			body:     `{"event": "user.deleted", "id": 3}`,
+			status:   http.StatusOK,
+			expected: `{"id":3,"user":"John Doe"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.True(t, strings.Contains(w.Body.String(), tt.expected))
+		})
+	}
+}
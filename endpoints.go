@@ -6,7 +6,13 @@ import (
 
 // addDefaultEndpoints adds the default endpoints to the Mux.
 func (a *Application) addDefaultEndpoints() {
-	a.Router.addRoute(http.MethodGet, "/health", http.HandlerFunc(healthCheck))
+	registerDefaultEndpoints(a.Router)
+	a.Router.onReload = append(a.Router.onReload, registerDefaultEndpoints)
+}
+
+// registerDefaultEndpoints registers the default endpoints with the router, also after a reload.
+func registerDefaultEndpoints(r *Router) {
+	r.addRoute(http.MethodGet, "/health", http.HandlerFunc(healthCheck))
 }
 
 // healthCheck is a simple health check endpoint.
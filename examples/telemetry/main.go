@@ -10,8 +10,8 @@ import (
 
 	"github.com/sillen102/simba"
 	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
 	"github.com/sillen102/simba/simbaErrors"
-	"github.com/sillen102/simba/telemetry/config"
 
 	// NOTE: Telemetry usage is now handled via the OtelTelemetryProvider explicitly constructed and injected below.
 	// Imports for OTel interfaces only if needed for demonstration or metric/span creation in handlers.
@@ -217,30 +217,11 @@ func recordCustomMetrics(ctx context.Context) error {
 func main() {
 	ctx := context.Background()
 
-	// Setup the OpenTelemetry configuration
-	tcfg := &config.TelemetryConfig{
-		Enabled:        true,
-		ServiceName:    "simba-telemetry-demo",
-		ServiceVersion: "1.0.0",
-		Environment:    "demo",
-		Tracing: config.TracingConfig{
-			Enabled:  true,
-			Exporter: "otlp",
-			Endpoint: "otel-collector:4317",
-			Insecure: true,
-		},
-		Metrics: config.MetricsConfig{
-			Enabled:        true,
-			Exporter:       "otlp",
-			Endpoint:       "otel-collector:4317",
-			Insecure:       true,
-			ExportInterval: 30,
-		},
-	}
+	// Build the Simba application, configured from the environment (see docker-compose.yml)
+	app := simba.Default(settings.FromEnv())
 
-	// Build the Simba application
-	// No longer use settings.WithTelemetry, wiring is now explicit with provider injection
-	app := simba.Default()
+	// Setup the OpenTelemetry configuration from the same settings
+	tcfg := telemetryPkg.ConfigFromSettings(app.Settings)
 
 	// Explicitly construct and inject the OtelTelemetryProvider
 	prov, err := telemetryPkg.NewOtelTelemetryProvider(ctx, tcfg)
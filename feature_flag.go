@@ -0,0 +1,42 @@
+package simba
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// featureFlag is a feature flag a route is gated behind.
+type featureFlag struct {
+	name     string
+	provider models.FeatureFlagProvider
+}
+
+// requireFeatureFlags responds with the disabled feature status of the request settings, unless all the
+// feature flags are enabled for the request.
+func requireFeatureFlags(flags []featureFlag) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, flag := range flags {
+				enabled, err := flag.provider.Enabled(r, flag.name)
+				if err != nil {
+					simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
+						http.StatusInternalServerError, "failed to evaluate feature flag", err,
+					))
+					return
+				}
+				if !enabled {
+					status := getConfigurationFromContext(r.Context()).DisabledFeatureStatus
+					if status == 0 {
+						status = http.StatusNotFound
+					}
+					simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(status, strings.ToLower(http.StatusText(status)), nil))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
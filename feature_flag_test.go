@@ -0,0 +1,93 @@
+package simba_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestWithFeatureFlag(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{Body: map[string]string{"search": "beta"}}, nil
+	}
+
+	// betaUsers enables flags for requests from beta users only.
+	betaUsers := models.FeatureFlagFunc(func(r *http.Request, flag string) (bool, error) {
+		return flag == "beta-search" && r.Header.Get("X-User") == "beta", nil
+	})
+
+	serve := func(app *simba.Application, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("enabled flag runs the handler", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New()
+		app.Router.GET("/search", simba.JsonHandler(handler), simba.WithFeatureFlag("beta-search", betaUsers))
+
+		w := serve(app, "beta")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"search":"beta"}`, w.Body.Bytes())
+	})
+
+	t.Run("disabled flag responds not found", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New()
+		app.Router.GET("/search", simba.JsonHandler(handler), simba.WithFeatureFlag("beta-search", betaUsers))
+
+		w := serve(app, "regular")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("disabled flag responds with configured status", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New(settings.WithDisabledFeatureStatus(http.StatusForbidden))
+		app.Router.GET("/search", simba.JsonHandler(handler), simba.WithFeatureFlag("beta-search", betaUsers))
+
+		w := serve(app, "regular")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("all flags must be enabled", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New()
+		app.Router.GET("/search", simba.JsonHandler(handler),
+			simba.WithFeatureFlag("beta-search", betaUsers),
+			simba.WithFeatureFlag("new-ranking", betaUsers),
+		)
+
+		w := serve(app, "beta")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("provider error responds internal server error", func(t *testing.T) {
+		t.Parallel()
+
+		failing := models.FeatureFlagFunc(func(r *http.Request, flag string) (bool, error) {
+			return false, errors.New("flag service unavailable")
+		})
+
+		app := simba.New()
+		app.Router.GET("/search", simba.JsonHandler(handler), simba.WithFeatureFlag("beta-search", failing))
+
+		w := serve(app, "beta")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
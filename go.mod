@@ -11,6 +11,7 @@ require (
 	github.com/sillen102/config-loader v0.3.0
 	github.com/sillen102/simba/models v0.30.0-dev.7
 	github.com/swaggest/openapi-go v0.2.61
+	golang.org/x/text v0.37.0
 )
 
 require github.com/stretchr/testify v1.11.1 // indirect
@@ -23,13 +24,12 @@ require (
 	github.com/hashicorp/go-envparse v0.1.0 // indirect
 	github.com/kr/pretty v0.3.1 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
-	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2
 	github.com/rogpeppe/go-internal v1.14.1 // indirect
 	github.com/swaggest/jsonschema-go v0.3.79
 	github.com/swaggest/refl v1.4.0 // indirect
 	golang.org/x/crypto v0.52.0 // indirect
 	golang.org/x/sys v0.45.0 // indirect
-	golang.org/x/text v0.37.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
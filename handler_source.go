@@ -0,0 +1,42 @@
+package simba
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// handlerSource returns the Go file and line of the handler function, such as /app/users.go:42,
+// or an empty string if the handler is not a function, e.g. a struct based WebSocket handler.
+func handlerSource(handler Handler) string {
+	val := reflect.ValueOf(handler.GetHandler())
+	if val.Kind() != reflect.Func || val.IsNil() {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(val.Pointer())
+	if fn == nil {
+		return ""
+	}
+	file, line := fn.FileLine(fn.Entry())
+	if file == "" {
+		return ""
+	}
+	return file + ":" + strconv.Itoa(line)
+}
+
+// withHandlerSource adds the source location of the route handler to the request context,
+// so it is included when errors of the route are logged.
+func withHandlerSource(source string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if source == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(simbaContext.WithHandlerSource(r.Context(), source)))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package simba_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func failingSourceHandler(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+	return nil, errors.New("boom")
+}
+
+func TestLogErrorSource(t *testing.T) {
+	t.Parallel()
+
+	errorLog := func(t *testing.T, opts ...settings.Option) map[string]any {
+		t.Helper()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		app := simba.Default(append(opts, settings.WithLogger(logger), settings.WithAccessLog(false))...)
+		app.Router.GET("/fail", simba.JsonHandler(failingSourceHandler))
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		return line
+	}
+
+	t.Run("includes handler source when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		line := errorLog(t, settings.WithLogErrorSource(true))
+
+		source, ok := line["handlerSource"].(string)
+		assert.True(t, ok)
+		assert.True(t, strings.HasSuffix(source, "handler_source_test.go:20"))
+	})
+
+	t.Run("omits handler source by default", func(t *testing.T) {
+		t.Parallel()
+
+		line := errorLog(t)
+
+		_, ok := line["handlerSource"]
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,284 @@
+package simba
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// JSONArrayHandlerFunc is a function type for handling routes that stream a JSON array element by element.
+type JSONArrayHandlerFunc[RequestBody, Params, Element any] func(ctx context.Context, req *models.Request[RequestBody, Params], emit func(Element) error) error
+
+// AuthenticatedJSONArrayHandlerFunc is a function type for handling authenticated routes that stream a JSON array
+// element by element.
+type AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element any] struct {
+	handler     func(ctx context.Context, req *models.Request[RequestBody, Params], authModel AuthModel, emit func(Element) error) error
+	authHandler auth.Handler[AuthModel]
+}
+
+// JSONArrayHandler handles a Request by streaming a JSON array (application/json) to the client element
+// by element. The opening bracket is written with the first element, each element passed to emit is written
+// and flushed, and the closing bracket is written when the handler returns, so clients start receiving data
+// immediately and large lists are returned without building them in memory. Unlike [NDJSONHandler], the
+// response is a regular JSON array that any JSON client can read.
+//
+// The response status is 200 and is sent with the first element, so headers computed by the handler must be
+// set with [SetHeaders] before emitting. An error returned before any element was emitted is written as a
+// regular error response; once streaming has started the error is logged and the response is ended without
+// the closing bracket, so clients can tell the array is incomplete. emit returns the context error if the
+// client has disconnected, which should stop the handler.
+//
+//	Example usage:
+//
+//	func(ctx context.Context, req *simba.Request[simba.NoBody, simba.NoParams], emit func(User) error) error {
+//		for user := range users.All(ctx) {
+//			if err := emit(user); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	}
+//
+// Register the handler:
+//
+//	Mux.GET("/users", simba.JSONArrayHandler(handler))
+func JSONArrayHandler[RequestBody, Params, Element any](h JSONArrayHandlerFunc[RequestBody, Params, Element]) Handler {
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface for JSONArrayHandlerFunc.
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := handleJsonRequest[RequestBody, Params](r)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	streamJSONArray(w, r, func(ctx context.Context, emit func(Element) error) error {
+		return h(ctx, req, emit)
+	})
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetRequestBody() any {
+	var rb RequestBody
+	return rb
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetResponseBody() any {
+	var elements []Element
+	return elements
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetParams() any {
+	var p Params
+	return p
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetProduces() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetHandler() any {
+	return h
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetAuthModel() any {
+	return nil
+}
+
+func (h JSONArrayHandlerFunc[RequestBody, Params, Element]) GetAuthHandler() any {
+	return nil
+}
+
+// AuthJSONArrayHandler handles an authenticated Request by streaming a JSON array to the client element
+// by element. See [JSONArrayHandler] for how elements and errors are written.
+//
+// Register the handler:
+//
+//	Mux.GET("/users", simba.AuthJSONArrayHandler(handler, authHandler))
+func AuthJSONArrayHandler[RequestBody, Params, AuthModel, Element any](
+	handler func(ctx context.Context, req *models.Request[RequestBody, Params], authModel AuthModel, emit func(Element) error) error,
+	authHandler auth.Handler[AuthModel],
+) Handler {
+	return AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]{
+		handler:     handler,
+		authHandler: authHandler,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface for AuthenticatedJSONArrayHandlerFunc.
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authModel, err := auth.HandleAuthRequest[AuthModel](h.authHandler, r)
+	if err != nil {
+		statusCode := http.StatusUnauthorized // Default status code for unauthorized access
+		if statusCoder, ok := err.(simbaErrors.StatusCodeProvider); ok {
+			statusCode = statusCoder.StatusCode()
+		}
+
+		errorMessage := "unauthorized" // Default error message for unauthorized access
+		if msgProvider, ok := err.(simbaErrors.PublicMessageProvider); ok {
+			errorMessage = msgProvider.PublicMessage()
+		}
+
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(statusCode, errorMessage, err))
+		return
+	}
+
+	r = withPrincipal(r, authModel)
+
+	req, err := handleJsonRequest[RequestBody, Params](r)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	streamJSONArray(w, r, func(ctx context.Context, emit func(Element) error) error {
+		return h.handler(ctx, req, authModel, emit)
+	})
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetRequestBody() any {
+	var rb RequestBody
+	return rb
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetParams() any {
+	var p Params
+	return p
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetResponseBody() any {
+	var elements []Element
+	return elements
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetProduces() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetHandler() any {
+	return h.handler
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetAuthModel() any {
+	var am AuthModel
+	return am
+}
+
+func (h AuthenticatedJSONArrayHandlerFunc[RequestBody, Params, AuthModel, Element]) GetAuthHandler() any {
+	return h.authHandler
+}
+
+// jsonArrayStream writes the elements of a JSON array, flushing after each element.
+type jsonArrayStream[Element any] struct {
+	ctx        context.Context
+	w          http.ResponseWriter
+	headers    *streamHeaders
+	controller *http.ResponseController
+	started    bool
+	elements   int
+}
+
+// streamJSONArray runs stream with a context accepting response headers until the first element and an emit
+// function that writes elements to the response.
+func streamJSONArray[Element any](w http.ResponseWriter, r *http.Request, stream func(ctx context.Context, emit func(Element) error) error) {
+	logger := logging.From(r.Context())
+	ctx, headers := withStreamHeaders(r.Context(), w)
+
+	s := &jsonArrayStream[Element]{
+		ctx:        ctx,
+		w:          w,
+		headers:    headers,
+		controller: http.NewResponseController(w),
+		started:    false,
+		elements:   0,
+	}
+
+	err := stream(ctx, s.emit)
+
+	switch {
+	case simbaContext.IsClientCancelled(r.Context()):
+		logger.Debug("request cancelled by client, ending JSON array stream",
+			"reason", simbaContext.ClientCancelledReason,
+			"elements", s.elements,
+		)
+	case err != nil && !s.started:
+		headers.send(func() { simbaErrors.WriteError(w, r, err) })
+	case err != nil:
+		logger.Error("failed to stream JSON array response", "error", err, "elements", s.elements)
+	default:
+		if err = s.start(); err == nil {
+			_, err = s.w.Write([]byte("]"))
+		}
+		if err != nil {
+			logger.Error("failed to close JSON array response", "error", err, "elements", s.elements)
+		}
+	}
+}
+
+// emit writes a single element and flushes it to the client.
+func (s *jsonArrayStream[Element]) emit(element Element) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	visible, err := applyVisibility(s.ctx, element)
+	if err != nil {
+		return err
+	}
+	formatted, err := applyTimeFormat(s.ctx, visible)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(formatted)
+	if err != nil {
+		return err
+	}
+
+	if s.elements > 0 {
+		data = append([]byte(","), data...)
+	} else if err = s.start(); err != nil {
+		return err
+	}
+
+	if _, err = s.w.Write(data); err != nil {
+		return err
+	}
+	s.elements++
+
+	if err = s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
+
+// start writes the response headers and the opening bracket of the array.
+func (s *jsonArrayStream[Element]) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+	s.headers.send(func() {
+		s.w.Header().Set("Content-Type", mimetypes.ApplicationJSON)
+		s.w.WriteHeader(http.StatusOK)
+	})
+	_, err := s.w.Write([]byte("["))
+	return err
+}
@@ -0,0 +1,59 @@
+package simba
+
+import (
+	"io"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// depthLimitReader scans JSON as it is read and fails once objects and arrays are nested deeper than the limit,
+// before the decoder recurses into them. A limit of 0 or less reads the JSON as is.
+type depthLimitReader struct {
+	reader   io.Reader
+	limit    int
+	depth    int
+	inString bool
+	escaped  bool
+	// err is set once the limit is exceeded, since decoders don't necessarily return the error of the reader
+	err *simbaErrors.BodyTooDeep
+}
+
+func newDepthLimitReader(body io.Reader, limit int) *depthLimitReader {
+	return &depthLimitReader{reader: body, limit: limit, depth: 0, inString: false, escaped: false, err: nil}
+}
+
+func (d *depthLimitReader) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.reader.Read(p)
+	if d.limit <= 0 {
+		return n, err
+	}
+
+	for i, b := range p[:n] {
+		switch {
+		case d.escaped:
+			d.escaped = false
+		case d.inString:
+			switch b {
+			case '\\':
+				d.escaped = true
+			case '"':
+				d.inString = false
+			}
+		case b == '"':
+			d.inString = true
+		case b == '{' || b == '[':
+			d.depth++
+			if d.depth > d.limit {
+				d.err = &simbaErrors.BodyTooDeep{Limit: d.limit}
+				return i, d.err
+			}
+		case b == '}' || b == ']':
+			d.depth--
+		}
+	}
+	return n, err
+}
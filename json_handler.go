@@ -104,7 +104,7 @@ func (h JsonHandlerFunc[RequestBody, Params, ResponseBody]) GetAccepts() string
 }
 
 func (h JsonHandlerFunc[RequestBody, Params, ResponseBody]) GetProduces() string {
-	return mimetypes.ApplicationJSON
+	return responseMediaType[ResponseBody]()
 }
 
 func (h JsonHandlerFunc[RequestBody, Params, ResponseBody]) GetHandler() any {
@@ -204,6 +204,9 @@ func (h AuthenticatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBod
 		return
 	}
 
+	r = withPrincipal(r, authModel)
+	ctx = r.Context()
+
 	req, err := handleJsonRequest[RequestBody, Params](r)
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
@@ -239,7 +242,7 @@ func (h AuthenticatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBod
 }
 
 func (h AuthenticatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetProduces() string {
-	return mimetypes.ApplicationJSON
+	return responseMediaType[ResponseBody]()
 }
 
 func (h AuthenticatedJsonHandlerFunc[RequestBody, Params, AuthModel, ResponseBody]) GetHandler() any {
@@ -6,16 +6,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/sillen102/simba"
 	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/models"
 	"github.com/sillen102/simba/settings"
 	"github.com/sillen102/simba/simbaErrors"
@@ -425,3 +429,652 @@ func TestDefaultValuesOnRequestBody(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 }
+
+func TestJsonHandlerWithErrorFormatter(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[simbaTest.RequestBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	formatter := func(ctx context.Context, err *simbaErrors.SimbaError) (int, string, any) {
+		return err.StatusCode(), "application/vnd.error+json", map[string]any{
+			"error":   err.PublicMessage(),
+			"details": err.Details(),
+		}
+	}
+
+	body := strings.NewReader(`{}`)
+	req := httptest.NewRequest(http.MethodPost, "/test", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	logBuffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logBuffer, &slog.HandlerOptions{}))
+	app := simba.New(settings.WithLogger(logger), settings.WithErrorFormatter(formatter))
+	app.Router.POST("/test", simba.JsonHandler(handler))
+	app.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/vnd.error+json", w.Header().Get("Content-Type"))
+
+	var errorBody map[string]any
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorBody))
+	assert.Equal(t, "request validation failed", errorBody["error"])
+	assert.Equal(t, 1, len(errorBody["details"].([]any)))
+}
+
+func TestJsonHandlerWithMapBody(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		Page int `query:"page" validate:"min=1"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[map[string]any, params]) (*models.Response[map[string]any], error) {
+		return &models.Response[map[string]any]{Body: req.Body}, nil
+	}
+
+	newApp := func(opts ...settings.Option) *simba.Application {
+		logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{}))
+		app := simba.New(append([]settings.Option{settings.WithLogger(logger)}, opts...)...)
+		app.Router.POST("/proxy", simba.JsonHandler(handler))
+		return app
+	}
+
+	t.Run("decodes arbitrary objects", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/proxy?page=1", strings.NewReader(`{"name":"John","nested":{"tags":["a","b"]}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		newApp().Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"name":"John","nested":{"tags":["a","b"]}}`+"\n", w.Body.String())
+	})
+
+	t.Run("validates params", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/proxy?page=0", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		newApp().Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects bodies over the size limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/proxy?page=1", strings.NewReader(`{"name":"a very long value"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		newApp(settings.WithMaxBodySize(10)).Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, "request body exceeds the limit of 10 bytes", w.Body.String())
+	})
+}
+
+func TestJsonHandlerMaxJSONDepth(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[map[string]any, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	testCases := []struct {
+		name           string
+		body           string
+		maxDepth       int
+		expectedStatus int
+	}{
+		{name: "within the limit", body: `{"a":{"b":[1,2]}}`, maxDepth: 3, expectedStatus: http.StatusNoContent},
+		{name: "exceeds the limit", body: `{"a":{"b":[[1]]}}`, maxDepth: 3, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "brackets in strings are not counted", body: `{"a":"[[[{{{","b":"\"[[["}`, maxDepth: 1, expectedStatus: http.StatusNoContent},
+		{name: "siblings do not add up", body: `{"a":{},"b":[],"c":{"d":[]}}`, maxDepth: 3, expectedStatus: http.StatusNoContent},
+		{name: "stack exhausting payload", body: `{"a":` + strings.Repeat("[", 100000) + strings.Repeat("]", 100000) + `}`, maxDepth: 32, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "no limit", body: `{"a":` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`, maxDepth: 0, expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{}))
+			app := simba.New(settings.WithLogger(logger), settings.WithMaxJSONDepth(tc.maxDepth))
+			app.Router.POST("/test", simba.JsonHandler(handler))
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusUnprocessableEntity {
+				assert.Contains(t, "request body exceeds the nesting depth limit of "+strconv.Itoa(tc.maxDepth), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJsonHandlerEnforceContentType(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[simbaTest.RequestBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		enforce        bool
+		expectedStatus int
+	}{
+		{name: "rejects missing content type", contentType: "", enforce: true, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "rejects mismatched content type", contentType: "text/plain", enforce: true, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "accepts matching content type", contentType: "application/json; charset=utf-8", enforce: true, expectedStatus: http.StatusNoContent},
+		{name: "disabled", contentType: "text/plain", enforce: false, expectedStatus: http.StatusBadRequest},
+	}
+
+	passThrough := func(next http.Handler) http.Handler { return next }
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{}))
+			app := simba.New(settings.WithLogger(logger), settings.WithEnforceContentType(tc.enforce))
+			app.Router.POST("/test", simba.JsonHandler(handler))
+			app.Router.POSTWithMiddleware("/middleware", simba.JsonHandler(handler), passThrough)
+
+			for _, path := range []string{"/test", "/middleware"} {
+				req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"John","age":30,"description":"test"}`))
+				if tc.contentType != "" {
+					req.Header.Set("Content-Type", tc.contentType)
+				}
+				w := httptest.NewRecorder()
+				app.Router.ServeHTTP(w, req)
+
+				assert.Equal(t, tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestJsonHandlerClientCancelled(t *testing.T) {
+	t.Parallel()
+
+	for _, handlerErr := range []error{nil, context.Canceled} {
+		t.Run(fmt.Sprintf("handler error %v", handlerErr), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+				cancel() // client disconnects while the handler is running
+				if handlerErr != nil {
+					return nil, handlerErr
+				}
+				return &models.Response[map[string]string]{Body: map[string]string{"message": "too late"}}, nil
+			}
+
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", nil)
+			w := httptest.NewRecorder()
+
+			logBuffer := &bytes.Buffer{}
+			logger := slog.New(slog.NewTextHandler(logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			app := simba.Default(settings.WithLogger(logger))
+			app.Router.GET("/test", simba.JsonHandler(handler))
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, "", w.Body.String())
+			assert.Equal(t, "", w.Header().Get("Content-Type"))
+			assert.Contains(t, "reason=client_cancelled", logBuffer.String())
+		})
+	}
+}
+
+func TestJsonHandlerAccepted(t *testing.T) {
+	t.Parallel()
+
+	app := simba.New()
+	app.Router.POST("/jobs", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return models.Accepted("/jobs/1", models.NoBody{}), nil
+	}))
+	app.Router.POST("/jobs/with-body", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return models.Accepted("/jobs/2", map[string]string{"status": "pending"}), nil
+	}))
+
+	t.Run("without body", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "/jobs/1", w.Header().Get("Location"))
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("with body", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/jobs/with-body", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "/jobs/2", w.Header().Get("Location"))
+		assert.Equal(t, `{"status":"pending"}`, strings.TrimSpace(w.Body.String()))
+	})
+}
+
+func TestJsonHandlerMultiStatus(t *testing.T) {
+	t.Parallel()
+
+	app := simba.New()
+	app.Router.POST("/users/bulk", simba.JsonHandler(func(ctx context.Context, req *models.Request[[]string, models.NoParams]) (*models.Response[models.MultiStatusBody[string]], error) {
+		results := make([]models.ItemResult[string], 0, len(req.Body))
+		for i, name := range req.Body {
+			if name == "" {
+				results = append(results, models.ItemFailed[string](i, http.StatusUnprocessableEntity, "name is required"))
+				continue
+			}
+			results = append(results, models.ItemSucceeded(i, http.StatusCreated, strings.ToUpper(name)))
+		}
+		return models.MultiStatus(results), nil
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/bulk", strings.NewReader(`["john", ""]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.Equal(t,
+		`{"results":[{"index":0,"status":201,"result":"JOHN"},{"index":1,"status":422,"error":"name is required"}]}`,
+		strings.TrimSpace(w.Body.String()),
+	)
+}
+
+func TestJsonHandlerFailFastValidation(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		Tenant string `header:"X-Tenant" validate:"required"`
+		Region string `header:"X-Region" validate:"required"`
+	}
+	type item struct {
+		SKU string `json:"sku" validate:"required"`
+	}
+	type body struct {
+		Customer string `json:"customer" validate:"required"`
+		Items    []item `json:"items" validate:"dive"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[body, params]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	collectAll := simba.New()
+	collectAll.Router.POST("/orders", simba.JsonHandler(handler))
+
+	failFast := simba.New(settings.WithFailFastValidation(true))
+	failFast.Router.POST("/orders", simba.JsonHandler(handler))
+
+	tests := []struct {
+		name     string
+		app      *simba.Application
+		params   bool
+		expected []string
+	}{
+		{
+			name:     "collect all params errors",
+			app:      collectAll,
+			expected: []string{"Tenant", "Region"},
+		},
+		{
+			name:     "fail fast on params",
+			app:      failFast,
+			expected: []string{"Tenant"},
+		},
+		{
+			name:     "collect all body errors",
+			app:      collectAll,
+			params:   true,
+			expected: []string{"customer", "sku", "sku"},
+		},
+		{
+			name:     "fail fast on body",
+			app:      failFast,
+			params:   true,
+			expected: []string{"customer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"items": [{}, {}]}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.params {
+				req.Header.Set("X-Tenant", "acme")
+				req.Header.Set("X-Region", "eu")
+			}
+			w := httptest.NewRecorder()
+			tt.app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var errorResponse simbaErrors.ErrorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+			details, ok := errorResponse.Details.([]any)
+			assert.True(t, ok)
+
+			fields := make([]string, 0, len(details))
+			for _, detail := range details {
+				fields = append(fields, detail.(map[string]any)["field"].(string))
+			}
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestJsonHandlerPointerValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		Tenant string `header:"X-Tenant" validate:"required"`
+	}
+	type item struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	type body struct {
+		Items []item `json:"items" validate:"dive"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[body, params]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	app := simba.New(settings.WithValidationErrorFormat(models.PointerValidationErrorFormat))
+	app.Router.POST("/orders", simba.JsonHandler(handler))
+
+	post := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"items":[{"email":"a@b.c"},{"email":"invalid"}]}`))
+		req.Header.Set("Content-Type", mimetypes.ApplicationJSON)
+		if tenant != "" {
+			req.Header.Set("X-Tenant", tenant)
+		}
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	var errorResponse struct {
+		Details json.RawMessage `json:"details"`
+	}
+
+	w := post("acme")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+	assert.JSONEq(t, `[{"pointer":"/items/1/email","code":"email","detail":"email must be a valid email address"}]`, errorResponse.Details)
+
+	w = post("")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+	assert.JSONEq(t, `[{"parameter":"Tenant","code":"required","detail":"Tenant is a required field"}]`, errorResponse.Details)
+}
+
+func TestJsonHandlerMethodScopedValidation(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		ID string `path:"id" validate:"excluded_on=POST"`
+	}
+	type body struct {
+		Name  string `json:"name" validate:"required_on=POST PUT,omitempty,min=2"`
+		Email string `json:"email" validate:"required_on=POST PUT,omitempty,email"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[body, params]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	app := simba.New()
+	app.Router.POST("/users", simba.JsonHandler(handler))
+	app.Router.PATCH("/users/{id}", simba.JsonHandler(handler))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expected []string
+	}{
+		{
+			name:     "required on POST",
+			method:   http.MethodPost,
+			path:     "/users",
+			body:     `{}`,
+			expected: []string{"name", "email"},
+		},
+		{
+			name:   "optional on PATCH",
+			method: http.MethodPatch,
+			path:   "/users/1",
+			body:   `{"name": "Jane"}`,
+		},
+		{
+			name:     "other rules apply on PATCH",
+			method:   http.MethodPatch,
+			path:     "/users/1",
+			body:     `{"email": "invalid"}`,
+			expected: []string{"email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", mimetypes.ApplicationJSON)
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			if len(tt.expected) == 0 {
+				assert.Equal(t, http.StatusNoContent, w.Code)
+				return
+			}
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var errorResponse simbaErrors.ErrorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+			details, ok := errorResponse.Details.([]any)
+			assert.True(t, ok)
+
+			fields := make([]string, 0, len(details))
+			for _, detail := range details {
+				fields = append(fields, detail.(map[string]any)["field"].(string))
+			}
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestJsonHandlerFileResponse(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(resp *models.Response[models.File]) *simba.Application {
+		app := simba.New()
+		app.Router.GET("/download", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.File], error) {
+			return resp, nil
+		}))
+		return app
+	}
+
+	t.Run("infers content type and sets attachment disposition", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "report.pdf", Content: strings.NewReader("%PDF-1.7")},
+		})
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/download", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=report.pdf`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "%PDF-1.7", w.Body.String())
+	})
+
+	t.Run("falls back to octet-stream and supports inline", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "data.unknownext", Content: strings.NewReader("data"), Inline: true},
+		})
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/download", nil))
+
+		assert.Equal(t, mimetypes.ApplicationOctetStream, w.Header().Get("Content-Type"))
+		assert.Equal(t, `inline; filename=data.unknownext`, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("explicit content type and headers take precedence", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Headers: http.Header{"Content-Disposition": {"attachment"}},
+			Body:    models.File{Filename: "notes.txt", Content: strings.NewReader("notes"), ContentType: mimetypes.TextCSV},
+			Status:  http.StatusCreated,
+		})
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/download", nil))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, mimetypes.TextCSV, w.Header().Get("Content-Type"))
+		assert.Equal(t, "attachment", w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("seekable content sets content length", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "report.pdf", Content: strings.NewReader("%PDF-1.7")},
+		})
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/download", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "8", w.Header().Get("Content-Length"))
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	})
+
+	t.Run("seekable content supports range requests", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "report.pdf", Content: strings.NewReader("%PDF-1.7")},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/download", nil)
+		req.Header.Set("Range", "bytes=5-")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "bytes 5-7/8", w.Header().Get("Content-Range"))
+		assert.Equal(t, "3", w.Header().Get("Content-Length"))
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, "1.7", w.Body.String())
+	})
+
+	t.Run("unsatisfiable range", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "report.pdf", Content: strings.NewReader("%PDF-1.7")},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/download", nil)
+		req.Header.Set("Range", "bytes=100-")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+		assert.Equal(t, "bytes */8", w.Header().Get("Content-Range"))
+	})
+
+	t.Run("range of a modified file is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "report.pdf", Content: strings.NewReader("%PDF-1.7"), ModTime: modTime},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/download", nil)
+		req.Header.Set("Range", "bytes=5-")
+		req.Header.Set("If-Range", modTime.Add(-time.Hour).Format(http.TimeFormat))
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, modTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+		assert.Equal(t, "%PDF-1.7", w.Body.String())
+	})
+
+	t.Run("explicit size of unseekable content", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(&models.Response[models.File]{
+			Body: models.File{Filename: "data.csv", Content: io.LimitReader(strings.NewReader("a,b\n1,2\n"), 8), Size: 8},
+		})
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/download", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "8", w.Header().Get("Content-Length"))
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "a,b\n1,2\n", w.Body.String())
+	})
+}
+
+func TestJsonHandlerResponseTransformer(t *testing.T) {
+	t.Parallel()
+
+	transformer := func(ctx context.Context, body any) any {
+		return map[string]any{"data": body}
+	}
+
+	app := simba.New(settings.WithResponseTransformer(transformer))
+	app.Router.GET("/users", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{Body: map[string]string{"name": "John"}}, nil
+	}))
+	app.Router.DELETE("/users", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}))
+
+	t.Run("wraps body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{\"data\":{\"name\":\"John\"}}\n", w.Body.String())
+	})
+
+	t.Run("no content is not transformed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/users", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "", w.Body.String())
+	})
+}
@@ -0,0 +1,72 @@
+package simba
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/text/language"
+
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// LocaleFromContext returns the locale of the request, such as en or pt-BR: the supported locale of
+// [settings.Request] that best matches its Accept-Language header, or the default locale if none matches.
+// Validation error messages are translated to this locale.
+// Returns an empty string if the context doesn't belong to a request handled by a simba router.
+func LocaleFromContext(ctx context.Context) string {
+	return simbaContext.GetLocale(ctx)
+}
+
+// localeNegotiator determines the locale of a request.
+type localeNegotiator struct {
+	defaultLocale string
+	locales       []string
+	matcher       language.Matcher
+}
+
+func newLocaleNegotiator(requestSettings *settings.Request) localeNegotiator {
+	defaultLocale := requestSettings.DefaultLocale
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+
+	negotiator := localeNegotiator{
+		defaultLocale: defaultLocale,
+		locales:       nil,
+		matcher:       nil,
+	}
+	if len(requestSettings.SupportedLocales) == 0 {
+		return negotiator
+	}
+
+	// The matcher falls back to the first tag, so the default locale goes first
+	negotiator.locales = append([]string{defaultLocale}, requestSettings.SupportedLocales...)
+	tags := make([]language.Tag, 0, len(negotiator.locales))
+	for _, locale := range negotiator.locales {
+		// Invalid locales are rejected when the settings are loaded
+		tags = append(tags, language.Make(locale))
+	}
+	negotiator.matcher = language.NewMatcher(tags)
+	return negotiator
+}
+
+// negotiate returns the supported locale that best matches the Accept-Language header of the request,
+// or the default locale if none matches.
+func (l localeNegotiator) negotiate(r *http.Request) string {
+	header := r.Header.Get("Accept-Language")
+	if l.matcher == nil || header == "" {
+		return l.defaultLocale
+	}
+
+	desired, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(desired) == 0 {
+		return l.defaultLocale
+	}
+
+	_, index, confidence := l.matcher.Match(desired...)
+	if confidence == language.No {
+		return l.defaultLocale
+	}
+	return l.locales[index]
+}
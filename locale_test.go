@@ -0,0 +1,102 @@
+package simba_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaTest/assert"
+	"github.com/sillen102/simba/validation"
+)
+
+func TestLocaleFromContext(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{Body: map[string]string{"locale": simba.LocaleFromContext(ctx)}}, nil
+	}
+
+	localized := simba.New(settings.WithLocales("en", "de", "pt-BR"))
+	localized.Router.GET("/locale", simba.JsonHandler(handler))
+
+	unlocalized := simba.New()
+	unlocalized.Router.GET("/locale", simba.JsonHandler(handler))
+
+	tests := []struct {
+		name           string
+		app            *simba.Application
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "no header", app: localized, acceptLanguage: "", expected: "en"},
+		{name: "regional variant of supported locale", app: localized, acceptLanguage: "de-AT,de;q=0.9", expected: "de"},
+		{name: "exact match", app: localized, acceptLanguage: "pt-BR", expected: "pt-BR"},
+		{name: "preferred supported locale", app: localized, acceptLanguage: "fr;q=1.0, de;q=0.5", expected: "de"},
+		{name: "unsupported locale", app: localized, acceptLanguage: "fr", expected: "en"},
+		{name: "invalid header", app: localized, acceptLanguage: "!!!", expected: "en"},
+		{name: "no supported locales", app: unlocalized, acceptLanguage: "de", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/locale", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			tt.app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"locale":"`+tt.expected+`"}`, w.Body.Bytes())
+		})
+	}
+}
+
+func TestLocalizedValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	type body struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	app := simba.New(settings.WithLocales("en", "de"))
+	app.Router.POST("/users", simba.JsonHandler(func(ctx context.Context, req *models.Request[body, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}))
+
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{acceptLanguage: "de-DE", expected: "name ist ein Pflichtfeld"},
+		{acceptLanguage: "en-US", expected: "name is a required field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errorResponse struct {
+				Details []validation.ValidationError `json:"details"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+			assert.Len(t, errorResponse.Details, 1)
+			assert.Equal(t, tt.expected, errorResponse.Details[0].Err)
+		})
+	}
+}
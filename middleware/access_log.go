@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// overflowLatencyBucket is the bucket of requests slower than the largest latency bucket.
+const overflowLatencyBucket = "+Inf"
+
+// DefaultLatencyBuckets are the upper bounds of the latency buckets if none are set.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// AccessLog writes a standardized access log line for every request.
+type AccessLog struct {
+
+	// Level is the level of the access log lines. Paths with a level set with [SetPathLogLevel] are
+	// logged at that level instead
+	Level slog.Level `exhaustruct:"optional"`
+
+	// Fields are the fields of the access log lines, in order. If empty, [models.DefaultAccessLogFields] are logged
+	Fields []models.AccessLogField `exhaustruct:"optional"`
+
+	// LatencyBuckets are the ascending upper bounds of the latency buckets. If empty, [DefaultLatencyBuckets] are used
+	LatencyBuckets []time.Duration `exhaustruct:"optional"`
+
+	// Stats counts the requests by status class and latency bucket. Requests are not counted if nil
+	Stats *AccessLogStats `exhaustruct:"optional"`
+
+	// PrincipalID renders the authenticated principal of a request. If nil, principals that are strings
+	// or implement [fmt.Stringer] are logged and other principals are left out
+	PrincipalID func(principal any) string `exhaustruct:"optional"`
+}
+
+// Log is a middleware that logs the method, path template, status, size, latency, client IP and principal
+// of every request once it has been handled. Paths excluded with [ExcludePaths] are neither logged nor counted,
+// and requests cancelled by the client before a response was written are logged at debug level without being
+// counted.
+func (a AccessLog) Log(next http.Handler) http.Handler {
+	fields := a.Fields
+	if len(fields) == 0 {
+		fields = models.DefaultAccessLogFields
+	}
+	buckets := a.LatencyBuckets
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := excludePaths[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		r = r.WithContext(simbaContext.WithPrincipalRecorder(r.Context()))
+		wrapped := wrapResponseWriter(w)
+
+		next.ServeHTTP(wrapped, r)
+
+		latency := time.Since(start)
+		ctx := r.Context()
+
+		// Requests abandoned before a response was written have no meaningful status, log them at a low level
+		if !wrapped.wroteHeader && simbaContext.IsClientCancelled(ctx) {
+			logging.From(ctx).Debug("request cancelled by client",
+				"reason", simbaContext.ClientCancelledReason,
+				"method", r.Method,
+				"path", routePath(r),
+				"latencyMs", roundDuration(latency),
+			)
+			return
+		}
+
+		status := wrapped.Status()
+		class := StatusClass(status)
+		bucket := LatencyBucket(latency, buckets)
+		if a.Stats != nil {
+			a.Stats.record(class, bucket)
+		}
+
+		level := a.Level
+		if pathLevel, ok := pathLogLevels[r.URL.Path]; ok {
+			level = pathLevel
+		}
+
+		logger := logging.From(ctx)
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		attrs := make([]slog.Attr, 0, len(fields))
+		for _, field := range fields {
+			key := field.String()
+			switch field {
+			case models.AccessLogMethod:
+				attrs = append(attrs, slog.String(key, r.Method))
+			case models.AccessLogPath:
+				attrs = append(attrs, slog.String(key, routePath(r)))
+			case models.AccessLogStatus:
+				attrs = append(attrs, slog.Int(key, status))
+			case models.AccessLogStatusClass:
+				attrs = append(attrs, slog.String(key, class))
+			case models.AccessLogBytes:
+				attrs = append(attrs, slog.Int64(key, wrapped.written))
+			case models.AccessLogLatency:
+				attrs = append(attrs, slog.Float64(key, roundDuration(latency)))
+			case models.AccessLogLatencyBucket:
+				attrs = append(attrs, slog.String(key, bucket))
+			case models.AccessLogClientIP:
+				attrs = append(attrs, slog.String(key, simbaContext.GetClientIP(ctx)))
+			case models.AccessLogRemoteIP:
+				attrs = append(attrs, slog.String(key, r.RemoteAddr))
+			case models.AccessLogPrincipal:
+				if principal := a.principalID(r); principal != "" {
+					attrs = append(attrs, slog.String(key, principal))
+				}
+			case models.AccessLogUserAgent:
+				attrs = append(attrs, slog.String(key, r.UserAgent()))
+			case models.AccessLogProtocol:
+				attrs = append(attrs, slog.String(key, r.Proto))
+			case models.AccessLogHost:
+				attrs = append(attrs, slog.String(key, r.Host))
+			case models.AccessLogReferer:
+				attrs = append(attrs, slog.String(key, r.Referer()))
+			}
+		}
+
+		logger.LogAttrs(ctx, level, "request processed", attrs...)
+	})
+}
+
+// principalID renders the principal authenticated while handling the request.
+func (a AccessLog) principalID(r *http.Request) string {
+	principal := simbaContext.RecordedPrincipal(r.Context())
+	if principal == nil {
+		principal = simbaContext.GetPrincipal(r.Context())
+	}
+	if principal == nil {
+		return ""
+	}
+	if a.PrincipalID != nil {
+		return a.PrincipalID(principal)
+	}
+
+	switch p := principal.(type) {
+	case string:
+		return p
+	case fmt.Stringer:
+		return p.String()
+	default:
+		return ""
+	}
+}
+
+// routePath returns the path template of the route matching the request, or the request path if none matched.
+func routePath(r *http.Request) string {
+	if r.Pattern == "" {
+		return r.URL.Path
+	}
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		return path
+	}
+	return r.Pattern
+}
+
+// StatusClass returns the class of a status, such as 2xx for 201.
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return strconv.Itoa(status)
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// LatencyBucket returns the smallest of the ascending bucket bounds the latency is within,
+// or +Inf if it is slower than all of them.
+func LatencyBucket(latency time.Duration, buckets []time.Duration) string {
+	for _, bound := range buckets {
+		if latency <= bound {
+			return bound.String()
+		}
+	}
+	return overflowLatencyBucket
+}
+
+// AccessLogStats counts the requests logged by [AccessLog] by status class and latency bucket.
+// It is safe for concurrent use.
+type AccessLogStats struct {
+	mu             sync.Mutex
+	statusClasses  map[string]int64
+	latencyBuckets map[string]int64
+}
+
+// NewAccessLogStats returns empty access log stats.
+func NewAccessLogStats() *AccessLogStats {
+	return &AccessLogStats{
+		mu:             sync.Mutex{},
+		statusClasses:  map[string]int64{},
+		latencyBuckets: map[string]int64{},
+	}
+}
+
+// StatusClasses returns the number of requests per status class, such as 2xx.
+func (s *AccessLogStats) StatusClasses() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.statusClasses)
+}
+
+// LatencyBuckets returns the number of requests per latency bucket, keyed by the upper bound of the bucket.
+func (s *AccessLogStats) LatencyBuckets() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.latencyBuckets)
+}
+
+func (s *AccessLogStats) record(class string, bucket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusClasses[class]++
+	s.latencyBuckets[bucket]++
+}
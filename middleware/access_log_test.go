@@ -0,0 +1,180 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type accessLogUser struct {
+	ID string
+}
+
+func (u accessLogUser) String() string {
+	return u.ID
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	serve := func(t *testing.T, accessLog middleware.AccessLog, handler http.HandlerFunc, target string) map[string]any {
+		t.Helper()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		mux := http.NewServeMux()
+		mux.Handle("GET /users/{id}", accessLog.Log(handler))
+
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.LoggerKey, logger))
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+
+		if buf.Len() == 0 {
+			return nil
+		}
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		return line
+	}
+
+	t.Run("logs default fields", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			// The principal is authenticated deeper in the handler chain, on a derived context
+			_ = simbaContext.WithPrincipal(r.Context(), accessLogUser{ID: "user-1"})
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("hello"))
+		}
+
+		line := serve(t, middleware.AccessLog{}, handler, "/users/42")
+
+		assert.Equal[any](t, "request processed", line["msg"])
+		assert.Equal[any](t, "INFO", line["level"])
+		assert.Equal[any](t, http.MethodGet, line["method"])
+		assert.Equal[any](t, "/users/{id}", line["path"])
+		assert.Equal[any](t, float64(http.StatusCreated), line["status"])
+		assert.Equal[any](t, "2xx", line["statusClass"])
+		assert.Equal[any](t, float64(5), line["bytes"])
+		assert.Equal[any](t, "5ms", line["latencyBucket"])
+		assert.Equal[any](t, "user-1", line["principal"])
+		assert.NotNil(t, line["latencyMs"])
+		_, ok := line["userAgent"]
+		assert.False(t, ok)
+	})
+
+	t.Run("logs selected fields at the configured level", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		accessLog := middleware.AccessLog{
+			Level:  slog.LevelWarn,
+			Fields: []models.AccessLogField{models.AccessLogPath, models.AccessLogStatusClass, models.AccessLogUserAgent},
+		}
+		line := serve(t, accessLog, handler, "/users/42")
+
+		assert.Equal[any](t, "WARN", line["level"])
+		assert.Equal[any](t, "/users/{id}", line["path"])
+		assert.Equal[any](t, "4xx", line["statusClass"])
+		assert.Equal[any](t, "", line["userAgent"])
+		_, ok := line["method"]
+		assert.False(t, ok)
+		_, ok = line["principal"]
+		assert.False(t, ok)
+	})
+
+	t.Run("renders principals with principal ID", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			_ = simbaContext.WithPrincipal(r.Context(), map[string]string{"sub": "user-2"})
+		}
+
+		accessLog := middleware.AccessLog{
+			PrincipalID: func(principal any) string { return principal.(map[string]string)["sub"] },
+		}
+		line := serve(t, accessLog, handler, "/users/42")
+
+		assert.Equal[any](t, "user-2", line["principal"])
+	})
+
+	t.Run("counts status classes and latency buckets", func(t *testing.T) {
+		t.Parallel()
+
+		stats := middleware.NewAccessLogStats()
+		accessLog := middleware.AccessLog{
+			LatencyBuckets: []time.Duration{time.Millisecond, time.Hour},
+			Stats:          stats,
+		}
+
+		ok := func(w http.ResponseWriter, r *http.Request) {}
+		slow := func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(2 * time.Millisecond)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		serve(t, accessLog, ok, "/users/1")
+		serve(t, accessLog, ok, "/users/2")
+		serve(t, accessLog, slow, "/users/3")
+
+		assert.Equal(t, map[string]int64{"2xx": 2, "5xx": 1}, stats.StatusClasses())
+		assert.Equal(t, int64(1), stats.LatencyBuckets()["1h0m0s"])
+	})
+	t.Run("counts requests cancelled after the response", func(t *testing.T) {
+		t.Parallel()
+
+		stats := middleware.NewAccessLogStats()
+		serveCancelled := func(handler http.HandlerFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/users/1", nil)
+
+			middleware.AccessLog{Stats: stats}.Log(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handler(w, r)
+				cancel() // The client goes away once the handler is done
+			})).ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		serveCancelled(func(w http.ResponseWriter, r *http.Request) {})
+		serveCancelled(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("done"))
+		})
+
+		assert.Equal(t, map[string]int64{"2xx": 1}, stats.StatusClasses())
+	})
+}
+
+func TestStatusClass(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1xx", middleware.StatusClass(http.StatusSwitchingProtocols))
+	assert.Equal(t, "2xx", middleware.StatusClass(http.StatusNoContent))
+	assert.Equal(t, "3xx", middleware.StatusClass(http.StatusFound))
+	assert.Equal(t, "4xx", middleware.StatusClass(http.StatusTeapot))
+	assert.Equal(t, "5xx", middleware.StatusClass(http.StatusBadGateway))
+	assert.Equal(t, "0", middleware.StatusClass(0))
+}
+
+func TestLatencyBucket(t *testing.T) {
+	t.Parallel()
+
+	buckets := []time.Duration{10 * time.Millisecond, time.Second}
+
+	assert.Equal(t, "10ms", middleware.LatencyBucket(3*time.Millisecond, buckets))
+	assert.Equal(t, "10ms", middleware.LatencyBucket(10*time.Millisecond, buckets))
+	assert.Equal(t, "1s", middleware.LatencyBucket(200*time.Millisecond, buckets))
+	assert.Equal(t, "+Inf", middleware.LatencyBucket(2*time.Second, buckets))
+}
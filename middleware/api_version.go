@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/validation"
+)
+
+const DefaultAPIVersionHeader = "API-Version"
+
+// APIVersion enforces a strict versioning policy by requiring every request to select one of the supported
+// API versions in a header. Requests without the header, or with an unsupported version, are rejected with
+// 400 Bad Request listing the allowed versions. The resolved version is stored in the context, read it with
+// simbaContext.GetAPIVersion, and echoed in the response header.
+type APIVersion struct {
+	// Header is the header the version is read from. Defaults to "API-Version"
+	Header string `exhaustruct:"optional"`
+	// Supported are the versions clients can select
+	Supported []string
+}
+
+// Require is a middleware that requires a supported API version in the request header.
+func (v APIVersion) Require(next http.Handler) http.Handler {
+	if len(v.Supported) == 0 {
+		panic("api version middleware must have at least one supported version")
+	}
+
+	header := http.CanonicalHeaderKey(valueOrDefault(v.Header, DefaultAPIVersionHeader))
+	supported := slices.Clone(v.Supported)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", header)
+
+		allowed := strings.Join(supported, ", ")
+		version := strings.TrimSpace(r.Header.Get(header))
+		if version == "" {
+			simbaErrors.WriteError(w, r, newAPIVersionError(header, "required", header+" is required, allowed versions are "+allowed))
+			return
+		}
+		if !slices.Contains(supported, version) {
+			simbaErrors.WriteError(w, r, newAPIVersionError(header, "oneof", header+" must be one of "+allowed))
+			return
+		}
+
+		w.Header().Set(header, version)
+		next.ServeHTTP(w, r.WithContext(simbaContext.WithAPIVersion(r.Context(), version)))
+	})
+}
+
+func newAPIVersionError(header, code, message string) *simbaErrors.SimbaError {
+	return simbaErrors.NewSimbaError(
+		http.StatusBadRequest,
+		"request validation failed",
+		nil,
+	).WithDetails([]validation.ValidationError{{
+		Field: header,
+		Err:   message,
+		Code:  code,
+	}})
+}
@@ -0,0 +1,99 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(simbaContext.GetAPIVersion(r.Context())))
+	})
+
+	tests := []struct {
+		name            string
+		apiVersion      middleware.APIVersion
+		header          string
+		version         string
+		expectedStatus  int
+		expectedVersion string
+		expectedError   string
+	}{
+		{
+			name:            "supported version",
+			apiVersion:      middleware.APIVersion{Supported: []string{"2024-01-01", "2025-01-01"}},
+			header:          "Api-Version",
+			version:         "2025-01-01",
+			expectedStatus:  http.StatusOK,
+			expectedVersion: "2025-01-01",
+		},
+		{
+			name:            "custom header",
+			apiVersion:      middleware.APIVersion{Header: "X-Api-Version", Supported: []string{"1", "2"}},
+			header:          "X-Api-Version",
+			version:         " 1 ",
+			expectedStatus:  http.StatusOK,
+			expectedVersion: "1",
+		},
+		{
+			name:           "missing version",
+			apiVersion:     middleware.APIVersion{Supported: []string{"1", "2"}},
+			header:         "Api-Version",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Api-Version is required, allowed versions are 1, 2",
+		},
+		{
+			name:           "unsupported version",
+			apiVersion:     middleware.APIVersion{Supported: []string{"1", "2"}},
+			header:         "Api-Version",
+			version:        "3",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Api-Version must be one of 1, 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tt.version != "" {
+				req.Header.Set(tt.header, tt.version)
+			}
+			w := httptest.NewRecorder()
+
+			tt.apiVersion.Require(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.header, w.Header().Get("Vary"))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedVersion, w.Body.String())
+				assert.Equal(t, tt.expectedVersion, w.Header().Get(tt.header))
+				return
+			}
+
+			var errorResponse simbaErrors.ErrorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+			assert.Equal(t, "request validation failed", errorResponse.Message)
+			assert.Equal[any](t, []any{map[string]any{"field": tt.header, "error": tt.expectedError}}, errorResponse.Details)
+		})
+	}
+}
+
+func TestAPIVersionWithoutSupportedVersions(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+	middleware.APIVersion{}.Require(http.NotFoundHandler())
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// ConcurrencyLimit configures a [ConcurrencyLimiter].
+type ConcurrencyLimit struct {
+	// MaxConcurrent is the maximum number of requests handled at once
+	MaxConcurrent int
+	// MaxQueued is the maximum number of requests waiting for a slot, requests beyond it are rejected right away
+	MaxQueued int `exhaustruct:"optional"`
+	// QueueTimeout is the maximum time a request waits for a slot, zero meaning until the client gives up
+	QueueTimeout time.Duration `exhaustruct:"optional"`
+	// RetryAfter is sent in the Retry-After header of rejected requests, one second if zero
+	RetryAfter time.Duration `exhaustruct:"optional"`
+}
+
+// ConcurrencyLimiter caps the number of requests handled at once across all the handlers it wraps, shedding
+// load without an external proxy. Requests beyond the limit wait in a queue for a slot to free up; requests
+// that don't fit in the queue or wait longer than the queue timeout are rejected with a 503 Service Unavailable
+// and a Retry-After header. It is safe for concurrent use.
+//
+//	Example usage:
+//
+//	limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{MaxConcurrent: 100, MaxQueued: 50})
+//	app.Router.UsePreRouting(limiter.Limit)
+type ConcurrencyLimiter struct {
+	limit    ConcurrencyLimit
+	slots    chan struct{}
+	inFlight atomic.Int64 `exhaustruct:"optional"`
+	queued   atomic.Int64 `exhaustruct:"optional"`
+	rejected atomic.Int64 `exhaustruct:"optional"`
+}
+
+// NewConcurrencyLimiter returns a limiter for the given limit. It panics if MaxConcurrent is not positive.
+func NewConcurrencyLimiter(limit ConcurrencyLimit) *ConcurrencyLimiter {
+	if limit.MaxConcurrent <= 0 {
+		panic("concurrency limiter requires a positive MaxConcurrent")
+	}
+	if limit.RetryAfter <= 0 {
+		limit.RetryAfter = time.Second
+	}
+	return &ConcurrencyLimiter{
+		limit: limit,
+		slots: make(chan struct{}, limit.MaxConcurrent),
+	}
+}
+
+// Limit is a middleware that enforces the limit. All handlers wrapped by the same limiter share its slots.
+func (l *ConcurrencyLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.acquire(r) {
+			if r.Context().Err() != nil {
+				return // The client gave up while queued
+			}
+			l.rejected.Add(1)
+			simbaErrors.WriteError(w, r, l.overloadedError())
+			return
+		}
+		defer l.release()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// InFlight returns the number of requests currently being handled.
+func (l *ConcurrencyLimiter) InFlight() int64 {
+	return l.inFlight.Load()
+}
+
+// Queued returns the number of requests currently waiting for a slot.
+func (l *ConcurrencyLimiter) Queued() int64 {
+	return l.queued.Load()
+}
+
+// Rejected returns the number of requests rejected because the limiter was saturated.
+func (l *ConcurrencyLimiter) Rejected() int64 {
+	return l.rejected.Load()
+}
+
+// acquire takes a slot for the request, waiting in the queue if none is free.
+// Returns false if the queue is full, the queue timeout expired or the request was cancelled.
+func (l *ConcurrencyLimiter) acquire(r *http.Request) bool {
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return true
+	default:
+	}
+
+	if l.queued.Add(1) > int64(l.limit.MaxQueued) {
+		l.queued.Add(-1)
+		return false
+	}
+	defer l.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.limit.QueueTimeout > 0 {
+		timer := time.NewTimer(l.limit.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return true
+	case <-timeout:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// release frees the slot of a handled request.
+func (l *ConcurrencyLimiter) release() {
+	l.inFlight.Add(-1)
+	<-l.slots
+}
+
+func (l *ConcurrencyLimiter) overloadedError() *simbaErrors.SimbaError {
+	seconds := int64(math.Ceil(l.limit.RetryAfter.Seconds()))
+	return simbaErrors.NewSimbaError(
+		http.StatusServiceUnavailable,
+		"server is overloaded",
+		nil,
+	).WithHeaders(http.Header{"Retry-After": {strconv.FormatInt(seconds, 10)}})
+}
@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Parallel()
+
+	// blockingHandler returns a handler that blocks until release is closed, signalling on started when a
+	// request is being handled.
+	blockingHandler := func(started chan<- struct{}, release <-chan struct{}) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+
+	// serve handles a request in the background and returns a channel receiving its response.
+	serve := func(ctx context.Context, handler http.Handler) <-chan *httptest.ResponseRecorder {
+		done := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequestWithContext(ctx, http.MethodGet, "/", nil))
+			done <- w
+		}()
+		return done
+	}
+
+	// waitQueued waits until the limiter has n queued requests.
+	waitQueued := func(t *testing.T, limiter *middleware.ConcurrencyLimiter, n int64) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for limiter.Queued() != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d queued requests, got %d", n, limiter.Queued())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("rejects requests beyond the limit", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{MaxConcurrent: 1})
+		handler := limiter.Limit(blockingHandler(started, release))
+
+		first := serve(context.Background(), handler)
+		<-started
+		assert.Equal(t, int64(1), limiter.InFlight())
+
+		w := <-serve(context.Background(), handler)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Equal(t, int64(1), limiter.Rejected())
+
+		close(release)
+		assert.Equal(t, http.StatusOK, (<-first).Code)
+		assert.Equal(t, int64(0), limiter.InFlight())
+	})
+
+	t.Run("queued request is handled once a slot frees up", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}, 2), make(chan struct{})
+		limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{MaxConcurrent: 1, MaxQueued: 1})
+		handler := limiter.Limit(blockingHandler(started, release))
+
+		first := serve(context.Background(), handler)
+		<-started
+		second := serve(context.Background(), handler)
+		waitQueued(t, limiter, 1)
+
+		w := <-serve(context.Background(), handler)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		close(release)
+		assert.Equal(t, http.StatusOK, (<-first).Code)
+		assert.Equal(t, http.StatusOK, (<-second).Code)
+		assert.Equal(t, int64(0), limiter.Queued())
+		assert.Equal(t, int64(1), limiter.Rejected())
+	})
+
+	t.Run("queued request is rejected after the queue timeout", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{
+			MaxConcurrent: 1,
+			MaxQueued:     1,
+			QueueTimeout:  10 * time.Millisecond,
+			RetryAfter:    1500 * time.Millisecond,
+		})
+		handler := limiter.Limit(blockingHandler(started, release))
+
+		first := serve(context.Background(), handler)
+		<-started
+
+		w := <-serve(context.Background(), handler)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.Equal(t, int64(0), limiter.Queued())
+
+		close(release)
+		assert.Equal(t, http.StatusOK, (<-first).Code)
+	})
+
+	t.Run("client giving up while queued is not counted as rejected", func(t *testing.T) {
+		t.Parallel()
+
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{MaxConcurrent: 1, MaxQueued: 1})
+		handler := limiter.Limit(blockingHandler(started, release))
+
+		first := serve(context.Background(), handler)
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		second := serve(ctx, handler)
+		waitQueued(t, limiter, 1)
+		cancel()
+		<-second
+
+		assert.Equal(t, int64(0), limiter.Queued())
+		assert.Equal(t, int64(0), limiter.Rejected())
+
+		close(release)
+		assert.Equal(t, http.StatusOK, (<-first).Code)
+	})
+
+	t.Run("panics without a positive limit", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+		middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimit{MaxConcurrent: 0})
+	})
+}
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestCSRF(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.CSRF{APIKeyHeaders: []string{"X-API-Key"}}.Protect(next)
+
+	t.Run("issues token cookie on safe requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		cookies := w.Result().Cookies()
+		assert.Equal(t, 1, len(cookies))
+		assert.Equal(t, middleware.DefaultCSRFCookieName, cookies[0].Name)
+		assert.NotEmpty(t, cookies[0].Value)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	})
+
+	t.Run("accepts matching header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookieName, Value: "token"})
+		req.Header.Set(middleware.DefaultCSRFHeaderName, "token")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 0, len(w.Result().Cookies()))
+	})
+
+	t.Run("accepts matching form field", func(t *testing.T) {
+		form := url.Values{middleware.DefaultCSRFFormField: []string{"token"}}
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookieName, Value: "token"})
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejects mismatched token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookieName, Value: "token"})
+		req.Header.Set(middleware.DefaultCSRFHeaderName, "other")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, "invalid CSRF token", w.Body.String())
+	})
+
+	t.Run("rejects missing token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/test", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("skips bearer and API key authenticated requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.Header.Set("Authorization", "Bearer abc")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.Header.Set("X-API-Key", "key")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
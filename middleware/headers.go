@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// DuplicateHeaders selects how [Headers] handles headers that are sent more than once.
+type DuplicateHeaders int
+
+const (
+	// KeepDuplicateHeaders keeps all values of repeated headers.
+	KeepDuplicateHeaders DuplicateHeaders = iota
+	// FirstDuplicateHeader keeps only the first value of repeated headers.
+	FirstDuplicateHeader
+	// LastDuplicateHeader keeps only the last value of repeated headers.
+	LastDuplicateHeader
+	// JoinDuplicateHeaders joins the values of repeated headers into a single comma-separated value,
+	// or semicolon-separated for Cookie.
+	JoinDuplicateHeaders
+	// RejectDuplicateHeaders rejects requests with repeated headers with a 400 Bad Request.
+	RejectDuplicateHeaders
+)
+
+// Headers sanitizes the headers of incoming requests before they reach handlers and param binding.
+// Header names are normalized to their canonical form, denied headers are removed, only allowed
+// headers are kept if an allowlist is set, and repeated headers are handled as configured.
+//
+// Register it with Router.UsePreRouting so it also runs before the default middleware, which for
+// example reads the trace ID header:
+//
+//	app.Router.UsePreRouting(middleware.Headers{
+//		Deny:       []string{"X-Request-Id", "X-Internal-User"},
+//		Duplicates: middleware.RejectDuplicateHeaders,
+//	}.Sanitize)
+type Headers struct {
+	// Deny lists headers that are removed from requests, such as internal headers set by a proxy
+	Deny []string `exhaustruct:"optional"`
+	// Allow lists the only headers kept in requests. All headers are allowed if empty
+	Allow []string `exhaustruct:"optional"`
+	// Duplicates selects how repeated headers are handled. Defaults to keeping all values
+	Duplicates DuplicateHeaders `exhaustruct:"optional"`
+}
+
+// Sanitize is a middleware that sanitizes the request headers.
+func (h Headers) Sanitize(next http.Handler) http.Handler {
+	deny := canonicalHeaderSet(h.Deny)
+	allow := canonicalHeaderSet(h.Allow)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := make(http.Header, len(r.Header))
+		for name, values := range r.Header {
+			name = http.CanonicalHeaderKey(name)
+			if deny[name] || (len(allow) > 0 && !allow[name]) {
+				continue
+			}
+			header[name] = append(header[name], values...)
+		}
+
+		for name, values := range header {
+			if len(values) < 2 {
+				continue
+			}
+			switch h.Duplicates {
+			case FirstDuplicateHeader:
+				header[name] = values[:1]
+			case LastDuplicateHeader:
+				header[name] = values[len(values)-1:]
+			case JoinDuplicateHeaders:
+				separator := ", "
+				if name == "Cookie" {
+					separator = "; "
+				}
+				header[name] = []string{strings.Join(values, separator)}
+			case RejectDuplicateHeaders:
+				simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
+					http.StatusBadRequest,
+					"duplicate header",
+					nil,
+				).WithDetails("header "+name+" is sent more than once"))
+				return
+			}
+		}
+
+		r = r.WithContext(r.Context())
+		r.Header = header
+		next.ServeHTTP(w, r)
+	})
+}
+
+// canonicalHeaderSet returns the set of canonical header names.
+func canonicalHeaderSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[http.CanonicalHeaderKey(name)] = true
+	}
+	return set
+}
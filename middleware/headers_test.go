@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		headers  middleware.Headers
+		request  http.Header
+		status   int
+		expected http.Header
+	}{
+		{
+			name:     "normalizes casing",
+			headers:  middleware.Headers{},
+			request:  http.Header{"x-tenant-id": {"acme"}, "Accept": {"application/json"}},
+			status:   http.StatusOK,
+			expected: http.Header{"X-Tenant-Id": {"acme"}, "Accept": {"application/json"}},
+		},
+		{
+			name:     "removes denied headers",
+			headers:  middleware.Headers{Deny: []string{"x-request-id"}},
+			request:  http.Header{"X-Request-Id": {"spoofed"}, "Accept": {"application/json"}},
+			status:   http.StatusOK,
+			expected: http.Header{"Accept": {"application/json"}},
+		},
+		{
+			name:     "keeps only allowed headers",
+			headers:  middleware.Headers{Allow: []string{"Accept", "Authorization"}},
+			request:  http.Header{"Accept": {"application/json"}, "Authorization": {"Bearer token"}, "X-Debug": {"1"}},
+			status:   http.StatusOK,
+			expected: http.Header{"Accept": {"application/json"}, "Authorization": {"Bearer token"}},
+		},
+		{
+			name:     "deny takes precedence over allow",
+			headers:  middleware.Headers{Allow: []string{"Accept", "X-Debug"}, Deny: []string{"X-Debug"}},
+			request:  http.Header{"Accept": {"application/json"}, "X-Debug": {"1"}},
+			status:   http.StatusOK,
+			expected: http.Header{"Accept": {"application/json"}},
+		},
+		{
+			name:     "keeps duplicates by default",
+			headers:  middleware.Headers{},
+			request:  http.Header{"X-Tag": {"a", "b"}},
+			status:   http.StatusOK,
+			expected: http.Header{"X-Tag": {"a", "b"}},
+		},
+		{
+			name:     "keeps first duplicate",
+			headers:  middleware.Headers{Duplicates: middleware.FirstDuplicateHeader},
+			request:  http.Header{"X-Tag": {"a", "b"}},
+			status:   http.StatusOK,
+			expected: http.Header{"X-Tag": {"a"}},
+		},
+		{
+			name:     "keeps last duplicate",
+			headers:  middleware.Headers{Duplicates: middleware.LastDuplicateHeader},
+			request:  http.Header{"X-Tag": {"a", "b"}},
+			status:   http.StatusOK,
+			expected: http.Header{"X-Tag": {"b"}},
+		},
+		{
+			name:     "joins duplicates",
+			headers:  middleware.Headers{Duplicates: middleware.JoinDuplicateHeaders},
+			request:  http.Header{"X-Tag": {"a", "b"}, "Cookie": {"a=1", "b=2"}},
+			status:   http.StatusOK,
+			expected: http.Header{"X-Tag": {"a, b"}, "Cookie": {"a=1; b=2"}},
+		},
+		{
+			name:     "rejects duplicates",
+			headers:  middleware.Headers{Duplicates: middleware.RejectDuplicateHeaders},
+			request:  http.Header{"X-Tag": {"a", "b"}},
+			status:   http.StatusBadRequest,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received http.Header
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = tt.request
+			w := httptest.NewRecorder()
+
+			tt.headers.Sanitize(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.expected, received)
+		})
+	}
+}
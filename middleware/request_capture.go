@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+const (
+	defaultCaptureBodySize = 64 << 10
+	redactedValue          = "[REDACTED]"
+)
+
+// alwaysRedactedHeaders are never captured since they carry credentials.
+var alwaysRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// RequestCapture captures sampled requests and the status of their responses into a sink for debugging.
+type RequestCapture struct {
+	Sink    models.CaptureSink
+	Options models.CaptureOptions
+}
+
+// Capture is a middleware that captures the method, URL, headers and body of sampled requests.
+// The captured body is the part of the body read by the handler, up to the maximum captured size.
+// The sink is called after the handler has written the response.
+func (c RequestCapture) Capture(next http.Handler) http.Handler {
+	maxBodySize := c.Options.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultCaptureBodySize
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.Sink == nil || !c.sampled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		headers := c.redactHeaders(r.Header)
+
+		var body *captureBody
+		if r.Body != nil {
+			body = &captureBody{ReadCloser: r.Body, limit: maxBodySize, buf: bytes.Buffer{}, truncated: false}
+			r.Body = body
+		}
+
+		wrapped := wrapResponseWriter(w)
+		next.ServeHTTP(wrapped, r)
+
+		traceID := simbaContext.GetTraceID(r.Context())
+		if traceID == "" {
+			traceID = w.Header().Get(simbaContext.TraceIDHeader)
+		}
+
+		captured := models.CapturedRequest{
+			Time:          start,
+			TraceID:       traceID,
+			Method:        r.Method,
+			URL:           r.URL.String(),
+			Headers:       headers,
+			Body:          nil,
+			Status:        wrapped.Status(),
+			Duration:      time.Since(start),
+			BodyTruncated: false,
+		}
+		if body != nil && body.buf.Len() > 0 {
+			captured.Body = body.buf.Bytes()
+			captured.BodyTruncated = body.truncated
+			if c.Options.RedactBody != nil {
+				captured.Body = c.Options.RedactBody(captured.Body)
+			}
+		}
+
+		// The request context may be cancelled once the response is written, don't let that abort the sink
+		if err := c.Sink.Capture(context.WithoutCancel(r.Context()), captured); err != nil {
+			logging.From(r.Context()).Error("failed to capture request", "error", err)
+		}
+	})
+}
+
+// sampled reports whether the current request should be captured.
+func (c RequestCapture) sampled() bool {
+	rate := c.Options.SampleRate
+	return rate <= 0 || rate >= 1 || rand.Float64() < rate
+}
+
+// redactHeaders returns a copy of the headers with sensitive values replaced.
+func (c RequestCapture) redactHeaders(header http.Header) http.Header {
+	headers := header.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	for _, name := range slices.Concat(alwaysRedactedHeaders, c.Options.RedactHeaders) {
+		if values := headers.Values(name); len(values) > 0 {
+			redacted := make([]string, len(values))
+			for i := range redacted {
+				redacted[i] = redactedValue
+			}
+			headers[http.CanonicalHeaderKey(name)] = redacted
+		}
+	}
+
+	return headers
+}
+
+// captureBody records the bytes read from a request body up to a limit.
+type captureBody struct {
+	io.ReadCloser
+	limit     int64
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		keep := min(int64(n), max(b.limit-int64(b.buf.Len()), 0))
+		b.buf.Write(p[:keep])
+		if keep < int64(n) {
+			b.truncated = true
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,124 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type captureSink struct {
+	mu       sync.Mutex
+	requests []models.CapturedRequest
+	err      error
+}
+
+func (s *captureSink) Capture(_ context.Context, req models.CapturedRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	return s.err
+}
+
+func TestRequestCapture(t *testing.T) {
+	t.Parallel()
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	})
+
+	t.Run("captures request and status", func(t *testing.T) {
+		t.Parallel()
+
+		sink := &captureSink{}
+		capture := middleware.RequestCapture{
+			Sink:    sink,
+			Options: models.CaptureOptions{RedactHeaders: []string{"X-Api-Key"}},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/users?active=true", strings.NewReader(`{"name":"John"}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("X-Api-Key", "secret")
+		req.Header.Set("X-Request-Source", "test")
+		w := httptest.NewRecorder()
+
+		capture.Capture(echo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, `{"name":"John"}`, w.Body.String())
+
+		assert.Len(t, sink.requests, 1)
+		captured := sink.requests[0]
+		assert.Equal(t, http.MethodPost, captured.Method)
+		assert.Equal(t, "/users?active=true", captured.URL)
+		assert.Equal(t, http.StatusCreated, captured.Status)
+		assert.Equal(t, `{"name":"John"}`, string(captured.Body))
+		assert.False(t, captured.BodyTruncated)
+		assert.Equal(t, "[REDACTED]", captured.Headers.Get("Authorization"))
+		assert.Equal(t, "[REDACTED]", captured.Headers.Get("X-Api-Key"))
+		assert.Equal(t, "test", captured.Headers.Get("X-Request-Source"))
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+	})
+
+	t.Run("truncates and redacts body", func(t *testing.T) {
+		t.Parallel()
+
+		sink := &captureSink{}
+		capture := middleware.RequestCapture{
+			Sink: sink,
+			Options: models.CaptureOptions{
+				MaxBodySize: 4,
+				RedactBody: func(body []byte) []byte {
+					return []byte(strings.ToUpper(string(body)))
+				},
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("abcdefgh"))
+		w := httptest.NewRecorder()
+
+		capture.Capture(echo).ServeHTTP(w, req)
+
+		assert.Equal(t, "abcdefgh", w.Body.String())
+		assert.Equal(t, "ABCD", string(sink.requests[0].Body))
+		assert.True(t, sink.requests[0].BodyTruncated)
+	})
+
+	t.Run("samples requests", func(t *testing.T) {
+		t.Parallel()
+
+		sink := &captureSink{}
+		capture := middleware.RequestCapture{Sink: sink, Options: models.CaptureOptions{SampleRate: 0.5}}
+
+		for range 1000 {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			capture.Capture(echo).ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		assert.True(t, len(sink.requests) > 350 && len(sink.requests) < 650)
+	})
+
+	t.Run("sink errors don't affect the response", func(t *testing.T) {
+		t.Parallel()
+
+		sink := &captureSink{err: errors.New("disk full")}
+		capture := middleware.RequestCapture{Sink: sink, Options: models.CaptureOptions{}}
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+		capture.Capture(echo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Len(t, sink.requests, 1)
+	})
+}
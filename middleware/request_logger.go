@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/simbaContext"
 )
 
 var (
@@ -35,6 +36,19 @@ func LogRequests(next http.Handler) http.Handler {
 		// Get duration
 		duration := roundDuration(time.Since(start))
 
+		// Requests abandoned before a response was written have no meaningful status, log them at a low level
+		if !wrapped.wroteHeader && simbaContext.IsClientCancelled(r.Context()) {
+			logging.From(r.Context()).Debug("request cancelled by client",
+				"reason", simbaContext.ClientCancelledReason,
+				"remoteIp", r.RemoteAddr,
+				"clientIp", simbaContext.GetClientIP(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"duration (ms)", duration,
+			)
+			return
+		}
+
 		// Log request details after processing
 		logLevel := slog.LevelInfo // Default log level
 		if level, ok := pathLogLevels[r.URL.Path]; ok {
@@ -44,6 +58,7 @@ func LogRequests(next http.Handler) http.Handler {
 		logging.From(r.Context()).
 			Log(r.Context(), logLevel, "request processed",
 				"remoteIp", r.RemoteAddr,
+				"clientIp", simbaContext.GetClientIP(r.Context()),
 				"userAgent", r.UserAgent(),
 				"method", r.Method,
 				"path", r.URL.Path,
@@ -106,6 +121,7 @@ func (rw *responseWriter) Status() int {
 }
 
 func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true // Writing the body writes the header with the default status
 	n, err := rw.ResponseWriter.Write(b)
 	rw.written += int64(n)
 	return n, err
@@ -118,3 +134,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 		rw.ResponseWriter.WriteHeader(code)
 	}
 }
+
+// Push implements http.Pusher if the underlying ResponseWriter does.
+func (rw *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := rw.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
@@ -1,11 +1,13 @@
 package middleware_test
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,4 +40,28 @@ func TestLogRequests(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, `{"message":"success"}`, w.Body.String())
 	})
+
+	t.Run("logs requests cancelled after the response as processed", func(t *testing.T) {
+		t.Parallel()
+
+		serve := func(handler http.HandlerFunc) string {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), simbaContext.LoggerKey, logger))
+			defer cancel()
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", nil)
+
+			middleware.LogRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handler(w, r)
+				cancel() // The client goes away once the handler is done
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			return buf.String()
+		}
+
+		assert.True(t, strings.Contains(serve(func(w http.ResponseWriter, r *http.Request) {}), "request cancelled by client"))
+		assert.True(t, strings.Contains(serve(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("done"))
+		}), "request processed"))
+	})
 }
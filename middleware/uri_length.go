@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// URILength rejects requests whose URI or query string is longer than the configured limits
+// with a 414 URI Too Long. A zero limit disables the check.
+type URILength struct {
+	// MaxURILength is the maximum length of the request URI (path and query) in bytes
+	MaxURILength int `exhaustruct:"optional"`
+	// MaxQueryLength is the maximum length of the raw query string in bytes
+	MaxQueryLength int `exhaustruct:"optional"`
+}
+
+// Limit is a middleware that enforces the URI and query string length limits.
+func (l URILength) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri := r.RequestURI
+		if uri == "" {
+			uri = r.URL.RequestURI()
+		}
+
+		switch {
+		case l.MaxURILength > 0 && len(uri) > l.MaxURILength:
+			simbaErrors.WriteError(w, r, newURITooLongError("URI", l.MaxURILength))
+			return
+		case l.MaxQueryLength > 0 && len(r.URL.RawQuery) > l.MaxQueryLength:
+			simbaErrors.WriteError(w, r, newURITooLongError("query string", l.MaxQueryLength))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newURITooLongError(part string, limit int) *simbaErrors.SimbaError {
+	return simbaErrors.NewSimbaError(
+		http.StatusRequestURITooLong,
+		"URI too long",
+		nil,
+	).WithDetails(part + " exceeds the limit of " + strconv.Itoa(limit) + " bytes")
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba/middleware"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestURILength(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		limits   middleware.URILength
+		target   string
+		expected int
+	}{
+		{
+			name:     "within limits",
+			limits:   middleware.URILength{MaxURILength: 20, MaxQueryLength: 5},
+			target:   "/users?a=1",
+			expected: http.StatusOK,
+		},
+		{
+			name:     "uri too long",
+			limits:   middleware.URILength{MaxURILength: 10},
+			target:   "/users/" + strings.Repeat("a", 10),
+			expected: http.StatusRequestURITooLong,
+		},
+		{
+			name:     "query too long",
+			limits:   middleware.URILength{MaxQueryLength: 5},
+			target:   "/users?name=john",
+			expected: http.StatusRequestURITooLong,
+		},
+		{
+			name:     "no limits",
+			limits:   middleware.URILength{},
+			target:   "/users?name=" + strings.Repeat("a", 1000),
+			expected: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			tt.limits.Limit(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusRequestURITooLong {
+				assert.True(t, strings.Contains(w.Body.String(), "URI too long"))
+			}
+		})
+	}
+}
@@ -1,15 +1,32 @@
 package mimetypes
 
+import (
+	"mime"
+	"path/filepath"
+)
+
 const (
 	ApplicationJSON        = "application/json"
 	ApplicationJSONPatch   = "application/json-patch+json"
 	ApplicationJSONMerge   = "application/merge-patch+json"
+	ApplicationNDJSON      = "application/x-ndjson"
+	ApplicationProblemJSON = "application/problem+json"
 	ApplicationYAML        = "application/yaml"
 	ApplicationXML         = "application/xml"
 	ApplicationForm        = "application/x-www-form-urlencoded"
 	MultipartForm          = "multipart/form-data"
+	MultipartMixed         = "multipart/mixed"
 	TextPlain              = "text/plain"
 	TextHTML               = "text/html"
 	TextCSV                = "text/csv"
 	ApplicationOctetStream = "application/octet-stream"
 )
+
+// FromFilename returns the media type for the extension of filename,
+// or ApplicationOctetStream if the extension is unknown.
+func FromFilename(filename string) string {
+	if mediaType := mime.TypeByExtension(filepath.Ext(filename)); mediaType != "" {
+		return mediaType
+	}
+	return ApplicationOctetStream
+}
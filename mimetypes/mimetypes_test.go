@@ -0,0 +1,28 @@
+package mimetypes_test
+
+import (
+	"testing"
+
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestFromFilename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{filename: "report.pdf", expected: "application/pdf"},
+		{filename: "data.JSON", expected: mimetypes.ApplicationJSON},
+		{filename: "archive.unknownext", expected: mimetypes.ApplicationOctetStream},
+		{filename: "no-extension", expected: mimetypes.ApplicationOctetStream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mimetypes.FromFilename(tt.filename))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package mimetypes
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// acceptedType is a single media range parsed from an Accept header.
+type acceptedType struct {
+	mediaType string
+	quality   float64
+}
+
+// Negotiate selects the best media type from offered based on the given Accept header value.
+// Offers are matched against the media ranges in the Accept header in order of preference,
+// taking quality values and wildcards (e.g. "text/*" and "*/*") into account.
+// Returns an empty string if none of the offered media types are acceptable.
+// If the Accept header is empty, the first offered media type is returned.
+func Negotiate(accept string, offered []string) string {
+	if len(offered) == 0 {
+		return ""
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offered[0]
+	}
+
+	for _, accepted := range parseAccept(accept) {
+		if accepted.quality <= 0 {
+			continue
+		}
+
+		for _, offer := range offered {
+			if matchesMediaRange(accepted.mediaType, offer) {
+				return offer
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseAccept parses an Accept header into media ranges ordered by quality.
+func parseAccept(accept string) []acceptedType {
+	parts := strings.Split(accept, ",")
+	accepted := make([]acceptedType, 0, len(parts))
+
+	for _, part := range parts {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, parseErr := strconv.ParseFloat(q, 64); parseErr == nil {
+				quality = parsed
+			}
+		}
+
+		accepted = append(accepted, acceptedType{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+
+	return accepted
+}
+
+// matchesMediaRange reports whether the offered media type falls within the accepted media range.
+func matchesMediaRange(mediaRange string, offer string) bool {
+	offerType, _, err := mime.ParseMediaType(offer)
+	if err != nil {
+		return false
+	}
+
+	if mediaRange == "*/*" || mediaRange == offerType {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(offerType, prefix+"/")
+	}
+
+	return false
+}
@@ -0,0 +1,35 @@
+package mimetypes_test
+
+import (
+	"testing"
+
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	offered := []string{mimetypes.ApplicationJSON, mimetypes.TextPlain, mimetypes.ApplicationXML}
+
+	tests := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "empty accept header returns first offer", accept: "", expected: mimetypes.ApplicationJSON},
+		{name: "exact match", accept: "text/plain", expected: mimetypes.TextPlain},
+		{name: "wildcard returns first offer", accept: "*/*", expected: mimetypes.ApplicationJSON},
+		{name: "type wildcard", accept: "text/*", expected: mimetypes.TextPlain},
+		{name: "highest quality wins", accept: "application/json;q=0.2, application/xml;q=0.8", expected: mimetypes.ApplicationXML},
+		{name: "zero quality is not acceptable", accept: "text/plain;q=0", expected: ""},
+		{name: "no acceptable offer", accept: "image/png", expected: ""},
+		{name: "ignores malformed media ranges", accept: "invalid;;, text/plain", expected: mimetypes.TextPlain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mimetypes.Negotiate(tt.accept, offered))
+		})
+	}
+}
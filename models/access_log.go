@@ -0,0 +1,71 @@
+package models
+
+// AccessLogField is a field of the access log line written for every request.
+type AccessLogField string
+
+const (
+	// AccessLogMethod is the HTTP method of the request.
+	AccessLogMethod AccessLogField = "method"
+	// AccessLogPath is the path template of the matched route, such as /users/{id}, or the
+	// request path if no route matched.
+	AccessLogPath AccessLogField = "path"
+	// AccessLogStatus is the status of the response.
+	AccessLogStatus AccessLogField = "status"
+	// AccessLogStatusClass is the class of the response status, such as 2xx.
+	AccessLogStatusClass AccessLogField = "statusClass"
+	// AccessLogBytes is the number of bytes written in the response body.
+	AccessLogBytes AccessLogField = "bytes"
+	// AccessLogLatency is the latency of the request in milliseconds.
+	AccessLogLatency AccessLogField = "latencyMs"
+	// AccessLogLatencyBucket is the upper bound of the latency bucket of the request, such as 250ms.
+	AccessLogLatencyBucket AccessLogField = "latencyBucket"
+	// AccessLogClientIP is the client IP of the request.
+	AccessLogClientIP AccessLogField = "clientIp"
+	// AccessLogRemoteIP is the address of the connection.
+	AccessLogRemoteIP AccessLogField = "remoteIp"
+	// AccessLogPrincipal is the authenticated principal of the request, if any.
+	AccessLogPrincipal AccessLogField = "principal"
+	// AccessLogUserAgent is the user agent of the request.
+	AccessLogUserAgent AccessLogField = "userAgent"
+	// AccessLogProtocol is the protocol of the request, such as HTTP/1.1.
+	AccessLogProtocol AccessLogField = "protocol"
+	// AccessLogHost is the host of the request.
+	AccessLogHost AccessLogField = "host"
+	// AccessLogReferer is the referer of the request.
+	AccessLogReferer AccessLogField = "referer"
+)
+
+// DefaultAccessLogFields are the fields of the access log line if none are selected.
+var DefaultAccessLogFields = []AccessLogField{
+	AccessLogMethod,
+	AccessLogPath,
+	AccessLogStatus,
+	AccessLogStatusClass,
+	AccessLogBytes,
+	AccessLogLatency,
+	AccessLogLatencyBucket,
+	AccessLogClientIP,
+	AccessLogPrincipal,
+}
+
+// AccessLogFields are all the supported access log fields.
+var AccessLogFields = []AccessLogField{
+	AccessLogMethod,
+	AccessLogPath,
+	AccessLogStatus,
+	AccessLogStatusClass,
+	AccessLogBytes,
+	AccessLogLatency,
+	AccessLogLatencyBucket,
+	AccessLogClientIP,
+	AccessLogRemoteIP,
+	AccessLogPrincipal,
+	AccessLogUserAgent,
+	AccessLogProtocol,
+	AccessLogHost,
+	AccessLogReferer,
+}
+
+func (f AccessLogField) String() string {
+	return string(f)
+}
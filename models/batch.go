@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BatchRequest is a single sub-request of a batch request.
+// Headers are added to the headers of the batch request, replacing headers with the same name.
+type BatchRequest struct {
+	ID      string            `json:"id,omitempty" description:"Optional ID echoed in the sub-response" exhaustruct:"optional"`
+	Method  string            `json:"method" description:"HTTP method of the sub-request" example:"GET" validate:"required"`
+	Path    string            `json:"path" description:"Path and query of the sub-request" example:"/users/1" validate:"required"`
+	Headers map[string]string `json:"headers,omitempty" description:"Headers of the sub-request" exhaustruct:"optional"`
+	Body    json.RawMessage   `json:"body,omitempty" description:"JSON body of the sub-request" exhaustruct:"optional"`
+}
+
+// BatchResponse is the response to a single sub-request of a batch request.
+// Body holds the response body as JSON, or as a JSON string if the body is not valid JSON.
+type BatchResponse struct {
+	ID      string          `json:"id,omitempty" description:"ID of the sub-request" exhaustruct:"optional"`
+	Status  int             `json:"status" description:"HTTP status of the sub-response" example:"200"`
+	Headers http.Header     `json:"headers,omitempty" description:"Headers of the sub-response" exhaustruct:"optional"`
+	Body    json.RawMessage `json:"body,omitempty" description:"Body of the sub-response" exhaustruct:"optional"`
+}
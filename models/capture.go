@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CapturedRequest is a request captured for debugging together with the status of its response.
+type CapturedRequest struct {
+	Time     time.Time
+	TraceID  string
+	Method   string
+	URL      string
+	Headers  http.Header
+	Body     []byte
+	Status   int
+	Duration time.Duration
+
+	// BodyTruncated is set if the body was larger than the maximum captured size
+	BodyTruncated bool
+}
+
+// CaptureSink receives captured requests, e.g. to write them to a file or a store.
+// Capture is called after the response has been written and must be safe for concurrent use.
+type CaptureSink interface {
+	Capture(ctx context.Context, req CapturedRequest) error
+}
+
+// CaptureOptions configures sampling and redaction of captured requests.
+type CaptureOptions struct {
+
+	// SampleRate is the fraction of requests captured, from 0.0 to 1.0. Zero captures every request
+	SampleRate float64 `exhaustruct:"optional"`
+
+	// RedactHeaders are headers whose values are replaced in captured requests, in addition to
+	// Authorization, Proxy-Authorization and Cookie which are always redacted
+	RedactHeaders []string `exhaustruct:"optional"`
+
+	// RedactBody rewrites the captured body, e.g. to mask sensitive fields. The body is captured as is if nil
+	RedactBody func(body []byte) []byte `exhaustruct:"optional"`
+
+	// MaxBodySize is the maximum number of body bytes captured. Zero means 64 KiB
+	MaxBodySize int64 `exhaustruct:"optional"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// ClientIPHeader is the header used to determine the client IP of requests from trusted proxies.
+type ClientIPHeader string
+
+const (
+	XForwardedFor ClientIPHeader = "X-Forwarded-For"
+	XRealIP       ClientIPHeader = "X-Real-IP"
+	Forwarded     ClientIPHeader = "Forwarded"
+)
+
+func (h ClientIPHeader) String() string {
+	return string(h)
+}
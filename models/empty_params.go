@@ -0,0 +1,19 @@
+package models
+
+// EmptyParams determines how parameters that are sent with an empty value, such as ?name= or an
+// empty header, are bound.
+type EmptyParams string
+
+const (
+	// EmptyParamsAsMissing treats empty parameters as if they were not sent. Default values are
+	// applied and required parameters fail validation.
+	EmptyParamsAsMissing EmptyParams = "Missing"
+	// EmptyParamsAsPresent treats empty parameters as sent with the zero value. Default values are
+	// not applied, and required pointer parameters pass validation while required non-pointer
+	// parameters still fail, following the validator's required semantics.
+	EmptyParamsAsPresent EmptyParams = "Present"
+)
+
+func (e EmptyParams) String() string {
+	return string(e)
+}
@@ -0,0 +1,12 @@
+package models
+
+type ErrorFormat string
+
+const (
+	DefaultErrorFormat ErrorFormat = "Default"
+	ProblemDetails     ErrorFormat = "ProblemDetails"
+)
+
+func (f ErrorFormat) String() string {
+	return string(f)
+}
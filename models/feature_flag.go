@@ -0,0 +1,20 @@
+package models
+
+import "net/http"
+
+// FeatureFlagProvider decides whether a feature flag is enabled, e.g. backed by a flag service or configuration.
+// Implementations must be safe for concurrent use.
+type FeatureFlagProvider interface {
+
+	// Enabled reports whether the flag is enabled for the request. The request can be used to roll out
+	// gradually, e.g. by a header or a principal set by router middleware
+	Enabled(r *http.Request, flag string) (bool, error)
+}
+
+// FeatureFlagFunc adapts a function to a [FeatureFlagProvider].
+type FeatureFlagFunc func(r *http.Request, flag string) (bool, error)
+
+// Enabled calls f(r, flag).
+func (f FeatureFlagFunc) Enabled(r *http.Request, flag string) (bool, error) {
+	return f(r, flag)
+}
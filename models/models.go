@@ -1,8 +1,10 @@
 package models
 
 import (
+	"io"
 	"mime/multipart"
 	"net/http"
+	"time"
 )
 
 // Request represents a HTTP Request.
@@ -22,6 +24,41 @@ type Response[ResponseBody any] struct {
 	Cookies []*http.Cookie `exhaustruct:"optional"`
 	Body    ResponseBody   `exhaustruct:"optional"`
 	Status  int            `exhaustruct:"optional"`
+	Preload []Preload      `exhaustruct:"optional"`
+}
+
+// Accepted returns a 202 Accepted response for an operation accepted for background processing.
+// The Location header points to the status resource clients can poll for the result, and the body,
+// such as a description of the job, is written as the response body. Use NoBody to send no body.
+//
+//	Example usage:
+//
+//	return models.Accepted("/jobs/"+job.ID, job), nil
+func Accepted[ResponseBody any](statusURL string, body ResponseBody) *Response[ResponseBody] {
+	return &Response[ResponseBody]{
+		Headers: http.Header{"Location": {statusURL}},
+		Body:    body,
+		Status:  http.StatusAccepted,
+	}
+}
+
+// File is a response body that sends a file download to the client.
+// The Content-Type is inferred from the extension of Filename unless ContentType is set,
+// falling back to application/octet-stream, and Content-Disposition is set to attachment
+// (or inline if Inline is set). Content is closed after writing if it implements io.Closer.
+//
+// If Content implements io.ReadSeeker, such as an *os.File, and the response has a 200 status, the length is
+// sent as Content-Length and Range requests are answered with 206 Partial Content, so clients can show progress
+// and resume downloads. ModTime, if set, is sent as Last-Modified and used with an ETag response header to
+// answer conditional requests. Otherwise Size, if set, is sent as Content-Length and a request for a single
+// byte range is answered by skipping Content up to the range, with If-Range checked against ModTime or the ETag.
+type File struct {
+	Filename    string
+	Content     io.Reader
+	ContentType string    `exhaustruct:"optional"`
+	Inline      bool      `exhaustruct:"optional"`
+	Size        int64     `exhaustruct:"optional"`
+	ModTime     time.Time `exhaustruct:"optional"`
 }
 
 // NoBody is an empty struct used to represent no body.
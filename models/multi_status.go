@@ -0,0 +1,54 @@
+package models
+
+import "net/http"
+
+// MultiStatusBody is the body of a 207 Multi-Status response, holding the outcome of each item of a bulk
+// operation in the order of the request.
+type MultiStatusBody[Result any] struct {
+	Results []ItemResult[Result] `json:"results" required:"true" nullable:"false" description:"Outcome of each item in the order of the request"`
+}
+
+// ItemResult is the outcome of a single item of a bulk operation. Status is the HTTP status of the item,
+// as if it was handled in a request of its own. Result is set for items that succeeded and Error for items that failed.
+type ItemResult[Result any] struct {
+	Index  int     `json:"index" required:"true" description:"Position of the item in the request" example:"0"`
+	Status int     `json:"status" required:"true" description:"HTTP status of the item" example:"201"`
+	Result *Result `json:"result,omitempty" description:"Result of the item if it succeeded" exhaustruct:"optional"`
+	Error  string  `json:"error,omitempty" description:"Reason the item failed" exhaustruct:"optional"`
+}
+
+// ItemSucceeded returns the result of an item of a bulk operation that succeeded with the given status.
+func ItemSucceeded[Result any](index int, status int, result Result) ItemResult[Result] {
+	return ItemResult[Result]{Index: index, Status: status, Result: &result}
+}
+
+// ItemFailed returns the result of an item of a bulk operation that failed with the given status.
+// The message is sent to the client, so it shouldn't reveal internal details.
+func ItemFailed[Result any](index int, status int, message string) ItemResult[Result] {
+	return ItemResult[Result]{Index: index, Status: status, Error: message}
+}
+
+// MultiStatus returns a 207 Multi-Status response for a bulk operation where items can succeed or
+// fail independently, with the outcome of each item in the body.
+//
+//	Example usage:
+//
+//	results := make([]models.ItemResult[User], 0, len(req.Body))
+//	for i, item := range req.Body {
+//		user, err := createUser(ctx, item)
+//		if err != nil {
+//			results = append(results, models.ItemFailed[User](i, http.StatusConflict, "user already exists"))
+//			continue
+//		}
+//		results = append(results, models.ItemSucceeded(i, http.StatusCreated, user))
+//	}
+//	return models.MultiStatus(results), nil
+func MultiStatus[Result any](results []ItemResult[Result]) *Response[MultiStatusBody[Result]] {
+	if results == nil {
+		results = []ItemResult[Result]{}
+	}
+	return &Response[MultiStatusBody[Result]]{
+		Body:   MultiStatusBody[Result]{Results: results},
+		Status: http.StatusMultiStatus,
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"io"
+	"net/textproto"
+)
+
+// Multipart is a response body streamed to the client as a multipart/mixed response, combining parts with their
+// own Content-Type, such as JSON metadata and binary content, without embedding the binary content in JSON.
+// The boundary and Content-Type header are set automatically and each part is flushed to the client once written.
+//
+// An error returned by Parts before any part was written is written as a regular error response; once streaming
+// has started the error is logged and the response is ended without the closing boundary, so clients can tell
+// the response is incomplete.
+type Multipart struct {
+	Parts func(parts PartWriter) error
+}
+
+// PartWriter writes the parts of a [Multipart] response.
+type PartWriter interface {
+
+	// Part writes a part with the content type and the content.
+	// Content is closed after writing if it implements io.Closer.
+	Part(contentType string, content io.Reader) error
+
+	// PartWithHeader writes a part with the header, such as Content-Type and Content-Disposition.
+	// Content is closed after writing if it implements io.Closer.
+	PartWithHeader(header textproto.MIMEHeader, content io.Reader) error
+
+	// JSON writes the value as an application/json part.
+	JSON(value any) error
+
+	// File writes a part with the Content-Type and Content-Disposition set like a [File] response body.
+	File(file File) error
+}
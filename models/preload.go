@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preload is a resource the client should fetch early, such as the script or stylesheet of a server-rendered
+// page. It is sent as a Link: rel=preload header and, when Push is set and the connection supports it,
+// pushed to the client with HTTP/2 server push.
+type Preload struct {
+	// URL of the resource, usually a path on the same origin
+	URL string
+	// As is the type of content of the resource, e.g. "script", "style", "font" or "image"
+	As string
+	// Type is the media type of the resource, which lets the client skip types it doesn't support
+	Type string `exhaustruct:"optional"`
+	// CrossOrigin fetches the resource in CORS mode, which is required for fonts
+	CrossOrigin bool `exhaustruct:"optional"`
+	// Push pushes the resource with HTTP/2 server push where supported
+	Push bool `exhaustruct:"optional"`
+}
+
+// LinkHeader returns the value of the Link header for the resource, e.g. </app.js>; rel=preload; as=script.
+func (p Preload) LinkHeader() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "<%s>; rel=preload", p.URL)
+	if p.As != "" {
+		_, _ = fmt.Fprintf(&b, "; as=%s", p.As)
+	}
+	if p.Type != "" {
+		_, _ = fmt.Fprintf(&b, "; type=%q", p.Type)
+	}
+	if p.CrossOrigin {
+		b.WriteString("; crossorigin")
+	}
+	return b.String()
+}
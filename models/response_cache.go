@@ -0,0 +1,26 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CachedResponse is a response stored in a [ResponseCacheStore].
+type CachedResponse struct {
+	Status  int
+	Headers http.Header
+	Body    []byte
+}
+
+// ResponseCacheStore stores cached responses, e.g. in memory or in Redis.
+// Implementations must be safe for concurrent use.
+type ResponseCacheStore interface {
+
+	// Get returns the response stored under the key. The second return value is false
+	// if there's no response stored or it has expired
+	Get(ctx context.Context, key string) (CachedResponse, bool, error)
+
+	// Set stores the response under the key for the given time to live
+	Set(ctx context.Context, key string, response CachedResponse, ttl time.Duration) error
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseHeader is a response header computed from the result of a handler, such as the total number of items
+// of a paginated list. Add it to a Response with [Response.SetHeaders], or to a streamed response with
+// simba.SetHeaders before the first record is emitted.
+type ResponseHeader interface {
+	Apply(header http.Header)
+}
+
+// ResponseHeaderFunc is a function that implements ResponseHeader, for headers without a dedicated type.
+type ResponseHeaderFunc func(header http.Header)
+
+func (f ResponseHeaderFunc) Apply(header http.Header) {
+	f(header)
+}
+
+// TotalCountHeader is the header TotalCount is sent as.
+const TotalCountHeader = "X-Total-Count"
+
+// TotalCount is the total number of items of a list the response holds a page of, sent as X-Total-Count.
+type TotalCount int64
+
+func (c TotalCount) Apply(header http.Header) {
+	header.Set(TotalCountHeader, strconv.FormatInt(int64(c), 10))
+}
+
+// RateLimit is the rate limit status of the client, sent as the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers. Reset is sent in seconds, rounded up.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is the time until the current window ends
+	Reset time.Duration
+}
+
+func (l RateLimit) Apply(header http.Header) {
+	header.Set("RateLimit-Limit", strconv.Itoa(l.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(max(l.Remaining, 0)))
+	header.Set("RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(max(l.Reset, 0).Seconds())), 10))
+}
+
+// SetHeaders applies the headers to the response, replacing headers with the same name. It returns the
+// response so it can be used when returning from a handler.
+//
+//	Example usage:
+//
+//	return (&models.Response[[]User]{Body: page}).SetHeaders(models.TotalCount(total)), nil
+func (r *Response[ResponseBody]) SetHeaders(headers ...ResponseHeader) *Response[ResponseBody] {
+	if r.Headers == nil {
+		r.Headers = http.Header{}
+	}
+	for _, header := range headers {
+		header.Apply(r.Headers)
+	}
+	return r
+}
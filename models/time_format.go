@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// TimeFormat is the format time.Time values are written in in JSON responses.
+// Values other than the predefined formats are used as a time layout, such as "2006-01-02".
+type TimeFormat string
+
+const (
+	// RFC3339TimeFormat writes times as RFC 3339 strings, the encoding/json default.
+	RFC3339TimeFormat TimeFormat = "RFC3339"
+	// UnixTimeFormat writes times as the number of seconds since the Unix epoch.
+	UnixTimeFormat TimeFormat = "Unix"
+	// UnixMilliTimeFormat writes times as the number of milliseconds since the Unix epoch.
+	UnixMilliTimeFormat TimeFormat = "UnixMilli"
+)
+
+func (f TimeFormat) String() string {
+	return string(f)
+}
+
+// IsDefault reports whether times are written in the encoding/json default format.
+func (f TimeFormat) IsDefault() bool {
+	return f == "" || f == RFC3339TimeFormat
+}
+
+// FormatJSON returns the time encoded as JSON in the format.
+func (f TimeFormat) FormatJSON(t time.Time) ([]byte, error) {
+	switch f {
+	case "", RFC3339TimeFormat:
+		return t.MarshalJSON()
+	case UnixTimeFormat:
+		return strconv.AppendInt(nil, t.Unix(), 10), nil
+	case UnixMilliTimeFormat:
+		return strconv.AppendInt(nil, t.UnixMilli(), 10), nil
+	default:
+		return json.Marshal(t.Format(string(f)))
+	}
+}
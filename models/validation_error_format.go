@@ -0,0 +1,18 @@
+package models
+
+type ValidationErrorFormat string
+
+const (
+	// DefaultValidationErrorFormat writes validation errors as a list of fields with their error message.
+	DefaultValidationErrorFormat ValidationErrorFormat = "Default"
+	// StructuredValidationErrorFormat writes validation errors as a list of fields with an error code and message.
+	StructuredValidationErrorFormat ValidationErrorFormat = "Structured"
+	// PointerValidationErrorFormat writes validation errors as a list of JSON Pointers (RFC 6901) to the invalid
+	// values in the request body, or the invalid params, with an error code and detail, with the status 422
+	// Unprocessable Entity instead of 400 Bad Request.
+	PointerValidationErrorFormat ValidationErrorFormat = "Pointer"
+)
+
+func (f ValidationErrorFormat) String() string {
+	return string(f)
+}
@@ -68,13 +68,17 @@ func MultipartHandler[Params any, ResponseBody any](h MultipartHandlerFunc[Param
 func (h MultipartHandlerFunc[Params, ResponseBody]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	req, err := handleMultipartRequest[Params](r)
+	req, limits, err := handleMultipartRequest[Params](r)
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
 		return
 	}
 
 	resp, err := h(ctx, req)
+	if limits.err != nil {
+		// The handler may not return the error of the reader once a limit is exceeded
+		err = limits.err
+	}
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
 		return
@@ -103,7 +107,7 @@ func (h MultipartHandlerFunc[Params, ResponseBody]) GetAccepts() string {
 }
 
 func (h MultipartHandlerFunc[Params, ResponseBody]) GetProduces() string {
-	return mimetypes.ApplicationJSON
+	return responseMediaType[ResponseBody]()
 }
 
 func (h MultipartHandlerFunc[Params, ResponseBody]) GetHandler() any {
@@ -198,13 +202,20 @@ func (h AuthenticatedMultipartHandlerFunc[Params, AuthModel, ResponseBody]) Serv
 		return
 	}
 
-	req, err := handleMultipartRequest[Params](r)
+	r = withPrincipal(r, authModel)
+	ctx = r.Context()
+
+	req, limits, err := handleMultipartRequest[Params](r)
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
 		return
 	}
 
 	resp, err := h.handler(ctx, req, authModel)
+	if limits.err != nil {
+		// The handler may not return the error of the reader once a limit is exceeded
+		err = limits.err
+	}
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
 		return
@@ -233,7 +244,7 @@ func (h AuthenticatedMultipartHandlerFunc[Params, AuthModel, ResponseBody]) GetA
 }
 
 func (h AuthenticatedMultipartHandlerFunc[Params, AuthModel, ResponseBody]) GetProduces() string {
-	return mimetypes.ApplicationJSON
+	return responseMediaType[ResponseBody]()
 }
 
 func (h AuthenticatedMultipartHandlerFunc[Params, AuthModel, ResponseBody]) GetHandler() any {
@@ -250,33 +261,38 @@ func (h AuthenticatedMultipartHandlerFunc[Params, AuthModel, ResponseBody]) GetA
 }
 
 // handleMultipartRequest handles extracting the [multipart.Reader] and params from the MultiPart Request.
-func handleMultipartRequest[Params any](r *http.Request) (*models.MultipartRequest[Params], error) {
+// The reader enforces the limits on the files of the request, which the returned multipartLimitReader reports
+// once exceeded.
+func handleMultipartRequest[Params any](r *http.Request) (*models.MultipartRequest[Params], *multipartLimitReader, error) {
 
 	contentType := r.Header.Get("Content-Type")
 	if contentType == "" || !strings.HasPrefix(contentType, "multipart/form-data") {
-		return nil, simbaErrors.ErrInvalidContentType
+		return nil, nil, simbaErrors.ErrInvalidContentType
 	}
 
 	reqParams, err := ParseAndValidateParams[Params](r)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if _, mediaParams, mediaErr := mime.ParseMediaType(contentType); mediaErr != nil || mediaParams["boundary"] == "" {
+	_, mediaParams, mediaErr := mime.ParseMediaType(contentType)
+	if mediaErr != nil || mediaParams["boundary"] == "" {
 		e := simbaErrors.ErrInvalidContentType
 		if mediaErr != nil {
 			e = e.WithDetails(mediaErr.Error())
 		}
-		return nil, e
+		return nil, nil, e
 	}
 
-	multipartReader, err := r.MultipartReader()
-	if err != nil {
-		return nil, simbaErrors.ErrInvalidRequest.WithDetails(err.Error())
+	boundary := mediaParams["boundary"]
+	requestSettings := getConfigurationFromContext(r.Context())
+	limits := newMultipartLimitReader(r.Body, boundary, requestSettings.MaxMultipartFiles, requestSettings.MaxMultipartSize)
+	if limits.enabled() && strings.ContainsAny(boundary, "\r\n") {
+		return nil, nil, simbaErrors.ErrInvalidContentType.WithDetails("invalid multipart boundary")
 	}
 
 	return &models.MultipartRequest[Params]{
-		Reader: multipartReader,
+		Reader: multipart.NewReader(limits, boundary),
 		Params: reqParams,
-	}, nil
+	}, limits, nil
 }
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -327,3 +329,284 @@ func TestAuthenticatedMultipartHandler(t *testing.T) {
 		assert.Equal(t, "forbidden", errorResponse.Message)
 	})
 }
+
+func TestMultipartHandlerLimits(t *testing.T) {
+	t.Parallel()
+
+	const boundary = "limits-boundary"
+
+	// part is a part of a multipart body, a file if it has a filename
+	type part struct {
+		name     string
+		filename string
+		content  string
+	}
+
+	newBody := func(parts ...part) *bytes.Buffer {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.SetBoundary(boundary)
+		for _, p := range parts {
+			var w io.Writer
+			if p.filename != "" {
+				w, _ = writer.CreateFormFile(p.name, p.filename)
+			} else {
+				w, _ = writer.CreateFormField(p.name)
+			}
+			_, _ = io.WriteString(w, p.content)
+		}
+		_ = writer.Close()
+		return body
+	}
+
+	// readParts reads all the parts, responding with the number of bytes read
+	readParts := func(ctx context.Context, req *models.MultipartRequest[models.NoParams]) (*models.Response[map[string]int64], error) {
+		var total int64
+		for {
+			p, err := req.Reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return &models.Response[map[string]int64]{Body: map[string]int64{"bytes": total}}, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			n, err := io.Copy(io.Discard, p)
+			if err != nil {
+				return nil, err
+			}
+			total += n
+		}
+	}
+
+	tests := []struct {
+		name      string
+		options   []simba.RouteOption
+		settings  []settings.Option
+		body      io.Reader
+		status    int
+		tooLarge  bool
+		tooMany   bool
+		bytesRead int64
+	}{
+		{
+			name:      "within the limits",
+			options:   []simba.RouteOption{simba.WithMultipartLimits(2, 10)},
+			body:      newBody(part{"a", "a.txt", "12345"}, part{"b", "b.txt", "12345"}),
+			status:    http.StatusOK,
+			bytesRead: 10,
+		},
+		{
+			name:    "too many files",
+			options: []simba.RouteOption{simba.WithMultipartLimits(2, 0)},
+			body:    newBody(part{"a", "a.txt", "1"}, part{"b", "b.txt", "2"}, part{"c", "c.txt", "3"}),
+			status:  http.StatusRequestEntityTooLarge,
+			tooMany: true,
+		},
+		{
+			name:     "files too large in total",
+			options:  []simba.RouteOption{simba.WithMultipartLimits(0, 9)},
+			body:     newBody(part{"a", "a.txt", "12345"}, part{"b", "b.txt", "12345"}),
+			status:   http.StatusRequestEntityTooLarge,
+			tooLarge: true,
+		},
+		{
+			name:      "form fields are not files",
+			options:   []simba.RouteOption{simba.WithMultipartLimits(1, 5)},
+			body:      newBody(part{"title", "", "a long title"}, part{"a", "a.txt", "12345"}, part{"description", "", "text"}),
+			status:    http.StatusOK,
+			bytesRead: 21,
+		},
+		{
+			name:     "limits from the settings",
+			settings: []settings.Option{settings.WithMaxMultipartFiles(1)},
+			body:     newBody(part{"a", "a.txt", "1"}, part{"b", "b.txt", "2"}),
+			status:   http.StatusRequestEntityTooLarge,
+			tooMany:  true,
+		},
+		{
+			name:      "route limits override the settings",
+			settings:  []settings.Option{settings.WithMaxMultipartFiles(1)},
+			options:   []simba.RouteOption{simba.WithMultipartLimits(2, 0)},
+			body:      newBody(part{"a", "a.txt", "1"}, part{"b", "b.txt", "2"}),
+			status:    http.StatusOK,
+			bytesRead: 2,
+		},
+		{
+			name:    "boundary followed by content is not a delimiter",
+			options: []simba.RouteOption{simba.WithMultipartLimits(0, 10)},
+			body: strings.NewReader("--" + boundary + "\r\n" +
+				"Content-Disposition: form-data; name=\"a\"; filename=\"a.txt\"\r\n\r\n" +
+				"1\r\n--" + boundary + "x\r\n\r\n" + strings.Repeat("2", 20) + "\r\n" +
+				"--" + boundary + "--\r\n"),
+			status:   http.StatusRequestEntityTooLarge,
+			tooLarge: true,
+		},
+		{
+			name:    "boundary without carriage return is not a delimiter",
+			options: []simba.RouteOption{simba.WithMultipartLimits(0, 10)},
+			body: strings.NewReader("--" + boundary + "\r\n" +
+				"Content-Disposition: form-data; name=\"a\"; filename=\"a.txt\"\r\n\r\n" +
+				"1\n--" + boundary + "\n\n" + strings.Repeat("2", 20) + "\r\n" +
+				"--" + boundary + "--\r\n"),
+			status:   http.StatusRequestEntityTooLarge,
+			tooLarge: true,
+		},
+		{
+			name:    "line feed delimiters",
+			options: []simba.RouteOption{simba.WithMultipartLimits(1, 3)},
+			body: strings.NewReader("--" + boundary + "\n" +
+				"Content-Disposition: form-data; name=\"a\"; filename=\"a.txt\"\n\n" +
+				"123\n--" + boundary + "--\n"),
+			status:    http.StatusOK,
+			bytesRead: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := simba.New(tt.settings...)
+			app.Router.POST("/upload", simba.MultipartHandler(readParts), tt.options...)
+
+			req := httptest.NewRequest(http.MethodPost, "/upload", tt.body)
+			req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.JSONEq(t, fmt.Sprintf(`{"bytes":%d}`, tt.bytesRead), w.Body.Bytes())
+				return
+			}
+
+			var errorResponse simbaErrors.ErrorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+			details, _ := errorResponse.Details.(string)
+			assert.Equal(t, tt.tooMany, strings.HasSuffix(details, " files"))
+			assert.Equal(t, tt.tooLarge, strings.HasPrefix(details, "uploaded files exceed"))
+		})
+	}
+
+	t.Run("handler ignoring the error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.MultipartRequest[models.NoParams]) (*models.Response[models.NoBody], error) {
+			for {
+				if _, err := req.Reader.NextPart(); err != nil {
+					return &models.Response[models.NoBody]{}, nil
+				}
+			}
+		}
+
+		app := simba.New()
+		app.Router.POST("/upload", simba.MultipartHandler(handler), simba.WithMultipartLimits(1, 0))
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", newBody(part{"a", "a.txt", "1"}, part{"b", "b.txt", "2"}))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+// FuzzMultipartLimits checks that the multipart limits count the file parts and the bytes of their content the
+// way [multipart.Reader] reads them, so bodies can't be crafted to be parsed differently and evade the limits.
+func FuzzMultipartLimits(f *testing.F) {
+	const boundary = "fuzz-boundary"
+
+	file := "Content-Disposition: form-data; name=\"a\"; filename=\"a.txt\""
+	field := "Content-Disposition: form-data; name=\"b\""
+	for _, seed := range []string{
+		"--" + boundary + "\r\n" + file + "\r\n\r\n123\r\n--" + boundary + "\r\n" + field + "\r\n\r\nab\r\n--" + boundary + "--\r\n",
+		"--" + boundary + "\n" + file + "\n\n123\n--" + boundary + "\n" + file + "\n\n45\n--" + boundary + "--\n",
+		"preamble\r\n--" + boundary + " \t\r\n" + file + "\r\n\r\n1\r\n--" + boundary + "x\r\n\r\n22\r\n--" + boundary + "--\r\nepilogue",
+		"--" + boundary + "\r\n" + file + "\r\n\r\n1\n--" + boundary + "\n\n22\r\n--" + boundary + "-\r\n333\r\n--" + boundary + "--",
+		"--" + boundary + "\r\n" + file + "\r\nX-Padding: " + strings.Repeat("x", 100) + "\r\n\r\n\r\n--\r\n--" + boundary + "--\r\n",
+		"--" + boundary + "\r\nContent-Disposition: form-data; name=\"c\"; filename=\"\"\r\n\r\n1\r\n--" + boundary + "\r\n" + file,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	// usage counts the file parts and the bytes of their content read until the body ends or fails to parse
+	type usage struct {
+		files int
+		size  int64
+	}
+
+	readFiles := func(reader *multipart.Reader) (usage, error) {
+		var u usage
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return u, nil
+			}
+			if err != nil {
+				return u, err
+			}
+			if part.FileName() != "" {
+				u.files++
+			}
+			n, err := io.Copy(io.Discard, part)
+			if part.FileName() != "" {
+				u.size += n
+			}
+			if err != nil {
+				return u, err
+			}
+		}
+	}
+
+	// upload sends the body to a route with the limits, returning the status and the usage seen by the handler
+	upload := func(body []byte, maxFiles int, maxSize int64) (int, usage) {
+		var seen usage
+		handler := func(ctx context.Context, req *models.MultipartRequest[models.NoParams]) (*models.Response[models.NoBody], error) {
+			var err error
+			seen, err = readFiles(req.Reader)
+			if err != nil {
+				return nil, simbaErrors.NewSimbaError(http.StatusBadRequest, "invalid multipart body", err)
+			}
+			return &models.Response[models.NoBody]{}, nil
+		}
+
+		app := simba.New(settings.WithLogLevel(slog.LevelError + 1))
+		app.Router.POST("/upload", simba.MultipartHandler(handler), simba.WithMultipartLimits(maxFiles, maxSize))
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		return w.Code, seen
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		expected, err := readFiles(multipart.NewReader(bytes.NewReader(body), boundary))
+
+		// The handler never sees more than the limits allow, whether or not the body can be parsed
+		status, seen := upload(body, 1, 1)
+		if seen.files > 1 || seen.size > 1 {
+			t.Fatalf("handler read %d files of %d bytes beyond the limits, status %d", seen.files, seen.size, status)
+		}
+		if err != nil {
+			return
+		}
+
+		// Bodies the multipart reader accepts are counted exactly like it reads them
+		status, seen = upload(body, expected.files, expected.size)
+		if status != http.StatusNoContent || seen != expected {
+			t.Fatalf("expected %d files of %d bytes to be within the limits, got status %d after %d files of %d bytes",
+				expected.files, expected.size, status, seen.files, seen.size)
+		}
+		if expected.files > 1 {
+			if status, _ = upload(body, expected.files-1, 0); status != http.StatusRequestEntityTooLarge {
+				t.Fatalf("expected %d files to exceed a limit of %d, got status %d", expected.files, expected.files-1, status)
+			}
+		}
+		if expected.size > 1 {
+			if status, _ = upload(body, 0, expected.size-1); status != http.StatusRequestEntityTooLarge {
+				t.Fatalf("expected %d bytes to exceed a limit of %d, got status %d", expected.size, expected.size-1, status)
+			}
+		}
+	})
+}
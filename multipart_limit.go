@@ -0,0 +1,307 @@
+package simba
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net/textproto"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// maxMultipartHeaderBytes is the size of the part headers inspected for a filename. Parts with longer headers
+// are counted as files, so the limits can't be evaded by padding the headers.
+const maxMultipartHeaderBytes = 64 << 10
+
+// multipartScanState is the position of a multipartLimitReader in the multipart body.
+type multipartScanState int
+
+const (
+	scanBody          multipartScanState = iota // in the preamble or the content of a part
+	scanAfterBoundary                           // after a boundary, which may turn out to be content
+	scanFinalDash                               // after a boundary and a dash, which may close the body
+	scanDelimiterLine                           // after a delimiter, up to the end of its line
+	scanHeaders                                 // in the headers of a part
+	scanDone                                    // after the closing delimiter
+)
+
+// multipartLimitReader scans a multipart body as it is read, counting the file parts and the bytes of their
+// content, and fails once either exceeds its limit, before the multipart reader hands the part to the handler.
+// It recognizes delimiters the way [multipart.Reader] does, so content can't be disguised as a delimiter to
+// evade the limits, which FuzzMultipartLimits checks against the multipart reader. A limit of 0 or less doesn't
+// restrict.
+type multipartLimitReader struct {
+	reader   io.Reader
+	maxFiles int
+	maxSize  int64
+
+	// delimiter is the newline and dashes preceding the boundary, whether a carriage return must precede it is
+	// decided by the line ending of the first delimiter
+	delimiter []byte
+	started   bool
+	requireCR bool
+	state     multipartScanState
+	matched   int
+	// startMatch is set while matched includes the newline preceding the body or ending the part headers,
+	// which the multipart reader lets a delimiter start with without being content
+	startMatch bool
+	prev       byte
+	crBefore   bool
+	sawCR      bool
+	header     []byte
+	lineLen    int
+
+	inFile   bool
+	files    int
+	size     int64
+	partSize int64
+
+	// err is set once a limit is exceeded, since handlers don't necessarily return the error of the reader
+	err *simbaErrors.SimbaError
+}
+
+func newMultipartLimitReader(body io.Reader, boundary string, maxFiles int, maxSize int64) *multipartLimitReader {
+	return &multipartLimitReader{
+		reader:     body,
+		maxFiles:   maxFiles,
+		maxSize:    maxSize,
+		delimiter:  []byte("\n--" + boundary),
+		started:    false,
+		requireCR:  false,
+		state:      scanBody,
+		matched:    1, // The first delimiter may start the body without a preceding newline
+		startMatch: true,
+		prev:       0,
+		crBefore:   false,
+		sawCR:      false,
+		header:     nil,
+		lineLen:    0,
+		inFile:     false,
+		files:      0,
+		size:       0,
+		partSize:   0,
+		err:        nil,
+	}
+}
+
+// enabled reports whether the reader enforces a limit.
+func (m *multipartLimitReader) enabled() bool {
+	return m.maxFiles > 0 || m.maxSize > 0
+}
+
+func (m *multipartLimitReader) Read(p []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	n, err := m.reader.Read(p)
+	if !m.enabled() {
+		return n, err
+	}
+
+	for i, b := range p[:n] {
+		switch m.state {
+		case scanBody:
+			m.scanBody(b)
+		case scanAfterBoundary:
+			m.scanAfterBoundary(b)
+		case scanFinalDash:
+			m.scanFinalDash(b)
+		case scanDelimiterLine:
+			m.scanDelimiterLine(b)
+		case scanHeaders:
+			m.scanHeaders(b)
+		case scanDone:
+		}
+		if m.err != nil {
+			return i, m.err
+		}
+	}
+	return n, err
+}
+
+// scanBody looks for the next boundary in the content of a part, counting the content of file parts.
+func (m *multipartLimitReader) scanBody(b byte) {
+	if m.inFile {
+		m.partSize++
+	}
+
+	switch {
+	case b == m.delimiter[m.matched]:
+		m.matched++
+	case b == m.delimiter[0]:
+		m.matched = 1
+	default:
+		m.matched = 0
+	}
+	if m.matched <= 1 {
+		m.startMatch = false
+	}
+	if m.matched == 1 {
+		m.crBefore = m.prev == '\r'
+	}
+	m.prev = b
+
+	switch {
+	case m.matched < len(m.delimiter):
+		m.checkSize()
+	case m.started && m.requireCR && !m.crBefore:
+		// Without the carriage return the multipart reader takes the boundary as content
+		m.matched = 0
+		m.checkSize()
+	default:
+		m.state = scanAfterBoundary
+	}
+}
+
+// checkSize fails if the content of the file parts exceeds the limit, not counting the bytes that may belong
+// to the next delimiter.
+func (m *multipartLimitReader) checkSize() {
+	if m.inFile && m.maxSize > 0 && m.size+m.partSize-m.pending() > m.maxSize {
+		m.err = simbaErrors.NewUploadTooLargeError(m.maxSize)
+	}
+}
+
+// pending returns the number of bytes counted for the current part that may belong to the next delimiter.
+func (m *multipartLimitReader) pending() int64 {
+	if m.startMatch {
+		return int64(m.matched) - 1
+	}
+
+	cr := m.crBefore
+	if m.matched == 0 {
+		cr = m.prev == '\r'
+	}
+	switch {
+	case cr && (!m.started || m.requireCR):
+		return int64(m.matched) + 1
+	case m.started && m.requireCR:
+		// Without the carriage return the multipart reader hands the bytes to the part right away
+		return 0
+	default:
+		return int64(m.matched)
+	}
+}
+
+// scanAfterBoundary decides whether a boundary is a delimiter, which it is if it is followed by whitespace,
+// a newline or two dashes, or content otherwise.
+func (m *multipartLimitReader) scanAfterBoundary(b byte) {
+	switch b {
+	case '-':
+		m.state = scanFinalDash
+	case ' ', '\t', '\r', '\n':
+		m.endPart()
+		m.sawCR = false
+		m.state = scanDelimiterLine
+		m.scanDelimiterLine(b)
+	default:
+		m.notDelimiter(b)
+	}
+}
+
+// scanFinalDash closes the body if a boundary is followed by two dashes.
+func (m *multipartLimitReader) scanFinalDash(b byte) {
+	if b == '-' {
+		m.endPart()
+		m.state = scanDone
+		return
+	}
+	m.notDelimiter('-')
+	m.scanBody(b)
+}
+
+// notDelimiter continues scanning the content of the part at the byte following a boundary that isn't a
+// delimiter.
+func (m *multipartLimitReader) notDelimiter(b byte) {
+	m.matched = 0
+	m.state = scanBody
+	m.scanBody(b)
+}
+
+// endPart adds the content of a file part to the total once its delimiter has been read.
+func (m *multipartLimitReader) endPart() {
+	if m.inFile {
+		m.size += m.partSize - m.pending()
+		if m.maxSize > 0 && m.size > m.maxSize {
+			m.err = simbaErrors.NewUploadTooLargeError(m.maxSize)
+			return
+		}
+	}
+	m.inFile = false
+	m.matched = 0
+}
+
+// scanDelimiterLine reads the rest of the line of a delimiter, which may only hold whitespace. The multipart
+// reader skips lines of the preamble that aren't delimiters and fails on any other invalid delimiter line, after
+// which no more parts are read. The line ending of the first delimiter sets that of the following ones.
+func (m *multipartLimitReader) scanDelimiterLine(b byte) {
+	switch {
+	case (b == ' ' || b == '\t') && !m.sawCR:
+		return
+	case b == '\r' && !m.sawCR:
+		m.sawCR = true
+		return
+	case b == '\n' && (!m.started || m.sawCR == m.requireCR):
+		if !m.started {
+			m.started = true
+			m.requireCR = m.sawCR
+		}
+		m.header = m.header[:0]
+		m.lineLen = 0
+		m.state = scanHeaders
+	case !m.started:
+		m.matched = 0
+		m.state = scanBody
+		m.scanBody(b)
+	default:
+		m.state = scanDone
+	}
+}
+
+// scanHeaders collects the headers of a part up to the blank line ending them, counting the part if it is a file.
+func (m *multipartLimitReader) scanHeaders(b byte) {
+	if len(m.header) <= maxMultipartHeaderBytes {
+		m.header = append(m.header, b)
+	}
+
+	switch b {
+	case '\r':
+		return
+	case '\n':
+		if m.lineLen > 0 {
+			m.lineLen = 0
+			return
+		}
+	default:
+		m.lineLen++
+		return
+	}
+
+	if len(m.header) > maxMultipartHeaderBytes || isFilePart(m.header) {
+		m.files++
+		if m.maxFiles > 0 && m.files > m.maxFiles {
+			m.err = simbaErrors.NewTooManyFilesError(m.maxFiles)
+			return
+		}
+		m.inFile = true
+		m.partSize = 0
+	}
+	// A delimiter may follow the headers right away, the newline ending them preceding it
+	m.prev = 0
+	m.matched = 1
+	m.crBefore = true
+	m.startMatch = true
+	m.state = scanBody
+}
+
+// isFilePart reports whether the part with the headers is a file, which it is if its Content-Disposition has
+// a filename, like [multipart.Part.FileName]. Headers that can't be parsed are counted as a file.
+func isFilePart(header []byte) bool {
+	h, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(header))).ReadMIMEHeader()
+	if err != nil {
+		return true
+	}
+	_, params, err := mime.ParseMediaType(h.Get("Content-Disposition"))
+	return err == nil && params["filename"] != ""
+}
@@ -0,0 +1,133 @@
+package simba
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// multipartStream writes the parts of a multipart/mixed response, flushing after each part.
+type multipartStream struct {
+	ctx        context.Context
+	w          http.ResponseWriter
+	status     int
+	writer     *multipart.Writer
+	controller *http.ResponseController
+	started    bool
+	parts      int
+}
+
+// writeMultipart streams a multipart/mixed response with the parts written by the body.
+func writeMultipart(w http.ResponseWriter, r *http.Request, status int, body models.Multipart) {
+	logger := logging.From(r.Context())
+
+	s := &multipartStream{
+		ctx:        r.Context(),
+		w:          w,
+		status:     status,
+		writer:     multipart.NewWriter(w),
+		controller: http.NewResponseController(w),
+		started:    false,
+		parts:      0,
+	}
+
+	var err error
+	if body.Parts != nil {
+		err = body.Parts(s)
+	}
+
+	switch {
+	case simbaContext.IsClientCancelled(r.Context()):
+		logger.Debug("request cancelled by client, ending multipart response",
+			"reason", simbaContext.ClientCancelledReason,
+			"parts", s.parts,
+		)
+	case err != nil && !s.started:
+		simbaErrors.WriteError(w, r, err)
+	case err != nil:
+		logger.Error("failed to stream multipart response", "error", err, "parts", s.parts)
+	default:
+		s.start()
+		if err = s.writer.Close(); err != nil {
+			logger.Error("failed to close multipart response", "error", err, "parts", s.parts)
+		}
+	}
+}
+
+// start writes the response header with the boundary of the parts.
+func (s *multipartStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.w.Header().Set("Content-Type", mime.FormatMediaType(mimetypes.MultipartMixed, map[string]string{"boundary": s.writer.Boundary()}))
+	s.w.WriteHeader(s.status)
+}
+
+func (s *multipartStream) Part(contentType string, content io.Reader) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	return s.PartWithHeader(header, content)
+}
+
+func (s *multipartStream) PartWithHeader(header textproto.MIMEHeader, content io.Reader) error {
+	if closer, ok := content.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	s.start()
+	part, err := s.writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if content != nil {
+		if _, err = io.Copy(part, content); err != nil {
+			return err
+		}
+	}
+	s.parts++
+
+	if err = s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+func (s *multipartStream) JSON(value any) error {
+	visible, err := applyVisibility(s.ctx, value)
+	if err != nil {
+		return err
+	}
+	formatted, err := applyTimeFormat(s.ctx, visible)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(formatted)
+	if err != nil {
+		return err
+	}
+	return s.Part(mimetypes.ApplicationJSON, bytes.NewReader(data))
+}
+
+func (s *multipartStream) File(file models.File) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", fileContentType(file))
+	header.Set("Content-Disposition", fileDisposition(file))
+	return s.PartWithHeader(header, file.Content)
+}
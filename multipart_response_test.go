@@ -0,0 +1,106 @@
+package simba_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestMultipartResponse(t *testing.T) {
+	t.Parallel()
+
+	type metadata struct {
+		Name string `json:"name"`
+		Size int    `json:"size"`
+	}
+
+	serve := func(parts func(parts models.PartWriter) error) *httptest.ResponseRecorder {
+		app := simba.New()
+		app.Router.GET("/reports/{id}", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.Multipart], error) {
+			return &models.Response[models.Multipart]{Body: models.Multipart{Parts: parts}}, nil
+		}))
+
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports/1", nil))
+		return w
+	}
+
+	t.Run("streams parts with their own content types", func(t *testing.T) {
+		t.Parallel()
+
+		w := serve(func(parts models.PartWriter) error {
+			if err := parts.JSON(metadata{Name: "report.pdf", Size: 8}); err != nil {
+				return err
+			}
+			if err := parts.File(models.File{Filename: "report.pdf", Content: strings.NewReader("%PDF-1.7")}); err != nil {
+				return err
+			}
+			return parts.Part(mimetypes.TextPlain, strings.NewReader("done"))
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		assert.NoError(t, err)
+		assert.Equal(t, mimetypes.MultipartMixed, mediaType)
+
+		reader := multipart.NewReader(w.Body, params["boundary"])
+		expected := []struct {
+			contentType string
+			disposition string
+			content     string
+		}{
+			{contentType: mimetypes.ApplicationJSON, content: `{"name":"report.pdf","size":8}`},
+			{contentType: "application/pdf", disposition: "attachment; filename=report.pdf", content: "%PDF-1.7"},
+			{contentType: mimetypes.TextPlain, content: "done"},
+		}
+		for _, part := range expected {
+			p, err := reader.NextPart()
+			assert.NoError(t, err)
+			assert.Equal(t, part.contentType, p.Header.Get("Content-Type"))
+			assert.Equal(t, part.disposition, p.Header.Get("Content-Disposition"))
+			content, err := io.ReadAll(p)
+			assert.NoError(t, err)
+			assert.Equal(t, part.content, string(content))
+		}
+		_, err = reader.NextPart()
+		assert.True(t, errors.Is(err, io.EOF))
+	})
+
+	t.Run("writes error response if no part was written", func(t *testing.T) {
+		t.Parallel()
+
+		w := serve(func(parts models.PartWriter) error {
+			return errors.New("report not found")
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, mimetypes.ApplicationJSON, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("ends response without closing boundary on error after first part", func(t *testing.T) {
+		t.Parallel()
+
+		w := serve(func(parts models.PartWriter) error {
+			if err := parts.Part(mimetypes.TextPlain, strings.NewReader("partial")); err != nil {
+				return err
+			}
+			return errors.New("storage unavailable")
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		assert.NoError(t, err)
+		assert.False(t, strings.Contains(w.Body.String(), "--"+params["boundary"]+"--"))
+	})
+}
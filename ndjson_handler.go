@@ -0,0 +1,267 @@
+package simba
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// NDJSONHandlerFunc is a function type for handling routes that stream records as newline-delimited JSON.
+type NDJSONHandlerFunc[RequestBody, Params, Record any] func(ctx context.Context, req *models.Request[RequestBody, Params], emit func(Record) error) error
+
+// AuthenticatedNDJSONHandlerFunc is a function type for handling authenticated routes that stream records
+// as newline-delimited JSON.
+type AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record any] struct {
+	handler     func(ctx context.Context, req *models.Request[RequestBody, Params], authModel AuthModel, emit func(Record) error) error
+	authHandler auth.Handler[AuthModel]
+}
+
+// NDJSONHandler handles a Request by streaming records to the client as newline-delimited JSON
+// (application/x-ndjson). Each record passed to emit is written on its own line and flushed,
+// so large datasets can be exported without building them in memory.
+//
+// The response status is 200 and is sent with the first record, so headers computed by the handler
+// must be set with [SetHeaders] before emitting. An error returned before any record was emitted is
+// written as a regular error response; once streaming has started the error is logged and the stream
+// is ended. emit returns the context error if the client has disconnected, which should stop the handler.
+//
+//	Example usage:
+//
+//	func(ctx context.Context, req *simba.Request[simba.NoBody, simba.NoParams], emit func(User) error) error {
+//		for user := range users.All(ctx) {
+//			if err := emit(user); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	}
+//
+// Register the handler:
+//
+//	Mux.GET("/users/export", simba.NDJSONHandler(handler))
+func NDJSONHandler[RequestBody, Params, Record any](h NDJSONHandlerFunc[RequestBody, Params, Record]) Handler {
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface for NDJSONHandlerFunc.
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := handleJsonRequest[RequestBody, Params](r)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	streamNDJSON(w, r, func(ctx context.Context, emit func(Record) error) error {
+		return h(ctx, req, emit)
+	})
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetRequestBody() any {
+	var rb RequestBody
+	return rb
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetResponseBody() any {
+	var record Record
+	return record
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetParams() any {
+	var p Params
+	return p
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetProduces() string {
+	return mimetypes.ApplicationNDJSON
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetHandler() any {
+	return h
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetAuthModel() any {
+	return nil
+}
+
+func (h NDJSONHandlerFunc[RequestBody, Params, Record]) GetAuthHandler() any {
+	return nil
+}
+
+// AuthNDJSONHandler handles an authenticated Request by streaming records to the client as
+// newline-delimited JSON. See [NDJSONHandler] for how records and errors are written.
+//
+// Register the handler:
+//
+//	Mux.GET("/users/export", simba.AuthNDJSONHandler(handler, authHandler))
+func AuthNDJSONHandler[RequestBody, Params, AuthModel, Record any](
+	handler func(ctx context.Context, req *models.Request[RequestBody, Params], authModel AuthModel, emit func(Record) error) error,
+	authHandler auth.Handler[AuthModel],
+) Handler {
+	return AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]{
+		handler:     handler,
+		authHandler: authHandler,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface for AuthenticatedNDJSONHandlerFunc.
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authModel, err := auth.HandleAuthRequest[AuthModel](h.authHandler, r)
+	if err != nil {
+		statusCode := http.StatusUnauthorized // Default status code for unauthorized access
+		if statusCoder, ok := err.(simbaErrors.StatusCodeProvider); ok {
+			statusCode = statusCoder.StatusCode()
+		}
+
+		errorMessage := "unauthorized" // Default error message for unauthorized access
+		if msgProvider, ok := err.(simbaErrors.PublicMessageProvider); ok {
+			errorMessage = msgProvider.PublicMessage()
+		}
+
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(statusCode, errorMessage, err))
+		return
+	}
+
+	r = withPrincipal(r, authModel)
+
+	req, err := handleJsonRequest[RequestBody, Params](r)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	streamNDJSON(w, r, func(ctx context.Context, emit func(Record) error) error {
+		return h.handler(ctx, req, authModel, emit)
+	})
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetRequestBody() any {
+	var rb RequestBody
+	return rb
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetParams() any {
+	var p Params
+	return p
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetResponseBody() any {
+	var record Record
+	return record
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetAccepts() string {
+	return mimetypes.ApplicationJSON
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetProduces() string {
+	return mimetypes.ApplicationNDJSON
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetHandler() any {
+	return h.handler
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetAuthModel() any {
+	var am AuthModel
+	return am
+}
+
+func (h AuthenticatedNDJSONHandlerFunc[RequestBody, Params, AuthModel, Record]) GetAuthHandler() any {
+	return h.authHandler
+}
+
+// ndjsonStream writes records as newline-delimited JSON, flushing after each record.
+type ndjsonStream[Record any] struct {
+	ctx        context.Context
+	w          http.ResponseWriter
+	headers    *streamHeaders
+	controller *http.ResponseController
+	encoder    *json.Encoder
+	started    bool
+	records    int
+}
+
+// streamNDJSON runs stream with a context accepting response headers until the first record and an emit
+// function that writes records to the response.
+func streamNDJSON[Record any](w http.ResponseWriter, r *http.Request, stream func(ctx context.Context, emit func(Record) error) error) {
+	logger := logging.From(r.Context())
+	ctx, headers := withStreamHeaders(r.Context(), w)
+
+	s := &ndjsonStream[Record]{
+		ctx:        ctx,
+		w:          w,
+		headers:    headers,
+		controller: http.NewResponseController(w),
+		encoder:    json.NewEncoder(w),
+		started:    false,
+		records:    0,
+	}
+
+	err := stream(ctx, s.emit)
+
+	switch {
+	case simbaContext.IsClientCancelled(r.Context()):
+		logger.Debug("request cancelled by client, ending NDJSON stream",
+			"reason", simbaContext.ClientCancelledReason,
+			"records", s.records,
+		)
+	case err != nil && !s.started:
+		headers.send(func() { simbaErrors.WriteError(w, r, err) })
+	case err != nil:
+		logger.Error("failed to stream NDJSON response", "error", err, "records", s.records)
+	case !s.started:
+		s.start()
+	}
+}
+
+// emit writes a single record and flushes it to the client.
+func (s *ndjsonStream[Record]) emit(record Record) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	visible, err := applyVisibility(s.ctx, record)
+	if err != nil {
+		return err
+	}
+	formatted, err := applyTimeFormat(s.ctx, visible)
+	if err != nil {
+		return err
+	}
+
+	if !s.started {
+		s.start()
+	}
+
+	if err := s.encoder.Encode(formatted); err != nil {
+		return err
+	}
+	s.records++
+
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
+
+// start writes the response headers.
+func (s *ndjsonStream[Record]) start() {
+	s.started = true
+	s.headers.send(func() {
+		s.w.Header().Set("Content-Type", mimetypes.ApplicationNDJSON)
+		s.w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,155 @@
+package simba_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestNDJSONHandler(t *testing.T) {
+	t.Parallel()
+
+	type Params struct {
+		Count int `query:"count"`
+	}
+
+	t.Run("streams records", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, Params], emit func(simbaTest.User) error) error {
+			for i := range req.Params.Count {
+				if err := emit(simbaTest.User{ID: i, Name: fmt.Sprintf("user-%d", i), Role: "admin"}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.NDJSONHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export?count=2", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, mimetypes.ApplicationNDJSON, w.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"id\":0,\"name\":\"user-0\",\"role\":\"admin\"}\n{\"id\":1,\"name\":\"user-1\",\"role\":\"admin\"}\n", w.Body.String())
+		assert.True(t, w.Flushed)
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, Params], emit func(simbaTest.User) error) error {
+			return nil
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.NDJSONHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, mimetypes.ApplicationNDJSON, w.Header().Get("Content-Type"))
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("error before first record", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, Params], emit func(simbaTest.User) error) error {
+			return simbaErrors.NewSimbaError(http.StatusNotFound, "export not found", errors.New("export not found"))
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.NDJSONHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, mimetypes.ApplicationJSON, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("error after first record ends the stream", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, Params], emit func(simbaTest.User) error) error {
+			if err := emit(simbaTest.User{ID: 1, Name: "John", Role: "admin"}); err != nil {
+				return err
+			}
+			return errors.New("database unavailable")
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.NDJSONHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{\"id\":1,\"name\":\"John\",\"role\":\"admin\"}\n", w.Body.String())
+	})
+
+	t.Run("emit stops when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		var emitErr error
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, Params], emit func(simbaTest.User) error) error {
+			emitErr = emit(simbaTest.User{ID: 1, Name: "John", Role: "admin"})
+			return emitErr
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.NDJSONHandler(handler))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.True(t, errors.Is(emitErr, context.Canceled))
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("authenticated", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], user *simbaTest.User, emit func(simbaTest.User) error) error {
+			return emit(*user)
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.AuthNDJSONHandler(handler, simbaTest.BearerAuthAuthenticationHandler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{\"id\":1,\"name\":\"John Doe\",\"role\":\"admin\"}\n", w.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/export", nil)
+		w = httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,114 @@
+package simba
+
+import (
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/sillen102/simba/settings"
+)
+
+// normalizeTag normalizes a string field before validation, e.g. `normalize:"trim"` or `normalize:"trim,nfc"`.
+// `normalize:"-"` excludes the field from the normalization configured in the settings.
+const normalizeTag = "normalize"
+
+// normalizeFields searches types for fields with a normalize tag.
+var normalizeFields = tagSearch(normalizeTag, nil)
+
+// stringNormalization holds the normalizations applied to a string.
+type stringNormalization struct {
+	trim bool
+	nfc  bool
+}
+
+// normalizeStrings normalizes the strings of the model in place: those of fields with a normalize tag,
+// and all of them if trimming or unicode normalization is enabled in the settings. Strings in nested
+// structs, pointers, slices and map values are included.
+func normalizeStrings(model any, requestSettings *settings.Request) {
+	global := stringNormalization{trim: requestSettings.TrimStrings, nfc: requestSettings.NormalizeUnicode}
+	normalizeValue(reflect.ValueOf(model), global)
+}
+
+// normalizeValue normalizes the strings of the value with the normalization, or the normalization of the
+// tagged fields of structs.
+func normalizeValue(v reflect.Value, n stringNormalization) {
+	if !v.IsValid() || (!n.enabled() && !hasNormalizeTags(v.Type())) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			normalizeValue(v.Elem(), n)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(n.apply(v.String()))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			normalizeValue(v.Index(i), n)
+		}
+	case reflect.Map:
+		// Map values aren't addressable, so only string values are normalized
+		if v.Type().Elem().Kind() != reflect.String || v.IsNil() {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(n.apply(iter.Value().String())).Convert(v.Type().Elem()))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldNormalization := n
+			if tag, ok := field.Tag.Lookup(normalizeTag); ok {
+				fieldNormalization = n.withTag(tag)
+			}
+			normalizeValue(v.Field(i), fieldNormalization)
+		}
+	}
+}
+
+// enabled reports whether any normalization is applied.
+func (n stringNormalization) enabled() bool {
+	return n.trim || n.nfc
+}
+
+// apply normalizes the string.
+func (n stringNormalization) apply(s string) string {
+	if n.trim {
+		s = strings.TrimSpace(s)
+	}
+	if n.nfc {
+		s = norm.NFC.String(s)
+	}
+	return s
+}
+
+// withTag returns the normalization of a field with a normalize tag, which adds to the normalization
+// of its parent, or disables it if the tag is "-".
+func (n stringNormalization) withTag(tag string) stringNormalization {
+	if tag == "-" {
+		return stringNormalization{trim: false, nfc: false}
+	}
+	for option := range strings.SplitSeq(tag, ",") {
+		switch strings.TrimSpace(option) {
+		case "trim":
+			n.trim = true
+		case "nfc":
+			n.nfc = true
+		}
+	}
+	return n
+}
+
+// hasNormalizeTags reports whether the type has fields with a normalize tag, including nested types.
+func hasNormalizeTags(t reflect.Type) bool {
+	return normalizeFields.has(t)
+}
@@ -0,0 +1,98 @@
+package simba_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestNormalizeStrings(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		Search string `query:"search" normalize:"trim" validate:"max=5"`
+		Raw    string `query:"raw"`
+	}
+	type address struct {
+		City string `json:"city" validate:"required,alpha"`
+	}
+	type body struct {
+		Email    string            `json:"email" normalize:"trim" validate:"required,email"`
+		Name     string            `json:"name" normalize:"nfc"`
+		Password string            `json:"password" normalize:"-"`
+		Note     *string           `json:"note"`
+		Tags     []string          `json:"tags"`
+		Labels   map[string]string `json:"labels"`
+		Address  address           `json:"address" normalize:"trim"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[body, params]) (*models.Response[map[string]any], error) {
+		return &models.Response[map[string]any]{Body: map[string]any{"params": req.Params, "body": req.Body}}, nil
+	}
+
+	// An e followed by a combining acute accent, which is a single é in NFC
+	const decomposed = "Rene\u0301"
+	const composed = "Ren\u00e9"
+
+	requestBody := `{"email":" john@example.com\n","name":"` + decomposed + `","password":" secret ","note":" hi ","tags":[" a "],"labels":{"k":" v "},"address":{"city":" Paris "}}`
+
+	serve := func(t *testing.T, opts ...settings.Option) (int, map[string]any) {
+		t.Helper()
+
+		app := simba.New(opts...)
+		app.Router.POST("/users", simba.JsonHandler(handler))
+
+		req := httptest.NewRequest(http.MethodPost, "/users?search=%20john%20&raw=%20x%20", strings.NewReader(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		var resp map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	t.Run("normalizes tagged fields before validation", func(t *testing.T) {
+		t.Parallel()
+
+		status, resp := serve(t)
+
+		assert.Equal(t, http.StatusOK, status)
+		p := resp["params"].(map[string]any)
+		assert.Equal[any](t, "john", p["Search"])
+		assert.Equal[any](t, " x ", p["Raw"])
+
+		b := resp["body"].(map[string]any)
+		assert.Equal[any](t, "john@example.com", b["email"])
+		assert.Equal[any](t, composed, b["name"])
+		assert.Equal[any](t, " secret ", b["password"])
+		assert.Equal[any](t, " hi ", b["note"])
+		assert.Equal[any](t, []any{" a "}, b["tags"])
+		assert.Equal[any](t, map[string]any{"city": "Paris"}, b["address"])
+	})
+
+	t.Run("normalizes all strings when enabled in settings", func(t *testing.T) {
+		t.Parallel()
+
+		status, resp := serve(t, settings.WithTrimStrings(true), settings.WithNormalizeUnicode(true))
+
+		assert.Equal(t, http.StatusOK, status)
+		p := resp["params"].(map[string]any)
+		assert.Equal[any](t, "x", p["Raw"])
+
+		b := resp["body"].(map[string]any)
+		assert.Equal[any](t, composed, b["name"])
+		assert.Equal[any](t, " secret ", b["password"])
+		assert.Equal[any](t, "hi", b["note"])
+		assert.Equal[any](t, []any{"a"}, b["tags"])
+		assert.Equal[any](t, map[string]any{"k": "v"}, b["labels"])
+	})
+}
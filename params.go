@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
 	"github.com/sillen102/simba/simbaErrors"
 	"github.com/sillen102/simba/validation"
 
@@ -31,9 +32,11 @@ func ParseAndValidateParams[Params any](r *http.Request) (Params, error) {
 	v := reflect.ValueOf(&instance).Elem()
 
 	validationErrors := make([]validation.ValidationError, 0)
+	requestSettings := getConfigurationFromContext(r.Context())
+	failFast := requestSettings.FailFastValidation
 
-	// Extract parameters from struct tags and set values
-	for i := 0; i < t.NumField(); i++ {
+	// Extract parameters from struct tags and set values, stopping at the first invalid parameter if failing fast
+	for i := 0; i < t.NumField() && !(failFast && len(validationErrors) > 0); i++ {
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
@@ -50,6 +53,16 @@ func ParseAndValidateParams[Params any](r *http.Request) (Params, error) {
 			continue
 		}
 
+		// Handle nested objects bound from bracket notation query keys
+		if isDeepObjectField(field) {
+			errs, err := parseDeepObjectParams(r, fieldValue, field.Tag.Get("query"))
+			if err != nil {
+				return instance, err
+			}
+			validationErrors = append(validationErrors, errs...)
+			continue
+		}
+
 		values := getParamValues(r, field)
 
 		// If no values was provided, try to set default values
@@ -71,9 +84,11 @@ func ParseAndValidateParams[Params any](r *http.Request) (Params, error) {
 	}
 
 	if len(validationErrors) == 0 {
-		if valErrs := validation.ValidateStruct(instance); len(valErrs) > 0 {
-			validationErrors = append(validationErrors, valErrs...)
-		}
+		normalizeStrings(&instance, requestSettings)
+		validationErrors = paramErrors(validateModel(instance, r.Method, failFast, simbaContext.GetLocale(r.Context())))
+	}
+	if failFast && len(validationErrors) > 1 {
+		validationErrors = validationErrors[:1]
 	}
 
 	if len(validationErrors) > 0 {
@@ -108,6 +123,18 @@ func parseEmbeddedParams(r *http.Request, embeddedInstance any) error {
 			continue
 		}
 
+		// Handle nested objects bound from bracket notation query keys
+		if isDeepObjectField(field) {
+			errs, err := parseDeepObjectParams(r, fieldValue, field.Tag.Get("query"))
+			if err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				return errs[0]
+			}
+			continue
+		}
+
 		values := getParamValues(r, field)
 
 		// If no values were provided, try to set default values
@@ -131,34 +158,140 @@ func parseEmbeddedParams(r *http.Request, embeddedInstance any) error {
 }
 
 // getParamValues returns the parameter value based on the struct tag.
+// Returns nil if the parameter was not sent, or was sent empty and empty parameters are treated as missing.
 func getParamValues(r *http.Request, field reflect.StructField) []string {
 	switch {
 	case field.Tag.Get("header") != "":
-		return []string{r.Header.Get(field.Tag.Get("header"))}
+		values := r.Header.Values(field.Tag.Get("header"))
+		if len(values) == 0 {
+			return nil
+		}
+		if field.Type.Kind() == reflect.Slice {
+			return withoutEmptyParams(r, getHeaderListValues(values))
+		}
+		return withoutEmptyParams(r, values[:1])
 	case field.Tag.Get("cookie") != "":
 		cookie, err := r.Cookie(field.Tag.Get("cookie"))
 		if err != nil {
 			return nil
 		}
-		return []string{cookie.Value}
+		return withoutEmptyParams(r, []string{cookie.Value})
 	case field.Tag.Get("path") != "":
 		paramName := field.Tag.Get("path")
-		return []string{r.PathValue(paramName)}
+		return withoutEmptyParams(r, []string{r.PathValue(paramName)})
 	case field.Tag.Get("query") != "":
-		queryValues := r.URL.Query()[field.Tag.Get("query")]
-		if len(queryValues) == 0 {
-			return nil
-		}
-		// Split comma-separated values
-		var result []string
-		for _, value := range queryValues {
-			result = append(result, strings.Split(value, ",")...)
+		return withoutEmptyParams(r, getQueryValues(r, field.Tag.Get("query")))
+	}
+	return nil
+}
+
+// withoutEmptyParams returns nil if all values are empty and empty parameters are treated as missing.
+func withoutEmptyParams(r *http.Request, values []string) []string {
+	if getConfigurationFromContext(r.Context()).EmptyParams == models.EmptyParamsAsPresent {
+		return values
+	}
+	for _, value := range values {
+		if value != "" {
+			return values
 		}
-		return result
 	}
 	return nil
 }
 
+// getHeaderListValues returns the elements of a header sent as repeated header lines, comma-separated
+// values or both. Empty elements are ignored, as for list headers in HTTP such as Accept-Language,
+// so a header without elements is treated as not sent.
+func getHeaderListValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for element := range strings.SplitSeq(value, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				result = append(result, element)
+			}
+		}
+	}
+	return result
+}
+
+// getQueryValues returns the values of a query parameter, splitting comma-separated values.
+func getQueryValues(r *http.Request, name string) []string {
+	queryValues := r.URL.Query()[name]
+	if len(queryValues) == 0 {
+		return nil
+	}
+	// Split comma-separated values
+	var result []string
+	for _, value := range queryValues {
+		result = append(result, strings.Split(value, ",")...)
+	}
+	return result
+}
+
+// isDeepObjectField reports whether a query field is a nested object bound from bracket
+// notation keys, such as filter[status]=open, rather than a single value.
+func isDeepObjectField(field reflect.StructField) bool {
+	if field.Tag.Get("query") == "" || field.Type.Kind() != reflect.Struct {
+		return false
+	}
+	switch field.Type {
+	case reflect.TypeFor[time.Time](), reflect.TypeFor[uuid.UUID]():
+		return false
+	}
+	if _, ok := DecimalParser(field.Type); ok {
+		return false
+	}
+	return !reflect.PointerTo(field.Type).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// parseDeepObjectParams binds query keys of the form prefix[name] into the fields of a nested struct.
+// Nested objects can be nested further, e.g. filter[owner][name]=me.
+// Returns an error if a default value is invalid, and validation errors for values that can't be parsed.
+func parseDeepObjectParams(r *http.Request, structValue reflect.Value, prefix string) ([]validation.ValidationError, error) {
+	var validationErrors []validation.ValidationError
+	t := structValue.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := structValue.Field(i)
+
+		name := field.Tag.Get("query")
+		if name == "" || !fieldValue.CanSet() {
+			continue
+		}
+		key := prefix + "[" + name + "]"
+
+		if isDeepObjectField(field) {
+			errs, err := parseDeepObjectParams(r, fieldValue, key)
+			if err != nil {
+				return nil, err
+			}
+			validationErrors = append(validationErrors, errs...)
+			continue
+		}
+
+		values := withoutEmptyParams(r, getQueryValues(r, key))
+
+		// If no values were provided, try to set default values
+		if len(values) == 0 {
+			if err := setDefaultValue(fieldValue, field); err != nil {
+				return nil, simbaErrors.NewSimbaError(
+					http.StatusInternalServerError,
+					"invalid default values",
+					err,
+				).WithDetails(err.Error())
+			}
+			continue
+		}
+
+		if validationErr := setFieldValue(fieldValue, values, field); validationErr != nil {
+			validationErr.Field = key
+			validationErrors = append(validationErrors, *validationErr)
+		}
+	}
+
+	return validationErrors, nil
+}
+
 // getFieldName returns the parameter name from struct tags.
 func getFieldName(field reflect.StructField) string {
 	if header := field.Tag.Get("header"); header != "" {
@@ -203,12 +336,22 @@ func setFieldValue(fieldValue reflect.Value, values []string, field reflect.Stru
 	return &validation.ValidationError{
 		Field: getFieldName(field),
 		Err:   fmt.Errorf("unsupported field type: %v", fieldValue.Kind()).Error(),
+		Code:  "unsupported_type",
 	}
 }
 
 // setSingleValue converts and sets a string value to the appropriate field type.
 func setSingleValue(fieldValue reflect.Value, value string, field reflect.StructField) *validation.ValidationError {
-	if value == "" {
+	if parse, ok := DecimalParser(fieldValue.Type()); ok && value != "" {
+		decimal, err := parse(value)
+		if err != nil {
+			return &validation.ValidationError{
+				Field: getFieldName(field),
+				Err:   fmt.Errorf("invalid decimal parameter value: %s", value).Error(),
+				Code:  "invalid_type",
+			}
+		}
+		fieldValue.Set(reflect.ValueOf(decimal))
 		return nil
 	}
 
@@ -219,6 +362,11 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 		return setSingleValue(fieldValue.Elem(), value, field)
 	}
 
+	// An empty value that is treated as present binds the zero value
+	if value == "" {
+		return nil
+	}
+
 	var err error
 	switch fieldValue.Type().String() {
 	case "time.Time":
@@ -231,16 +379,29 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 			return &validation.ValidationError{
 				Field: getFieldName(field),
 				Err:   fmt.Errorf("invalid time parameter value: %s", value).Error(),
+				Code:  "invalid_type",
 			}
 		}
 		fieldValue.Set(reflect.ValueOf(timeVal))
 		return nil
+	case "time.Duration":
+		var durationVal time.Duration
+		if durationVal, err = time.ParseDuration(value); err != nil {
+			return &validation.ValidationError{
+				Field: getFieldName(field),
+				Err:   fmt.Errorf("invalid duration parameter value: %s", value).Error(),
+				Code:  "invalid_type",
+			}
+		}
+		fieldValue.SetInt(int64(durationVal))
+		return nil
 	case "uuid.UUID":
 		var uuidVal uuid.UUID
 		if uuidVal, err = uuid.Parse(value); err != nil {
 			return &validation.ValidationError{
 				Field: getFieldName(field),
 				Err:   fmt.Errorf("invalid UUID parameter value: %s", value).Error(),
+				Code:  "invalid_type",
 			}
 		}
 		fieldValue.Set(reflect.ValueOf(uuidVal))
@@ -256,6 +417,7 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 				return &validation.ValidationError{
 					Field: fieldName,
 					Err:   fmt.Errorf("invalid value %s for %s", value, fieldName).Error(),
+					Code:  "invalid_type",
 				}
 			}
 			return nil
@@ -271,6 +433,7 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 			return &validation.ValidationError{
 				Field: getFieldName(field),
 				Err:   fmt.Errorf("invalid int parameter value: %s", value).Error(),
+				Code:  "invalid_type",
 			}
 		}
 		fieldValue.SetInt(intVal)
@@ -281,6 +444,7 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 			return &validation.ValidationError{
 				Field: getFieldName(field),
 				Err:   fmt.Errorf("invalid bool parameter value: %s", value).Error(),
+				Code:  "invalid_type",
 			}
 		}
 		fieldValue.SetBool(boolVal)
@@ -291,6 +455,7 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 			return &validation.ValidationError{
 				Field: getFieldName(field),
 				Err:   fmt.Errorf("invalid float parameter value: %s", value).Error(),
+				Code:  "invalid_type",
 			}
 		}
 		fieldValue.SetFloat(floatVal)
@@ -299,6 +464,7 @@ func setSingleValue(fieldValue reflect.Value, value string, field reflect.Struct
 		return &validation.ValidationError{
 			Field: getFieldName(field),
 			Err:   fmt.Errorf("unsupported field type: %v", fieldValue.Kind()).Error(),
+			Code:  "unsupported_type",
 		}
 	}
 
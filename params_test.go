@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/sillen102/simba"
 	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
 	"github.com/sillen102/simba/simbaErrors"
 	"github.com/sillen102/simba/simbaTest"
 	"github.com/sillen102/simba/simbaTest/assert"
@@ -290,6 +292,89 @@ func TestFloatParameters(t *testing.T) {
 	assert.Equal(t, "request validation failed", errorResponse.Message)
 }
 
+func TestDurationParameters(t *testing.T) {
+	t.Parallel()
+
+	type DurationParams struct {
+		Interval time.Duration   `path:"interval"`
+		Timeout  time.Duration   `query:"timeout" default:"30s"`
+		Retry    *time.Duration  `header:"X-Retry-After"`
+		Backoff  []time.Duration `query:"backoff"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, DurationParams]) (*models.Response[map[string]any], error) {
+		body := map[string]any{
+			"interval": req.Params.Interval.String(),
+			"timeout":  req.Params.Timeout.String(),
+			"backoff":  fmt.Sprint(req.Params.Backoff),
+		}
+		if req.Params.Retry != nil {
+			body["retry"] = req.Params.Retry.String()
+		}
+		return &models.Response[map[string]any]{Body: body}, nil
+	}
+
+	app := simba.New()
+	app.Router.GET("/test/{interval}", simba.JsonHandler(handler))
+
+	testCases := []struct {
+		name           string
+		target         string
+		retry          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid durations",
+			target:         "/test/1h30m?timeout=500ms&backoff=1s,2s",
+			retry:          "10s",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"backoff":"[1s 2s]","interval":"1h30m0s","retry":"10s","timeout":"500ms"}`,
+		},
+		{
+			name:           "default duration",
+			target:         "/test/5m",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"backoff":"[]","interval":"5m0s","timeout":"30s"}`,
+		},
+		{
+			name:           "invalid query duration",
+			target:         "/test/5m?timeout=30",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"invalid duration parameter value: 30"`,
+		},
+		{
+			name:           "invalid path duration",
+			target:         "/test/soon",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"interval"`,
+		},
+		{
+			name:           "invalid header duration",
+			target:         "/test/5m",
+			retry:          "later",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"X-Retry-After"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.retry != "" {
+				req.Header.Set("X-Retry-After", tt.retry)
+			}
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.True(t, strings.Contains(w.Body.String(), tt.expectedBody), w.Body.String())
+		})
+	}
+}
+
 func TestInvalidParameterTypes(t *testing.T) {
 	t.Parallel()
 
@@ -468,6 +553,80 @@ func TestPointerQueryParameters(t *testing.T) {
 	})
 }
 
+func TestDeepObjectQueryParameters(t *testing.T) {
+	t.Parallel()
+
+	type Owner struct {
+		Name string `query:"name"`
+	}
+
+	type Filter struct {
+		Status string   `query:"status" validate:"omitempty,oneof=open closed"`
+		Limit  int      `query:"limit" default:"10"`
+		Tags   []string `query:"tags"`
+		Owner  Owner    `query:"owner"`
+	}
+
+	type DeepObjectParams struct {
+		Filter Filter `query:"filter"`
+		Page   int    `query:"page"`
+	}
+
+	newApp := func(handler func(ctx context.Context, req *models.Request[models.NoBody, DeepObjectParams]) (*models.Response[models.NoBody], error)) *simba.Application {
+		app := simba.New()
+		app.Router.GET("/test", simba.JsonHandler(handler))
+		return app
+	}
+
+	t.Run("binds bracket notation keys into nested struct", func(t *testing.T) {
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, DeepObjectParams]) (*models.Response[models.NoBody], error) {
+			assert.Equal(t, DeepObjectParams{
+				Filter: Filter{
+					Status: "open",
+					Limit:  10,
+					Tags:   []string{"a", "b"},
+					Owner:  Owner{Name: "me"},
+				},
+				Page: 2,
+			}, req.Params)
+			return &models.Response[models.NoBody]{Status: http.StatusOK}, nil
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/test?filter[status]=open&filter[tags]=a,b&filter[owner][name]=me&page=2", nil)
+		w := httptest.NewRecorder()
+		newApp(handler).Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("reports invalid nested values with bracket notation field", func(t *testing.T) {
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, DeepObjectParams]) (*models.Response[models.NoBody], error) {
+			return &models.Response[models.NoBody]{Status: http.StatusOK}, nil
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/test?filter[limit]=many", nil)
+		w := httptest.NewRecorder()
+		newApp(handler).Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errorResponse simbaErrors.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+		assert.Contains(t, "filter[limit]", fmt.Sprint(errorResponse.Details))
+	})
+
+	t.Run("validates nested values", func(t *testing.T) {
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, DeepObjectParams]) (*models.Response[models.NoBody], error) {
+			return &models.Response[models.NoBody]{Status: http.StatusOK}, nil
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/test?filter[status]=unknown", nil)
+		w := httptest.NewRecorder()
+		newApp(handler).Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 // CustomID is a type that implements TextMarshaler and TextUnmarshaler
 type CustomID string
 
@@ -532,6 +691,64 @@ func TestTextUnmarshalerParameters(t *testing.T) {
 	})
 }
 
+func TestWildcardPathParams(t *testing.T) {
+	t.Parallel()
+
+	type wildcardParams struct {
+		Bucket string `path:"bucket"`
+		Path   string `path:"path"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, wildcardParams]) (*models.Response[wildcardParams], error) {
+		return &models.Response[wildcardParams]{Body: req.Params}, nil
+	}
+
+	app := simba.New()
+	app.Router.GET("/buckets/{bucket}/files/{path...}", simba.JsonHandler(handler))
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected wildcardParams
+	}{
+		{
+			name:     "single segment",
+			path:     "/buckets/docs/files/readme.md",
+			expected: wildcardParams{Bucket: "docs", Path: "readme.md"},
+		},
+		{
+			name:     "multiple segments",
+			path:     "/buckets/docs/files/guides/v1/intro.md",
+			expected: wildcardParams{Bucket: "docs", Path: "guides/v1/intro.md"},
+		},
+		{
+			name:     "escaped slash",
+			path:     "/buckets/docs/files/a%2Fb.md",
+			expected: wildcardParams{Bucket: "docs", Path: "a/b.md"},
+		},
+		{
+			name:     "empty remainder",
+			path:     "/buckets/docs/files/",
+			expected: wildcardParams{Bucket: "docs", Path: ""},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var params wildcardParams
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &params))
+			assert.Equal(t, tt.expected, params)
+		})
+	}
+}
+
 func TestCookieParams(t *testing.T) {
 	t.Parallel()
 
@@ -589,3 +806,225 @@ func TestCookieParams(t *testing.T) {
 		})
 	}
 }
+
+func TestHeaderSliceParams(t *testing.T) {
+	t.Parallel()
+
+	type headerParams struct {
+		Languages []string `header:"Accept-Language" validate:"max=3,dive,min=2"`
+		Features  []string `header:"X-Feature"`
+		Limits    []int    `header:"X-Limit"`
+		Tenant    string   `header:"X-Tenant"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, headerParams]) (*models.Response[map[string]any], error) {
+		return &models.Response[map[string]any]{Body: map[string]any{
+			"languages": req.Params.Languages,
+			"features":  req.Params.Features,
+			"limits":    req.Params.Limits,
+			"tenant":    req.Params.Tenant,
+		}}, nil
+	}
+
+	app := simba.New()
+	app.Router.GET("/test", simba.JsonHandler(handler))
+
+	testCases := []struct {
+		name           string
+		headers        http.Header
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "repeated headers",
+			headers: http.Header{
+				"X-Feature": {"beta", "dark-mode"},
+				"X-Tenant":  {"acme", "other"},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"features":["beta","dark-mode"],"languages":null,"limits":null,"tenant":"acme"}`,
+		},
+		{
+			name: "comma-separated and repeated headers",
+			headers: http.Header{
+				"Accept-Language": {"en-US, sv", "de"},
+				"X-Limit":         {"10,20"},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"features":null,"languages":["en-US","sv","de"],"limits":[10,20],"tenant":""}`,
+		},
+		{
+			name:           "empty elements are ignored",
+			headers:        http.Header{"X-Feature": {" , beta,"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"features":["beta"],"languages":null,"limits":null,"tenant":""}`,
+		},
+		{
+			name:           "element validation",
+			headers:        http.Header{"Accept-Language": {"en", "x"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"Languages[1]"`,
+		},
+		{
+			name:           "slice validation",
+			headers:        http.Header{"Accept-Language": {"en, sv, de, fi"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"Languages"`,
+		},
+		{
+			name:           "invalid element",
+			headers:        http.Header{"X-Limit": {"10", "many"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"X-Limit"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header = tt.headers
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.True(t, strings.Contains(w.Body.String(), tt.expectedBody), w.Body.String())
+		})
+	}
+}
+
+func TestEmptyParams(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		Name   *string `query:"name" validate:"required"`
+		Tenant *string `header:"X-Tenant" validate:"required"`
+		Token  *string `cookie:"token" validate:"required"`
+		Sort   string  `query:"sort" default:"asc"`
+		Page   int     `query:"page" default:"1"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, params]) (*models.Response[map[string]any], error) {
+		return &models.Response[map[string]any]{
+			Body: map[string]any{
+				"name":   *req.Params.Name,
+				"tenant": *req.Params.Tenant,
+				"token":  *req.Params.Token,
+				"sort":   req.Params.Sort,
+				"page":   req.Params.Page,
+			},
+		}, nil
+	}
+
+	testCases := []struct {
+		name           string
+		mode           models.EmptyParams
+		target         string
+		tenant         string
+		token          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "empty query is missing",
+			mode:           models.EmptyParamsAsMissing,
+			target:         "/test?name=",
+			tenant:         "acme",
+			token:          "secret",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"Name"`,
+		},
+		{
+			name:           "empty header is missing",
+			mode:           models.EmptyParamsAsMissing,
+			target:         "/test?name=john",
+			tenant:         "",
+			token:          "secret",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"Tenant"`,
+		},
+		{
+			name:           "empty cookie is missing",
+			mode:           models.EmptyParamsAsMissing,
+			target:         "/test?name=john",
+			tenant:         "acme",
+			token:          "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"Token"`,
+		},
+		{
+			name:           "missing applies defaults to empty values",
+			mode:           models.EmptyParamsAsMissing,
+			target:         "/test?name=john&sort=&page=",
+			tenant:         "acme",
+			token:          "secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"john","page":1,"sort":"asc","tenant":"acme","token":"secret"}`,
+		},
+		{
+			name:           "empty values are present",
+			mode:           models.EmptyParamsAsPresent,
+			target:         "/test?name=&sort=&page=",
+			tenant:         "",
+			token:          "",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"","page":0,"sort":"","tenant":"","token":""}`,
+		},
+		{
+			name:           "absent values are missing when empty values are present",
+			mode:           models.EmptyParamsAsPresent,
+			target:         "/test",
+			tenant:         "",
+			token:          "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"field":"Name"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := simba.New(settings.WithEmptyParams(tt.mode))
+			app.Router.GET("/test", simba.JsonHandler(handler))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.target != "/test" {
+				req.Header.Set("X-Tenant", tt.tenant)
+				req.AddCookie(&http.Cookie{Name: "token", Value: tt.token})
+			}
+
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.True(t, strings.Contains(w.Body.String(), tt.expectedBody))
+		})
+	}
+}
+
+func TestFuzzParams(t *testing.T) {
+	t.Parallel()
+
+	type Embedded struct {
+		Tenant string `header:"X-Tenant"`
+	}
+
+	type params struct {
+		Embedded
+		ID      uuid.UUID     `path:"id"`
+		Version int           `path:"version" validate:"min=1" example:"2"`
+		Since   time.Time     `query:"since" format:"2006-01-02"`
+		Limit   *int64        `query:"limit"`
+		Score   float64       `query:"score"`
+		Active  bool          `query:"active"`
+		Tags    []int         `query:"tags"`
+		Session string        `cookie:"session"`
+		Level   slog.Level    `query:"level" example:"WARN"`
+		Timeout time.Duration `query:"timeout"`
+	}
+
+	simbaTest.FuzzParams[params](t, 42, 5)
+	simbaTest.FuzzParams[simbaTest.Params](t, 7, 5)
+}
@@ -0,0 +1,77 @@
+package simba
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// pathPattern constrains the values of a path param to a regular expression.
+type pathPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// enforcePathPatterns rejects Requests whose path params don't match the regular expression in the pattern tag
+// of their params field with a 404 Not Found, before the handler is called, since Go's ServeMux doesn't constrain
+// path segments. Patterns follow JSON Schema semantics and match anywhere in the value unless anchored with ^ and $.
+// An invalid pattern panics when the route is registered.
+func enforcePathPatterns(params any, handler http.Handler) http.Handler {
+	patterns := pathPatterns(reflect.TypeOf(params))
+	if len(patterns) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range patterns {
+			if !p.pattern.MatchString(r.PathValue(p.name)) {
+				simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
+					http.StatusNotFound,
+					"not found",
+					fmt.Errorf("path param %s does not match pattern %s", p.name, p.pattern),
+				))
+				return
+			}
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// pathPatterns returns the patterns of the path params of the params type, including embedded params.
+func pathPatterns(t reflect.Type) []pathPattern {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var patterns []pathPattern
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			patterns = append(patterns, pathPatterns(field.Type)...)
+			continue
+		}
+
+		name, pattern := field.Tag.Get("path"), field.Tag.Get("pattern")
+		if name == "" || pattern == "" {
+			continue
+		}
+
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			panic(fmt.Sprintf("invalid pattern of path param %s: %v", name, err))
+		}
+		patterns = append(patterns, pathPattern{name: name, pattern: compiled})
+	}
+	return patterns
+}
@@ -0,0 +1,84 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type OrderID struct {
+	ID int `path:"id" pattern:"^[0-9]+$"`
+}
+
+type orderItemParams struct {
+	OrderID
+	SKU string `path:"sku" pattern:"^[A-Z]{3}-[0-9]{4}$"`
+}
+
+func TestPathPatterns(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, orderItemParams]) (*models.Response[map[string]any], error) {
+		return &models.Response[map[string]any]{Body: map[string]any{"id": req.Params.ID, "sku": req.Params.SKU}}, nil
+	}
+
+	app := simba.New()
+	app.Router.GET("/orders/{id}/items/{sku}", simba.JsonHandler(handler))
+
+	tests := []struct {
+		name     string
+		target   string
+		expected int
+	}{
+		{name: "matching params", target: "/orders/42/items/ABC-1234", expected: http.StatusOK},
+		{name: "param from embedded params not matching", target: "/orders/abc/items/ABC-1234", expected: http.StatusNotFound},
+		{name: "param not matching", target: "/orders/42/items/abc-1234", expected: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+
+	t.Run("documented in OpenAPI", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New()
+		app.Router.GET("/orders/{id}/items/{sku}", simba.JsonHandler(handler))
+		assert.NoError(t, app.Router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Contains(t, `"pattern":"^[0-9]+$"`, w.Body.String())
+		assert.Contains(t, `"pattern":"^[A-Z]{3}-[0-9]{4}$"`, w.Body.String())
+	})
+
+	t.Run("invalid pattern panics", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := func(ctx context.Context, req *models.Request[models.NoBody, struct {
+			ID string `path:"id" pattern:"[0-9"`
+		}]) (*models.Response[models.NoBody], error) {
+			return &models.Response[models.NoBody]{}, nil
+		}
+
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+		simba.New().Router.GET("/orders/{id}", simba.JsonHandler(invalid))
+	})
+}
@@ -0,0 +1,64 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (p *pushRecorder) Push(target string, _ *http.PushOptions) error {
+	p.pushed = append(p.pushed, target)
+	return nil
+}
+
+func TestPreload(t *testing.T) {
+	t.Parallel()
+
+	app := simba.Default()
+	app.Router.GET("/", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{
+			Body: map[string]string{"page": "home"},
+			Preload: []models.Preload{
+				{URL: "/static/app.js", As: "script", Push: true},
+				{URL: "/static/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: true},
+			},
+		}, nil
+	}))
+
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{
+		"</static/app.js>; rel=preload; as=script",
+		`</static/inter.woff2>; rel=preload; as=font; type="font/woff2"; crossorigin`,
+	}, w.Header().Values("Link"))
+	assert.Equal(t, []string{"/static/app.js"}, w.pushed)
+}
+
+func TestPreloadWithoutPushSupport(t *testing.T) {
+	t.Parallel()
+
+	app := simba.New()
+	app.Router.GET("/", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{
+			Preload: []models.Preload{{URL: "/static/app.css", As: "style", Push: true}},
+		}, nil
+	}))
+
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "</static/app.css>; rel=preload; as=style", w.Header().Get("Link"))
+}
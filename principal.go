@@ -0,0 +1,35 @@
+package simba
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// PrincipalFromContext retrieves the principal authenticated by an auth handler, such as the auth model
+// passed to an [AuthJsonHandler], from the context. The principal is available in the context passed to
+// the handler and to anything called with it. The second return value is false if the request was not
+// authenticated or the principal is not of type T.
+//
+//	Example usage:
+//
+//	user, ok := simba.PrincipalFromContext[User](ctx)
+//	if !ok {
+//		return errors.New("not authenticated")
+//	}
+func PrincipalFromContext[T any](ctx context.Context) (T, bool) {
+	principal, ok := simbaContext.GetPrincipal(ctx).(T)
+	return principal, ok
+}
+
+// withPrincipal adds the auth model to the request context, together with its roles if it
+// implements [auth.RoleProvider].
+func withPrincipal[AuthModel any](r *http.Request, authModel AuthModel) *http.Request {
+	ctx := simbaContext.WithPrincipal(r.Context(), authModel)
+	if provider, ok := any(authModel).(auth.RoleProvider); ok {
+		ctx = simbaContext.WithRoles(ctx, provider.Roles()...)
+	}
+	return r.WithContext(ctx)
+}
@@ -0,0 +1,70 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestPrincipalFromContext(t *testing.T) {
+	t.Parallel()
+
+	// currentUserName is called by the handler with its context, without the principal being passed
+	currentUserName := func(ctx context.Context) string {
+		user, ok := simba.PrincipalFromContext[*simbaTest.User](ctx)
+		if !ok {
+			return "anonymous"
+		}
+		return user.Name
+	}
+
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	app := simba.New()
+	app.Router.GET("/me", simba.AuthJsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], user *simbaTest.User) (*models.Response[response], error) {
+		return &models.Response[response]{Body: response{Name: currentUserName(ctx)}}, nil
+	}, simbaTest.BearerAuthAuthenticationHandler))
+	app.Router.GET("/anonymous", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[response], error) {
+		return &models.Response[response]{Body: response{Name: currentUserName(ctx)}}, nil
+	}))
+
+	t.Run("authenticated", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{\"name\":\"John Doe\"}\n", w.Body.String())
+	})
+
+	t.Run("not authenticated", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/anonymous", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{\"name\":\"anonymous\"}\n", w.Body.String())
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := simbaContext.WithPrincipal(context.Background(), &simbaTest.User{ID: 1, Name: "John Doe", Role: "admin"})
+		_, ok := simba.PrincipalFromContext[simbaTest.User](ctx)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,40 @@
+package simba
+
+import (
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+)
+
+// WithResponseContentType documents an additional media type the handler can respond with for
+// its success status, such as CSV next to JSON for handlers that negotiate the response format.
+// The body is used as the schema for the media type. Can be applied several times.
+//
+//	Example usage:
+//
+//	app.Router.GET("/reports", simba.WithResponseContentType(simba.JsonHandler(getReport), "text/csv", ""))
+func WithResponseContentType(handler Handler, contentType string, body any) Handler {
+	response := openapiModels.ResponseContent{ContentType: contentType, Body: body}
+
+	if h, ok := handler.(alternativeResponseHandler); ok {
+		responses := append(h.responses[:len(h.responses):len(h.responses)], response)
+		return alternativeResponseHandler{Handler: h.Handler, responses: responses}
+	}
+
+	return alternativeResponseHandler{Handler: handler, responses: []openapiModels.ResponseContent{response}}
+}
+
+// alternativeResponseHandler decorates a [Handler] with additional documented response media types.
+type alternativeResponseHandler struct {
+	Handler
+	responses []openapiModels.ResponseContent
+}
+
+func (h alternativeResponseHandler) GetAlternativeResponses() []openapiModels.ResponseContent {
+	return h.responses
+}
+
+func (h alternativeResponseHandler) ShouldDocument() bool {
+	if controller, ok := h.Handler.(routeDocumentationController); ok {
+		return controller.ShouldDocument()
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package simba
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// byteRange is a range of bytes of a response, from start to end inclusive.
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+// length returns the number of bytes in the range.
+func (br byteRange) length() int64 {
+	return br.end - br.start + 1
+}
+
+// contentRange returns the Content-Range header of the range of content with the given size.
+func (br byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", br.start, br.end, size)
+}
+
+// requestedRange returns the single byte range requested of content with the given size, and whether one was.
+// No range is requested if the request has no Range header, requests several ranges, or has an If-Range header
+// that doesn't match the ETag response header or modTime, since the content has then changed and must be sent
+// in full. Returns an error with status 416 if the range is invalid or not satisfiable.
+func requestedRange(r *http.Request, header http.Header, size int64, modTime time.Time) (byteRange, bool, error) {
+	spec, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes=")
+	if !ok || strings.Contains(spec, ",") || !ifRangeMatches(r, header, modTime) {
+		return byteRange{}, false, nil
+	}
+
+	br, ok := parseByteRange(strings.TrimSpace(spec), size)
+	switch {
+	case !ok:
+		return byteRange{}, false, simbaErrors.NewSimbaError(http.StatusRequestedRangeNotSatisfiable, "invalid range", nil)
+	case br.start >= size:
+		return byteRange{}, false, simbaErrors.NewSimbaError(
+			http.StatusRequestedRangeNotSatisfiable,
+			"requested range not satisfiable",
+			nil,
+		).WithHeaders(http.Header{"Content-Range": {fmt.Sprintf("bytes */%d", size)}})
+	}
+	return br, true, nil
+}
+
+// parseByteRange parses a byte range spec, such as "0-499", "500-" or "-500", of content with the given size.
+// An end beyond the content is clamped to its last byte. Returns false if the spec is invalid.
+func parseByteRange(spec string, size int64) (byteRange, bool) {
+	first, last, ok := strings.Cut(spec, "-")
+	if !ok {
+		return byteRange{}, false
+	}
+
+	if first == "" {
+		// A suffix range of the last bytes of the content
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix <= 0 {
+			return byteRange{}, false
+		}
+		return byteRange{start: max(size-suffix, 0), end: size - 1}, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return byteRange{}, false
+	}
+	if last == "" {
+		return byteRange{start: start, end: size - 1}, true
+	}
+
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return byteRange{}, false
+	}
+	return byteRange{start: start, end: min(end, size-1)}, true
+}
+
+// ifRangeMatches reports whether the Range header of the request applies, which it does unless the If-Range
+// header holds a validator that doesn't match the strong ETag of the response or its modification time.
+func ifRangeMatches(r *http.Request, header http.Header, modTime time.Time) bool {
+	ifRange := r.Header.Get("If-Range")
+	if ifRange == "" {
+		return true
+	}
+
+	if strings.HasPrefix(ifRange, `"`) {
+		etag := header.Get("ETag")
+		return etag != "" && !strings.HasPrefix(etag, "W/") && etag == ifRange
+	}
+
+	if modTime.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifRange)
+	return err == nil && modTime.Truncate(time.Second).Equal(since)
+}
@@ -0,0 +1,152 @@
+package simba_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestFileResponseRange(t *testing.T) {
+	t.Parallel()
+
+	const content = "%PDF-1.7"
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Ranges are answered the same whether the content can seek or is streamed with a known size
+	files := map[string]func() models.File{
+		"seekable": func() models.File {
+			return models.File{Filename: "report.pdf", Content: strings.NewReader(content), ModTime: modTime}
+		},
+		"sized stream": func() models.File {
+			return models.File{Filename: "report.pdf", Content: io.NopCloser(strings.NewReader(content)), Size: int64(len(content)), ModTime: modTime}
+		},
+	}
+
+	tests := []struct {
+		name         string
+		header       http.Header
+		status       int
+		contentRange string
+		body         string
+	}{
+		{
+			name:         "first bytes",
+			header:       http.Header{"Range": {"bytes=0-3"}},
+			status:       http.StatusPartialContent,
+			contentRange: "bytes 0-3/8",
+			body:         "%PDF",
+		},
+		{
+			name:         "open ended",
+			header:       http.Header{"Range": {"bytes=5-"}},
+			status:       http.StatusPartialContent,
+			contentRange: "bytes 5-7/8",
+			body:         "1.7",
+		},
+		{
+			name:         "suffix",
+			header:       http.Header{"Range": {"bytes=-2"}},
+			status:       http.StatusPartialContent,
+			contentRange: "bytes 6-7/8",
+			body:         ".7",
+		},
+		{
+			name:         "end beyond content",
+			header:       http.Header{"Range": {"bytes=4-100"}},
+			status:       http.StatusPartialContent,
+			contentRange: "bytes 4-7/8",
+			body:         "-1.7",
+		},
+		{
+			name:         "start beyond content",
+			header:       http.Header{"Range": {"bytes=8-"}},
+			status:       http.StatusRequestedRangeNotSatisfiable,
+			contentRange: "bytes */8",
+		},
+		{
+			name:   "invalid range",
+			header: http.Header{"Range": {"bytes=5-2"}},
+			status: http.StatusRequestedRangeNotSatisfiable,
+		},
+		{
+			name:         "if-range with the modification time",
+			header:       http.Header{"Range": {"bytes=5-"}, "If-Range": {modTime.Format(http.TimeFormat)}},
+			status:       http.StatusPartialContent,
+			contentRange: "bytes 5-7/8",
+			body:         "1.7",
+		},
+		{
+			name:   "if-range with a stale modification time",
+			header: http.Header{"Range": {"bytes=5-"}, "If-Range": {modTime.Add(-time.Hour).Format(http.TimeFormat)}},
+			status: http.StatusOK,
+			body:   content,
+		},
+		{
+			name:         "if-range with the etag",
+			header:       http.Header{"Range": {"bytes=5-"}, "If-Range": {`"v1"`}},
+			status:       http.StatusPartialContent,
+			contentRange: "bytes 5-7/8",
+			body:         "1.7",
+		},
+		{
+			name:   "if-range with a stale etag",
+			header: http.Header{"Range": {"bytes=5-"}, "If-Range": {`"v0"`}},
+			status: http.StatusOK,
+			body:   content,
+		},
+	}
+
+	for kind, file := range files {
+		for _, tt := range tests {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				app := simba.New()
+				app.Router.GET("/download", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.File], error) {
+					return &models.Response[models.File]{
+						Headers: http.Header{"ETag": {`"v1"`}},
+						Body:    file(),
+					}, nil
+				}))
+
+				req := httptest.NewRequest(http.MethodGet, "/download", nil)
+				req.Header = tt.header
+				w := httptest.NewRecorder()
+				app.Router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.status, w.Code)
+				assert.Equal(t, tt.contentRange, w.Header().Get("Content-Range"))
+				if tt.status != http.StatusRequestedRangeNotSatisfiable {
+					assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+					assert.Equal(t, modTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+					assert.Equal(t, tt.body, w.Body.String())
+				}
+			})
+		}
+	}
+
+	t.Run("several ranges of a sized stream are answered with the whole content", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.New()
+		app.Router.GET("/download", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.File], error) {
+			return &models.Response[models.File]{Body: files["sized stream"]()}, nil
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/download", nil)
+		req.Header.Set("Range", "bytes=0-1,4-5")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, content, w.Body.String())
+	})
+}
@@ -68,7 +68,7 @@ func (h RawBodyHandlerFunc[Params, ResponseBody]) GetAccepts() string {
 }
 
 func (h RawBodyHandlerFunc[Params, ResponseBody]) GetProduces() string {
-	return mimetypes.ApplicationJSON
+	return responseMediaType[ResponseBody]()
 }
 
 func (h RawBodyHandlerFunc[Params, ResponseBody]) GetHandler() any {
@@ -118,6 +118,9 @@ func (h AuthenticatedRawBodyHandlerFunc[Params, AuthModel, ResponseBody]) ServeH
 		return
 	}
 
+	r = withPrincipal(r, authModel)
+	ctx = r.Context()
+
 	req, err := handleRawRequest[Params](r)
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
@@ -153,7 +156,7 @@ func (h AuthenticatedRawBodyHandlerFunc[Params, AuthModel, ResponseBody]) GetAcc
 }
 
 func (h AuthenticatedRawBodyHandlerFunc[Params, AuthModel, ResponseBody]) GetProduces() string {
-	return mimetypes.ApplicationJSON
+	return responseMediaType[ResponseBody]()
 }
 
 func (h AuthenticatedRawBodyHandlerFunc[Params, AuthModel, ResponseBody]) GetHandler() any {
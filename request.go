@@ -32,10 +32,23 @@ func closeRequestBody(next http.Handler) http.Handler {
 	})
 }
 
-// injectRequestSettings injects the application Simba into the Request context.
+// injectRequestSettings injects the application Simba and the client IP into the Request context.
 func injectRequestSettings(next http.Handler, requestSettings *settings.Request) http.Handler {
+	clientIP := newClientIPResolver(requestSettings)
+	locale := newLocaleNegotiator(requestSettings)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := context.WithValue(r.Context(), simbaContext.RequestSettingsKey, requestSettings)
+		ctx = simbaContext.WithClientIP(ctx, clientIP.resolve(r))
+		ctx = simbaContext.WithLocale(ctx, locale.negotiate(r))
+		if requestSettings.ErrorFormatter != nil {
+			ctx = context.WithValue(ctx, simbaContext.ErrorFormatterKey, requestSettings.ErrorFormatter)
+		}
+		if requestSettings.ValidationErrorFormat != "" {
+			ctx = context.WithValue(ctx, simbaContext.ValidationErrorFormatKey, requestSettings.ValidationErrorFormat)
+		}
+		if !requestSettings.TimeFormat.IsDefault() {
+			ctx = context.WithValue(ctx, simbaContext.TimeFormatKey, requestSettings.TimeFormat)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -60,11 +73,8 @@ func handleJsonBody[RequestBody any](r *http.Request, req *RequestBody) error {
 		return nil
 	}
 
-	contentType := r.Header.Get("Content-Type")
-	mediaType, _, err := mime.ParseMediaType(contentType)
-	if err != nil || mediaType != "application/json" {
-		return simbaErrors.ErrInvalidContentType.
-			WithDetails("expected application/json, got: " + contentType)
+	if err := checkJsonContentType(r); err != nil {
+		return err
 	}
 
 	requestSettings := getConfigurationFromContext(r.Context())
@@ -72,11 +82,31 @@ func handleJsonBody[RequestBody any](r *http.Request, req *RequestBody) error {
 		logging.From(r.Context()).Info("request body", "body", r.Body)
 	}
 
-	err = readJson(r.Body, requestSettings, req)
+	return decodeJsonBody(r, r.Body, requestSettings, req)
+}
+
+// checkJsonContentType returns an error if the content type of the request is not "application/json".
+func checkJsonContentType(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return simbaErrors.ErrInvalidContentType.
+			WithDetails("expected application/json, got: " + contentType)
+	}
+	return nil
+}
+
+// decodeJsonBody unmarshalls the JSON body into the model, which must be a pointer, sets the default
+// values of its fields and validates it for the method of the request, with the validation messages in
+// its locale.
+func decodeJsonBody(r *http.Request, body io.ReadCloser, requestSettings *settings.Request, req any) error {
+	err := readJson(body, requestSettings, req)
 	if err != nil {
 		return err
 	}
 
+	normalizeStrings(req, requestSettings)
+
 	// Handle setting defaults on request body fields
 	errs := setDefaultsFromTags(req)
 	if len(errs) > 0 {
@@ -87,16 +117,7 @@ func handleJsonBody[RequestBody any](r *http.Request, req *RequestBody) error {
 		).WithDetails(errs)
 	}
 
-	var validationTarget any = req
-	v := reflect.ValueOf(req)
-	if v.Kind() == reflect.Pointer && !v.IsNil() {
-		elem := v.Elem()
-		if elem.Kind() == reflect.Pointer && !elem.IsNil() {
-			validationTarget = elem.Interface()
-		}
-	}
-
-	if validationErrors := validation.ValidateStruct(validationTarget); len(validationErrors) > 0 {
+	if validationErrors := validateModel(req, r.Method, requestSettings.FailFastValidation, simbaContext.GetLocale(r.Context())); len(validationErrors) > 0 {
 		return simbaErrors.NewSimbaError(
 			http.StatusBadRequest,
 			"request validation failed",
@@ -109,13 +130,29 @@ func handleJsonBody[RequestBody any](r *http.Request, req *RequestBody) error {
 
 // readJson reads the JSON body and unmarshalls it into the model.
 func readJson(body io.ReadCloser, requestSettings *settings.Request, model any) error {
-	decoder := json.NewDecoder(body)
+	if requestSettings.MaxBodySize > 0 {
+		body = http.MaxBytesReader(nil, body, requestSettings.MaxBodySize)
+	}
+
+	depthLimit := newDepthLimitReader(body, requestSettings.MaxJSONDepth)
+	decoder := json.NewDecoder(depthLimit)
 	if !requestSettings.AllowUnknownFields {
 		decoder.DisallowUnknownFields()
 	}
+	if requestSettings.UseNumber {
+		decoder.UseNumber()
+	}
 	err := decoder.Decode(&model)
 	if err != nil {
 
+		if maxBytesError, ok := errors.AsType[*http.MaxBytesError](err); ok {
+			return simbaErrors.NewBodyTooLargeError(maxBytesError.Limit)
+		}
+
+		if depthLimit.err != nil {
+			return simbaErrors.NewBodyTooDeepError(depthLimit.err.Limit)
+		}
+
 		if unmarshalTypeError, ok := errors.AsType[*json.UnmarshalTypeError](err); ok {
 			return simbaErrors.NewSimbaError(
 				http.StatusUnprocessableEntity,
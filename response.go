@@ -2,10 +2,17 @@ package simba
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
+	"strconv"
 
 	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
 	"github.com/sillen102/simba/simbaErrors"
 )
 
@@ -16,9 +23,17 @@ import (
 //  4. Response specific test cases (such as 204 when body is nil and status is 0)
 
 // writeResponse writes the response to the client.
+// Nothing is written if the client has already disconnected.
 func writeResponse[ResponseBody any](w http.ResponseWriter, r *http.Request, resp *models.Response[ResponseBody], err error) {
 	logger := logging.From(r.Context())
 
+	if simbaContext.IsClientCancelled(r.Context()) {
+		logger.Debug("request cancelled by client, skipping response",
+			"reason", simbaContext.ClientCancelledReason,
+		)
+		return
+	}
+
 	if err != nil {
 		simbaErrors.WriteError(w, r, err)
 		return
@@ -46,6 +61,8 @@ func writeResponse[ResponseBody any](w http.ResponseWriter, r *http.Request, res
 		}
 	}
 
+	writePreloads(w, r, resp.Preload)
+
 	var status int
 	switch {
 	case resp.Status != 0:
@@ -53,16 +70,46 @@ func writeResponse[ResponseBody any](w http.ResponseWriter, r *http.Request, res
 	case any(resp.Body) == (models.NoBody{}):
 		status = http.StatusNoContent
 	default:
-		status = http.StatusOK
+		status = successStatus(r)
+	}
+
+	if file, ok := any(resp.Body).(models.File); ok {
+		if err = writeFile(w, r, status, file); err != nil {
+			logger.Error("failed to write file response", "error", err)
+		}
+		return
 	}
 
-	// Return early for No Content responses without a body
-	if status == http.StatusNoContent {
+	if parts, ok := any(resp.Body).(models.Multipart); ok {
+		writeMultipart(w, r, status, parts)
+		return
+	}
+
+	// Return early for responses without a body
+	if status == http.StatusNoContent || any(resp.Body) == (models.NoBody{}) {
 		w.WriteHeader(status)
 		return
 	}
 
-	err = writeJSON(w, status, resp.Body)
+	body, err := applyVisibility(r.Context(), resp.Body)
+	if err != nil {
+		logger.Error("failed to apply field visibility", "error", err)
+		simbaErrors.HandleUnexpectedError(w)
+		return
+	}
+
+	if transform := getConfigurationFromContext(r.Context()).ResponseTransformer; transform != nil {
+		body = transform(r.Context(), body)
+	}
+
+	body, err = applyTimeFormat(r.Context(), body)
+	if err != nil {
+		logger.Error("failed to format times", "error", err)
+		simbaErrors.HandleUnexpectedError(w)
+		return
+	}
+
+	err = writeJSON(w, status, body)
 	if err != nil {
 		logger.Error("failed to write JSON response", "error", err)
 		simbaErrors.HandleUnexpectedError(w)
@@ -70,9 +117,143 @@ func writeResponse[ResponseBody any](w http.ResponseWriter, r *http.Request, res
 	}
 }
 
+// successStatus returns the status of a successful response with a body whose handler doesn't set one,
+// which is the default status configured for the request method or 200.
+func successStatus(r *http.Request) int {
+	if status, ok := getConfigurationFromContext(r.Context()).DefaultStatuses[r.Method]; ok {
+		return status
+	}
+	return http.StatusOK
+}
+
+// responseMediaType returns the media type documented for a response body type.
+// File bodies are documented as binary downloads, multipart bodies as multipart/mixed and all other bodies as JSON.
+func responseMediaType[ResponseBody any]() string {
+	var body ResponseBody
+	switch any(body).(type) {
+	case models.File:
+		return mimetypes.ApplicationOctetStream
+	case models.Multipart:
+		return mimetypes.MultipartMixed
+	default:
+		return mimetypes.ApplicationJSON
+	}
+}
+
+// writeFile streams a file download, setting Content-Type and Content-Disposition
+// unless they have already been set through the response headers. Seekable content is served with
+// http.ServeContent, which sets Content-Length and answers Range and conditional requests.
+func writeFile(w http.ResponseWriter, r *http.Request, status int, file models.File) error {
+	if closer, ok := file.Content.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", fileContentType(file))
+	}
+
+	if w.Header().Get("Content-Disposition") == "" {
+		w.Header().Set("Content-Disposition", fileDisposition(file))
+	}
+
+	if content, ok := file.Content.(io.ReadSeeker); ok && status == http.StatusOK {
+		http.ServeContent(w, r, file.Filename, file.ModTime, content)
+		return nil
+	}
+
+	if file.Content != nil && file.Size > 0 && status == http.StatusOK {
+		return writeSizedFile(w, r, file)
+	}
+
+	if file.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	}
+
+	w.WriteHeader(status)
+	if file.Content == nil {
+		return nil
+	}
+	_, err := io.Copy(w, file.Content)
+	return err
+}
+
+// writeSizedFile streams unseekable content of a known size. A request for a single byte range is answered
+// with 206 Partial Content by skipping the content before the range.
+func writeSizedFile(w http.ResponseWriter, r *http.Request, file models.File) error {
+	w.Header().Set("Accept-Ranges", "bytes")
+	if !file.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", file.ModTime.UTC().Format(http.TimeFormat))
+	}
+
+	br, partial, err := requestedRange(r, w.Header(), file.Size, file.ModTime)
+	if err != nil {
+		w.Header().Del("Content-Disposition")
+		simbaErrors.WriteError(w, r, err)
+		return nil
+	}
+
+	if !partial {
+		w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+		w.WriteHeader(http.StatusOK)
+		_, err = io.Copy(w, file.Content)
+		return err
+	}
+
+	if _, err = io.CopyN(io.Discard, file.Content, br.start); err != nil {
+		w.Header().Del("Content-Disposition")
+		simbaErrors.HandleUnexpectedError(w)
+		return err
+	}
+
+	w.Header().Set("Content-Range", br.contentRange(file.Size))
+	w.Header().Set("Content-Length", strconv.FormatInt(br.length(), 10))
+	w.WriteHeader(http.StatusPartialContent)
+	_, err = io.CopyN(w, file.Content, br.length())
+	return err
+}
+
+// fileContentType returns the content type of the file, inferred from the filename unless set.
+func fileContentType(file models.File) string {
+	if file.ContentType != "" {
+		return file.ContentType
+	}
+	return mimetypes.FromFilename(file.Filename)
+}
+
+// fileDisposition returns the Content-Disposition of the file, attachment or inline with the filename.
+func fileDisposition(file models.File) string {
+	disposition := "attachment"
+	if file.Inline {
+		disposition = "inline"
+	}
+	if file.Filename != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": filepath.Base(file.Filename)})
+	}
+	return disposition
+}
+
 // writeJSON is a helper function for writing JSON responses.
 func writeJSON(w http.ResponseWriter, status int, v any) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	return json.NewEncoder(w).Encode(v)
 }
+
+// writePreloads adds a Link header for each preloaded resource and pushes the resources marked for push when
+// the connection supports HTTP/2 server push.
+func writePreloads(w http.ResponseWriter, r *http.Request, preloads []models.Preload) {
+	if len(preloads) == 0 {
+		return
+	}
+
+	pusher, canPush := w.(http.Pusher)
+	for _, preload := range preloads {
+		w.Header().Add("Link", preload.LinkHeader())
+		if !preload.Push || !canPush {
+			continue
+		}
+		if err := pusher.Push(preload.URL, nil); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logging.From(r.Context()).Debug("failed to push preloaded resource", "url", preload.URL, "error", err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package simba
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/models"
+	oapi "github.com/swaggest/openapi-go"
+)
+
+// minCacheSweepSize is the number of entries a [MemoryResponseCache] holds before expired entries are removed.
+const minCacheSweepSize = 1024
+
+// responseCacheConfig configures the response cache of a route.
+type responseCacheConfig struct {
+	store models.ResponseCacheStore
+	ttl   time.Duration
+	vary  []string
+	// authenticated opts authenticated routes in to caching, see [WithAuthenticatedResponseCache]
+	authenticated bool
+}
+
+// forAuth returns the cache configuration of a route authenticated by the auth handler, which varies on the
+// header the credentials are read from, so cached responses are only served to requests with the same
+// credentials. Returns nil, disabling the cache, unless the route opted in to caching authenticated
+// responses, or for auth handlers that don't tell where credentials are read from, since their responses
+// can't be told apart.
+func (cfg *responseCacheConfig) forAuth(authHandler any) *responseCacheConfig {
+	if !cfg.authenticated {
+		return nil
+	}
+	h, ok := authHandler.(interface {
+		GetFieldName() string
+		GetIn() oapi.In
+	})
+	if !ok {
+		return nil
+	}
+
+	// API key handlers read the key from the header named by the field, wherever it is documented to be
+	header := h.GetFieldName()
+	if h.GetIn() == oapi.InCookie {
+		header = "Cookie"
+	}
+	return &responseCacheConfig{
+		store:         cfg.store,
+		ttl:           cfg.ttl,
+		vary:          append(slices.Clone(cfg.vary), header),
+		authenticated: cfg.authenticated,
+	}
+}
+
+// cacheResponses returns middleware that serves GET requests from the cache and caches successful responses.
+func cacheResponses(cfg responseCacheConfig) func(http.Handler) http.Handler {
+	vary := make([]string, len(cfg.vary))
+	for i, header := range cfg.vary {
+		vary[i] = http.CanonicalHeaderKey(header)
+	}
+	slices.Sort(vary)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestDirectives := r.Header.Get("Cache-Control")
+			if r.Method != http.MethodGet || cfg.ttl <= 0 || !cacheableRequest(r, vary) || hasCacheDirective(requestDirectives, "no-store") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := logging.From(ctx)
+			key := responseCacheKey(r, vary)
+
+			// A client sending no-cache wants a fresh response, which then replaces the cached one
+			if !hasCacheDirective(requestDirectives, "no-cache") {
+				cached, ok, err := cfg.store.Get(ctx, key)
+				if err != nil {
+					logger.Error("failed to get cached response", "error", err)
+				}
+				if ok {
+					writeCachedResponse(w, cached)
+					return
+				}
+			}
+
+			recorder := newResponseRecorder()
+			next.ServeHTTP(recorder, r)
+
+			if cacheableResponse(recorder) {
+				err := cfg.store.Set(ctx, key, models.CachedResponse{
+					Status:  recorder.status,
+					Headers: recorder.header.Clone(),
+					Body:    slices.Clone(recorder.body.Bytes()),
+				}, cfg.ttl)
+				if err != nil {
+					logger.Error("failed to cache response", "error", err)
+				}
+			}
+
+			recorder.header.Set("X-Cache", "MISS")
+			recorder.writeTo(w)
+		})
+	}
+}
+
+// cacheableRequest reports whether the request can be served from the cache. Requests with credentials
+// are only cached if the credential headers are part of the cache key.
+func cacheableRequest(r *http.Request, vary []string) bool {
+	for _, header := range []string{"Authorization", "Cookie"} {
+		if r.Header.Get(header) != "" && !slices.Contains(vary, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheableResponse reports whether the response can be cached.
+func cacheableResponse(recorder *responseRecorder) bool {
+	if recorder.status != http.StatusOK || recorder.header.Get("Set-Cookie") != "" {
+		return false
+	}
+
+	directives := recorder.header.Get("Cache-Control")
+	return !hasCacheDirective(directives, "no-store") &&
+		!hasCacheDirective(directives, "no-cache") &&
+		!hasCacheDirective(directives, "private")
+}
+
+// responseCacheKey returns the cache key of the request, made up of the path, query and the vary headers.
+// Header values are hashed, so credentials such as bearer tokens and session cookies aren't written to the store.
+func responseCacheKey(r *http.Request, vary []string) string {
+	var key strings.Builder
+	key.WriteString(r.URL.RequestURI())
+	for _, header := range vary {
+		sum := sha256.Sum256([]byte(strings.Join(r.Header.Values(header), ",")))
+		key.WriteString("\n")
+		key.WriteString(header)
+		key.WriteString(": ")
+		key.WriteString(hex.EncodeToString(sum[:]))
+	}
+	return key.String()
+}
+
+// hasCacheDirective reports whether the Cache-Control header value contains the directive.
+func hasCacheDirective(header, directive string) bool {
+	for part := range strings.SplitSeq(header, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(name, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeCachedResponse(w http.ResponseWriter, cached models.CachedResponse) {
+	for key, values := range cached.Headers {
+		w.Header()[key] = slices.Clone(values)
+	}
+	w.Header().Set("X-Cache", "HIT")
+	w.WriteHeader(cached.Status)
+	_, _ = w.Write(cached.Body)
+}
+
+// MemoryResponseCache is an in-memory [models.ResponseCacheStore]. Expired entries are removed as the cache grows.
+type MemoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	sweepAt int
+}
+
+type memoryCacheEntry struct {
+	response  models.CachedResponse
+	expiresAt time.Time
+}
+
+// NewMemoryResponseCache returns an empty [MemoryResponseCache].
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{
+		mu:      sync.Mutex{},
+		entries: make(map[string]memoryCacheEntry),
+		sweepAt: minCacheSweepSize,
+	}
+}
+
+// Get returns the response stored under the key if it hasn't expired.
+func (c *MemoryResponseCache) Get(_ context.Context, key string) (models.CachedResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return models.CachedResponse{}, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return models.CachedResponse{}, false, nil
+	}
+	return entry.response, true, nil
+}
+
+// Set stores the response under the key for the given time to live.
+func (c *MemoryResponseCache) Set(_ context.Context, key string, response models.CachedResponse, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= c.sweepAt {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.sweepAt = max(2*len(c.entries), minCacheSweepSize)
+	}
+
+	c.entries[key] = memoryCacheEntry{response: response, expiresAt: now.Add(ttl)}
+	return nil
+}
@@ -0,0 +1,242 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaTest/assert"
+	oapi "github.com/swaggest/openapi-go"
+)
+
+func TestWithResponseCache(t *testing.T) {
+	t.Parallel()
+
+	type response struct {
+		Count int32 `json:"count"`
+	}
+
+	newApp := func(ttl time.Duration, headers http.Header, vary ...string) (*simba.Application, *atomic.Int32) {
+		var calls atomic.Int32
+		app := simba.New()
+		app.Router.GET("/count", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[response], error) {
+			return &models.Response[response]{Headers: headers, Body: response{Count: calls.Add(1)}}, nil
+		}), simba.WithResponseCache(simba.NewMemoryResponseCache(), ttl, vary...))
+		return app, &calls
+	}
+
+	get := func(app *simba.Application, target string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("serves cached response", func(t *testing.T) {
+		t.Parallel()
+
+		app, calls := newApp(time.Minute, http.Header{"X-Custom": {"value"}})
+
+		w := get(app, "/count", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+		w = get(app, "/count", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+		assert.Equal(t, "value", w.Header().Get("X-Custom"))
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"count\":1}\n", w.Body.String())
+		assert.Equal(t, int32(1), calls.Load())
+
+		w = get(app, "/count?page=2", nil)
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("expires after ttl", func(t *testing.T) {
+		t.Parallel()
+
+		app, calls := newApp(10*time.Millisecond, nil)
+
+		get(app, "/count", nil)
+		time.Sleep(20 * time.Millisecond)
+		w := get(app, "/count", nil)
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("client cache control", func(t *testing.T) {
+		t.Parallel()
+
+		app, calls := newApp(time.Minute, nil)
+
+		get(app, "/count", nil)
+		w := get(app, "/count", map[string]string{"Cache-Control": "no-cache"})
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		assert.Equal(t, "{\"count\":2}\n", w.Body.String())
+
+		// The refreshed response is cached
+		w = get(app, "/count", nil)
+		assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+		assert.Equal(t, "{\"count\":2}\n", w.Body.String())
+
+		w = get(app, "/count", map[string]string{"Cache-Control": "no-store"})
+		assert.Equal(t, "", w.Header().Get("X-Cache"))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("vary headers", func(t *testing.T) {
+		t.Parallel()
+
+		app, calls := newApp(time.Minute, nil, "Accept-Language", "authorization")
+
+		get(app, "/count", map[string]string{"Accept-Language": "en", "Authorization": "Bearer a"})
+		w := get(app, "/count", map[string]string{"Accept-Language": "sv", "Authorization": "Bearer a"})
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		w = get(app, "/count", map[string]string{"Accept-Language": "en", "Authorization": "Bearer b"})
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		w = get(app, "/count", map[string]string{"Accept-Language": "en", "Authorization": "Bearer a"})
+		assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("credentials are not cached without vary", func(t *testing.T) {
+		t.Parallel()
+
+		app, calls := newApp(time.Minute, nil)
+
+		for i := range 2 {
+			w := get(app, "/count", map[string]string{"Authorization": "Bearer token"})
+			assert.Equal(t, "{\"count\":"+strconv.Itoa(i+1)+"}\n", w.Body.String())
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("private responses are not cached", func(t *testing.T) {
+		t.Parallel()
+
+		app, calls := newApp(time.Minute, http.Header{"Cache-Control": {"private, max-age=60"}})
+
+		get(app, "/count", nil)
+		w := get(app, "/count", nil)
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("authenticated routes vary on credentials", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		authHandler := auth.APIKeyAuth(func(ctx context.Context, apiKey string) (string, error) {
+			if apiKey == "" || apiKey == "revoked" {
+				return "", simbaErrors.NewSimbaError(http.StatusUnauthorized, "unauthorized", nil)
+			}
+			return apiKey, nil
+		}, auth.APIKeyAuthConfig{Name: "apiKey", FieldName: "X-API-Key", In: oapi.InHeader})
+
+		app := simba.New()
+		app.Router.GET("/count", simba.AuthJsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], user string) (*models.Response[response], error) {
+			return &models.Response[response]{Body: response{Count: calls.Add(1)}}, nil
+		}, authHandler), simba.WithAuthenticatedResponseCache(simba.NewMemoryResponseCache(), time.Minute))
+
+		w := get(app, "/count", map[string]string{"X-API-Key": "a"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+		w = get(app, "/count", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		w = get(app, "/count", map[string]string{"X-API-Key": "revoked"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = get(app, "/count", map[string]string{"X-API-Key": "a"})
+		assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("authenticated routes are not cached by default", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		authHandler := auth.APIKeyAuth(func(ctx context.Context, apiKey string) (string, error) {
+			return apiKey, nil
+		}, auth.APIKeyAuthConfig{Name: "apiKey", FieldName: "X-API-Key", In: oapi.InHeader})
+
+		app := simba.New()
+		app.Router.GET("/count", simba.AuthJsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], user string) (*models.Response[response], error) {
+			return &models.Response[response]{Body: response{Count: calls.Add(1)}}, nil
+		}, authHandler), simba.WithResponseCache(simba.NewMemoryResponseCache(), time.Minute))
+
+		for range 2 {
+			w := get(app, "/count", map[string]string{"X-API-Key": "a"})
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "", w.Header().Get("X-Cache"))
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("vary values are hashed in keys", func(t *testing.T) {
+		t.Parallel()
+
+		store := &keyRecordingCache{MemoryResponseCache: simba.NewMemoryResponseCache()}
+		app := simba.New()
+		app.Router.GET("/count", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[response], error) {
+			return &models.Response[response]{Body: response{Count: 1}}, nil
+		}), simba.WithResponseCache(store, time.Minute, "Authorization"))
+
+		get(app, "/count", map[string]string{"Authorization": "Bearer secret-token"})
+		w := get(app, "/count", map[string]string{"Authorization": "Bearer secret-token"})
+		assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+
+		keys := store.recorded()
+		assert.Equal(t, 3, len(keys))
+		for _, key := range keys {
+			assert.True(t, strings.HasPrefix(key, "/count\n"), key)
+			assert.False(t, strings.Contains(key, "secret-token"), key)
+		}
+	})
+}
+
+// keyRecordingCache records the keys responses are looked up and stored under.
+type keyRecordingCache struct {
+	*simba.MemoryResponseCache
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (c *keyRecordingCache) Get(ctx context.Context, key string) (models.CachedResponse, bool, error) {
+	c.record(key)
+	return c.MemoryResponseCache.Get(ctx, key)
+}
+
+func (c *keyRecordingCache) Set(ctx context.Context, key string, response models.CachedResponse, ttl time.Duration) error {
+	c.record(key)
+	return c.MemoryResponseCache.Set(ctx, key, response, ttl)
+}
+
+func (c *keyRecordingCache) record(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = append(c.keys, key)
+}
+
+func (c *keyRecordingCache) recorded() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.keys)
+}
@@ -0,0 +1,49 @@
+package simba
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// responseRecorder records a response in memory so it can be written later, possibly more than once.
+type responseRecorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header:      http.Header{},
+		body:        bytes.Buffer{},
+		status:      http.StatusOK,
+		wroteHeader: false,
+	}
+}
+
+func (w *responseRecorder) Header() http.Header {
+	return w.header
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
+
+// writeTo writes the recorded response to dst.
+func (w *responseRecorder) writeTo(dst http.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = append(dst.Header()[key], values...)
+	}
+	dst.WriteHeader(w.status)
+	_, _ = dst.Write(w.body.Bytes())
+}
@@ -0,0 +1,311 @@
+package simba
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+)
+
+// RouteOption configures a single route registered with the [Router].
+type RouteOption func(*routeConfig)
+
+// routeConfig holds the configuration of a single route.
+type routeConfig struct {
+	middleware    []func(http.Handler) http.Handler
+	deprecated    bool
+	deprecatedAt  time.Time
+	sunset        time.Time
+	coalesceKey   func(r *http.Request) string
+	cache         *responseCacheConfig
+	useNumber     bool
+	multipart     *multipartLimits
+	defaultStatus int
+	rateLimit     *openapiModels.RateLimit
+	featureFlags  []featureFlag
+}
+
+// WithRouteMiddleware attaches middleware to a single route.
+// Route middleware runs after route matching and the router middleware registered with [Router.Use],
+// in the order given, and before the request params and body are parsed by the handler.
+func WithRouteMiddleware(middleware ...func(http.Handler) http.Handler) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.middleware = append(cfg.middleware, middleware...)
+	}
+}
+
+// WithDeprecation marks the route as deprecated since the given date. Responses include the
+// Deprecation header (RFC 9745) and the route is marked deprecated in the OpenAPI documentation.
+func WithDeprecation(deprecatedAt time.Time) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.deprecated = true
+		cfg.deprecatedAt = deprecatedAt
+	}
+}
+
+// WithSunset marks the route as deprecated and to be removed at the given date. Responses include the
+// Sunset header (RFC 8594) and the Deprecation header, and the route is marked deprecated in the OpenAPI
+// documentation. Combine with [WithDeprecation] to set the date the route was deprecated, otherwise
+// the Deprecation header is set to "true".
+func WithSunset(sunset time.Time) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.deprecated = true
+		cfg.sunset = sunset
+	}
+}
+
+// WithCoalescing shares a single handler execution among concurrent GET and HEAD requests to the route
+// with the same key, so that a burst of identical requests for an expensive resource, such as on a cache
+// miss, only runs the handler once. The response is buffered and written to all the waiting requests.
+//
+// The key must identify everything the response depends on, such as the path, query and the user if the
+// response differs per user, otherwise responses can be shared with requests they weren't meant for.
+// Requests with an empty key are not coalesced. Route middleware runs for every request before coalescing.
+//
+//	Example usage:
+//
+//	app.Router.GET("/products/{id}", simba.JsonHandler(getProduct), simba.WithCoalescing(func(r *http.Request) string {
+//		return r.URL.RequestURI()
+//	}))
+func WithCoalescing(keyFunc func(r *http.Request) string) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.coalesceKey = keyFunc
+	}
+}
+
+// WithResponseCache caches successful GET responses of the route in the store for the given time to live,
+// so that later requests are served from the cache without calling the handler. Responses are cached by
+// path and query, and the values of the vary headers if given. Served responses have the X-Cache header
+// set to HIT or MISS.
+//
+// Requests with Cache-Control: no-cache bypass the cache and refresh it, while requests with no-store
+// bypass it entirely. Responses with Set-Cookie or Cache-Control no-store, no-cache or private are not
+// cached. Requests with an Authorization or Cookie header are only cached if that header is a vary header.
+// Vary header values are hashed in the cache keys, so credentials aren't written to the store.
+//
+// Responses of authenticated routes aren't cached, since a cache hit doesn't run the auth handler. Use
+// [WithAuthenticatedResponseCache] to cache them.
+//
+//	Example usage:
+//
+//	cache := simba.NewMemoryResponseCache()
+//	app.Router.GET("/products/{id}", simba.JsonHandler(getProduct), simba.WithResponseCache(cache, time.Minute, "Accept-Language"))
+func WithResponseCache(store models.ResponseCacheStore, ttl time.Duration, vary ...string) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.cache = &responseCacheConfig{store: store, ttl: ttl, vary: vary, authenticated: false}
+	}
+}
+
+// WithAuthenticatedResponseCache caches responses like [WithResponseCache], including those of authenticated
+// routes. Authenticated routes vary on the header their auth handler reads credentials from, so responses are
+// cached per credential, and aren't cached if the auth handler doesn't tell where it reads credentials from.
+// A cache hit doesn't run the auth handler, so a credential that is revoked or expires keeps getting its
+// cached responses until they expire, which the time to live should allow for.
+//
+//	Example usage:
+//
+//	cache := simba.NewMemoryResponseCache()
+//	app.Router.GET("/me/orders", simba.AuthJsonHandler(listOrders, authHandler), simba.WithAuthenticatedResponseCache(cache, 10*time.Second))
+func WithAuthenticatedResponseCache(store models.ResponseCacheStore, ttl time.Duration, vary ...string) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.cache = &responseCacheConfig{store: store, ttl: ttl, vary: vary, authenticated: true}
+	}
+}
+
+// WithJsonNumbers decodes numbers in the JSON request bodies of the route into interface values, such as
+// map[string]any, as json.Number instead of float64, overriding settings.WithUseNumber. Large integers and
+// decimals keep their precision, which matters for pass-through and financial endpoints.
+//
+//	Example usage:
+//
+//	app.Router.POST("/ledger/entries", simba.JsonHandler(createEntry), simba.WithJsonNumbers())
+func WithJsonNumbers() RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.useNumber = true
+	}
+}
+
+// WithMultipartLimits limits the number of file parts and their total size in bytes in the multipart request
+// bodies of the route, overriding settings.WithMaxMultipartFiles and settings.WithMaxMultipartSize. The limits
+// are enforced while the body is read, so an oversized upload is rejected with a 413 Request Entity Too Large
+// as soon as it exceeds them instead of after it has been received. Zero means no limit.
+//
+//	Example usage:
+//
+//	app.Router.POST("/photos", simba.MultipartHandler(uploadPhotos), simba.WithMultipartLimits(10, 50<<20))
+func WithMultipartLimits(maxFiles int, maxSize int64) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.multipart = &multipartLimits{files: maxFiles, size: maxSize}
+	}
+}
+
+// WithDefaultStatus sets the status of successful responses with a body from the route whose handler
+// doesn't set one, overriding the default status of the method set with settings.WithDefaultStatus.
+// The status is used in the OpenAPI documentation unless the handler sets another one.
+//
+//	Example usage:
+//
+//	app.Router.PUT("/users/{id}", simba.JsonHandler(upsertUser), simba.WithDefaultStatus(http.StatusCreated))
+func WithDefaultStatus(status int) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.defaultStatus = status
+	}
+}
+
+// WithRateLimit documents the rate limit of the route, as requests per window, in the x-ratelimit extension
+// of its OpenAPI operation when operation extensions are enabled with settings.WithOperationExtensions.
+// Simba doesn't enforce the limit, it is meant for API gateways that configure themselves from the documentation.
+//
+//	Example usage:
+//
+//	app.Router.POST("/search", simba.JsonHandler(search), simba.WithRateLimit(100, time.Minute))
+func WithRateLimit(requests int, window time.Duration) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.rateLimit = &openapiModels.RateLimit{Requests: requests, Window: window}
+	}
+}
+
+// WithFeatureFlag gates the route behind a feature flag evaluated by the provider for every request, for dark
+// launches and gradual rollouts. Requests for which the flag is disabled get a 404 Not Found, or the status set
+// with settings.WithDisabledFeatureStatus, before the route middleware and handler run. A route gated behind
+// several flags requires all of them to be enabled. The route is still documented in OpenAPI.
+//
+//	Example usage:
+//
+//	flags := models.FeatureFlagFunc(func(r *http.Request, flag string) (bool, error) {
+//		return rollout.Enabled(flag, r.Header.Get("X-User-ID")), nil
+//	})
+//	app.Router.GET("/beta/search", simba.JsonHandler(search), simba.WithFeatureFlag("beta-search", flags))
+func WithFeatureFlag(name string, provider models.FeatureFlagProvider) RouteOption {
+	return func(cfg *routeConfig) {
+		if provider != nil {
+			cfg.featureFlags = append(cfg.featureFlags, featureFlag{name: name, provider: provider})
+		}
+	}
+}
+
+func newRouteConfig(opts []RouteOption) routeConfig {
+	cfg := routeConfig{
+		middleware:    nil,
+		deprecated:    false,
+		deprecatedAt:  time.Time{},
+		sunset:        time.Time{},
+		coalesceKey:   nil,
+		cache:         nil,
+		useNumber:     false,
+		multipart:     nil,
+		defaultStatus: 0,
+		rateLimit:     nil,
+		featureFlags:  nil,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// wrap wraps the handler with the route middleware so that the first middleware runs first.
+func (cfg routeConfig) wrap(handler http.Handler) http.Handler {
+	if cfg.coalesceKey != nil {
+		handler = coalesce(cfg.coalesceKey)(handler)
+	}
+	if cfg.cache != nil && cfg.cache.store != nil {
+		handler = cacheResponses(*cfg.cache)(handler)
+	}
+
+	for i := len(cfg.middleware) - 1; i >= 0; i-- {
+		if cfg.middleware[i] != nil {
+			handler = cfg.middleware[i](handler)
+		}
+	}
+
+	if cfg.useNumber {
+		handler = useNumber(handler)
+	}
+	if cfg.multipart != nil {
+		handler = limitMultipart(*cfg.multipart)(handler)
+	}
+	if cfg.defaultStatus != 0 {
+		handler = defaultStatus(cfg.defaultStatus)(handler)
+	}
+
+	if cfg.deprecated {
+		handler = cfg.deprecationHeaders(handler)
+	}
+
+	// Check feature flags first, so disabled routes don't reveal themselves through headers or cached responses
+	if len(cfg.featureFlags) > 0 {
+		handler = requireFeatureFlags(cfg.featureFlags)(handler)
+	}
+
+	return handler
+}
+
+// deprecationHeaders sets the Deprecation and Sunset headers before the route handles the request,
+// so they are included in error responses as well.
+func (cfg routeConfig) deprecationHeaders(next http.Handler) http.Handler {
+	deprecation := "true"
+	if !cfg.deprecatedAt.IsZero() {
+		deprecation = "@" + strconv.FormatInt(cfg.deprecatedAt.Unix(), 10)
+	}
+
+	var sunset string
+	if !cfg.sunset.IsZero() {
+		sunset = cfg.sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", deprecation)
+		if sunset != "" {
+			w.Header().Set("Sunset", sunset)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// useNumber decodes JSON numbers as json.Number for the request by overriding the request settings in the context.
+func useNumber(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestSettings := *getConfigurationFromContext(r.Context())
+		requestSettings.UseNumber = true
+		ctx := context.WithValue(r.Context(), simbaContext.RequestSettingsKey, &requestSettings)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// multipartLimits are the limits on the files of the multipart request bodies of a route.
+type multipartLimits struct {
+	files int
+	size  int64
+}
+
+// limitMultipart sets the limits on the files of a multipart request body by overriding the request settings
+// in the context.
+func limitMultipart(limits multipartLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestSettings := *getConfigurationFromContext(r.Context())
+			requestSettings.MaxMultipartFiles = limits.files
+			requestSettings.MaxMultipartSize = limits.size
+			ctx := context.WithValue(r.Context(), simbaContext.RequestSettingsKey, &requestSettings)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// defaultStatus sets the default status of successful responses for the request by overriding the request
+// settings in the context.
+func defaultStatus(status int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestSettings := *getConfigurationFromContext(r.Context())
+			requestSettings.DefaultStatuses = map[string]int{r.Method: status}
+			ctx := context.WithValue(r.Context(), simbaContext.RequestSettingsKey, &requestSettings)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
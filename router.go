@@ -2,8 +2,13 @@ package simba
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/settings"
@@ -28,29 +33,60 @@ type routeDocumentationController interface {
 	ShouldDocument() bool
 }
 
+type alternativeResponseProducer interface {
+	GetAlternativeResponses() []openapiModels.ResponseContent
+}
+
+// ErrHotReloadDisabled is returned by [Router.Reload] if hot reload is not enabled in [settings.Server].
+var ErrHotReloadDisabled = errors.New("hot reload is not enabled")
+
 type openApiGenerator interface {
 	GenerateDocumentation(ctx context.Context, title string, version string, routeInfos []openapiModels.RouteInfo) ([]byte, error)
 }
 
 // Router is a simple Mux that wraps [http.ServeMux] and allows for middleware chaining
 // and type information storage for routes.
+//
+// Middleware runs in the following phases, and in registration order within each phase:
+//  1. Pre-routing middleware ([Router.UsePreRouting]) wraps the whole router and runs for every
+//     request, including requests that don't match a route. Path values are not available yet.
+//  2. The request is matched against the registered routes.
+//  3. Router middleware ([Router.Use]) runs for matched routes registered after it was added.
+//     Path values are available through [http.Request.PathValue].
+//  4. Route middleware ([WithRouteMiddleware]) runs for the single route it was attached to.
+//  5. The handler parses and validates the params and body, then calls the handler function.
+//
+// Auth and rate limiting that don't depend on the route belong in pre-routing middleware,
+// while checks that depend on the matched route belong in router or route middleware.
 type Router struct {
 	Mux                    *http.ServeMux
+	preRoutingMiddleware   []func(http.Handler) http.Handler
+	preRouting             atomic.Pointer[http.Handler]
 	middleware             []func(http.Handler) http.Handler
 	docsSettings           settings.Docs
 	routes                 []openapiModels.RouteInfo
+	registeredRoutes       []openapiModels.RouteInfo
+	mounts                 []mountedRouter
 	schema                 []byte
 	openAPIEndpointMounted bool
 	docsEndpointsMounted   bool
 	openAPIGenerator       openApiGenerator
+	docsTitle              string
+	docsVersion            string
+	requestSettings        settings.Request
+	hotReload              bool
+	onReload               []func(r *Router)
+	mu                     sync.RWMutex
 }
 
 // GenerateOpenAPIDocumentation generates the OpenAPI documentation for the routes mounted in the router
 // if enabled in [settings.Docs].
 func (r *Router) GenerateOpenAPIDocumentation(ctx context.Context, title, version string) error {
+	r.docsTitle, r.docsVersion = title, version
+
 	if r.docsSettings.GenerateOpenAPIDocs {
 		var err error
-		r.schema, err = r.openAPIGenerator.GenerateDocumentation(ctx, title, version, r.routes)
+		r.schema, err = r.openAPIGenerator.GenerateDocumentation(ctx, title, version, r.docRoutes())
 		if err != nil {
 			return fmt.Errorf("failed to generate OpenAPI documentation: %w", err)
 		}
@@ -64,43 +100,169 @@ func (r *Router) GenerateOpenAPIDocumentation(ctx context.Context, title, versio
 }
 
 func newRouter(requestSettings settings.Request, docsSettings settings.Docs) *Router {
-	router := &Router{
-		Mux: http.NewServeMux(),
-		middleware: []func(http.Handler) http.Handler{
-			closeRequestBody,
-			func(next http.Handler) http.Handler {
-				return injectRequestSettings(next, &requestSettings)
-			},
+	router := newRouterWithMiddleware(docsSettings, requestSettings, []func(http.Handler) http.Handler{
+		closeRequestBody,
+		func(next http.Handler) http.Handler {
+			return injectRequestSettings(next, &requestSettings)
 		},
-		docsSettings: docsSettings,
+	})
+
+	if docsSettings.GenerateOpenAPIDocs {
+		router.mountOpenAPIEndpoint()
+	}
+	if docsSettings.MountDocsUIEndpoint {
+		router.mountDocsUIEndpoint()
+	}
+
+	return router
+}
+
+func newRouterWithMiddleware(docsSettings settings.Docs, requestSettings settings.Request, middleware []func(http.Handler) http.Handler) *Router {
+	return &Router{
+		Mux:                  http.NewServeMux(),
+		preRoutingMiddleware: nil,
+		preRouting:           atomic.Pointer[http.Handler]{},
+		middleware:           middleware,
+		docsSettings:         docsSettings,
 		routes: func() []openapiModels.RouteInfo {
 			if docsSettings.GenerateOpenAPIDocs {
 				return make([]openapiModels.RouteInfo, 0, 100)
 			}
 			return nil
 		}(),
+		registeredRoutes:       make([]openapiModels.RouteInfo, 0),
+		mounts:                 nil,
 		schema:                 nil,
 		openAPIEndpointMounted: false,
 		docsEndpointsMounted:   false,
-		openAPIGenerator:       simbaOpenapi.NewOpenAPIGenerator(),
+		openAPIGenerator:       newOpenAPIGenerator(docsSettings, requestSettings),
+		requestSettings:        requestSettings,
+		docsTitle:              "",
+		docsVersion:            "",
+		hotReload:              false,
+		onReload:               nil,
+		mu:                     sync.RWMutex{},
 	}
+}
 
-	if docsSettings.GenerateOpenAPIDocs {
-		router.mountOpenAPIEndpoint()
+func newOpenAPIGenerator(docsSettings settings.Docs, requestSettings settings.Request) openApiGenerator {
+	return simbaOpenapi.NewOpenAPIGenerator(
+		simbaOpenapi.WithErrorSchema(docsSettings.ErrorSchema, docsSettings.ErrorContentType),
+		simbaOpenapi.WithErrorExamples(docsSettings.ErrorExamples),
+		simbaOpenapi.WithTags(docsSettings.Tags...),
+		simbaOpenapi.WithResponseEnvelope(docsSettings.ResponseEnvelope, docsSettings.ResponseEnvelopeField),
+		simbaOpenapi.WithTimeFormat(requestSettings.TimeFormat),
+		simbaOpenapi.WithDefaultStatuses(requestSettings.DefaultStatuses),
+		simbaOpenapi.WithOperationExtensions(docsSettings.OperationExtensions),
+	)
+}
+
+// Reload atomically replaces all routes of the router with the routes registered by register, without
+// restarting the server. Requests already in flight are completed by the previous routes. This is meant
+// to be used with code reloaders during development and returns [ErrHotReloadDisabled] unless hot reload
+// is enabled in [settings.Server].
+//
+// The new routes are registered with all the router middleware added with [Router.Use], and the OpenAPI
+// documentation is regenerated if it has already been generated. Pre-routing middleware is kept as is.
+//
+//	Example usage:
+//
+//	err := app.Router.Reload(ctx, func(r *simba.Router) {
+//		r.GET("/users/{id}", simba.JsonHandler(getUser))
+//	})
+func (r *Router) Reload(ctx context.Context, register func(r *Router)) error {
+	if !r.hotReload {
+		return ErrHotReloadDisabled
 	}
-	if docsSettings.MountDocsUIEndpoint {
-		router.mountDocsUIEndpoint()
+
+	staged := newRouterWithMiddleware(r.docsSettings, r.requestSettings, slices.Clone(r.middleware))
+	if r.docsSettings.GenerateOpenAPIDocs {
+		// Serve the schema of this router, which is generated on start if it hasn't been yet
+		staged.Mux.Handle(fmt.Sprintf("%s %s", http.MethodGet, r.docsSettings.OpenAPIFilePath), r.openAPIDocsHandler())
+	}
+	if r.docsSettings.MountDocsUIEndpoint {
+		staged.mountDocsUIEndpoint()
 	}
+	for _, hook := range r.onReload {
+		hook(staged)
+	}
+	register(staged)
 
-	return router
+	r.mu.RLock()
+	generated := r.docsSettings.GenerateOpenAPIDocs && r.openAPIGenerator == nil
+	r.mu.RUnlock()
+
+	if generated {
+		if err := staged.GenerateOpenAPIDocumentation(ctx, r.docsTitle, r.docsVersion); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Mux = staged.Mux
+	r.registeredRoutes = staged.registeredRoutes
+	r.mounts = staged.mounts
+	if generated {
+		r.schema = staged.schema
+	} else {
+		r.routes = staged.routes
+		r.openAPIGenerator = staged.openAPIGenerator
+	}
+
+	return nil
+}
+
+// Routes returns the metadata of all routes registered with the router, in registration order.
+// Routes registered with [Router.HandleHTTP] only carry their method, path and handler.
+func (r *Router) Routes() []openapiModels.RouteInfo {
+	if r.hotReload {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+
+	return r.withMounted(r.registeredRoutes, func(m mountedRouter) int { return m.routeAt }, (*Router).Routes)
 }
 
 // ServeHTTP implements the [http.Handler] interface for the [Router] type.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	r.Mux.ServeHTTP(w, req)
+	if preRouting := r.preRouting.Load(); preRouting != nil {
+		(*preRouting).ServeHTTP(w, req)
+		return
+	}
+	r.serveMux(w, req)
 }
 
-// Use registers a middleware handler.
+// serveMux routes the request with the current mux, which is replaced when the routes are reloaded.
+func (r *Router) serveMux(w http.ResponseWriter, req *http.Request) {
+	if !r.hotReload {
+		r.Mux.ServeHTTP(w, req)
+		return
+	}
+
+	r.mu.RLock()
+	mux := r.Mux
+	r.mu.RUnlock()
+	mux.ServeHTTP(w, req)
+}
+
+// UsePreRouting registers a middleware handler that runs before route matching for every request,
+// including requests that don't match any route. The middleware wraps the router once, when it is
+// registered, rather than for every request.
+func (r *Router) UsePreRouting(middleware func(http.Handler) http.Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.preRoutingMiddleware = append(r.preRoutingMiddleware, middleware)
+
+	var handler http.Handler = http.HandlerFunc(r.serveMux)
+	for i := len(r.preRoutingMiddleware) - 1; i >= 0; i-- {
+		handler = r.preRoutingMiddleware[i](handler)
+	}
+	r.preRouting.Store(&handler)
+}
+
+// Use registers a middleware handler that runs after route matching, for routes registered after the call.
 func (r *Router) Use(middleware func(http.Handler) http.Handler) {
 	r.middleware = append(r.middleware, middleware)
 }
@@ -111,9 +273,9 @@ func (r *Router) Extend(middleware []func(http.Handler) http.Handler) {
 }
 
 // POST registers a handler for POST requests to the given pattern.
-func (r *Router) POST(path string, handler Handler) {
+func (r *Router) POST(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodPost
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // POSTWithMiddleware registers a handler for POST requests to the given pattern wrapped with a middleware function.
@@ -123,9 +285,9 @@ func (r *Router) POSTWithMiddleware(path string, handler Handler, middleware ...
 }
 
 // GET registers a handler for GET requests to the given pattern.
-func (r *Router) GET(path string, handler Handler) {
+func (r *Router) GET(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodGet
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // GETWithMiddleware registers a handler for GET requests to the given pattern wrapped with a middleware function.
@@ -135,9 +297,9 @@ func (r *Router) GETWithMiddleware(path string, handler Handler, middleware ...f
 }
 
 // PUT registers a handler for PUT requests to the given pattern.
-func (r *Router) PUT(path string, handler Handler) {
+func (r *Router) PUT(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodPut
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // PUTWithMiddleware registers a handler for PUT requests to the given pattern wrapped with a middleware function.
@@ -147,9 +309,9 @@ func (r *Router) PUTWithMiddleware(path string, handler Handler, middleware ...f
 }
 
 // DELETE registers a handler for DELETE requests to the given pattern.
-func (r *Router) DELETE(path string, handler Handler) {
+func (r *Router) DELETE(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodDelete
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // DELETEWithMiddleware registers a handler for DELETE requests to the given pattern wrapped with a middleware function.
@@ -159,9 +321,9 @@ func (r *Router) DELETEWithMiddleware(path string, handler Handler, middleware .
 }
 
 // PATCH registers a handler for PATCH requests to the given pattern.
-func (r *Router) PATCH(path string, handler Handler) {
+func (r *Router) PATCH(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodPatch
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // PATCHWithMiddleware registers a handler for PATCH requests to the given pattern wrapped with a middleware function.
@@ -171,9 +333,9 @@ func (r *Router) PATCHWithMiddleware(path string, handler Handler, middleware ..
 }
 
 // OPTIONS registers a handler for OPTIONS requests to the given pattern.
-func (r *Router) OPTIONS(path string, handler Handler) {
+func (r *Router) OPTIONS(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodOptions
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // OPTIONSWithMiddleware registers a handler for OPTIONS requests to the given pattern wrapped with a middleware function.
@@ -183,9 +345,9 @@ func (r *Router) OPTIONSWithMiddleware(path string, handler Handler, middleware
 }
 
 // HEAD registers a handler for HEAD requests to the given pattern.
-func (r *Router) HEAD(path string, handler Handler) {
+func (r *Router) HEAD(path string, handler Handler, opts ...RouteOption) {
 	method := http.MethodHead
-	r.Handle(method, path, handler)
+	r.Handle(method, path, handler, opts...)
 }
 
 // HEADWithMiddleware registers a handler for HEAD requests to the given pattern wrapped with a middleware function.
@@ -195,35 +357,155 @@ func (r *Router) HEADWithMiddleware(path string, handler Handler, middleware ...
 }
 
 // WithMiddleware registers a handler for the given method and pattern wrapped with a middleware function.
+// It is equivalent to [Router.Handle] with [WithRouteMiddleware].
 func (r *Router) WithMiddleware(method, path string, handler Handler, middleware ...func(http.Handler) http.Handler) {
-	h := handlerToHTTPHandler(handler)
-	if len(middleware) > 0 {
-		for i := len(middleware) - 1; i >= 0; i-- {
-			if middleware[i] != nil {
-				h = middleware[i](h)
+	r.handle(method, path, handler, "", []RouteOption{WithRouteMiddleware(middleware...)})
+}
+
+// Handle registers a handler for the given method and pattern.
+// Route options can attach route-scoped middleware or deprecate the route, see [WithRouteMiddleware] and [WithSunset].
+func (r *Router) Handle(method, path string, handler Handler, opts ...RouteOption) {
+	r.handle(method, path, handler, "", opts)
+}
+
+// HandleAll registers the handler for every combination of the methods and paths, such as both /users and
+// /people, or GET and HEAD. The first method and path is the canonical route, the other combinations are
+// documented in OpenAPI as aliases of it with their own operation IDs.
+//
+//	Example usage:
+//
+//	app.Router.HandleAll([]string{http.MethodGet, http.MethodHead}, []string{"/users", "/people"}, simba.JsonHandler(listUsers))
+func (r *Router) HandleAll(methods []string, paths []string, handler Handler, opts ...RouteOption) {
+	if len(methods) == 0 || len(paths) == 0 {
+		return
+	}
+
+	canonical := methods[0] + " " + paths[0]
+	for _, path := range paths {
+		for _, method := range methods {
+			aliasOf := canonical
+			if method+" "+path == canonical {
+				aliasOf = ""
 			}
+			r.handle(method, path, handler, aliasOf, opts)
 		}
 	}
-	r.addRoute(method, path, h)
-	r.addRouteToDocs(method, path, handler)
 }
 
-func handlerToHTTPHandler(h Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		h.ServeHTTP(w, r)
-	})
-}
+// handle registers the handler for the method and path, documented as an alias if aliasOf is set.
+func (r *Router) handle(method, path string, handler Handler, aliasOf string, opts []RouteOption) {
+	cfg := newRouteConfig(opts)
+	if authHandler := handler.GetAuthHandler(); cfg.cache != nil && authHandler != nil {
+		cfg.cache = cfg.cache.forAuth(authHandler)
+	}
+	routeHandler := cfg.wrap(enforcePathPatterns(handler.GetParams(), enforceContentType(handler)))
+	if r.requestSettings.LogErrorSource {
+		routeHandler = withHandlerSource(handlerSource(handler))(routeHandler)
+	}
+	r.addRoute(method, path, routeHandler)
 
-// Handle registers a handler for the given method and pattern.
-func (r *Router) Handle(method, path string, handler Handler) {
-	r.addRoute(method, path, handler)
-	r.addRouteToDocs(method, path, handler)
+	route := newRouteInfo(method, path, handler)
+	route.Deprecated = cfg.deprecated
+	route.AliasOf = aliasOf
+	route.DefaultStatus = cfg.defaultStatus
+	route.DeprecatedAt = cfg.deprecatedAt
+	route.Sunset = cfg.sunset
+	route.RateLimit = cfg.rateLimit
+	r.registeredRoutes = append(r.registeredRoutes, route)
+	r.addRouteToDocs(handler, route)
 }
 
 // HandleHTTP registers a plain http.Handler for the given method and path.
 // This is useful for protocol upgrades such as WebSockets where OpenAPI metadata does not apply.
 func (r *Router) HandleHTTP(method, path string, handler http.Handler) {
 	r.addRoute(method, path, handler)
+	r.registeredRoutes = append(r.registeredRoutes, openapiModels.RouteInfo{
+		Method:      method,
+		Path:        path,
+		Accepts:     "",
+		Produces:    "",
+		ReqBody:     nil,
+		Params:      nil,
+		RespBody:    nil,
+		Handler:     handler,
+		AuthModel:   nil,
+		AuthHandler: nil,
+	})
+}
+
+// Mount delegates all requests below the given prefix to the handler with the prefix stripped from the path.
+// Mounted handlers are not included in the OpenAPI documentation, unless the handler is the [Router] of
+// another simba application, in which case its documented routes are added under the prefix. The routes
+// of a mounted application are read when they are needed, so routes registered on it after it was mounted
+// are included in [Router.Routes], and in the documentation if registered before it is generated.
+func (r *Router) Mount(prefix string, handler http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	r.Mux.Handle(prefix+"/", r.applyMiddleware(http.StripPrefix(prefix, handler)))
+
+	subRouter, ok := handler.(*Router)
+	if !ok {
+		r.registeredRoutes = append(r.registeredRoutes, openapiModels.RouteInfo{
+			Method:      "",
+			Path:        prefix + "/",
+			Accepts:     "",
+			Produces:    "",
+			ReqBody:     nil,
+			Params:      nil,
+			RespBody:    nil,
+			Handler:     handler,
+			AuthModel:   nil,
+			AuthHandler: nil,
+		})
+		return
+	}
+
+	r.mounts = append(r.mounts, mountedRouter{
+		prefix:  prefix,
+		router:  subRouter,
+		routeAt: len(r.registeredRoutes),
+		docAt:   len(r.routes),
+	})
+}
+
+// mountedRouter is the router of another simba application mounted under a prefix. Its routes are read
+// whenever the routes of the router are, so routes registered on it after it was mounted are included.
+type mountedRouter struct {
+	prefix string
+	router *Router
+	// routeAt and docAt are the number of routes and documented routes registered before the router was
+	// mounted, which its routes follow
+	routeAt int
+	docAt   int
+}
+
+// withMounted returns the routes with those of the mounted routers, read by routesOf, inserted where
+// the routers were mounted.
+func (r *Router) withMounted(
+	routes []openapiModels.RouteInfo,
+	at func(m mountedRouter) int,
+	routesOf func(sub *Router) []openapiModels.RouteInfo,
+) []openapiModels.RouteInfo {
+	result := make([]openapiModels.RouteInfo, 0, len(routes))
+	next := 0
+	for _, m := range r.mounts {
+		end := min(at(m), len(routes))
+		result = append(result, routes[next:end]...)
+		next = end
+
+		for _, route := range routesOf(m.router) {
+			route.Path = m.prefix + route.Path
+			result = append(result, route)
+		}
+	}
+	return append(result, routes[next:]...)
+}
+
+// docRoutes returns the documented routes of the router, including those of mounted routers.
+func (r *Router) docRoutes() []openapiModels.RouteInfo {
+	if len(r.mounts) == 0 || !r.docsSettings.GenerateOpenAPIDocs {
+		return r.routes
+	}
+	return r.withMounted(r.routes, func(m mountedRouter) int { return m.docAt }, (*Router).docRoutes)
 }
 
 func (r *Router) addRoute(method, path string, handler http.Handler) {
@@ -237,24 +519,35 @@ func (r *Router) applyMiddleware(handler http.Handler) http.Handler {
 	return handler
 }
 
-func (r *Router) addRouteToDocs(method string, path string, handler Handler) {
+func (r *Router) addRouteToDocs(handler Handler, route openapiModels.RouteInfo) {
 	if controller, ok := handler.(routeDocumentationController); ok && !controller.ShouldDocument() {
 		return
 	}
 
 	if r.docsSettings.GenerateOpenAPIDocs {
-		r.routes = append(r.routes, openapiModels.RouteInfo{
-			Method:      method,
-			Path:        path,
-			Accepts:     handler.GetAccepts(),
-			Produces:    handler.GetProduces(),
-			ReqBody:     handler.GetRequestBody(),
-			Params:      handler.GetParams(),
-			RespBody:    handler.GetResponseBody(),
-			Handler:     handler.GetHandler(),
-			AuthModel:   handler.GetAuthModel(),
-			AuthHandler: handler.GetAuthHandler(),
-		})
+		r.routes = append(r.routes, route)
+	}
+}
+
+func newRouteInfo(method string, path string, handler Handler) openapiModels.RouteInfo {
+	var alternativeResponses []openapiModels.ResponseContent
+	if producer, ok := handler.(alternativeResponseProducer); ok {
+		alternativeResponses = producer.GetAlternativeResponses()
+	}
+
+	return openapiModels.RouteInfo{
+		Method:      method,
+		Path:        path,
+		Accepts:     handler.GetAccepts(),
+		Produces:    handler.GetProduces(),
+		ReqBody:     handler.GetRequestBody(),
+		Params:      handler.GetParams(),
+		RespBody:    handler.GetResponseBody(),
+		Handler:     handler.GetHandler(),
+		AuthModel:   handler.GetAuthModel(),
+		AuthHandler: handler.GetAuthHandler(),
+
+		AlternativeResponses: alternativeResponses,
 	}
 }
 
@@ -286,7 +579,16 @@ func (r *Router) mountOpenAPIEndpoint() {
 
 func (r *Router) openAPIDocsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
+		var schema []byte
+		if r.hotReload {
+			r.mu.RLock()
+			schema = r.schema
+			r.mu.RUnlock()
+		} else {
+			schema = r.schema
+		}
+
 		w.Header().Set("Content-Type", mimetypes.ApplicationJSON)
-		_, _ = w.Write(r.schema)
+		_, _ = w.Write(schema)
 	}
 }
@@ -3,23 +3,31 @@ package simba_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+	"github.com/sillen102/simba/simbaTest"
 	"github.com/sillen102/simba/simbaTest/assert"
 )
 
 // TODO: Add more tests
 // 	1. Route conflicts
 // 	2. Wildcard routes
-//  3. Middleware chain ordering
-//  4. Route parameter validation
-//  5. OPTIONS requests handling
-//  6. HEAD requests handling
+//  3. Route parameter validation
+//  4. OPTIONS requests handling
+//  5. HEAD requests handling
 
 func TestEndpoints(t *testing.T) {
 	t.Parallel()
@@ -332,6 +340,256 @@ func TestRouter_Use(t *testing.T) {
 	})
 }
 
+func TestRouter_MiddlewarePhases(t *testing.T) {
+	t.Parallel()
+
+	router := simba.Default().Router
+
+	var calls []string
+	var wraps atomic.Int32
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			wraps.Add(1)
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name+":"+r.PathValue("id"))
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	router.UsePreRouting(record("pre-1"))
+	router.UsePreRouting(record("pre-2"))
+	router.Use(record("router"))
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, struct {
+		ID string `path:"id"`
+	}]) (*models.Response[models.NoBody], error) {
+		calls = append(calls, "handler:"+req.Params.ID)
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	router.GET("/items/{id}", simba.JsonHandler(handler),
+		simba.WithRouteMiddleware(record("route-1"), record("route-2")),
+	)
+
+	t.Run("ordering", func(t *testing.T) {
+		calls = nil
+		req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []string{"pre-1:", "pre-2:", "router:42", "route-1:42", "route-2:42", "handler:42"}, calls)
+	})
+
+	t.Run("pre-routing runs for unmatched requests", func(t *testing.T) {
+		calls = nil
+		req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []string{"pre-1:", "pre-2:"}, calls)
+	})
+
+	t.Run("middleware wraps once", func(t *testing.T) {
+		before := wraps.Load()
+		for range 3 {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/42", nil))
+		}
+		assert.Equal(t, before, wraps.Load())
+	})
+}
+
+func TestRouter_Sunset(t *testing.T) {
+	t.Parallel()
+
+	router := simba.Default().Router
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	deprecatedAt := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, time.December, 31, 23, 59, 59, 0, time.UTC)
+	router.GET("/v1/sunset", simba.JsonHandler(handler), simba.WithSunset(sunset))
+	router.GET("/v1/deprecated", simba.JsonHandler(handler), simba.WithDeprecation(deprecatedAt), simba.WithSunset(sunset))
+	router.GET("/v2", simba.JsonHandler(handler))
+
+	t.Run("sunset", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/sunset", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Thu, 31 Dec 2026 23:59:59 GMT", w.Header().Get("Sunset"))
+	})
+
+	t.Run("deprecation date", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/deprecated", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Thu, 31 Dec 2026 23:59:59 GMT", w.Header().Get("Sunset"))
+	})
+
+	t.Run("not deprecated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "", w.Header().Get("Deprecation"))
+		assert.Equal(t, "", w.Header().Get("Sunset"))
+	})
+
+	t.Run("routes marked deprecated", func(t *testing.T) {
+		deprecated := map[string]bool{}
+		for _, route := range router.Routes() {
+			deprecated[route.Path] = route.Deprecated
+		}
+
+		assert.True(t, deprecated["/v1/sunset"])
+		assert.True(t, deprecated["/v1/deprecated"])
+		assert.False(t, deprecated["/v2"])
+	})
+
+	t.Run("routes carry deprecation dates", func(t *testing.T) {
+		for _, route := range router.Routes() {
+			if route.Path == "/v1/deprecated" {
+				assert.Equal(t, deprecatedAt, route.DeprecatedAt)
+				assert.Equal(t, sunset, route.Sunset)
+			}
+		}
+	})
+}
+
+func TestRouter_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	router := simba.New().Router
+	router.POST("/search", simba.JsonHandler(handler), simba.WithRateLimit(100, time.Minute))
+	router.GET("/users", simba.JsonHandler(handler))
+
+	limits := map[string]*openapiModels.RateLimit{}
+	for _, route := range router.Routes() {
+		limits[route.Path] = route.RateLimit
+	}
+
+	assert.Equal(t, &openapiModels.RateLimit{Requests: 100, Window: time.Minute}, limits["/search"])
+	assert.Nil(t, limits["/users"])
+}
+
+func TestRouter_JsonNumbers(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[map[string]any, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{
+			Body: map[string]string{"type": fmt.Sprintf("%T", req.Body["id"]), "id": fmt.Sprint(req.Body["id"])},
+		}, nil
+	}
+
+	router := simba.New().Router
+	router.POST("/numbers", simba.JsonHandler(handler), simba.WithJsonNumbers())
+	router.POST("/floats", simba.JsonHandler(handler))
+
+	useNumberRouter := simba.New(settings.WithUseNumber(true)).Router
+	useNumberRouter.POST("/numbers", simba.JsonHandler(handler))
+
+	tests := []struct {
+		name     string
+		router   *simba.Router
+		path     string
+		expected string
+	}{
+		{
+			name:     "route option",
+			router:   router,
+			path:     "/numbers",
+			expected: `{"id":"9007199254740993","type":"json.Number"}`,
+		},
+		{
+			name:     "setting",
+			router:   useNumberRouter,
+			path:     "/numbers",
+			expected: `{"id":"9007199254740993","type":"json.Number"}`,
+		},
+		{
+			name:     "default",
+			router:   router,
+			path:     "/floats",
+			expected: `{"id":"9.007199254740992e+15","type":"float64"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"id": 9007199254740993}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			tt.router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestRouter_DefaultStatus(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{Body: map[string]string{"id": "1"}}, nil
+	}
+	statusHandler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[map[string]string], error) {
+		return &models.Response[map[string]string]{Body: map[string]string{"id": "1"}, Status: http.StatusOK}, nil
+	}
+	noBodyHandler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	router := simba.New(settings.WithDefaultStatus("post", http.StatusCreated)).Router
+	router.POST("/users", simba.JsonHandler(handler))
+	router.PUT("/users", simba.JsonHandler(handler))
+	router.POST("/jobs", simba.JsonHandler(handler), simba.WithDefaultStatus(http.StatusAccepted))
+	router.POST("/status", simba.JsonHandler(statusHandler))
+	router.POST("/no-body", simba.JsonHandler(noBodyHandler))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected int
+	}{
+		{name: "method default", method: http.MethodPost, path: "/users", expected: http.StatusCreated},
+		{name: "method without default", method: http.MethodPut, path: "/users", expected: http.StatusOK},
+		{name: "route default", method: http.MethodPost, path: "/jobs", expected: http.StatusAccepted},
+		{name: "handler status", method: http.MethodPost, path: "/status", expected: http.StatusOK},
+		{name: "no body", method: http.MethodPost, path: "/no-body", expected: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
 func TestRouter_Extend(t *testing.T) {
 	t.Parallel()
 
@@ -622,3 +880,235 @@ func TestRouter_HandlerWithPointerRequestBody(t *testing.T) {
 		assert.Equal(t, "pending", *resp.Status)
 	})
 }
+
+func TestRouter_HandleAll(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	router := simba.New().Router
+	router.HandleAll([]string{http.MethodGet, http.MethodPost}, []string{"/users", "/people"}, simba.JsonHandler(handler))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		for _, path := range []string{"/users", "/people"} {
+			req := httptest.NewRequest(method, path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+		}
+	}
+
+	routes := router.Routes()
+	assert.Len(t, routes, 4)
+	assert.Equal(t, "", routes[0].AliasOf)
+	for _, route := range routes[1:] {
+		assert.Equal(t, "GET /users", route.AliasOf)
+	}
+}
+
+func TestRouter_Routes(t *testing.T) {
+	t.Parallel()
+
+	router := simba.New().Router
+
+	router.POST("/users/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+	router.HandleHTTP(http.MethodGet, "/ws", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.GETWithMiddleware("/health/{id}", simba.JsonHandler(simbaTest.NoTagsHandler), func(next http.Handler) http.Handler { return next })
+
+	routes := router.Routes()
+	assert.Equal(t, 3, len(routes))
+
+	assert.Equal(t, http.MethodPost, routes[0].Method)
+	assert.Equal(t, "/users/{id}", routes[0].Path)
+	assert.Equal(t, mimetypes.ApplicationJSON, routes[0].Accepts)
+	assert.Equal(t, any(simbaTest.RequestBody{}), routes[0].ReqBody)
+
+	assert.Equal(t, http.MethodGet, routes[1].Method)
+	assert.Equal(t, "/ws", routes[1].Path)
+
+	assert.Equal(t, http.MethodGet, routes[2].Method)
+	assert.Equal(t, "/health/{id}", routes[2].Path)
+
+	// Routes remain available after documentation has been generated
+	assert.NoError(t, router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+	assert.Equal(t, 3, len(router.Routes()))
+}
+
+func TestRouter_Mount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("external handler", func(t *testing.T) {
+		router := simba.New().Router
+		router.Mount("/debug/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.URL.Path))
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/pprof/heap", w.Body.String())
+
+		routes := router.Routes()
+		assert.Equal(t, 1, len(routes))
+		assert.Equal(t, "/debug/", routes[0].Path)
+	})
+
+	t.Run("simba application", func(t *testing.T) {
+		subApp := simba.New()
+		subApp.Router.POST("/users/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+
+		router := simba.New().Router
+		router.Mount("/api/v1", subApp.Router)
+
+		body := strings.NewReader(`{"name":"John"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users/"+uuid.NewString(), body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		routes := router.Routes()
+		assert.Equal(t, 1, len(routes))
+		assert.Equal(t, "/api/v1/users/{id}", routes[0].Path)
+
+		assert.NoError(t, router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+		req = httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Contains(t, "/api/v1/users/{id}", w.Body.String())
+	})
+
+	t.Run("routes registered after mounting", func(t *testing.T) {
+		subApp := simba.New()
+		subApp.Router.POST("/users/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+
+		router := simba.New().Router
+		router.GET("/health/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+		router.Mount("/api/v1", subApp.Router)
+		router.GET("/status/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+		subApp.Router.PUT("/orders/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+
+		paths := make([]string, 0, 4)
+		for _, route := range router.Routes() {
+			paths = append(paths, route.Method+" "+route.Path)
+		}
+		assert.Equal(t, []string{"GET /health/{id}", "POST /api/v1/users/{id}", "PUT /api/v1/orders/{id}", "GET /status/{id}"}, paths)
+
+		assert.NoError(t, router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Contains(t, "/api/v1/orders/{id}", w.Body.String())
+	})
+}
+
+func TestRouter_Reload(t *testing.T) {
+	t.Parallel()
+
+	get := func(router *simba.Router, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	text := func(body string) simba.Handler {
+		return simba.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		router := simba.New().Router
+		router.GET("/v1", text("v1"))
+
+		err := router.Reload(context.Background(), func(r *simba.Router) {
+			r.GET("/v2", text("v2"))
+		})
+		assert.Equal(t, simba.ErrHotReloadDisabled, err)
+		assert.Equal(t, http.StatusOK, get(router, "/v1").Code)
+		assert.Equal(t, http.StatusNotFound, get(router, "/v2").Code)
+	})
+
+	t.Run("replaces routes", func(t *testing.T) {
+		t.Parallel()
+
+		app := simba.Default(settings.WithHotReload(true))
+		app.Router.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Middleware", "true")
+				next.ServeHTTP(w, r)
+			})
+		})
+		app.Router.GET("/greeting", text("hello"))
+		app.Router.GET("/removed", text("removed"))
+		assert.NoError(t, app.Router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+
+		err := app.Router.Reload(context.Background(), func(r *simba.Router) {
+			r.GET("/greeting", text("hi"))
+			r.POST("/users/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+		})
+		assert.NoError(t, err)
+
+		w := get(app.Router, "/greeting")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hi", w.Body.String())
+		assert.Equal(t, "true", w.Header().Get("X-Middleware"))
+
+		assert.Equal(t, http.StatusNotFound, get(app.Router, "/removed").Code)
+		assert.Equal(t, http.StatusOK, get(app.Router, "/health").Code)
+		assert.Len(t, app.Router.Routes(), 2)
+
+		w = get(app.Router, "/openapi.json")
+		assert.Contains(t, "/users/{id}", w.Body.String())
+		assert.False(t, strings.Contains(w.Body.String(), "/removed"))
+	})
+
+	t.Run("documentation generated after reload", func(t *testing.T) {
+		t.Parallel()
+
+		router := simba.New(settings.WithHotReload(true)).Router
+		router.GET("/removed", text("removed"))
+
+		err := router.Reload(context.Background(), func(r *simba.Router) {
+			r.POST("/users/{id}", simba.JsonHandler(simbaTest.NoTagsHandler))
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+
+		w := get(router, "/openapi.json")
+		assert.Contains(t, "/users/{id}", w.Body.String())
+		assert.False(t, strings.Contains(w.Body.String(), "/removed"))
+	})
+}
+
+func TestWithResponseContentType(t *testing.T) {
+	t.Parallel()
+
+	router := simba.New().Router
+	router.POST("/users/{id}", simba.WithResponseContentType(
+		simba.WithResponseContentType(simba.JsonHandler(simbaTest.NoTagsHandler), "text/csv", ""),
+		"application/xml", simbaTest.ResponseBody{},
+	))
+
+	routes := router.Routes()
+	assert.Equal(t, 1, len(routes))
+	assert.Equal(t, mimetypes.ApplicationJSON, routes[0].Produces)
+	assert.Equal(t, 2, len(routes[0].AlternativeResponses))
+	assert.Equal(t, "text/csv", routes[0].AlternativeResponses[0].ContentType)
+	assert.Equal(t, "application/xml", routes[0].AlternativeResponses[1].ContentType)
+
+	assert.NoError(t, router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Contains(t, `"text/csv"`, w.Body.String())
+}
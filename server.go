@@ -26,6 +26,15 @@ func (a *Application) Start() {
 		cancel()
 	}()
 
+	// Run start hooks before accepting requests, aborting the startup if one fails
+	if err := a.runStartHooks(ctx); err != nil {
+		log.Error("error starting application", "error", err)
+		if stopErr := a.Stop(); stopErr != nil {
+			log.Error("error shutting down server", "error", stopErr)
+		}
+		panic(err)
+	}
+
 	// Generate OpenAPI documentation in a goroutine
 	go func() {
 		log.Debug("generating OpenAPI documentation...")
@@ -44,7 +53,7 @@ func (a *Application) Start() {
 	// Run server in a goroutine
 	go func() {
 		log.Info("server listening on " + a.Server.Addr)
-		if err := a.Server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := a.listenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error("error starting server", "error", err)
 			panic(err)
 		}
@@ -61,6 +70,24 @@ func (a *Application) Start() {
 	}
 }
 
+// runStartHooks runs the hooks registered with OnStart in registration order, stopping at the first error.
+func (a *Application) runStartHooks(ctx context.Context) error {
+	for _, hook := range a.startHooks {
+		if err := hook(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listenAndServe starts the server, with TLS if a certificate and key file are configured.
+func (a *Application) listenAndServe() error {
+	if a.Settings.TLSCertFile != "" && a.Settings.TLSKeyFile != "" {
+		return a.Server.ListenAndServeTLS(a.Settings.TLSCertFile, a.Settings.TLSKeyFile)
+	}
+	return a.Server.ListenAndServe()
+}
+
 func (a *Application) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,111 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FromEnv loads settings from well-known environment variables used by container platforms
+// and OpenTelemetry, in addition to the SIMBA_ prefixed variables that are always loaded:
+//
+//   - PORT sets the server port
+//   - LOG_LEVEL sets the level of the default logger
+//   - TLS_CERT_FILE and TLS_KEY_FILE set the TLS certificate and key files
+//   - OTEL_SERVICE_NAME sets the telemetry service name
+//   - OTEL_EXPORTER_OTLP_ENDPOINT sets the trace and metrics exporter endpoints, which can be set
+//     separately with OTEL_EXPORTER_OTLP_TRACES_ENDPOINT and OTEL_EXPORTER_OTLP_METRICS_ENDPOINT.
+//     An http:// endpoint is insecure and an https:// endpoint is secure
+//   - OTEL_EXPORTER_OTLP_INSECURE sets whether the exporter connections are insecure
+//
+// Explicit options take precedence over environment variables, and SIMBA_ prefixed variables
+// take precedence over the well-known variables they overlap with.
+func FromEnv() Option {
+	return func(s *Simba) {
+		s.fromEnv = true
+	}
+}
+
+// loadWellKnownEnv applies the well-known environment variables that are set,
+// skipping those whose SIMBA_ prefixed counterpart is set.
+func (s *Simba) loadWellKnownEnv() error {
+	if port, ok := s.wellKnownEnv("PORT", "SIMBA_SERVER_PORT"); ok {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("invalid PORT %q: %w", port, err)
+		}
+		s.Port = p
+	}
+
+	if level, ok := s.wellKnownEnv("LOG_LEVEL", "SIMBA_LOG_LEVEL"); ok {
+		s.Level = level
+	}
+	if certFile, ok := s.wellKnownEnv("TLS_CERT_FILE", "SIMBA_SERVER_TLS_CERT_FILE"); ok {
+		s.TLSCertFile = certFile
+	}
+	if keyFile, ok := s.wellKnownEnv("TLS_KEY_FILE", "SIMBA_SERVER_TLS_KEY_FILE"); ok {
+		s.TLSKeyFile = keyFile
+	}
+	if serviceName, ok := s.wellKnownEnv("OTEL_SERVICE_NAME", "SIMBA_TELEMETRY_SERVICE_NAME"); ok {
+		s.Telemetry.ServiceName = serviceName
+	}
+
+	if insecure, ok := s.wellKnownEnv("OTEL_EXPORTER_OTLP_INSECURE", ""); ok {
+		b, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return fmt.Errorf("invalid OTEL_EXPORTER_OTLP_INSECURE %q: %w", insecure, err)
+		}
+		if s.envGetter("SIMBA_TELEMETRY_TRACING_INSECURE") == "" {
+			s.Tracing.Insecure = b
+		}
+		if s.envGetter("SIMBA_TELEMETRY_METRICS_INSECURE") == "" {
+			s.Metrics.Insecure = b
+		}
+	}
+
+	endpoint, _ := s.wellKnownEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if err := s.loadOTLPEndpoint(endpoint, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "SIMBA_TELEMETRY_TRACING", &s.Tracing.Endpoint, &s.Tracing.Insecure); err != nil {
+		return err
+	}
+	return s.loadOTLPEndpoint(endpoint, "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "SIMBA_TELEMETRY_METRICS", &s.Metrics.Endpoint, &s.Metrics.Insecure)
+}
+
+// loadOTLPEndpoint sets an exporter endpoint from the signal specific variable, falling back to the
+// shared endpoint. The exporters take a host:port, so the scheme of a URL is stripped and used to
+// determine whether the connection is insecure. simbaPrefix is the prefix of the SIMBA_ prefixed
+// endpoint and insecure variables that take precedence.
+func (s *Simba) loadOTLPEndpoint(shared string, key string, simbaPrefix string, endpoint *string, insecure *bool) error {
+	value, ok := s.wellKnownEnv(key, simbaPrefix+"_ENDPOINT")
+	if !ok {
+		if shared == "" || s.envGetter(simbaPrefix+"_ENDPOINT") != "" {
+			return nil
+		}
+		value = shared
+	}
+
+	if !strings.Contains(value, "://") {
+		*endpoint = value
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid OTLP endpoint %q", value)
+	}
+	*endpoint = u.Host
+	if s.envGetter(simbaPrefix+"_INSECURE") == "" {
+		*insecure = u.Scheme == "http"
+	}
+	return nil
+}
+
+// wellKnownEnv returns the value of the environment variable if it is set
+// and the SIMBA_ prefixed variable overriding it is not.
+func (s *Simba) wellKnownEnv(key string, simbaKey string) (string, bool) {
+	if simbaKey != "" && s.envGetter(simbaKey) != "" {
+		return "", false
+	}
+	value := strings.TrimSpace(s.envGetter(key))
+	return value, value != ""
+}
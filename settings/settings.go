@@ -1,12 +1,21 @@
 package settings
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"net/netip"
 	"os"
+	"strings"
+	"time"
 
 	configloader "github.com/sillen102/config-loader"
 
+	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
 )
 
 // Simba is a struct that holds the application settings.
@@ -27,10 +36,16 @@ type Simba struct {
 	// Telemetry settings
 	Telemetry `yaml:"telemetry" exhaustruct:"optional"`
 
+	// Logging settings
+	Logging `yaml:"logging" exhaustruct:"optional"`
+
 	// Logger settings
 	Logger *slog.Logger `yaml:"-" env:"-"`
 
 	envGetter func(string) string
+
+	// fromEnv enables loading well-known environment variables, see [FromEnv]
+	fromEnv bool `exhaustruct:"optional"`
 }
 
 type Application struct {
@@ -46,6 +61,74 @@ type Server struct {
 
 	// Addr is the address the server will listen on
 	Port int `yaml:"port" env:"SIMBA_SERVER_PORT" default:"9999"`
+
+	// TLSCertFile is the path to the TLS certificate. The server is started with TLS
+	// if both TLSCertFile and TLSKeyFile are set
+	TLSCertFile string `yaml:"tls-cert-file" env:"SIMBA_SERVER_TLS_CERT_FILE" exhaustruct:"optional"`
+
+	// TLSKeyFile is the path to the TLS private key
+	TLSKeyFile string `yaml:"tls-key-file" env:"SIMBA_SERVER_TLS_KEY_FILE" exhaustruct:"optional"`
+
+	// MaxHeaderBytes is the maximum size of the Request headers, including the Request line, in bytes.
+	// Zero means the default of 1 MB
+	MaxHeaderBytes int `yaml:"max-header-bytes" env:"SIMBA_SERVER_MAX_HEADER_BYTES" default:"0" exhaustruct:"optional"`
+
+	// HotReload allows the routes of the router to be replaced with Router.Reload while the
+	// server is running. Meant for development only and should not be enabled in production
+	HotReload bool `yaml:"hot-reload" env:"SIMBA_SERVER_HOT_RELOAD" default:"false" exhaustruct:"optional"`
+
+	// MaxConcurrentRequests is the maximum number of requests handled at once. Requests beyond the limit wait
+	// in a queue or are rejected with a 503 Service Unavailable and a Retry-After header. Zero means no limit
+	MaxConcurrentRequests int `yaml:"max-concurrent-requests" env:"SIMBA_SERVER_MAX_CONCURRENT_REQUESTS" default:"0" exhaustruct:"optional"`
+
+	// MaxQueuedRequests is the maximum number of requests waiting for a slot when MaxConcurrentRequests is
+	// reached. Zero rejects requests beyond the limit right away
+	MaxQueuedRequests int `yaml:"max-queued-requests" env:"SIMBA_SERVER_MAX_QUEUED_REQUESTS" default:"0" exhaustruct:"optional"`
+
+	// QueueTimeoutMillis is the maximum time in milliseconds a request waits in the queue before it is rejected.
+	// Zero means waiting until the client gives up
+	QueueTimeoutMillis int `yaml:"queue-timeout-millis" env:"SIMBA_SERVER_QUEUE_TIMEOUT_MILLIS" default:"0" exhaustruct:"optional"`
+}
+
+// Logging holds the Simba for the default logger.
+type Logging struct {
+
+	// Level is the minimum level logged by the default logger (debug, info, warn or error).
+	// If empty, [slog.Default] is used as is. Ignored if a logger is set with [WithLogger]
+	Level string `yaml:"level" env:"SIMBA_LOG_LEVEL" exhaustruct:"optional"`
+
+	// AccessLog enables the access log line written for every request by the default application
+	AccessLog bool `yaml:"access-log" env:"SIMBA_LOG_ACCESS_LOG" default:"true" exhaustruct:"optional"`
+
+	// AccessLogLevel is the level of the access log lines (debug, info, warn or error)
+	AccessLogLevel string `yaml:"access-log-level" env:"SIMBA_LOG_ACCESS_LOG_LEVEL" default:"info" exhaustruct:"optional"`
+
+	// AccessLogFields are the fields of the access log lines, in order, such as method, path, status,
+	// statusClass, bytes, latencyMs, latencyBucket, clientIp and principal. If empty, those fields are logged
+	AccessLogFields []string `yaml:"access-log-fields" env:"SIMBA_LOG_ACCESS_LOG_FIELDS" exhaustruct:"optional"`
+}
+
+// AccessLogSlogLevel parses the configured access log level. An empty level is info.
+func (l Logging) AccessLogSlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if l.AccessLogLevel == "" {
+		return level, nil
+	}
+	err := level.UnmarshalText([]byte(l.AccessLogLevel))
+	return level, err
+}
+
+// AccessLogFieldList returns the configured access log fields.
+// If none are configured, [models.DefaultAccessLogFields] are returned.
+func (l Logging) AccessLogFieldList() []models.AccessLogField {
+	if len(l.AccessLogFields) == 0 {
+		return models.DefaultAccessLogFields
+	}
+	fields := make([]models.AccessLogField, 0, len(l.AccessLogFields))
+	for _, field := range l.AccessLogFields {
+		fields = append(fields, models.AccessLogField(strings.TrimSpace(field)))
+	}
+	return fields
 }
 
 // Request holds the Simba for the Request processing.
@@ -60,16 +143,155 @@ type Request struct {
 	// If set to "disabled", the Request body will not be logged, which is also the default
 	LogRequestBody bool `yaml:"log-request-body" env:"SIMBA_REQUEST_LOG_REQUEST_BODY" default:"false"`
 
+	// EnforceContentType will reject Requests with a body whose Content-Type does not match
+	// the media type accepted by the route with a 415 Unsupported Media Type
+	EnforceContentType bool `yaml:"enforce-content-type" env:"SIMBA_REQUEST_ENFORCE_CONTENT_TYPE" default:"false" exhaustruct:"optional"`
+
+	// UseNumber decodes numbers in JSON Request bodies into interface values, such as map[string]any,
+	// as json.Number instead of float64, so large integers and decimals keep their precision
+	UseNumber bool `yaml:"use-number" env:"SIMBA_REQUEST_USE_NUMBER" default:"false" exhaustruct:"optional"`
+
+	// TrimStrings trims leading and trailing whitespace from all the strings of Request params and bodies before
+	// they're validated. Fields tagged with normalize:"-" are left as is
+	TrimStrings bool `yaml:"trim-strings" env:"SIMBA_REQUEST_TRIM_STRINGS" default:"false" exhaustruct:"optional"`
+
+	// NormalizeUnicode normalizes all the strings of Request params and bodies to Unicode NFC before they're
+	// validated. Fields tagged with normalize:"-" are left as is
+	NormalizeUnicode bool `yaml:"normalize-unicode" env:"SIMBA_REQUEST_NORMALIZE_UNICODE" default:"false" exhaustruct:"optional"`
+
+	// FailFastValidation stops validating the params and body of a Request at the first error, returning only
+	// that error instead of all of them. This saves work for large bodies at the cost of complete error reports
+	FailFastValidation bool `yaml:"fail-fast-validation" env:"SIMBA_REQUEST_FAIL_FAST_VALIDATION" default:"false" exhaustruct:"optional"`
+
+	// LogErrorSource includes the Go file and line of the route handler in the log entries of errors
+	// returned by the handler, to quickly locate the failing handler. Meant for debugging
+	LogErrorSource bool `yaml:"log-error-source" env:"SIMBA_REQUEST_LOG_ERROR_SOURCE" default:"false" exhaustruct:"optional"`
+
+	// DefaultStatuses maps HTTP methods to the status of successful responses with a body whose handler
+	// doesn't set one, such as POST: 201. Methods not listed default to 200. Responses without a body are 204
+	DefaultStatuses map[string]int `yaml:"default-statuses" env:"-" exhaustruct:"optional"`
+
+	// MaxBodySize is the maximum size of a JSON Request body in bytes.
+	// Larger bodies are rejected with a 413 Request Entity Too Large. Zero means no limit
+	MaxBodySize int64 `yaml:"max-body-size" env:"SIMBA_REQUEST_MAX_BODY_SIZE" default:"0" exhaustruct:"optional"`
+
+	// MaxJSONDepth is the maximum nesting depth of objects and arrays in a JSON Request body. Deeper bodies are
+	// rejected with a 422 Unprocessable Entity while they are read, before they are decoded. Zero means no limit
+	MaxJSONDepth int `yaml:"max-json-depth" env:"SIMBA_REQUEST_MAX_JSON_DEPTH" default:"0" exhaustruct:"optional"`
+
+	// MaxMultipartFiles is the maximum number of file parts in a multipart Request body. Requests with more files
+	// are rejected with a 413 Request Entity Too Large while they are read. Zero means no limit
+	MaxMultipartFiles int `yaml:"max-multipart-files" env:"SIMBA_REQUEST_MAX_MULTIPART_FILES" default:"0" exhaustruct:"optional"`
+
+	// MaxMultipartSize is the maximum total size in bytes of the file parts in a multipart Request body. Larger
+	// uploads are rejected with a 413 Request Entity Too Large while they are read. Zero means no limit
+	MaxMultipartSize int64 `yaml:"max-multipart-size" env:"SIMBA_REQUEST_MAX_MULTIPART_SIZE" default:"0" exhaustruct:"optional"`
+
+	// MaxURILength is the maximum length of the Request URI (path and query) in bytes.
+	// Longer URIs are rejected with a 414 URI Too Long. Zero means no limit
+	MaxURILength int `yaml:"max-uri-length" env:"SIMBA_REQUEST_MAX_URI_LENGTH" default:"0" exhaustruct:"optional"`
+
+	// MaxQueryLength is the maximum length of the Request query string in bytes.
+	// Longer query strings are rejected with a 414 URI Too Long. Zero means no limit
+	MaxQueryLength int `yaml:"max-query-length" env:"SIMBA_REQUEST_MAX_QUERY_LENGTH" default:"0" exhaustruct:"optional"`
+
+	// DefaultLocale is the locale of requests whose Accept-Language header doesn't match a supported locale
+	DefaultLocale string `yaml:"default-locale" env:"SIMBA_REQUEST_DEFAULT_LOCALE" default:"en" exhaustruct:"optional"`
+
+	// SupportedLocales are the locales negotiated from the Accept-Language header of requests, such as en or pt-BR.
+	// If empty, every request has the default locale
+	SupportedLocales []string `yaml:"supported-locales" env:"SIMBA_REQUEST_SUPPORTED_LOCALES" exhaustruct:"optional"`
+
+	// DisabledFeatureStatus is the status of responses to routes gated behind a disabled feature flag:
+	// 404 Not Found to hide the route or 403 Forbidden
+	DisabledFeatureStatus int `yaml:"disabled-feature-status" env:"SIMBA_REQUEST_DISABLED_FEATURE_STATUS" default:"404" exhaustruct:"optional"`
+
+	// EmptyParams determines how query, header, cookie and path parameters sent with an empty value are bound:
+	// Missing treats them as not sent and Present as sent with the zero value
+	EmptyParams models.EmptyParams `yaml:"empty-params" env:"SIMBA_REQUEST_EMPTY_PARAMS" default:"Missing" exhaustruct:"optional"`
+
 	// TraceIDMode determines how the Trace ID will be handled
 	TraceIDMode models.TraceIDMode `yaml:"trace-id-mode" env:"SIMBA_TRACE_ID_MODE" default:"AcceptFromHeader"`
+
+	// ErrorFormat selects the built-in format used for error responses.
+	// Ignored if a custom ErrorFormatter is configured
+	ErrorFormat models.ErrorFormat `yaml:"error-format" env:"SIMBA_REQUEST_ERROR_FORMAT" default:"Default"`
+
+	// ErrorFormatter formats error responses into a custom envelope.
+	// If nil, errors are written as a [simbaErrors.ErrorResponse]
+	ErrorFormatter simbaErrors.ErrorFormatter `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// ValidationErrorFormat selects the format of the validation error details in error responses.
+	// A Request can select another format with the validation-errors query or Accept header parameter
+	ValidationErrorFormat models.ValidationErrorFormat `yaml:"validation-error-format" env:"SIMBA_REQUEST_VALIDATION_ERROR_FORMAT" default:"Default" exhaustruct:"optional"`
+
+	// TrustedProxies is a list of IP addresses or CIDR ranges of proxies allowed to set the client IP header.
+	// If empty, the client IP header is ignored and the client IP is the address of the connection
+	TrustedProxies []string `yaml:"trusted-proxies" env:"SIMBA_REQUEST_TRUSTED_PROXIES" exhaustruct:"optional"`
+
+	// ClientIPHeader is the header used to determine the client IP of requests from trusted proxies
+	ClientIPHeader models.ClientIPHeader `yaml:"client-ip-header" env:"SIMBA_REQUEST_CLIENT_IP_HEADER" default:"X-Forwarded-For" exhaustruct:"optional"`
+
+	// Capture captures sampled requests for debugging. Capturing is disabled if nil
+	Capture *RequestCapture `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// TimeFormat is the format time.Time values are written in in JSON responses: RFC3339, Unix, UnixMilli
+	// or a time layout such as 2006-01-02. Request bodies are always parsed as RFC3339
+	TimeFormat models.TimeFormat `yaml:"time-format" env:"SIMBA_REQUEST_TIME_FORMAT" default:"RFC3339" exhaustruct:"optional"`
+
+	// ResponseTransformer transforms every JSON response body before it is encoded,
+	// e.g. to wrap responses in an envelope. Error, file and streamed responses are not transformed
+	ResponseTransformer func(ctx context.Context, body any) any `yaml:"-" env:"-" exhaustruct:"optional"`
+}
+
+// RequestCapture holds the sink and options for capturing requests for debugging.
+type RequestCapture struct {
+	Sink    models.CaptureSink
+	Options models.CaptureOptions
 }
 
 func DefaultRequestSettings() Request {
 	return Request{
-		AllowUnknownFields: true,
-		LogRequestBody:     false,
-		TraceIDMode:        models.AcceptFromHeader,
+		AllowUnknownFields:    true,
+		LogRequestBody:        false,
+		EmptyParams:           models.EmptyParamsAsMissing,
+		DisabledFeatureStatus: http.StatusNotFound,
+		DefaultLocale:         "en",
+		TraceIDMode:           models.AcceptFromHeader,
+		ErrorFormat:           models.DefaultErrorFormat,
+		ValidationErrorFormat: models.DefaultValidationErrorFormat,
+		TimeFormat:            models.RFC3339TimeFormat,
+		ClientIPHeader:        models.XForwardedFor,
+	}
+}
+
+// TrustedProxyPrefixes parses TrustedProxies into IP prefixes.
+// A single IP address is parsed as a prefix that only contains that address.
+func (r Request) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(r.TrustedProxies))
+	for _, proxy := range r.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
 	}
+	return prefixes, nil
 }
 
 type Docs struct {
@@ -88,6 +310,29 @@ type Docs struct {
 
 	// ServiceName is the name of the service
 	ServiceName string
+
+	// ErrorSchema is the error response body documented for error responses in the OpenAPI documentation.
+	// If nil, [simbaErrors.ErrorResponse] is documented
+	ErrorSchema any `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// ErrorContentType is the content type documented for error responses in the OpenAPI documentation
+	ErrorContentType string `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// ErrorExamples holds the example bodies, by HTTP status, documented for error responses in the OpenAPI documentation
+	ErrorExamples map[int]any `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// Tags holds the metadata of tags listed in the top-level tags of the OpenAPI documentation
+	Tags []openapiModels.Tag `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// ResponseEnvelope is the envelope success responses are documented in, see [WithResponseEnvelope]
+	ResponseEnvelope any `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// ResponseEnvelopeField is the JSON name of the envelope property holding the response body
+	ResponseEnvelopeField string `yaml:"-" env:"-" exhaustruct:"optional"`
+
+	// OperationExtensions documents the rate limit and deprecation dates of routes in the x-ratelimit and
+	// x-deprecated extensions of their operations, for API gateways
+	OperationExtensions bool `yaml:"operation-extensions" env:"SIMBA_DOCS_OPERATION_EXTENSIONS" default:"false" exhaustruct:"optional"`
 }
 
 // Telemetry holds the settings for OpenTelemetry integration.
@@ -109,6 +354,10 @@ type Telemetry struct {
 
 	// Environment is the deployment environment (development, staging, production, etc.)
 	Environment string `yaml:"environment" env:"SIMBA_TELEMETRY_ENVIRONMENT" default:"development"`
+
+	// RedactPaths are path patterns, such as /reset/{token} or /files/{path...}, whose matching request
+	// paths contain sensitive data and are recorded in spans and metrics as the pattern instead
+	RedactPaths []string `yaml:"redact-paths" env:"SIMBA_TELEMETRY_REDACT_PATHS" exhaustruct:"optional"`
 }
 
 // TracingConfig holds the configuration for distributed tracing.
@@ -227,6 +476,299 @@ func WithDocsUIPath(path string) Option {
 	}
 }
 
+// WithEnforceContentType sets whether Requests with a mismatched Content-Type are rejected with 415.
+func WithEnforceContentType(enforce bool) Option {
+	return func(s *Simba) {
+		s.EnforceContentType = enforce
+	}
+}
+
+// WithMaxBodySize sets the maximum size of a JSON request body in bytes.
+func WithMaxBodySize(size int64) Option {
+	return func(s *Simba) {
+		s.MaxBodySize = size
+	}
+}
+
+// WithMaxJSONDepth sets the maximum nesting depth of objects and arrays in a JSON request body.
+func WithMaxJSONDepth(depth int) Option {
+	return func(s *Simba) {
+		s.MaxJSONDepth = depth
+	}
+}
+
+// WithMaxMultipartFiles sets the maximum number of file parts in a multipart request body.
+func WithMaxMultipartFiles(files int) Option {
+	return func(s *Simba) {
+		s.MaxMultipartFiles = files
+	}
+}
+
+// WithMaxMultipartSize sets the maximum total size in bytes of the file parts in a multipart request body.
+func WithMaxMultipartSize(size int64) Option {
+	return func(s *Simba) {
+		s.MaxMultipartSize = size
+	}
+}
+
+// WithMaxURILength sets the maximum length of a request URI (path and query) in bytes.
+func WithMaxURILength(length int) Option {
+	return func(s *Simba) {
+		s.MaxURILength = length
+	}
+}
+
+// WithMaxQueryLength sets the maximum length of a request query string in bytes.
+func WithMaxQueryLength(length int) Option {
+	return func(s *Simba) {
+		s.MaxQueryLength = length
+	}
+}
+
+// WithErrorFormat sets the built-in format used for error responses.
+func WithErrorFormat(format models.ErrorFormat) Option {
+	return func(s *Simba) {
+		s.ErrorFormat = format
+	}
+}
+
+// WithValidationErrorFormat sets the default format of the validation error details in error responses.
+func WithValidationErrorFormat(format models.ValidationErrorFormat) Option {
+	return func(s *Simba) {
+		s.ValidationErrorFormat = format
+	}
+}
+
+// WithUseNumber sets whether numbers in JSON request bodies are decoded into interface values as json.Number.
+func WithUseNumber(useNumber bool) Option {
+	return func(s *Simba) {
+		s.UseNumber = useNumber
+	}
+}
+
+// WithTrimStrings sets whether leading and trailing whitespace is trimmed from all the strings of request params
+// and bodies before they're validated.
+func WithTrimStrings(enabled bool) Option {
+	return func(s *Simba) {
+		s.TrimStrings = enabled
+	}
+}
+
+// WithNormalizeUnicode sets whether all the strings of request params and bodies are normalized to Unicode NFC
+// before they're validated.
+func WithNormalizeUnicode(enabled bool) Option {
+	return func(s *Simba) {
+		s.NormalizeUnicode = enabled
+	}
+}
+
+// WithFailFastValidation sets whether validation of request params and bodies stops at the first error.
+func WithFailFastValidation(failFast bool) Option {
+	return func(s *Simba) {
+		s.FailFastValidation = failFast
+	}
+}
+
+// WithDefaultStatus sets the status of successful responses with a body to requests with the given method,
+// used if the handler doesn't set one, e.g. WithDefaultStatus(http.MethodPost, http.StatusCreated).
+func WithDefaultStatus(method string, status int) Option {
+	return func(s *Simba) {
+		if s.DefaultStatuses == nil {
+			s.DefaultStatuses = make(map[string]int)
+		}
+		s.DefaultStatuses[strings.ToUpper(method)] = status
+	}
+}
+
+// WithLogErrorSource sets whether the Go file and line of the route handler is included in error logs.
+func WithLogErrorSource(enabled bool) Option {
+	return func(s *Simba) {
+		s.LogErrorSource = enabled
+	}
+}
+
+// WithLocales sets the locales negotiated from the Accept-Language header of requests and the default locale
+// of requests that don't match any of them. Validation error messages are translated to the negotiated locale.
+func WithLocales(defaultLocale string, supported ...string) Option {
+	return func(s *Simba) {
+		s.DefaultLocale = defaultLocale
+		s.SupportedLocales = supported
+	}
+}
+
+// WithDisabledFeatureStatus sets the status of responses to routes gated behind a disabled feature flag,
+// 404 Not Found or 403 Forbidden.
+func WithDisabledFeatureStatus(status int) Option {
+	return func(s *Simba) {
+		s.DisabledFeatureStatus = status
+	}
+}
+
+// WithEmptyParams sets how parameters sent with an empty value are bound.
+func WithEmptyParams(mode models.EmptyParams) Option {
+	return func(s *Simba) {
+		s.EmptyParams = mode
+	}
+}
+
+// WithTimeFormat sets the format time.Time values are written in in JSON responses, such as
+// [models.UnixTimeFormat] or a time layout.
+func WithTimeFormat(format models.TimeFormat) Option {
+	return func(s *Simba) {
+		s.TimeFormat = format
+	}
+}
+
+// WithErrorFormatter sets a custom formatter for error responses.
+// Use [WithErrorSchema] to document the resulting error body in the OpenAPI documentation.
+func WithErrorFormatter(formatter simbaErrors.ErrorFormatter) Option {
+	return func(s *Simba) {
+		s.ErrorFormatter = formatter
+	}
+}
+
+// WithErrorSchema sets the error response body and content type documented in the OpenAPI documentation.
+func WithErrorSchema(schema any, contentType string) Option {
+	return func(s *Simba) {
+		s.ErrorSchema = schema
+		s.ErrorContentType = contentType
+	}
+}
+
+// WithTrustedProxies sets the IP addresses or CIDR ranges of proxies allowed to set the client IP header.
+func WithTrustedProxies(proxies ...string) Option {
+	return func(s *Simba) {
+		s.TrustedProxies = proxies
+	}
+}
+
+// WithClientIPHeader sets the header used to determine the client IP of requests from trusted proxies.
+func WithClientIPHeader(header models.ClientIPHeader) Option {
+	return func(s *Simba) {
+		s.ClientIPHeader = header
+	}
+}
+
+// WithErrorExamples sets example error response bodies, by HTTP status, documented in the OpenAPI documentation.
+// The examples should match the documented error schema.
+func WithErrorExamples(examples map[int]any) Option {
+	return func(s *Simba) {
+		s.ErrorExamples = examples
+	}
+}
+
+// WithTags sets the metadata of tags in the OpenAPI documentation, such as a description and a link to
+// external documentation.
+func WithTags(tags ...openapiModels.Tag) Option {
+	return func(s *Simba) {
+		s.Tags = tags
+	}
+}
+
+// WithRequestCapture captures sampled requests, including their headers and body, and the resulting
+// status into the sink for debugging. Sensitive headers are redacted.
+func WithRequestCapture(sink models.CaptureSink, opts models.CaptureOptions) Option {
+	return func(s *Simba) {
+		s.Capture = &RequestCapture{Sink: sink, Options: opts}
+	}
+}
+
+// WithResponseTransformer sets a function that transforms every JSON response body before it is encoded,
+// e.g. to wrap responses in an envelope or add a server timestamp.
+// Use [WithResponseEnvelope] to document the resulting body in the OpenAPI documentation.
+func WithResponseTransformer(transformer func(ctx context.Context, body any) any) Option {
+	return func(s *Simba) {
+		s.ResponseTransformer = transformer
+	}
+}
+
+// WithResponseEnvelope documents success responses as wrapped in the envelope in the OpenAPI documentation.
+// The envelope is a struct and field is the JSON name of its property holding the response body.
+func WithResponseEnvelope(envelope any, field string) Option {
+	return func(s *Simba) {
+		s.ResponseEnvelope = envelope
+		s.ResponseEnvelopeField = field
+	}
+}
+
+// WithOperationExtensions documents the rate limit and deprecation dates of routes in the x-ratelimit and
+// x-deprecated extensions of their operations in the OpenAPI documentation, for API gateways that configure
+// themselves from the documentation.
+func WithOperationExtensions(enabled bool) Option {
+	return func(s *Simba) {
+		s.OperationExtensions = enabled
+	}
+}
+
+// WithTLS sets the TLS certificate and key files the server is started with.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Simba) {
+		s.TLSCertFile = certFile
+		s.TLSKeyFile = keyFile
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of the request headers in bytes.
+func WithMaxHeaderBytes(maxBytes int) Option {
+	return func(s *Simba) {
+		s.MaxHeaderBytes = maxBytes
+	}
+}
+
+// WithMaxConcurrentRequests sets the maximum number of requests handled at once.
+func WithMaxConcurrentRequests(limit int) Option {
+	return func(s *Simba) {
+		s.MaxConcurrentRequests = limit
+	}
+}
+
+// WithRequestQueue sets the maximum number of requests waiting for a slot when the maximum number of
+// concurrent requests is reached, and how long they wait before being rejected.
+func WithRequestQueue(maxQueued int, timeout time.Duration) Option {
+	return func(s *Simba) {
+		s.MaxQueuedRequests = maxQueued
+		s.QueueTimeoutMillis = int(timeout.Milliseconds())
+	}
+}
+
+// WithHotReload sets whether the routes can be replaced while the server is running.
+func WithHotReload(enabled bool) Option {
+	return func(s *Simba) {
+		s.HotReload = enabled
+	}
+}
+
+// WithLogLevel sets the minimum level logged by the default logger.
+func WithLogLevel(level slog.Level) Option {
+	return func(s *Simba) {
+		s.Level = level.String()
+	}
+}
+
+// WithAccessLog sets whether the default application writes an access log line for every request.
+func WithAccessLog(enabled bool) Option {
+	return func(s *Simba) {
+		s.AccessLog = enabled
+	}
+}
+
+// WithAccessLogLevel sets the level of the access log lines.
+func WithAccessLogLevel(level slog.Level) Option {
+	return func(s *Simba) {
+		s.AccessLogLevel = level.String()
+	}
+}
+
+// WithAccessLogFields sets the fields of the access log lines, in order.
+func WithAccessLogFields(fields ...models.AccessLogField) Option {
+	return func(s *Simba) {
+		s.AccessLogFields = make([]string, 0, len(fields))
+		for _, field := range fields {
+			s.AccessLogFields = append(s.AccessLogFields, field.String())
+		}
+	}
+}
+
 // WithLogger sets the logger.
 func WithLogger(logger *slog.Logger) Option {
 	return func(s *Simba) {
@@ -265,24 +807,48 @@ func Load(opts ...Option) (*Simba, error) {
 		work.Logger = savedLogger
 	}
 
+	if work.fromEnv {
+		if err = work.loadWellKnownEnv(); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, opt := range opts {
 		opt(work)
 	}
 
+	if err = work.Validate(); err != nil {
+		return nil, err
+	}
+
 	logger := work.Logger
 	if logger == nil {
 		logger = slog.Default()
+		if work.Level != "" {
+			level, _ := work.logLevel()
+			logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+		}
 	}
 
 	docs := work.Docs
 	docs.ServiceName = work.Name
 
+	request := work.Request
+	if request.ErrorFormat == models.ProblemDetails && request.ErrorFormatter == nil {
+		request.ErrorFormatter = simbaErrors.ProblemDetailsFormatter
+		if docs.ErrorSchema == nil {
+			docs.ErrorSchema = simbaErrors.ProblemDetails{}
+			docs.ErrorContentType = mimetypes.ApplicationProblemJSON
+		}
+	}
+
 	return &Simba{
 		Application: work.Application,
 		Server:      work.Server,
-		Request:     work.Request,
+		Request:     request,
 		Docs:        docs,
 		Telemetry:   work.Telemetry,
+		Logging:     work.Logging,
 		Logger:      logger,
 		envGetter:   work.envGetter,
 	}, nil
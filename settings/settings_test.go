@@ -1,12 +1,17 @@
 package settings_test
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sillen102/simba/models"
 	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaErrors"
 	"github.com/sillen102/simba/simbaTest/assert"
 )
 
@@ -94,6 +99,13 @@ func TestWithServerPort(t *testing.T) {
 	assert.Equal(t, 8080, s.Port)
 }
 
+func TestLoadMaxHeaderBytesFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_SERVER_MAX_HEADER_BYTES", "16384")))
+	assert.NoError(t, err)
+	assert.Equal(t, 16384, s.MaxHeaderBytes)
+}
+
 func TestLoadAllowUnknownFieldsDefault(t *testing.T) {
 	t.Parallel()
 	s, err := settings.Load()
@@ -115,6 +127,75 @@ func TestWithAllowUnknownFields(t *testing.T) {
 	assert.False(t, s.AllowUnknownFields)
 }
 
+func TestLoadUseNumberFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_REQUEST_USE_NUMBER", "true")))
+	assert.NoError(t, err)
+	assert.True(t, s.UseNumber)
+}
+
+func TestLoadStringNormalizationFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(envGetter(map[string]string{
+		"SIMBA_REQUEST_TRIM_STRINGS":      "true",
+		"SIMBA_REQUEST_NORMALIZE_UNICODE": "true",
+	})))
+	assert.NoError(t, err)
+	assert.True(t, s.TrimStrings)
+	assert.True(t, s.NormalizeUnicode)
+}
+
+func TestLoadFailFastValidationFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_REQUEST_FAIL_FAST_VALIDATION", "true")))
+	assert.NoError(t, err)
+	assert.True(t, s.FailFastValidation)
+}
+
+func TestLoadLogErrorSourceFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_REQUEST_LOG_ERROR_SOURCE", "true")))
+	assert.NoError(t, err)
+	assert.True(t, s.LogErrorSource)
+}
+
+func TestLoadAccessLogDefault(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load()
+	assert.NoError(t, err)
+	assert.True(t, s.AccessLog)
+	assert.Equal(t, "info", s.AccessLogLevel)
+	assert.Equal(t, models.DefaultAccessLogFields, s.AccessLogFieldList())
+}
+
+func TestLoadAccessLogFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(envGetter(map[string]string{
+		"SIMBA_LOG_ACCESS_LOG_LEVEL":  "debug",
+		"SIMBA_LOG_ACCESS_LOG_FIELDS": "method, path,status",
+	})))
+	assert.NoError(t, err)
+	level, err := s.AccessLogSlogLevel()
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, level)
+	assert.Equal(t, []models.AccessLogField{models.AccessLogMethod, models.AccessLogPath, models.AccessLogStatus}, s.AccessLogFieldList())
+}
+
+func TestWithAccessLog(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(
+		settings.WithAccessLog(false),
+		settings.WithAccessLogLevel(slog.LevelWarn),
+		settings.WithAccessLogFields(models.AccessLogStatusClass),
+	)
+	assert.NoError(t, err)
+	assert.False(t, s.AccessLog)
+	level, err := s.AccessLogSlogLevel()
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+	assert.Equal(t, []models.AccessLogField{models.AccessLogStatusClass}, s.AccessLogFieldList())
+}
+
 func TestLoadLogRequestBodyDefault(t *testing.T) {
 	t.Parallel()
 	s, err := settings.Load()
@@ -241,6 +322,60 @@ func TestWithDocsUIPath(t *testing.T) {
 	assert.Equal(t, "/api-docs", s.DocsUIPath)
 }
 
+func TestLoadOperationExtensions(t *testing.T) {
+	t.Parallel()
+
+	s, err := settings.Load()
+	assert.NoError(t, err)
+	assert.False(t, s.OperationExtensions)
+
+	s, err = settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_DOCS_OPERATION_EXTENSIONS", "true")))
+	assert.NoError(t, err)
+	assert.True(t, s.OperationExtensions)
+
+	s, err = settings.Load(settings.WithOperationExtensions(true))
+	assert.NoError(t, err)
+	assert.True(t, s.OperationExtensions)
+}
+
+func TestLoadLocales(t *testing.T) {
+	t.Parallel()
+
+	s, err := settings.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "en", s.DefaultLocale)
+	assert.Len(t, s.SupportedLocales, 0)
+
+	s, err = settings.Load(settings.WithEnvGetter(envGetter(map[string]string{
+		"SIMBA_REQUEST_DEFAULT_LOCALE":    "de",
+		"SIMBA_REQUEST_SUPPORTED_LOCALES": "en,pt-BR",
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, "de", s.DefaultLocale)
+	assert.Equal(t, []string{"en", "pt-BR"}, s.SupportedLocales)
+
+	s, err = settings.Load(settings.WithLocales("fr", "en", "de"))
+	assert.NoError(t, err)
+	assert.Equal(t, "fr", s.DefaultLocale)
+	assert.Equal(t, []string{"en", "de"}, s.SupportedLocales)
+}
+
+func TestLoadDisabledFeatureStatus(t *testing.T) {
+	t.Parallel()
+
+	s, err := settings.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, s.DisabledFeatureStatus)
+
+	s, err = settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_REQUEST_DISABLED_FEATURE_STATUS", "403")))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, s.DisabledFeatureStatus)
+
+	s, err = settings.Load(settings.WithDisabledFeatureStatus(http.StatusForbidden))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, s.DisabledFeatureStatus)
+}
+
 func TestNilLogger(t *testing.T) {
 	t.Parallel()
 	s, err := settings.Load(settings.WithLogger(nil))
@@ -278,3 +413,318 @@ func mockEnvGetter(key, value string) func(key string) string {
 
 	return getEnvFunc
 }
+
+func TestWithErrorFormatter(t *testing.T) {
+	t.Parallel()
+	formatter := func(ctx context.Context, err *simbaErrors.SimbaError) (int, string, any) {
+		return err.StatusCode(), "application/problem+json", nil
+	}
+	s, err := settings.Load(settings.WithErrorFormatter(formatter))
+	assert.NoError(t, err)
+	assert.NotNil(t, s.ErrorFormatter)
+}
+
+func TestWithErrorSchema(t *testing.T) {
+	t.Parallel()
+	type problem struct {
+		Title string `json:"title"`
+	}
+	s, err := settings.Load(settings.WithErrorSchema(problem{}, "application/problem+json"))
+	assert.NoError(t, err)
+	assert.Equal(t, any(problem{}), s.ErrorSchema)
+	assert.Equal(t, "application/problem+json", s.ErrorContentType)
+}
+
+func TestWithErrorExamples(t *testing.T) {
+	t.Parallel()
+	examples := map[int]any{http.StatusNotFound: map[string]any{"message": "user not found"}}
+	s, err := settings.Load(settings.WithErrorExamples(examples))
+	assert.NoError(t, err)
+	assert.Equal(t, examples, s.ErrorExamples)
+}
+
+func TestWithErrorFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("problem details", func(t *testing.T) {
+		s, err := settings.Load(settings.WithErrorFormat(models.ProblemDetails))
+		assert.NoError(t, err)
+		assert.NotNil(t, s.ErrorFormatter)
+		assert.Equal(t, any(simbaErrors.ProblemDetails{}), s.ErrorSchema)
+		assert.Equal(t, "application/problem+json", s.ErrorContentType)
+	})
+
+	t.Run("problem details from environment", func(t *testing.T) {
+		s, err := settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_REQUEST_ERROR_FORMAT", "ProblemDetails")))
+		assert.NoError(t, err)
+		assert.Equal(t, models.ProblemDetails, s.ErrorFormat)
+		assert.NotNil(t, s.ErrorFormatter)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		s, err := settings.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, models.DefaultErrorFormat, s.ErrorFormat)
+		assert.Nil(t, s.ErrorFormatter)
+	})
+}
+
+func TestLoadTrustedProxiesFromEnvironment(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithEnvGetter(mockEnvGetter("SIMBA_REQUEST_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, s.TrustedProxies)
+	assert.Equal(t, models.XForwardedFor, s.ClientIPHeader)
+
+	prefixes, err := s.TrustedProxyPrefixes()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", prefixes[0].String())
+	assert.Equal(t, "192.168.1.1/32", prefixes[1].String())
+}
+
+func TestWithTrustedProxies(t *testing.T) {
+	t.Parallel()
+	s, err := settings.Load(settings.WithTrustedProxies("10.0.0.0/8"), settings.WithClientIPHeader(models.Forwarded))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8"}, s.TrustedProxies)
+	assert.Equal(t, models.Forwarded, s.ClientIPHeader)
+}
+
+func TestLoadInvalidTrustedProxies(t *testing.T) {
+	t.Parallel()
+	_, err := settings.Load(settings.WithTrustedProxies("10.0.0.0/33"))
+	assert.Error(t, err)
+
+	_, err = settings.Load(settings.WithTrustedProxies("proxy.internal"))
+	assert.Error(t, err)
+
+	_, err = settings.Load(settings.WithClientIPHeader("X-Client-IP"))
+	assert.Error(t, err)
+}
+
+func envGetter(env map[string]string) func(key string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads well-known variables", func(t *testing.T) {
+		t.Parallel()
+		s, err := settings.Load(settings.FromEnv(), settings.WithEnvGetter(envGetter(map[string]string{
+			"PORT":                                "8080",
+			"LOG_LEVEL":                           "debug",
+			"TLS_CERT_FILE":                       "cert.pem",
+			"TLS_KEY_FILE":                        "key.pem",
+			"OTEL_SERVICE_NAME":                   "users",
+			"OTEL_EXPORTER_OTLP_ENDPOINT":         "https://collector:4317",
+			"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT": "http://metrics:4317",
+		})))
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, s.Port)
+		assert.Equal(t, "debug", s.Level)
+		assert.True(t, s.Logger.Enabled(context.Background(), slog.LevelDebug))
+		assert.Equal(t, "cert.pem", s.TLSCertFile)
+		assert.Equal(t, "key.pem", s.TLSKeyFile)
+		assert.Equal(t, "users", s.Telemetry.ServiceName)
+		assert.Equal(t, "collector:4317", s.Tracing.Endpoint)
+		assert.False(t, s.Tracing.Insecure)
+		assert.Equal(t, "metrics:4317", s.Metrics.Endpoint)
+		assert.True(t, s.Metrics.Insecure)
+	})
+
+	t.Run("ignored without FromEnv", func(t *testing.T) {
+		t.Parallel()
+		s, err := settings.Load(settings.WithEnvGetter(envGetter(map[string]string{"PORT": "8080"})))
+		assert.NoError(t, err)
+		assert.Equal(t, 9999, s.Port)
+	})
+
+	t.Run("simba variables take precedence", func(t *testing.T) {
+		t.Parallel()
+		s, err := settings.Load(settings.FromEnv(), settings.WithEnvGetter(envGetter(map[string]string{
+			"PORT":                             "8080",
+			"SIMBA_SERVER_PORT":                "9090",
+			"OTEL_EXPORTER_OTLP_ENDPOINT":      "http://collector:4317",
+			"SIMBA_TELEMETRY_TRACING_ENDPOINT": "tracing:4317",
+		})))
+		assert.NoError(t, err)
+		assert.Equal(t, 9090, s.Port)
+		assert.Equal(t, "tracing:4317", s.Tracing.Endpoint)
+		assert.Equal(t, "collector:4317", s.Metrics.Endpoint)
+	})
+
+	t.Run("explicit options take precedence", func(t *testing.T) {
+		t.Parallel()
+		s, err := settings.Load(
+			settings.WithServerPort(7070),
+			settings.FromEnv(),
+			settings.WithEnvGetter(envGetter(map[string]string{"PORT": "8080", "LOG_LEVEL": "debug"})),
+			settings.WithLogLevel(slog.LevelWarn),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 7070, s.Port)
+		assert.Equal(t, "WARN", s.Level)
+		assert.False(t, s.Logger.Enabled(context.Background(), slog.LevelInfo))
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		t.Parallel()
+		_, err := settings.Load(settings.FromEnv(), settings.WithEnvGetter(envGetter(map[string]string{"PORT": "http"})))
+		assert.Error(t, err)
+
+		_, err = settings.Load(settings.FromEnv(), settings.WithEnvGetter(envGetter(map[string]string{"LOG_LEVEL": "verbose"})))
+		assert.Error(t, err)
+
+		_, err = settings.Load(settings.FromEnv(), settings.WithEnvGetter(envGetter(map[string]string{"TLS_CERT_FILE": "cert.pem"})))
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults are valid", func(t *testing.T) {
+		t.Parallel()
+		s, err := settings.Load()
+		assert.NoError(t, err)
+		assert.NoError(t, s.Validate())
+	})
+
+	tests := []struct {
+		name     string
+		opts     []settings.Option
+		expected string
+	}{
+		{
+			name:     "port out of range",
+			opts:     []settings.Option{settings.WithServerPort(70000)},
+			expected: "server port 70000 must be between 0 and 65535",
+		},
+		{
+			name:     "TLS certificate without key",
+			opts:     []settings.Option{settings.WithTLS("cert.pem", "")},
+			expected: "both the TLS certificate and key file must be set to enable TLS",
+		},
+		{
+			name:     "negative max body size",
+			opts:     []settings.Option{settings.WithMaxBodySize(-1)},
+			expected: "max body size -1 must not be negative",
+		},
+		{
+			name:     "unsupported error format",
+			opts:     []settings.Option{settings.WithErrorFormat("XML")},
+			expected: `unsupported error format "XML"`,
+		},
+		{
+			name:     "unsupported validation error format",
+			opts:     []settings.Option{settings.WithValidationErrorFormat("Flat")},
+			expected: `unsupported validation error format "Flat"`,
+		},
+		{
+			name:     "default status outside success range",
+			opts:     []settings.Option{settings.WithDefaultStatus("POST", 302)},
+			expected: "default status 302 for POST must be between 200 and 299",
+		},
+		{
+			name:     "invalid supported locale",
+			opts:     []settings.Option{settings.WithLocales("en", "not a locale")},
+			expected: `invalid supported locale "not a locale"`,
+		},
+		{
+			name:     "unsupported disabled feature status",
+			opts:     []settings.Option{settings.WithDisabledFeatureStatus(http.StatusGone)},
+			expected: "disabled feature status 410 must be 404 or 403",
+		},
+		{
+			name:     "negative max header bytes",
+			opts:     []settings.Option{settings.WithMaxHeaderBytes(-1)},
+			expected: "max header bytes -1 must not be negative",
+		},
+		{
+			name:     "negative max JSON depth",
+			opts:     []settings.Option{settings.WithMaxJSONDepth(-1)},
+			expected: "max JSON depth -1 must not be negative",
+		},
+		{
+			name:     "negative max multipart files",
+			opts:     []settings.Option{settings.WithMaxMultipartFiles(-1)},
+			expected: "max multipart files -1 must not be negative",
+		},
+		{
+			name:     "negative max multipart size",
+			opts:     []settings.Option{settings.WithMaxMultipartSize(-1)},
+			expected: "max multipart size -1 must not be negative",
+		},
+		{
+			name:     "negative max concurrent requests",
+			opts:     []settings.Option{settings.WithMaxConcurrentRequests(-1)},
+			expected: "max concurrent requests -1 must not be negative",
+		},
+		{
+			name:     "negative queue timeout",
+			opts:     []settings.Option{settings.WithRequestQueue(10, -time.Second)},
+			expected: "queue timeout -1000ms must not be negative",
+		},
+		{
+			name:     "invalid access log level",
+			opts:     []settings.Option{settings.WithEnvGetter(mockEnvGetter("SIMBA_LOG_ACCESS_LOG_LEVEL", "verbose"))},
+			expected: `invalid access log level "verbose"`,
+		},
+		{
+			name:     "unsupported access log field",
+			opts:     []settings.Option{settings.WithAccessLogFields(models.AccessLogMethod, "duration")},
+			expected: `unsupported access log field "duration"`,
+		},
+		{
+			name:     "unsupported empty params mode",
+			opts:     []settings.Option{settings.WithEmptyParams("Ignore")},
+			expected: `unsupported empty params mode "Ignore"`,
+		},
+		{
+			name: "telemetry redact path without slash",
+			opts: []settings.Option{settings.WithEnvGetter(envGetter(map[string]string{
+				"SIMBA_TELEMETRY_REDACT_PATHS": "/reset/{token},invites/{code}",
+			}))},
+			expected: `telemetry redact path "invites/{code}" must start with /`,
+		},
+		{
+			name:     "docs UI path without slash",
+			opts:     []settings.Option{settings.WithDocsUIPath("docs")},
+			expected: `docs UI path "docs" must start with /`,
+		},
+		{
+			name:     "capture without sink",
+			opts:     []settings.Option{settings.WithRequestCapture(nil, models.CaptureOptions{})},
+			expected: "request capture requires a sink",
+		},
+		{
+			name: "telemetry without endpoint",
+			opts: []settings.Option{settings.WithEnvGetter(envGetter(map[string]string{
+				"SIMBA_TELEMETRY_ENABLED":          "true",
+				"SIMBA_TELEMETRY_TRACING_ENDPOINT": " ",
+				"SIMBA_TELEMETRY_METRICS_EXPORTER": "prometheus",
+			}))},
+			expected: "tracing is enabled with the otlp exporter but no endpoint is set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := settings.Load(tt.opts...)
+			assert.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), tt.expected), err.Error())
+		})
+	}
+
+	t.Run("reports every problem", func(t *testing.T) {
+		t.Parallel()
+		_, err := settings.Load(settings.WithServerPort(-1), settings.WithMaxQueryLength(-1))
+		assert.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "server port -1"))
+		assert.True(t, strings.Contains(err.Error(), "max query length -1"))
+	})
+}
@@ -0,0 +1,150 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/sillen102/simba/models"
+)
+
+// Validate checks the settings for invalid or contradictory configuration, returning an error
+// describing every problem found. It is called by [Load], so applications created with
+// simba.New or simba.Default fail at startup rather than when the setting is first used.
+func (s *Simba) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	// Server
+	check(s.Port >= 0 && s.Port <= 65535, "server port %d must be between 0 and 65535", s.Port)
+	check(s.MaxHeaderBytes >= 0, "max header bytes %d must not be negative", s.MaxHeaderBytes)
+	check(s.MaxConcurrentRequests >= 0, "max concurrent requests %d must not be negative", s.MaxConcurrentRequests)
+	check(s.MaxQueuedRequests >= 0, "max queued requests %d must not be negative", s.MaxQueuedRequests)
+	check(s.QueueTimeoutMillis >= 0, "queue timeout %dms must not be negative", s.QueueTimeoutMillis)
+	check((s.TLSCertFile == "") == (s.TLSKeyFile == ""), "both the TLS certificate and key file must be set to enable TLS")
+
+	// Logging
+	if s.Level != "" {
+		_, err := s.logLevel()
+		check(err == nil, "invalid log level %q, must be debug, info, warn or error", s.Level)
+	}
+	if s.AccessLogLevel != "" {
+		_, err := s.AccessLogSlogLevel()
+		check(err == nil, "invalid access log level %q, must be debug, info, warn or error", s.AccessLogLevel)
+	}
+	for _, field := range s.AccessLogFields {
+		check(slices.Contains(models.AccessLogFields, models.AccessLogField(strings.TrimSpace(field))), "unsupported access log field %q", field)
+	}
+
+	// Request
+	check(s.MaxBodySize >= 0, "max body size %d must not be negative", s.MaxBodySize)
+	check(s.MaxJSONDepth >= 0, "max JSON depth %d must not be negative", s.MaxJSONDepth)
+	check(s.MaxMultipartFiles >= 0, "max multipart files %d must not be negative", s.MaxMultipartFiles)
+	check(s.MaxMultipartSize >= 0, "max multipart size %d must not be negative", s.MaxMultipartSize)
+	check(s.MaxURILength >= 0, "max URI length %d must not be negative", s.MaxURILength)
+	check(s.MaxQueryLength >= 0, "max query length %d must not be negative", s.MaxQueryLength)
+	switch s.ErrorFormat {
+	case models.DefaultErrorFormat, models.ProblemDetails:
+	default:
+		check(false, "unsupported error format %q", s.ErrorFormat)
+	}
+	switch s.ValidationErrorFormat {
+	case "", models.DefaultValidationErrorFormat, models.StructuredValidationErrorFormat, models.PointerValidationErrorFormat:
+	default:
+		check(false, "unsupported validation error format %q", s.ValidationErrorFormat)
+	}
+	for method, status := range s.DefaultStatuses {
+		check(status >= 200 && status <= 299, "default status %d for %s must be between 200 and 299", status, method)
+	}
+	if s.DefaultLocale != "" {
+		_, err := language.Parse(s.DefaultLocale)
+		check(err == nil, "invalid default locale %q", s.DefaultLocale)
+	}
+	for _, locale := range s.SupportedLocales {
+		_, err := language.Parse(locale)
+		check(err == nil, "invalid supported locale %q", locale)
+	}
+	switch s.DisabledFeatureStatus {
+	case 0, http.StatusNotFound, http.StatusForbidden:
+	default:
+		check(false, "disabled feature status %d must be 404 or 403", s.DisabledFeatureStatus)
+	}
+	switch s.EmptyParams {
+	case "", models.EmptyParamsAsMissing, models.EmptyParamsAsPresent:
+	default:
+		check(false, "unsupported empty params mode %q, must be Missing or Present", s.EmptyParams)
+	}
+	if _, err := s.TrustedProxyPrefixes(); err != nil {
+		errs = append(errs, err)
+	}
+	switch s.ClientIPHeader {
+	case models.XForwardedFor, models.XRealIP, models.Forwarded:
+	default:
+		check(false, "unsupported client IP header %q", s.ClientIPHeader)
+	}
+	if s.Capture != nil {
+		check(s.Capture.Sink != nil, "request capture requires a sink")
+		check(s.Capture.Options.SampleRate >= 0 && s.Capture.Options.SampleRate <= 1,
+			"request capture sample rate %v must be between 0 and 1", s.Capture.Options.SampleRate)
+	}
+
+	// Docs
+	if s.GenerateOpenAPIDocs {
+		check(strings.HasPrefix(s.OpenAPIFilePath, "/"), "OpenAPI file path %q must start with /", s.OpenAPIFilePath)
+	}
+	if s.MountDocsUIEndpoint {
+		check(strings.HasPrefix(s.DocsUIPath, "/"), "docs UI path %q must start with /", s.DocsUIPath)
+	}
+	check(s.ResponseEnvelope == nil || s.ResponseEnvelopeField != "", "response envelope requires the field holding the response body")
+
+	// Telemetry
+	for _, pattern := range s.RedactPaths {
+		check(strings.HasPrefix(pattern, "/"), "telemetry redact path %q must start with /", pattern)
+	}
+	if s.Telemetry.Enabled {
+		if s.Tracing.Enabled {
+			errs = append(errs, validateExporter("tracing", s.Tracing.Exporter, s.Tracing.Endpoint)...)
+			check(s.Tracing.SamplingRate >= 0 && s.Tracing.SamplingRate <= 1,
+				"tracing sampling rate %v must be between 0 and 1", s.Tracing.SamplingRate)
+		}
+		if s.Metrics.Enabled {
+			errs = append(errs, validateExporter("metrics", s.Metrics.Exporter, s.Metrics.Endpoint)...)
+			check(s.Metrics.ExportInterval > 0, "metrics export interval %d must be positive", s.Metrics.ExportInterval)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid settings: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateExporter checks that the exporter is supported and that an otlp exporter has an endpoint.
+func validateExporter(signal string, exporter string, endpoint string) []error {
+	switch exporter {
+	case "otlp":
+		if endpoint == "" {
+			return []error{fmt.Errorf("%s is enabled with the otlp exporter but no endpoint is set", signal)}
+		}
+	case "stdout":
+	default:
+		return []error{fmt.Errorf("unsupported %s exporter %q, must be otlp or stdout", signal, exporter)}
+	}
+	return nil
+}
+
+// logLevel parses the configured log level.
+func (s *Simba) logLevel() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s.Level))
+	return level, err
+}
@@ -0,0 +1,15 @@
+package simbaContext
+
+import "context"
+
+// WithAPIVersion returns a context with the API version selected by the request.
+func WithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, APIVersionKey, version)
+}
+
+// GetAPIVersion retrieves the API version selected by the request from the context.
+// If no version is present, it returns an empty string.
+func GetAPIVersion(ctx context.Context) string {
+	version, _ := ctx.Value(APIVersionKey).(string)
+	return version
+}
@@ -0,0 +1,16 @@
+package simbaContext
+
+import (
+	"context"
+	"errors"
+)
+
+// ClientCancelledReason is the reason logged and reported in telemetry for requests
+// that were abandoned by the client before a response was written.
+const ClientCancelledReason = "client_cancelled"
+
+// IsClientCancelled reports whether the request context was cancelled because the client
+// disconnected. Deadlines exceeded by the server are not considered client cancellations.
+func IsClientCancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
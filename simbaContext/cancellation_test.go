@@ -0,0 +1,29 @@
+package simbaContext_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba/simbaContext"
+)
+
+func TestIsClientCancelled(t *testing.T) {
+	t.Parallel()
+
+	if simbaContext.IsClientCancelled(context.Background()) {
+		t.Error("expected active context not to be cancelled")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if !simbaContext.IsClientCancelled(cancelled) {
+		t.Error("expected cancelled context to be reported as client cancelled")
+	}
+
+	expired, cancelTimeout := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelTimeout()
+	if simbaContext.IsClientCancelled(expired) {
+		t.Error("expected exceeded deadline not to be reported as client cancelled")
+	}
+}
@@ -0,0 +1,17 @@
+package simbaContext
+
+import "context"
+
+// WithClientIP returns a context with the provided client IP.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, ClientIPKey, clientIP)
+}
+
+// GetClientIP retrieves the client IP from the context. If no client IP is present, it returns an empty string.
+func GetClientIP(ctx context.Context) string {
+	clientIP, ok := ctx.Value(ClientIPKey).(string)
+	if !ok {
+		return ""
+	}
+	return clientIP
+}
@@ -0,0 +1,15 @@
+package simbaContext
+
+import "context"
+
+// WithHandlerSource returns a context with the Go file and line of the handler handling the request.
+func WithHandlerSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, HandlerSourceKey, source)
+}
+
+// GetHandlerSource retrieves the Go file and line of the handler handling the request from the context.
+// If no source is present, it returns an empty string.
+func GetHandlerSource(ctx context.Context) string {
+	source, _ := ctx.Value(HandlerSourceKey).(string)
+	return source
+}
@@ -4,11 +4,30 @@ type LoggerContextKey string
 type RequestContextKey string
 type TraceIDContextKey string
 type ConnectionIDContextKey string
+type ErrorFormatterContextKey string
+type ValidationErrorFormatContextKey string
+type TimeFormatContextKey string
+type ClientIPContextKey string
+type RolesContextKey string
+type PrincipalContextKey string
+type HandlerSourceContextKey string
+type LocaleContextKey string
+type APIVersionContextKey string
 
 const (
-	LoggerKey          LoggerContextKey       = "logger"
-	TraceIDKey         TraceIDContextKey      = "traceId"
-	TraceIDHeader      string                 = "X-Trace-Id"
-	RequestSettingsKey RequestContextKey      = "requestSettings"
-	ConnectionIDKey    ConnectionIDContextKey = "connectionId"
+	LoggerKey                LoggerContextKey                = "logger"
+	TraceIDKey               TraceIDContextKey               = "traceId"
+	TraceIDHeader            string                          = "X-Trace-Id"
+	RequestSettingsKey       RequestContextKey               = "requestSettings"
+	ConnectionIDKey          ConnectionIDContextKey          = "connectionId"
+	ErrorFormatterKey        ErrorFormatterContextKey        = "errorFormatter"
+	ValidationErrorFormatKey ValidationErrorFormatContextKey = "validationErrorFormat"
+	TimeFormatKey            TimeFormatContextKey            = "timeFormat"
+	ClientIPKey              ClientIPContextKey              = "clientIp"
+	RolesKey                 RolesContextKey                 = "roles"
+	PrincipalKey             PrincipalContextKey             = "principal"
+	PrincipalRecorderKey     PrincipalContextKey             = "principalRecorder"
+	HandlerSourceKey         HandlerSourceContextKey         = "handlerSource"
+	LocaleKey                LocaleContextKey                = "locale"
+	APIVersionKey            APIVersionContextKey            = "apiVersion"
 )
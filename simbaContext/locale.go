@@ -0,0 +1,15 @@
+package simbaContext
+
+import "context"
+
+// WithLocale returns a context with the locale negotiated for the request, such as en or pt-BR.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, LocaleKey, locale)
+}
+
+// GetLocale retrieves the locale negotiated for the request from the context.
+// If no locale is present, it returns an empty string.
+func GetLocale(ctx context.Context) string {
+	locale, _ := ctx.Value(LocaleKey).(string)
+	return locale
+}
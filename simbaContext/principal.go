@@ -0,0 +1,48 @@
+package simbaContext
+
+import (
+	"context"
+	"sync"
+)
+
+// principalRecorder holds the principal authenticated deeper in the handler chain,
+// so middleware running before authentication can read it after the request.
+type principalRecorder struct {
+	mu        sync.Mutex
+	principal any
+}
+
+// WithPrincipal returns a context with the authenticated principal.
+// The principal is also recorded for [RecordedPrincipal] if the context has a recorder.
+func WithPrincipal(ctx context.Context, principal any) context.Context {
+	if recorder, ok := ctx.Value(PrincipalRecorderKey).(*principalRecorder); ok {
+		recorder.mu.Lock()
+		recorder.principal = principal
+		recorder.mu.Unlock()
+	}
+	return context.WithValue(ctx, PrincipalKey, principal)
+}
+
+// GetPrincipal retrieves the authenticated principal from the context.
+// If no principal is present, it returns nil.
+func GetPrincipal(ctx context.Context) any {
+	return ctx.Value(PrincipalKey)
+}
+
+// WithPrincipalRecorder returns a context that records the principal set with [WithPrincipal]
+// on any context derived from it.
+func WithPrincipalRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, PrincipalRecorderKey, &principalRecorder{mu: sync.Mutex{}, principal: nil})
+}
+
+// RecordedPrincipal returns the principal recorded on a context created with [WithPrincipalRecorder].
+// If no principal was recorded, it returns nil.
+func RecordedPrincipal(ctx context.Context) any {
+	recorder, ok := ctx.Value(PrincipalRecorderKey).(*principalRecorder)
+	if !ok {
+		return nil
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.principal
+}
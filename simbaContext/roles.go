@@ -0,0 +1,18 @@
+package simbaContext
+
+import "context"
+
+// WithRoles returns a context with the roles of the authenticated principal.
+func WithRoles(ctx context.Context, roles ...string) context.Context {
+	return context.WithValue(ctx, RolesKey, roles)
+}
+
+// GetRoles retrieves the roles of the authenticated principal from the context.
+// If no roles are present, it returns nil.
+func GetRoles(ctx context.Context) []string {
+	roles, ok := ctx.Value(RolesKey).([]string)
+	if !ok {
+		return nil
+	}
+	return roles
+}
@@ -0,0 +1,122 @@
+package simbaErrors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/sillen102/simba/mimetypes"
+)
+
+// ErrorEncoder encodes an [ErrorResponse] into the response body.
+type ErrorEncoder func(w io.Writer, errorResponse *ErrorResponse) error
+
+type errorEncoderEntry struct {
+	contentType string
+	encoder     ErrorEncoder
+}
+
+var (
+	errorEncodersMu sync.RWMutex
+	errorEncoders   = []errorEncoderEntry{
+		{contentType: mimetypes.ApplicationJSON, encoder: encodeJSONError},
+		{contentType: mimetypes.TextPlain, encoder: encodeTextError},
+		{contentType: mimetypes.ApplicationXML, encoder: encodeXMLError},
+	}
+)
+
+// RegisterErrorEncoder registers an encoder used for error responses when the client
+// accepts the given content type. Registering an encoder for an already registered
+// content type replaces the existing encoder.
+func RegisterErrorEncoder(contentType string, encoder ErrorEncoder) {
+	errorEncodersMu.Lock()
+	defer errorEncodersMu.Unlock()
+
+	for i, entry := range errorEncoders {
+		if entry.contentType == contentType {
+			errorEncoders[i].encoder = encoder
+			return
+		}
+	}
+
+	errorEncoders = append(errorEncoders, errorEncoderEntry{contentType: contentType, encoder: encoder})
+}
+
+// DisableErrorEncoder removes the encoder for the given content type so that clients
+// accepting only that content type receive the default JSON error body instead.
+// The JSON encoder itself cannot be disabled since it is the fallback for all error responses.
+func DisableErrorEncoder(contentType string) {
+	if contentType == mimetypes.ApplicationJSON {
+		return
+	}
+
+	errorEncodersMu.Lock()
+	defer errorEncodersMu.Unlock()
+
+	for i, entry := range errorEncoders {
+		if entry.contentType == contentType {
+			errorEncoders = append(errorEncoders[:i], errorEncoders[i+1:]...)
+			return
+		}
+	}
+}
+
+// negotiateErrorEncoder selects the error encoder matching the Accept header.
+// Falls back to JSON if no registered encoder is acceptable.
+func negotiateErrorEncoder(accept string) (string, ErrorEncoder) {
+	errorEncodersMu.RLock()
+	defer errorEncodersMu.RUnlock()
+
+	offered := make([]string, len(errorEncoders))
+	for i, entry := range errorEncoders {
+		offered[i] = entry.contentType
+	}
+
+	contentType := mimetypes.Negotiate(accept, offered)
+	for _, entry := range errorEncoders {
+		if entry.contentType == contentType {
+			return entry.contentType, entry.encoder
+		}
+	}
+
+	return mimetypes.ApplicationJSON, encodeJSONError
+}
+
+// encodeJSONError encodes the error response as JSON.
+func encodeJSONError(w io.Writer, errorResponse *ErrorResponse) error {
+	return json.NewEncoder(w).Encode(errorResponse)
+}
+
+// encodeXMLError encodes the error response as XML.
+func encodeXMLError(w io.Writer, errorResponse *ErrorResponse) error {
+	return xml.NewEncoder(w).Encode(errorResponse)
+}
+
+// encodeTextError encodes the error response as human-readable plain text.
+// The first line holds the status and message, followed by one line per detail.
+func encodeTextError(w io.Writer, errorResponse *ErrorResponse) error {
+	if _, err := fmt.Fprintf(w, "%d %s: %s\n", errorResponse.Status, errorResponse.Error, errorResponse.Message); err != nil {
+		return err
+	}
+
+	if errorResponse.Details == nil {
+		return nil
+	}
+
+	details := reflect.ValueOf(errorResponse.Details)
+	if details.Kind() != reflect.Slice && details.Kind() != reflect.Array {
+		_, err := fmt.Fprintf(w, "%v\n", errorResponse.Details)
+		return err
+	}
+
+	for i := 0; i < details.Len(); i++ {
+		if _, err := fmt.Fprintf(w, "%v\n", details.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,19 @@
 package simbaErrors
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/models"
 	"github.com/sillen102/simba/simbaContext"
 )
 
@@ -26,11 +33,19 @@ type DetailProvider interface {
 	Details() any
 }
 
+// ErrorFormatter formats an error into a custom error response envelope, such as problem+json.
+// It returns the status code, content type and body to write. Bodies of type string or []byte are
+// written as-is, all other bodies are encoded as JSON.
+type ErrorFormatter func(ctx context.Context, err *SimbaError) (status int, contentType string, body any)
+
 type SimbaError struct {
 	statusCode    int
 	publicMessage string
 	err           error
-	details       any `exhaustruct:"optional"`
+	errorCode     string         `exhaustruct:"optional"`
+	details       any            `exhaustruct:"optional"`
+	headers       http.Header    `exhaustruct:"optional"`
+	cookies       []*http.Cookie `exhaustruct:"optional"`
 }
 
 func NewSimbaError(statusCode int, publicMessage string, err error) *SimbaError {
@@ -42,12 +57,31 @@ func NewSimbaError(statusCode int, publicMessage string, err error) *SimbaError
 }
 
 func (e *SimbaError) WithDetails(details any) *SimbaError {
-	return &SimbaError{
-		statusCode:    e.statusCode,
-		publicMessage: e.publicMessage,
-		err:           e.err,
-		details:       details,
-	}
+	clone := *e
+	clone.details = details
+	return &clone
+}
+
+// WithErrorCode returns a copy of the error with an application specific error code, which is written in the
+// error response and passed to error formatters.
+func (e *SimbaError) WithErrorCode(errorCode string) *SimbaError {
+	clone := *e
+	clone.errorCode = errorCode
+	return &clone
+}
+
+// WithHeaders returns a copy of the error with headers that are written alongside the error response.
+func (e *SimbaError) WithHeaders(headers http.Header) *SimbaError {
+	clone := *e
+	clone.headers = headers
+	return &clone
+}
+
+// WithCookies returns a copy of the error with cookies that are set alongside the error response.
+func (e *SimbaError) WithCookies(cookies ...*http.Cookie) *SimbaError {
+	clone := *e
+	clone.cookies = cookies
+	return &clone
 }
 
 func (e *SimbaError) Unwrap() error {
@@ -69,34 +103,216 @@ func (e *SimbaError) PublicMessage() string {
 	return e.publicMessage
 }
 
+func (e *SimbaError) ErrorCode() string {
+	return e.errorCode
+}
+
 func (e *SimbaError) Details() any {
 	return e.details
 }
 
+func (e *SimbaError) Headers() http.Header {
+	return e.headers
+}
+
+func (e *SimbaError) Cookies() []*http.Cookie {
+	return e.cookies
+}
+
+// BodyTooLarge is the cause of the error returned when a request body exceeds the configured size limit.
+// Use errors.As to distinguish oversized payloads from malformed ones.
+type BodyTooLarge struct {
+	// Limit is the maximum allowed body size in bytes
+	Limit int64
+}
+
+// NewBodyTooLargeError creates a 413 Request Entity Too Large error caused by a BodyTooLarge error.
+func NewBodyTooLargeError(limit int64) *SimbaError {
+	return NewSimbaError(
+		http.StatusRequestEntityTooLarge,
+		"request body too large",
+		&BodyTooLarge{Limit: limit},
+	).WithDetails("request body exceeds the limit of " + strconv.FormatInt(limit, 10) + " bytes")
+}
+
+func (e *BodyTooLarge) Error() string {
+	return "request body exceeds the limit of " + strconv.FormatInt(e.Limit, 10) + " bytes"
+}
+
+func (e *BodyTooLarge) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// TooManyFiles is the cause of the error returned when a multipart request body has more file parts than the
+// configured limit. Use errors.As to distinguish it from other oversized payloads.
+type TooManyFiles struct {
+	// Limit is the maximum allowed number of file parts
+	Limit int
+}
+
+// NewTooManyFilesError creates a 413 Request Entity Too Large error caused by a TooManyFiles error.
+func NewTooManyFilesError(limit int) *SimbaError {
+	return NewSimbaError(
+		http.StatusRequestEntityTooLarge,
+		"request body too large",
+		&TooManyFiles{Limit: limit},
+	).WithDetails("request body exceeds the limit of " + strconv.Itoa(limit) + " files")
+}
+
+func (e *TooManyFiles) Error() string {
+	return "request body exceeds the limit of " + strconv.Itoa(e.Limit) + " files"
+}
+
+func (e *TooManyFiles) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// UploadTooLarge is the cause of the error returned when the files of a multipart request body are larger in
+// total than the configured limit. Use errors.As to distinguish it from other oversized payloads.
+type UploadTooLarge struct {
+	// Limit is the maximum allowed total size of the files in bytes
+	Limit int64
+}
+
+// NewUploadTooLargeError creates a 413 Request Entity Too Large error caused by an UploadTooLarge error.
+func NewUploadTooLargeError(limit int64) *SimbaError {
+	return NewSimbaError(
+		http.StatusRequestEntityTooLarge,
+		"request body too large",
+		&UploadTooLarge{Limit: limit},
+	).WithDetails("uploaded files exceed the limit of " + strconv.FormatInt(limit, 10) + " bytes")
+}
+
+func (e *UploadTooLarge) Error() string {
+	return "uploaded files exceed the limit of " + strconv.FormatInt(e.Limit, 10) + " bytes"
+}
+
+func (e *UploadTooLarge) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// BodyTooDeep is the cause of the error returned when a JSON request body is nested deeper than the configured
+// limit. Use errors.As to distinguish it from other malformed payloads.
+type BodyTooDeep struct {
+	// Limit is the maximum allowed nesting depth of objects and arrays
+	Limit int
+}
+
+// NewBodyTooDeepError creates a 422 Unprocessable Entity error caused by a BodyTooDeep error.
+func NewBodyTooDeepError(limit int) *SimbaError {
+	return NewSimbaError(
+		http.StatusUnprocessableEntity,
+		"invalid request body",
+		&BodyTooDeep{Limit: limit},
+	).WithDetails("request body exceeds the nesting depth limit of " + strconv.Itoa(limit))
+}
+
+func (e *BodyTooDeep) Error() string {
+	return "request body exceeds the nesting depth limit of " + strconv.Itoa(e.Limit)
+}
+
+func (e *BodyTooDeep) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// Throttled is the cause of the error returned by a handler that throttles the client.
+// Use errors.As to read when the client may retry.
+type Throttled struct {
+	// RetryAfter is how long the client should wait before retrying
+	RetryAfter time.Duration
+}
+
+// RetryDetails are the details of a 429 Too Many Requests error created with [TooManyRequests].
+type RetryDetails struct {
+	// RetryAfterSeconds is the number of seconds the client should wait before retrying
+	RetryAfterSeconds int64 `json:"retryAfterSeconds" xml:"retryAfterSeconds" example:"30"`
+}
+
+// TooManyRequests creates a 429 Too Many Requests error for handlers that throttle clients themselves.
+// The Retry-After header is set to retryAfter in seconds, rounded up, and the same value is included in
+// the details so clients reading only the body can back off too. The error is written with the configured
+// error formatter like any other error.
+//
+//	Example usage:
+//
+//	if !quota.Allow(userID) {
+//		return nil, simbaErrors.TooManyRequests(quota.ResetIn(userID), "export quota exceeded")
+//	}
+func TooManyRequests(retryAfter time.Duration, msg string) *SimbaError {
+	seconds := max(int64(math.Ceil(retryAfter.Seconds())), 0)
+	return NewSimbaError(
+		http.StatusTooManyRequests,
+		msg,
+		&Throttled{RetryAfter: retryAfter},
+	).WithDetails(RetryDetails{RetryAfterSeconds: seconds}).
+		WithHeaders(http.Header{"Retry-After": {strconv.FormatInt(seconds, 10)}})
+}
+
+func (e *Throttled) Error() string {
+	return "too many requests, retry after " + e.RetryAfter.String()
+}
+
+func (e *Throttled) StatusCode() int {
+	return http.StatusTooManyRequests
+}
+
 // ErrorResponse defines the structure of an error message.
 type ErrorResponse struct {
+	// XML element name used when the error is encoded as XML
+	XMLName xml.Name `json:"-" xml:"errorResponse" exhaustruct:"optional"`
 	// Timestamp of the error
-	Timestamp time.Time `json:"timestamp" example:"2021-01-01T12:00:00Z"`
+	Timestamp time.Time `json:"timestamp" xml:"timestamp" example:"2021-01-01T12:00:00Z"`
 	// HTTP status code
-	Status int `json:"status" example:"400"`
+	Status int `json:"status" xml:"status" example:"400"`
 	// HTTP error type
-	Error string `json:"error" example:"Bad Request"`
+	Error string `json:"error" xml:"error" example:"Bad Request"`
 	// Path of the Request
-	Path string `json:"path" example:"/api/v1/users"`
+	Path string `json:"path" xml:"path" example:"/api/v1/users"`
 	// Method of the Request
-	Method string `json:"method" example:"GET"`
+	Method string `json:"method" xml:"method" example:"GET"`
 	// Request ID
-	RequestID string `json:"requestId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" required:"false"`
+	RequestID string `json:"requestId,omitempty" xml:"requestId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" required:"false"`
 	// Error code
-	ErrorCode string `json:"errorCode,omitempty" example:"123-123" required:"false"`
+	ErrorCode string `json:"errorCode,omitempty" xml:"errorCode,omitempty" example:"123-123" required:"false"`
 	// Error message
-	Message string `json:"message,omitempty" example:"Validation failed"`
+	Message string `json:"message,omitempty" xml:"message,omitempty" example:"Validation failed"`
 	// Validation errors
-	Details any `json:"details,omitempty" required:"false"`
+	Details any `json:"details,omitempty" xml:"details,omitempty" required:"false"`
+	// Format of the timestamp in JSON
+	timeFormat models.TimeFormat `exhaustruct:"optional"`
+}
+
+// MarshalJSON encodes the error response as JSON with the timestamp in the time format of the request.
+func (e ErrorResponse) MarshalJSON() ([]byte, error) {
+	type errorResponse ErrorResponse
+	if e.timeFormat.IsDefault() {
+		return json.Marshal(errorResponse(e))
+	}
+
+	timestamp, err := e.timeFormat.FormatJSON(e.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Timestamp json.RawMessage `json:"timestamp"`
+		errorResponse
+	}{
+		Timestamp:     timestamp,
+		errorResponse: errorResponse(e),
+	})
 }
 
 // WriteError is a helper function for handling errors in HTTP handlers.
+// No response is written if the client has already disconnected.
 func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if simbaContext.IsClientCancelled(r.Context()) {
+		logging.From(r.Context()).Debug("request cancelled by client, skipping error response",
+			"reason", simbaContext.ClientCancelledReason,
+			"error", err,
+		)
+		return
+	}
+
 	statusCode := http.StatusInternalServerError
 	errorCode := ""
 	message := err.Error()
@@ -105,8 +321,10 @@ func WriteError(w http.ResponseWriter, r *http.Request, err error) {
 	if simbaErr, ok := errors.AsType[*SimbaError](err); ok && simbaErr != nil {
 		// If the error is a SimbaError, extract its properties
 		statusCode = simbaErr.StatusCode()
+		errorCode = simbaErr.ErrorCode()
 		message = simbaErr.PublicMessage()
 		details = simbaErr.Details()
+		writeErrorHeaders(w, simbaErr)
 	} else {
 		if statusCoder, ok := err.(StatusCodeProvider); ok {
 			statusCode = statusCoder.StatusCode()
@@ -125,12 +343,28 @@ func WriteError(w http.ResponseWriter, r *http.Request, err error) {
 		}
 	}
 
-	logging.From(r.Context()).Error(err.Error(),
-		"statusCode", statusCode,
-		"error", err,
-	)
+	logArgs := []any{"statusCode", statusCode, "error", err}
+	if source := simbaContext.GetHandlerSource(r.Context()); source != "" {
+		logArgs = append(logArgs, "handlerSource", source)
+	}
+	logging.From(r.Context()).Error(err.Error(), logArgs...)
+
+	statusCode, details = formatValidationDetails(r, statusCode, details)
+
+	if formatter, ok := r.Context().Value(simbaContext.ErrorFormatterKey).(ErrorFormatter); ok && formatter != nil {
+		simbaErr, isSimbaErr := errors.AsType[*SimbaError](err)
+		if !isSimbaErr || simbaErr == nil {
+			simbaErr = NewSimbaError(statusCode, message, err).WithErrorCode(errorCode)
+		}
+		simbaErr = simbaErr.WithDetails(details)
+		simbaErr.statusCode = statusCode
+		if writeErr := writeFormattedError(w, r, formatter, simbaErr); writeErr != nil {
+			HandleUnexpectedError(w)
+		}
+		return
+	}
 
-	err = writeJSONError(w, newErrorResponse(r, statusCode, message, errorCode, details))
+	err = writeErrorResponse(w, r, newErrorResponse(r, statusCode, message, errorCode, details))
 	if err != nil {
 		HandleUnexpectedError(w)
 		return
@@ -143,11 +377,84 @@ func HandleUnexpectedError(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusInternalServerError)
 }
 
-// writeJSONError writes a JSON error response to the response writer.
-func writeJSONError(w http.ResponseWriter, errorResponse *ErrorResponse) error {
-	w.Header().Set("Content-Type", "application/json")
+// writeErrorHeaders writes the headers and cookies carried by the error to the response writer.
+func writeErrorHeaders(w http.ResponseWriter, simbaErr *SimbaError) {
+	for key, values := range simbaErr.Headers() {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	for _, cookie := range simbaErr.Cookies() {
+		http.SetCookie(w, cookie)
+	}
+}
+
+// writeErrorResponse writes the error response to the response writer using the encoder
+// negotiated from the Request's Accept header, defaulting to JSON. The response is encoded before
+// anything is written, falling back to JSON if the negotiated encoder fails, such as XML with map
+// details. Returns an error, without writing anything, if the response can't be encoded.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, errorResponse *ErrorResponse) error {
+	contentType, encoder := negotiateErrorEncoder(r.Header.Get("Accept"))
+
+	var body bytes.Buffer
+	if err := encoder(&body, errorResponse); err != nil {
+		body.Reset()
+		contentType = mimetypes.ApplicationJSON
+		if err := encodeJSONError(&body, errorResponse); err != nil {
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(errorResponse.Status)
-	return json.NewEncoder(w).Encode(errorResponse)
+	_, _ = w.Write(body.Bytes())
+	return nil
+}
+
+type requestContextKey struct{}
+
+// RequestFromContext returns the Request an error response is written for.
+// It is available in the context passed to an [ErrorFormatter] and returns nil elsewhere.
+func RequestFromContext(ctx context.Context) *http.Request {
+	r, _ := ctx.Value(requestContextKey{}).(*http.Request)
+	return r
+}
+
+// writeFormattedError writes the error response produced by a custom [ErrorFormatter]. Returns an error,
+// without writing anything, if the body can't be encoded.
+func writeFormattedError(w http.ResponseWriter, r *http.Request, formatter ErrorFormatter, simbaErr *SimbaError) error {
+	ctx := context.WithValue(r.Context(), requestContextKey{}, r)
+	status, contentType, body := formatter(ctx, simbaErr)
+	if status == 0 {
+		status = simbaErr.StatusCode()
+	}
+	if contentType == "" {
+		contentType = mimetypes.ApplicationJSON
+	}
+
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		payload = []byte(b)
+	case []byte:
+		payload = b
+	default:
+		// Encode before writing the status, so an error can still be answered with a 500
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(b); err != nil {
+			return err
+		}
+		payload = buf.Bytes()
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if len(payload) > 0 {
+		_, _ = w.Write(payload)
+	}
+	return nil
 }
 
 // newErrorResponse creates a new ErrorResponse instance with the given status and message.
@@ -160,21 +467,25 @@ func newErrorResponse(r *http.Request, status int, message string, errorCode str
 		}
 	}
 
+	timeFormat, _ := r.Context().Value(simbaContext.TimeFormatKey).(models.TimeFormat)
+
 	return &ErrorResponse{
-		Timestamp: time.Now().UTC(),
-		Status:    status,
-		Error:     http.StatusText(status),
-		Path:      r.URL.Path,
-		Method:    r.Method,
-		RequestID: traceID,
-		ErrorCode: errorCode,
-		Message:   message,
-		Details:   details,
+		Timestamp:  time.Now().UTC(),
+		Status:     status,
+		Error:      http.StatusText(status),
+		Path:       r.URL.Path,
+		Method:     r.Method,
+		RequestID:  traceID,
+		ErrorCode:  errorCode,
+		Message:    message,
+		Details:    details,
+		timeFormat: timeFormat,
 	}
 }
 
 // Predefined errors for common scenarios.
 var (
-	ErrInvalidContentType = NewSimbaError(http.StatusBadRequest, "invalid content type", errors.New("invalid content type"))
-	ErrInvalidRequest     = NewSimbaError(http.StatusUnprocessableEntity, "invalid request", errors.New("failed to decode request body"))
+	ErrInvalidContentType   = NewSimbaError(http.StatusBadRequest, "invalid content type", errors.New("invalid content type"))
+	ErrUnsupportedMediaType = NewSimbaError(http.StatusUnsupportedMediaType, "unsupported media type", errors.New("unsupported media type"))
+	ErrInvalidRequest       = NewSimbaError(http.StatusUnprocessableEntity, "invalid request", errors.New("failed to decode request body"))
 )
@@ -1,14 +1,22 @@
 package simbaErrors_test
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
 	"github.com/sillen102/simba/simbaErrors"
 	"github.com/sillen102/simba/simbaTest/assert"
+	"github.com/sillen102/simba/validation"
 )
 
 func TestNewSimbaError(t *testing.T) {
@@ -32,6 +40,79 @@ func TestSimbaErrorWithDetails(t *testing.T) {
 	}
 }
 
+func TestBodyTooLargeError(t *testing.T) {
+	t.Parallel()
+
+	var err error = simbaErrors.NewBodyTooLargeError(1024)
+
+	bodyTooLarge, ok := errors.AsType[*simbaErrors.BodyTooLarge](err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1024), bodyTooLarge.Limit)
+
+	simbaErr, ok := errors.AsType[*simbaErrors.SimbaError](err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, simbaErr.StatusCode())
+	assert.Equal(t, "request body too large", simbaErr.PublicMessage())
+	assert.Equal(t, "request body exceeds the limit of 1024 bytes", simbaErr.Details())
+}
+
+func TestTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		var err error = simbaErrors.TooManyRequests(1500*time.Millisecond, "export quota exceeded")
+
+		throttled, ok := errors.AsType[*simbaErrors.Throttled](err)
+		assert.True(t, ok)
+		assert.Equal(t, 1500*time.Millisecond, throttled.RetryAfter)
+
+		simbaErr, ok := errors.AsType[*simbaErrors.SimbaError](err)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusTooManyRequests, simbaErr.StatusCode())
+		assert.Equal(t, "export quota exceeded", simbaErr.PublicMessage())
+		assert.Equal[any](t, simbaErrors.RetryDetails{RetryAfterSeconds: 2}, simbaErr.Details())
+		assert.Equal(t, "2", simbaErr.Headers().Get("Retry-After"))
+	})
+
+	t.Run("negative retry after", func(t *testing.T) {
+		t.Parallel()
+
+		err := simbaErrors.TooManyRequests(-time.Second, "slow down")
+		assert.Equal(t, "0", err.Headers().Get("Retry-After"))
+	})
+
+	t.Run("written response", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/exports", nil)
+		w := httptest.NewRecorder()
+		simbaErrors.WriteError(w, req, simbaErrors.TooManyRequests(30*time.Second, "export quota exceeded"))
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+		var errorResponse simbaErrors.ErrorResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+		assert.Equal(t, "export quota exceeded", errorResponse.Message)
+		assert.Equal[any](t, map[string]any{"retryAfterSeconds": float64(30)}, errorResponse.Details)
+	})
+
+	t.Run("written with error formatter", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/exports", nil)
+		ctx := context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, simbaErrors.ErrorFormatter(simbaErrors.ProblemDetailsFormatter))
+		w := httptest.NewRecorder()
+		simbaErrors.WriteError(w, req.WithContext(ctx), simbaErrors.TooManyRequests(30*time.Second, "export quota exceeded"))
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	})
+}
+
 func TestWriteError(t *testing.T) {
 	t.Parallel()
 
@@ -74,3 +155,409 @@ func TestPredefinedErrors(t *testing.T) {
 	assert.Equal(t, http.StatusUnprocessableEntity, simbaErrors.ErrInvalidRequest.StatusCode())
 	assert.Equal(t, "invalid request", simbaErrors.ErrInvalidRequest.PublicMessage())
 }
+
+func TestWriteErrorContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	validationErr := simbaErrors.NewSimbaError(http.StatusBadRequest, "request validation failed", nil).
+		WithDetails([]string{"name is required", "age must be positive"})
+
+	t.Run("defaults to JSON without Accept header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("writes plain text when text/plain is accepted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept", "text/plain")
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+		assert.Equal(t, "400 Bad Request: request validation failed\nname is required\nage must be positive\n", w.Body.String())
+	})
+
+	t.Run("writes XML when application/xml is preferred", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept", "application/json;q=0.5, application/xml")
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+
+		var errorResponse simbaErrors.ErrorResponse
+		assert.NoError(t, xml.NewDecoder(w.Body).Decode(&errorResponse))
+		assert.Equal(t, http.StatusBadRequest, errorResponse.Status)
+		assert.Equal(t, "request validation failed", errorResponse.Message)
+	})
+
+	t.Run("falls back to JSON when the negotiated encoder fails", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept", "application/xml")
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, simbaErrors.NewSimbaError(http.StatusBadRequest, "request validation failed", nil).
+			WithDetails(map[string]string{"name": "is required"}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var errorResponse simbaErrors.ErrorResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+		assert.Equal(t, any(map[string]any{"name": "is required"}), errorResponse.Details)
+	})
+
+	t.Run("falls back to JSON for unsupported content types", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept", "image/png")
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("uses registered encoder and falls back to JSON once disabled", func(t *testing.T) {
+		contentType := "application/vnd.simba.error"
+		simbaErrors.RegisterErrorEncoder(contentType, func(w io.Writer, errorResponse *simbaErrors.ErrorResponse) error {
+			_, err := io.WriteString(w, errorResponse.Message)
+			return err
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept", contentType)
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "request validation failed", w.Body.String())
+
+		simbaErrors.DisableErrorEncoder(contentType)
+		w = httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+}
+
+func TestWriteErrorWithFormatter(t *testing.T) {
+	t.Parallel()
+
+	type envelope struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Details any    `json:"details"`
+	}
+
+	formatter := simbaErrors.ErrorFormatter(func(ctx context.Context, err *simbaErrors.SimbaError) (int, string, any) {
+		return err.StatusCode(), "application/vnd.error+json", envelope{
+			Code:    err.StatusCode(),
+			Message: err.PublicMessage(),
+			Details: err.Details(),
+		}
+	})
+
+	t.Run("formats SimbaError", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, formatter))
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, simbaErrors.NewSimbaError(http.StatusBadRequest, "validation failed", nil).
+			WithDetails([]string{"name is required"}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/vnd.error+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, `{"code":400,"message":"validation failed","details":["name is required"]}`+"\n", w.Body.String())
+	})
+
+	t.Run("formats plain errors", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, formatter))
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, errors.New("boom"))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, `{"code":500,"message":"boom","details":null}`+"\n", w.Body.String())
+	})
+
+	t.Run("bodies that can't be encoded", func(t *testing.T) {
+		unencodable := simbaErrors.ErrorFormatter(func(ctx context.Context, err *simbaErrors.SimbaError) (int, string, any) {
+			return err.StatusCode(), "application/vnd.error+json", func() {}
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, unencodable))
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, simbaErrors.NewSimbaError(http.StatusBadRequest, "bad", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("writes string bodies as-is", func(t *testing.T) {
+		stringFormatter := simbaErrors.ErrorFormatter(func(ctx context.Context, err *simbaErrors.SimbaError) (int, string, any) {
+			return http.StatusTeapot, "text/plain", err.PublicMessage()
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, stringFormatter))
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, simbaErrors.NewSimbaError(http.StatusBadRequest, "bad", nil))
+
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Equal(t, "bad", w.Body.String())
+	})
+}
+
+func TestWriteErrorWithProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	ctx := context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, simbaErrors.ErrorFormatter(simbaErrors.ProblemDetailsFormatter))
+	ctx = context.WithValue(ctx, simbaContext.TraceIDKey, "trace-123")
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	simbaErrors.WriteError(w, req, simbaErrors.NewSimbaError(http.StatusBadRequest, "request validation failed", nil).
+		WithDetails([]string{"name is required"}))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","instance":"/users","requestId":"trace-123","errors":["name is required"]}`+"\n", w.Body.String())
+}
+
+// codedError is an error with a status and an application specific error code.
+type codedError struct{}
+
+func (codedError) Error() string     { return "out of stock" }
+func (codedError) StatusCode() int   { return http.StatusConflict }
+func (codedError) ErrorCode() string { return "STOCK-001" }
+
+func TestWriteErrorCode(t *testing.T) {
+	t.Parallel()
+
+	problemDetails := simbaErrors.ErrorFormatter(simbaErrors.ProblemDetailsFormatter)
+
+	tests := []struct {
+		name      string
+		err       error
+		formatter simbaErrors.ErrorFormatter
+		expected  string
+	}{
+		{
+			name:     "SimbaError",
+			err:      simbaErrors.NewSimbaError(http.StatusConflict, "out of stock", nil).WithErrorCode("STOCK-001"),
+			expected: `"errorCode":"STOCK-001"`,
+		},
+		{
+			name:     "error code provider",
+			err:      codedError{},
+			expected: `"errorCode":"STOCK-001"`,
+		},
+		{
+			name:      "SimbaError with error formatter",
+			err:       simbaErrors.NewSimbaError(http.StatusConflict, "out of stock", nil).WithErrorCode("STOCK-001"),
+			formatter: problemDetails,
+			expected:  `"errorCode":"STOCK-001"`,
+		},
+		{
+			name:      "error code provider with error formatter",
+			err:       codedError{},
+			formatter: problemDetails,
+			expected:  `"errorCode":"STOCK-001"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			if tt.formatter != nil {
+				req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, tt.formatter))
+			}
+			w := httptest.NewRecorder()
+			simbaErrors.WriteError(w, req, tt.err)
+
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, tt.expected, w.Body.String())
+		})
+	}
+}
+
+func TestWriteErrorValidationErrorFormat(t *testing.T) {
+	t.Parallel()
+
+	validationErr := simbaErrors.NewSimbaError(http.StatusBadRequest, "request validation failed", nil).
+		WithDetails([]validation.ValidationError{
+			{Field: "name", Err: "name is a required field", Code: "required", Pointer: "/name"},
+			{Field: "id", Err: "invalid int parameter value: abc"},
+		})
+
+	defaultDetails := `[{"field":"name","error":"name is a required field"},{"field":"id","error":"invalid int parameter value: abc"}]`
+	structuredDetails := `[{"field":"name","code":"required","message":"name is a required field"},{"field":"id","code":"invalid","message":"invalid int parameter value: abc"}]`
+	pointerDetails := `[{"pointer":"/name","code":"required","detail":"name is a required field"},{"parameter":"id","code":"invalid","detail":"invalid int parameter value: abc"}]`
+
+	tests := []struct {
+		name     string
+		target   string
+		accept   string
+		format   models.ValidationErrorFormat
+		expected string
+		status   int
+	}{
+		{
+			name:     "default format",
+			target:   "/users",
+			expected: defaultDetails,
+		},
+		{
+			name:     "structured format from settings",
+			target:   "/users",
+			format:   models.StructuredValidationErrorFormat,
+			expected: structuredDetails,
+		},
+		{
+			name:     "structured format from query parameter",
+			target:   "/users?validation-errors=structured",
+			expected: structuredDetails,
+		},
+		{
+			name:     "structured format from Accept header",
+			target:   "/users",
+			accept:   "application/json; validation-errors=structured",
+			expected: structuredDetails,
+		},
+		{
+			name:     "request overrides settings",
+			target:   "/users?validation-errors=default",
+			format:   models.StructuredValidationErrorFormat,
+			expected: defaultDetails,
+		},
+		{
+			name:     "pointer format from settings",
+			target:   "/users",
+			format:   models.PointerValidationErrorFormat,
+			expected: pointerDetails,
+			status:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "pointer format from query parameter",
+			target:   "/users?validation-errors=pointer",
+			expected: pointerDetails,
+			status:   http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.format != "" {
+				req = req.WithContext(context.WithValue(req.Context(), simbaContext.ValidationErrorFormatKey, tt.format))
+			}
+			w := httptest.NewRecorder()
+
+			simbaErrors.WriteError(w, req, validationErr)
+
+			var body struct {
+				Details json.RawMessage `json:"details"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, cmp.Or(tt.status, http.StatusBadRequest), w.Code)
+			assert.Equal(t, tt.expected, string(body.Details))
+		})
+	}
+
+	t.Run("structured format with problem details", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/users?validation-errors=structured", nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, simbaErrors.ErrorFormatter(simbaErrors.ProblemDetailsFormatter)))
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		var body struct {
+			Errors json.RawMessage `json:"errors"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, structuredDetails, string(body.Errors))
+	})
+
+	t.Run("pointer format with problem details", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/users?validation-errors=pointer", nil)
+		req = req.WithContext(context.WithValue(req.Context(), simbaContext.ErrorFormatterKey, simbaErrors.ErrorFormatter(simbaErrors.ProblemDetailsFormatter)))
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr)
+
+		var body struct {
+			Status int             `json:"status"`
+			Errors json.RawMessage `json:"errors"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, body.Status)
+		assert.Equal(t, pointerDetails, string(body.Errors))
+	})
+
+	t.Run("pointer format keeps the status of errors without validation errors", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/users?validation-errors=pointer", nil)
+		w := httptest.NewRecorder()
+
+		simbaErrors.WriteError(w, req, validationErr.WithDetails(nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWriteErrorWithHeadersAndCookies(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	w := httptest.NewRecorder()
+
+	simbaErrors.WriteError(w, req, simbaErrors.NewSimbaError(http.StatusForbidden, "invalid token", nil).
+		WithDetails("token expired").
+		WithHeaders(http.Header{"X-Retry": []string{"later"}}).
+		WithCookies(&http.Cookie{Name: "csrf_token", Value: "rotated", Path: "/", HttpOnly: true, Secure: true, SameSite: http.SameSiteStrictMode}))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "later", w.Header().Get("X-Retry"))
+
+	cookies := w.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+	assert.Equal(t, "csrf_token", cookies[0].Name)
+	assert.Equal(t, "rotated", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+
+	var errorResponse simbaErrors.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+	assert.Equal(t, "token expired", errorResponse.Details)
+}
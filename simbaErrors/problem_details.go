@@ -0,0 +1,49 @@
+package simbaErrors
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sillen102/simba/mimetypes"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// ProblemDetails defines the structure of an RFC 7807 problem details error message.
+type ProblemDetails struct {
+	// URI reference identifying the problem type
+	Type string `json:"type" example:"about:blank"`
+	// Short summary of the problem type
+	Title string `json:"title" example:"Bad Request"`
+	// HTTP status code
+	Status int `json:"status" example:"400"`
+	// Explanation specific to this occurrence of the problem
+	Detail string `json:"detail,omitempty" example:"request validation failed" required:"false"`
+	// URI reference identifying this occurrence of the problem
+	Instance string `json:"instance,omitempty" example:"/api/v1/users" required:"false"`
+	// Request ID
+	RequestID string `json:"requestId,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" required:"false"`
+	// Error code
+	ErrorCode string `json:"errorCode,omitempty" example:"123-123" required:"false"`
+	// Validation errors
+	Errors any `json:"errors,omitempty" required:"false"`
+}
+
+// ProblemDetailsFormatter is an [ErrorFormatter] that writes errors as RFC 7807
+// problem details documents with the application/problem+json content type.
+func ProblemDetailsFormatter(ctx context.Context, err *SimbaError) (int, string, any) {
+	var instance string
+	if r := RequestFromContext(ctx); r != nil {
+		instance = r.URL.Path
+	}
+
+	return err.StatusCode(), mimetypes.ApplicationProblemJSON, ProblemDetails{
+		Type:      "about:blank",
+		Title:     http.StatusText(err.StatusCode()),
+		Status:    err.StatusCode(),
+		Detail:    err.PublicMessage(),
+		Instance:  instance,
+		RequestID: simbaContext.GetTraceID(ctx),
+		ErrorCode: err.ErrorCode(),
+		Errors:    err.Details(),
+	}
+}
@@ -0,0 +1,65 @@
+package simbaErrors
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/validation"
+)
+
+// ValidationErrorFormatParam is the query parameter and Accept header media type parameter
+// a request can select the validation error format with, e.g. ?validation-errors=structured
+// or Accept: application/json; validation-errors=structured.
+const ValidationErrorFormatParam = "validation-errors"
+
+// formatValidationDetails returns the status and details in the validation error format selected for the request.
+// The pointer format responds to bad requests with 422 Unprocessable Entity. Details other than validation errors
+// are returned as is.
+func formatValidationDetails(r *http.Request, statusCode int, details any) (int, any) {
+	errs, ok := details.([]validation.ValidationError)
+	if !ok {
+		return statusCode, details
+	}
+
+	switch validationErrorFormat(r) {
+	case models.StructuredValidationErrorFormat:
+		return statusCode, validation.Structured(errs)
+	case models.PointerValidationErrorFormat:
+		if statusCode == http.StatusBadRequest {
+			statusCode = http.StatusUnprocessableEntity
+		}
+		return statusCode, validation.Pointers(errs)
+	default:
+		return statusCode, details
+	}
+}
+
+// validationErrorFormat returns the validation error format requested by the request,
+// falling back to the format configured in the request settings.
+func validationErrorFormat(r *http.Request) models.ValidationErrorFormat {
+	requested := r.URL.Query().Get(ValidationErrorFormatParam)
+	if requested == "" {
+		for mediaRange := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+			if _, params, err := mime.ParseMediaType(mediaRange); err == nil && params[ValidationErrorFormatParam] != "" {
+				requested = params[ValidationErrorFormatParam]
+				break
+			}
+		}
+	}
+
+	for _, format := range []models.ValidationErrorFormat{
+		models.DefaultValidationErrorFormat,
+		models.StructuredValidationErrorFormat,
+		models.PointerValidationErrorFormat,
+	} {
+		if strings.EqualFold(requested, format.String()) {
+			return format
+		}
+	}
+
+	format, _ := r.Context().Value(simbaContext.ValidationErrorFormatKey).(models.ValidationErrorFormat)
+	return format
+}
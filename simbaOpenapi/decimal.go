@@ -0,0 +1,55 @@
+package simbaOpenapi
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+var (
+	decimalTypesMu sync.RWMutex
+	decimalTypes   = map[reflect.Type]bool{}
+)
+
+// RegisterDecimal documents the type of the sample as an exact decimal number, a string in the decimal
+// format, in parameters and bodies. Decimal types are usually registered with simba.RegisterDecimal,
+// which also binds and validates them.
+func RegisterDecimal(sample any) {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+
+	decimalTypesMu.Lock()
+	defer decimalTypesMu.Unlock()
+	decimalTypes[t] = true
+}
+
+// documentDecimal documents registered decimal types as strings in the decimal format.
+func documentDecimal(params jsonschema.InterceptSchemaParams) bool {
+	if !params.Value.IsValid() {
+		return false
+	}
+	t := params.Value.Type()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	decimalTypesMu.RLock()
+	registered := decimalTypes[t]
+	decimalTypesMu.RUnlock()
+	if !registered {
+		return false
+	}
+
+	nullable := params.Schema.HasType(jsonschema.Null)
+	params.Schema.WithType(jsonschema.String.Type()).WithFormat("decimal")
+	if nullable {
+		params.Schema.AddType(jsonschema.Null)
+	}
+	return true
+}
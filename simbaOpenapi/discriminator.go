@@ -0,0 +1,68 @@
+package simbaOpenapi
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+)
+
+// oneOfBody documents a discriminated request body inline as oneOf its variants. It's a slice,
+// so it's documented as a JSON body without having tagged fields.
+type oneOfBody []any
+
+// newOneOfBody returns the body documenting the variants, along with the discriminator values
+// in the same order as the variants.
+func newOneOfBody(body openapiModels.DiscriminatedBody) (oneOfBody, []string) {
+	values := slices.Sorted(maps.Keys(body.Variants))
+	variants := make([]any, 0, len(values))
+	for _, value := range values {
+		variants = append(variants, body.Variants[value])
+	}
+	return variants, values
+}
+
+func (b oneOfBody) JSONSchemaOneOf() []any {
+	return b
+}
+
+func (b oneOfBody) InlineJSONSchema() {}
+
+func (b oneOfBody) PrepareJSONSchema(schema *jsonschema.Schema) error {
+	schema.Type = nil
+	schema.Items = nil
+	return nil
+}
+
+// setDiscriminator adds the discriminator to the oneOf schemas of a request body, mapping each
+// discriminator value to the schema of its variant.
+func setDiscriminator(propertyName string, values []string) func(cor openapi.ContentOrReference) {
+	return func(cor openapi.ContentOrReference) {
+		requestBody, ok := cor.(*openapi31.RequestBodyOrReference)
+		if !ok || requestBody.RequestBody == nil {
+			return
+		}
+
+		for _, mediaType := range requestBody.RequestBody.Content {
+			oneOf, isSlice := mediaType.Schema["oneOf"].([]any)
+			if !isSlice || len(oneOf) != len(values) {
+				continue
+			}
+
+			mapping := make(map[string]any, len(values))
+			for i, schema := range oneOf {
+				if ref, hasRef := schema.(map[string]any)["$ref"]; hasRef {
+					mapping[values[i]] = ref
+				}
+			}
+			mediaType.Schema["discriminator"] = map[string]any{
+				"propertyName": propertyName,
+				"mapping":      mapping,
+			}
+		}
+	}
+}
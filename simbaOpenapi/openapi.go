@@ -2,25 +2,31 @@ package simbaOpenapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"maps"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/iancoleman/strcase"
+	"github.com/swaggest/jsonschema-go"
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi31"
 
 	simbaHttp "github.com/sillen102/simba/http"
+	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/models"
 	"github.com/sillen102/simba/simbaErrors"
 	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
@@ -35,10 +41,89 @@ const (
 	statusCodeTag  = "@StatusCode"
 	errorTag       = "@Error"
 	deprecatedTag  = "@Deprecated"
+	exampleTag     = "@Example"
 )
 
 type OpenAPIGenerator struct {
-	fileCache *fileCache
+	fileCache        *fileCache
+	errorSchema      any                 `exhaustruct:"optional"`
+	errorContentType string              `exhaustruct:"optional"`
+	tags             []openapiModels.Tag `exhaustruct:"optional"`
+	envelope         any                 `exhaustruct:"optional"`
+	envelopeField    string              `exhaustruct:"optional"`
+	timeFormat       models.TimeFormat   `exhaustruct:"optional"`
+	defaultStatuses  map[string]int      `exhaustruct:"optional"`
+	errorExamples    map[int]any         `exhaustruct:"optional"`
+	extensions       bool                `exhaustruct:"optional"`
+}
+
+// GeneratorOption configures an [OpenAPIGenerator].
+type GeneratorOption func(*OpenAPIGenerator)
+
+// WithErrorSchema sets the body and content type documented for error responses.
+// Use this when errors are written with a custom [simbaErrors.ErrorFormatter].
+func WithErrorSchema(schema any, contentType string) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		if schema != nil {
+			g.errorSchema = schema
+		}
+		if contentType != "" {
+			g.errorContentType = contentType
+		}
+	}
+}
+
+// WithTags registers metadata for tags, such as a description and a link to external documentation.
+// Registered tags are listed in the top-level tags of the documentation in registration order,
+// which documentation UIs use to group and order the operations.
+func WithTags(tags ...openapiModels.Tag) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		g.tags = append(g.tags, tags...)
+	}
+}
+
+// WithResponseEnvelope documents success responses as wrapped in an envelope, for applications that
+// shape responses with a response transformer. The envelope is a struct whose other properties are documented
+// as is, while the property named field (by its JSON name) holds the response body of each route.
+func WithResponseEnvelope(envelope any, field string) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		if envelope != nil && field != "" {
+			g.envelope = envelope
+			g.envelopeField = field
+		}
+	}
+}
+
+// WithTimeFormat documents time.Time values in responses in the given format, for applications that write
+// times in another format than RFC 3339.
+func WithTimeFormat(format models.TimeFormat) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		g.timeFormat = format
+	}
+}
+
+// WithDefaultStatuses documents the given statuses, by HTTP method, as the success status of routes with
+// a response body whose handler doesn't set a status, for applications that change the default 200.
+func WithDefaultStatuses(statuses map[string]int) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		g.defaultStatuses = statuses
+	}
+}
+
+// WithErrorExamples documents the given bodies, by HTTP status, as examples of the error responses with that
+// status, so documentation UIs show realistic error bodies. Error responses of other statuses have no example.
+func WithErrorExamples(examples map[int]any) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		g.errorExamples = examples
+	}
+}
+
+// WithOperationExtensions documents the rate limit and deprecation dates of routes in the x-ratelimit and
+// x-deprecated extensions of their operations, for API gateways that configure themselves from the documentation.
+func WithOperationExtensions(enabled bool) GeneratorOption {
+	return func(g *OpenAPIGenerator) {
+		g.extensions = enabled
+	}
 }
 
 type handlerInfo struct {
@@ -47,17 +132,37 @@ type handlerInfo struct {
 	summary     string   `exhaustruct:"optional"`
 	description string   `exhaustruct:"optional"`
 	statusCode  int      `exhaustruct:"optional"`
+	accepted    bool     `exhaustruct:"optional"`
 	deprecated  bool     `exhaustruct:"optional"`
+	example     any      `exhaustruct:"optional"`
 	errors      []struct {
 		Code    int
 		Message string
 	} `exhaustruct:"optional"`
 }
 
-func NewOpenAPIGenerator() *OpenAPIGenerator {
-	return &OpenAPIGenerator{
-		fileCache: newFileCache(),
+// hasError reports whether the handler documents an error response with the given status.
+func (info handlerInfo) hasError(code int) bool {
+	for _, e := range info.errors {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func NewOpenAPIGenerator(opts ...GeneratorOption) *OpenAPIGenerator {
+	generator := &OpenAPIGenerator{
+		fileCache:        newFileCache(),
+		errorSchema:      (*simbaErrors.ErrorResponse)(nil),
+		errorContentType: mimetypes.ApplicationJSON,
 	}
+
+	for _, opt := range opts {
+		opt(generator)
+	}
+
+	return generator
 }
 
 // GenerateDocumentation generates OpenAPI documentation for all routes.
@@ -67,16 +172,29 @@ func (g *OpenAPIGenerator) GenerateDocumentation(ctx context.Context, title stri
 		return nil, fmt.Errorf("failed to create OpenAPI reflector: %w", err)
 	}
 
+	if !g.timeFormat.IsDefault() {
+		reflector.DefaultOptions = append(reflector.DefaultOptions, documentTimeFormat(g.timeFormat))
+	}
+
 	reflector.SpecEns().Info.Title = title
 	reflector.SpecEns().Info.Version = version
 
+	envelope, err := g.envelopeSchema(reflector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reflect response envelope: %w", err)
+	}
+
 	for _, routeInfo := range routeInfos {
-		err = g.generateRouteDocumentation(ctx, reflector, &routeInfo)
+		err = g.generateRouteDocumentation(ctx, reflector, &routeInfo, envelope)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate documentation for route: %w", err)
 		}
 	}
 
+	if len(g.tags) > 0 {
+		reflector.SpecEns().Tags = specTags(g.tags)
+	}
+
 	schema, err := reflector.Spec.MarshalJSON()
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal OpenAPI schema: %w", err)
@@ -85,25 +203,78 @@ func (g *OpenAPIGenerator) GenerateDocumentation(ctx context.Context, title stri
 	return schema, nil
 }
 
+// specTags converts the registered tags to OpenAPI tags.
+// A tag registered more than once keeps its first position and the last metadata.
+func specTags(tags []openapiModels.Tag) []openapi31.Tag {
+	result := make([]openapi31.Tag, 0, len(tags))
+	positions := make(map[string]int, len(tags))
+	for _, tag := range tags {
+		specTag := openapi31.Tag{Name: tag.Name}
+		if tag.Description != "" {
+			specTag.WithDescription(tag.Description)
+		}
+		if tag.ExternalDocs != nil {
+			externalDocs := openapi31.ExternalDocumentation{URL: tag.ExternalDocs.URL}
+			if tag.ExternalDocs.Description != "" {
+				externalDocs.WithDescription(tag.ExternalDocs.Description)
+			}
+			specTag.WithExternalDocs(externalDocs)
+		}
+
+		if i, ok := positions[tag.Name]; ok {
+			result[i] = specTag
+			continue
+		}
+		positions[tag.Name] = len(result)
+		result = append(result, specTag)
+	}
+	return result
+}
+
 // generateRouteDocumentation generates OpenAPI documentation for a route.
-func (g *OpenAPIGenerator) generateRouteDocumentation(ctx context.Context, reflector *openapi31.Reflector, routeInfo *openapiModels.RouteInfo) error {
-	operationContext, err := reflector.NewOperationContext(routeInfo.Method, routeInfo.Path)
+func (g *OpenAPIGenerator) generateRouteDocumentation(ctx context.Context, reflector *openapi31.Reflector, routeInfo *openapiModels.RouteInfo, envelope map[string]any) error {
+	path, wildcards := specPath(routeInfo.Path)
+	operationContext, err := reflector.NewOperationContext(routeInfo.Method, path)
 	if err != nil {
 		return err
 	}
 
 	info := g.getHandlerInfo(ctx, routeInfo.Handler)
+	routeInfo, versionParams := resolveVersionedBodies(routeInfo)
+
+	id, description := info.id, info.description
+	if routeInfo.AliasOf != "" {
+		id, description = aliasOperation(id, description, routeInfo.Method, path, routeInfo.AliasOf)
+	}
 
-	operationContext.SetIsDeprecated(info.deprecated)
-	operationContext.SetID(info.id)
+	operationContext.SetIsDeprecated(info.deprecated || routeInfo.Deprecated)
+	operationContext.SetID(id)
 	operationContext.SetTags(info.tags...)
 	operationContext.SetSummary(info.summary)
-	operationContext.SetDescription(info.description)
+	operationContext.SetDescription(description)
 
 	// Add request body if it exists
 	if routeInfo.ReqBody != nil {
-		operationContext.AddReqStructure(routeInfo.ReqBody, func(cu *openapi.ContentUnit) {
+		reqBody := routeInfo.ReqBody
+		var customizers []func(cor openapi.ContentOrReference)
+		if body, ok := reqBody.(openapiModels.DiscriminatedBody); ok {
+			var values []string
+			reqBody, values = newOneOfBody(body)
+			customizers = append(customizers, setDiscriminator(body.Discriminator, values))
+		}
+		if info.example != nil {
+			customizers = append(customizers, setRequestBodyExample(info.example))
+		}
+
+		operationContext.AddReqStructure(reqBody, func(cu *openapi.ContentUnit) {
 			cu.ContentType = routeInfo.Accepts
+			if len(customizers) > 0 {
+				cu.Customize = func(cor openapi.ContentOrReference) {
+					for _, customize := range customizers {
+						customize(cor)
+					}
+				}
+			}
 		})
 	}
 
@@ -111,42 +282,61 @@ func (g *OpenAPIGenerator) generateRouteDocumentation(ctx context.Context, refle
 	if routeInfo.Params != nil {
 		operationContext.AddReqStructure(routeInfo.Params)
 	}
+	if versionParams != nil {
+		operationContext.AddReqStructure(versionParams)
+	}
 
 	// Get response status code
 	if info.statusCode == 0 {
-		if routeInfo.RespBody == (*models.NoBody)(nil) {
-			info.statusCode = http.StatusNoContent // Default for no response body
-		} else {
-			info.statusCode = http.StatusOK // Default for response body
-		}
+		info.statusCode = g.defaultStatus(routeInfo)
+	}
+
+	// File downloads and multipart responses are documented as binary content rather than their structs
+	respBody := routeInfo.RespBody
+	isBinary := false
+	switch respBody.(type) {
+	case models.File, models.Multipart:
+		isBinary = true
+		respBody = ""
 	}
 
 	// Add response with the status code
-	operationContext.AddRespStructure(routeInfo.RespBody, func(cu *openapi.ContentUnit) {
+	operationContext.AddRespStructure(respBody, func(cu *openapi.ContentUnit) {
 		cu.HTTPStatus = info.statusCode
 		cu.ContentType = routeInfo.Produces
+		switch {
+		case isBinary:
+			cu.Customize = setBinaryResponseFormat
+		case envelope != nil && cu.ContentType == mimetypes.ApplicationJSON:
+			cu.Customize = wrapResponseInEnvelope(envelope, g.envelopeField)
+		}
+		if info.accepted {
+			customize := cu.Customize
+			cu.Customize = func(cor openapi.ContentOrReference) {
+				if customize != nil {
+					customize(cor)
+				}
+				setLocationHeader(cor)
+			}
+		}
 	})
 
+	// Add alternative media types for the same status
+	for _, response := range routeInfo.AlternativeResponses {
+		operationContext.AddRespStructure(response.Body, func(cu *openapi.ContentUnit) {
+			cu.HTTPStatus = info.statusCode
+			cu.ContentType = response.ContentType
+		})
+	}
+
 	// Add default error responses
-	operationContext.AddRespStructure((*simbaErrors.ErrorResponse)(nil), func(cu *openapi.ContentUnit) {
-		cu.HTTPStatus = http.StatusBadRequest
-		cu.Description = "Request body contains invalid data"
-	})
-	operationContext.AddRespStructure((*simbaErrors.ErrorResponse)(nil), func(cu *openapi.ContentUnit) {
-		cu.HTTPStatus = http.StatusUnprocessableEntity
-		cu.Description = "Request body could not be processed"
-	})
-	operationContext.AddRespStructure((*simbaErrors.ErrorResponse)(nil), func(cu *openapi.ContentUnit) {
-		cu.HTTPStatus = http.StatusInternalServerError
-		cu.Description = "Unexpected error"
-	})
+	g.addErrorResponse(operationContext, http.StatusBadRequest, "Request body contains invalid data")
+	g.addErrorResponse(operationContext, http.StatusUnprocessableEntity, "Request body could not be processed")
+	g.addErrorResponse(operationContext, http.StatusInternalServerError, "Unexpected error")
 
 	// Add custom error responses
 	for _, e := range info.errors {
-		operationContext.AddRespStructure((*simbaErrors.ErrorResponse)(nil), func(cu *openapi.ContentUnit) {
-			cu.HTTPStatus = e.Code
-			cu.Description = e.Message
-		})
+		g.addErrorResponse(operationContext, e.Code, e.Message)
 	}
 
 	// Add security if authenticated route
@@ -197,14 +387,8 @@ func (g *OpenAPIGenerator) generateRouteDocumentation(ctx context.Context, refle
 
 			operationContext.AddSecurity(authHandler.GetName())
 
-			operationContext.AddRespStructure((*simbaErrors.ErrorResponse)(nil), func(cu *openapi.ContentUnit) {
-				cu.HTTPStatus = http.StatusUnauthorized
-				cu.Description = "Authorization failed"
-			})
-			operationContext.AddRespStructure((*simbaErrors.ErrorResponse)(nil), func(cu *openapi.ContentUnit) {
-				cu.HTTPStatus = http.StatusForbidden
-				cu.Description = "Access denied"
-			})
+			g.addErrorResponse(operationContext, http.StatusUnauthorized, "Authorization failed")
+			g.addErrorResponse(operationContext, http.StatusForbidden, "Access denied")
 		}
 	}
 
@@ -213,9 +397,258 @@ func (g *OpenAPIGenerator) generateRouteDocumentation(ctx context.Context, refle
 		return err
 	}
 
+	operation, err := specOperation(reflector, routeInfo.Method, path)
+	if err != nil {
+		return err
+	}
+	describeWildcards(operation, wildcards)
+	referenceParameters(reflector, operation, parameterReferences(routeInfo.Params))
+	if routeInfo.AliasOf != "" {
+		operation.WithMapOfAnythingItem("x-alias-of", routeInfo.AliasOf)
+	}
+	if g.extensions {
+		addOperationExtensions(operation, routeInfo)
+	}
+
 	return nil
 }
 
+// addOperationExtensions documents the rate limit of the route in the x-ratelimit extension and the
+// deprecation dates of deprecated routes in the x-deprecated extension of the operation.
+func addOperationExtensions(operation *openapi31.Operation, routeInfo *openapiModels.RouteInfo) {
+	if limit := routeInfo.RateLimit; limit != nil {
+		operation.WithMapOfAnythingItem("x-ratelimit", map[string]any{
+			"limit":         limit.Requests,
+			"windowSeconds": int64(limit.Window / time.Second),
+		})
+	}
+
+	if routeInfo.Deprecated {
+		deprecation := map[string]any{}
+		if !routeInfo.DeprecatedAt.IsZero() {
+			deprecation["since"] = routeInfo.DeprecatedAt.UTC().Format(time.RFC3339)
+		}
+		if !routeInfo.Sunset.IsZero() {
+			deprecation["sunset"] = routeInfo.Sunset.UTC().Format(time.RFC3339)
+		}
+		operation.WithMapOfAnythingItem("x-deprecated", deprecation)
+	}
+}
+
+// aliasOperation returns the operation ID and description of an alias route. The method and path are
+// appended to the operation ID to keep it unique, and the description notes the canonical route.
+func aliasOperation(id string, description string, method string, path string, aliasOf string) (string, string) {
+	if id != "" {
+		words := []string{strings.ToLower(method)}
+		for _, segment := range strings.Split(path, "/") {
+			if segment = strings.Trim(segment, "{}"); segment != "" {
+				words = append(words, segment)
+			}
+		}
+		id += "-" + strcase.ToKebab(strings.Join(words, " "))
+	}
+
+	note := "Alias of " + aliasOf + "."
+	if description != "" {
+		note = description + "\n\n" + note
+	}
+	return id, note
+}
+
+// specOperation returns the operation added to the spec for the method and path.
+func specOperation(reflector *openapi31.Reflector, method string, path string) (*openapi31.Operation, error) {
+	_, path, _, err := openapi.SanitizeMethodPath(method, path)
+	if err != nil {
+		return nil, err
+	}
+	return reflector.SpecEns().PathsEns().MapOfPathItemValues[path].Operation(method)
+}
+
+// specPath returns the route path with wildcard segments such as {path...} written as regular path
+// parameters, along with the names of the wildcards.
+func specPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var wildcards []string
+	for i, segment := range segments {
+		if name, ok := strings.CutSuffix(segment, "...}"); ok && strings.HasPrefix(name, "{") {
+			wildcards = append(wildcards, name[1:])
+			segments[i] = name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), wildcards
+}
+
+// describeWildcards notes in the description of wildcard path parameters that they match the
+// remainder of the path.
+func describeWildcards(operation *openapi31.Operation, wildcards []string) {
+	for _, param := range operation.Parameters {
+		if param.Parameter == nil || param.Parameter.In != openapi31.ParameterInPath || !slices.Contains(wildcards, param.Parameter.Name) {
+			continue
+		}
+		note := "Matches the remainder of the path, including slashes."
+		if param.Parameter.Description != nil && *param.Parameter.Description != "" {
+			note = strings.TrimSuffix(*param.Parameter.Description, ".") + ". " + note
+		}
+		param.Parameter.WithDescription(note)
+	}
+}
+
+// defaultStatus returns the success status of a route whose handler doesn't set one. Routes without a
+// response body respond with 204, other routes with the default status of the route or its method, or 200.
+func (g *OpenAPIGenerator) defaultStatus(routeInfo *openapiModels.RouteInfo) int {
+	switch routeInfo.RespBody {
+	case models.NoBody{}, (*models.NoBody)(nil):
+		return http.StatusNoContent
+	}
+	if routeInfo.DefaultStatus != 0 {
+		return routeInfo.DefaultStatus
+	}
+	if status, ok := g.defaultStatuses[routeInfo.Method]; ok {
+		return status
+	}
+	return http.StatusOK
+}
+
+// setLocationHeader documents the Location header of a 202 Accepted response, pointing to the status
+// resource of the accepted operation.
+func setLocationHeader(cor openapi.ContentOrReference) {
+	response, ok := cor.(*openapi31.ResponseOrReference)
+	if !ok || response.Response == nil {
+		return
+	}
+
+	response.Response.WithHeadersItem("Location", openapi31.HeaderOrReference{
+		Header: (&openapi31.Header{
+			Schema: map[string]any{"type": "string", "format": "uri-reference"},
+		}).WithDescription("URL of the status resource of the accepted operation.").WithRequired(true),
+	})
+}
+
+// setBinaryResponseFormat marks the string schemas of a response as binary content, such as a file download.
+func setBinaryResponseFormat(cor openapi.ContentOrReference) {
+	response, ok := cor.(*openapi31.ResponseOrReference)
+	if !ok || response.Response == nil {
+		return
+	}
+
+	for _, mediaType := range response.Response.Content {
+		if mediaType.Schema != nil {
+			mediaType.Schema["format"] = "binary"
+		}
+	}
+}
+
+// envelopeSchema returns the inlined JSON schema of the response envelope, or nil if none is configured.
+func (g *OpenAPIGenerator) envelopeSchema(reflector *openapi31.Reflector) (map[string]any, error) {
+	if g.envelope == nil {
+		return nil, nil
+	}
+
+	schema, err := reflector.JSONSchemaReflector().Reflect(g.envelope, jsonschema.InlineRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope map[string]any
+	if err = json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+// wrapResponseInEnvelope documents the response body as the given property of the envelope.
+func wrapResponseInEnvelope(envelope map[string]any, field string) func(cor openapi.ContentOrReference) {
+	return func(cor openapi.ContentOrReference) {
+		response, ok := cor.(*openapi31.ResponseOrReference)
+		if !ok || response.Response == nil {
+			return
+		}
+
+		for contentType, mediaType := range response.Response.Content {
+			if mediaType.Schema == nil {
+				continue
+			}
+
+			properties := map[string]any{}
+			if envelopeProperties, isMap := envelope["properties"].(map[string]any); isMap {
+				maps.Copy(properties, envelopeProperties)
+			}
+			properties[field] = mediaType.Schema
+
+			schema := maps.Clone(envelope)
+			schema["properties"] = properties
+			mediaType.Schema = schema
+			response.Response.Content[contentType] = mediaType
+		}
+	}
+}
+
+// setRequestBodyExample sets the example on all media types of a request body.
+func setRequestBodyExample(example any) func(cor openapi.ContentOrReference) {
+	return func(cor openapi.ContentOrReference) {
+		requestBody, ok := cor.(*openapi31.RequestBodyOrReference)
+		if !ok || requestBody.RequestBody == nil {
+			return
+		}
+
+		for contentType, mediaType := range requestBody.RequestBody.Content {
+			requestBody.RequestBody.Content[contentType] = *mediaType.WithExample(example)
+		}
+	}
+}
+
+// addErrorResponse documents an error response with the configured error schema and example.
+func (g *OpenAPIGenerator) addErrorResponse(operationContext openapi.OperationContext, status int, description string) {
+	operationContext.AddRespStructure(g.errorSchema, func(cu *openapi.ContentUnit) {
+		cu.HTTPStatus = status
+		cu.Description = description
+		cu.ContentType = g.errorContentType
+		example, hasExample := g.errorExamples[status]
+		cu.Customize = func(cor openapi.ContentOrReference) {
+			if hasExample {
+				setResponseExample(example)(cor)
+			}
+			if status == http.StatusTooManyRequests {
+				setRetryAfterHeader(cor)
+			}
+		}
+	})
+}
+
+// setRetryAfterHeader documents the Retry-After header of a 429 Too Many Requests response.
+func setRetryAfterHeader(cor openapi.ContentOrReference) {
+	response, ok := cor.(*openapi31.ResponseOrReference)
+	if !ok || response.Response == nil {
+		return
+	}
+
+	response.Response.WithHeadersItem("Retry-After", openapi31.HeaderOrReference{
+		Header: (&openapi31.Header{
+			Schema: map[string]any{"type": "integer", "minimum": 0},
+		}).WithDescription("Number of seconds to wait before retrying the request."),
+	})
+}
+
+// setResponseExample sets the example on all media types of a response.
+func setResponseExample(example any) func(cor openapi.ContentOrReference) {
+	return func(cor openapi.ContentOrReference) {
+		response, ok := cor.(*openapi31.ResponseOrReference)
+		if !ok || response.Response == nil {
+			return
+		}
+
+		for contentType, mediaType := range response.Response.Content {
+			response.Response.Content[contentType] = *mediaType.WithExample(example)
+		}
+	}
+}
+
 // getHandlerInfo extracts the handler information from the handler function.
 func (g *OpenAPIGenerator) getHandlerInfo(ctx context.Context, handler any) handlerInfo {
 	functionPointer := g.getFunctionPointer(handler)
@@ -255,7 +688,15 @@ func (g *OpenAPIGenerator) getHandlerInfo(ctx context.Context, handler any) hand
 	}
 
 	if info.statusCode == 0 {
-		info.statusCode = g.findStatusInAST(functionFile, methodName)
+		info.statusCode, info.accepted = g.findStatusInAST(functionFile, methodName)
+	}
+
+	// Handlers returning simbaErrors.TooManyRequests respond with 429, document it unless already done
+	if !info.hasError(http.StatusTooManyRequests) && g.findThrottlingInAST(functionFile, methodName) {
+		info.errors = append(info.errors, struct {
+			Code    int
+			Message string
+		}{Code: http.StatusTooManyRequests, Message: "Too many requests"})
 	}
 
 	return info
@@ -455,7 +896,9 @@ func (g *OpenAPIGenerator) parseHandlerCommentTags(comment string) handlerInfo {
 	}
 
 	var descLines []string
+	var exampleLines []string
 	insideDesc := false
+	insideExample := false
 
 	for _, line := range lines {
 		switch {
@@ -466,6 +909,15 @@ func (g *OpenAPIGenerator) parseHandlerCommentTags(comment string) handlerInfo {
 			info.tags = append(info.tags, tag)
 		case strings.HasPrefix(line, summaryTag):
 			info.summary = strings.TrimSpace(strings.TrimPrefix(line, summaryTag))
+		case strings.HasPrefix(line, exampleTag):
+			insideDesc = false
+			insideExample = true
+			text := strings.TrimSpace(strings.TrimPrefix(line, exampleTag))
+			if text != "" {
+				exampleLines = append(exampleLines, text)
+			}
+		case insideExample && !strings.HasPrefix(line, "@"):
+			exampleLines = append(exampleLines, line)
 		case strings.HasPrefix(line, descriptionTag):
 			insideDesc = true
 			text := strings.TrimSpace(strings.TrimPrefix(line, descriptionTag))
@@ -500,20 +952,32 @@ func (g *OpenAPIGenerator) parseHandlerCommentTags(comment string) handlerInfo {
 		case strings.HasPrefix(line, "@"):
 			insideDesc = false
 		}
+
+		if strings.HasPrefix(line, "@") && !strings.HasPrefix(line, exampleTag) {
+			insideExample = false
+		}
 	}
 
 	info.description = strings.Join(descLines, "\n")
 
+	if len(exampleLines) > 0 {
+		var example any
+		if err := json.Unmarshal([]byte(strings.Join(exampleLines, "\n")), &example); err == nil {
+			info.example = example
+		}
+	}
+
 	return info
 }
 
 // findStatusInAST looks for status codes in the AST.
-func (g *OpenAPIGenerator) findStatusInAST(node *ast.File, methodName string) int {
+func (g *OpenAPIGenerator) findStatusInAST(node *ast.File, methodName string) (int, bool) {
 	if node == nil {
-		return 0
+		return 0, false
 	}
 
 	var status int
+	var accepted bool
 
 	ast.Inspect(node, func(n ast.Node) bool {
 		funcDecl, ok := n.(*ast.FuncDecl)
@@ -531,6 +995,14 @@ func (g *OpenAPIGenerator) findStatusInAST(node *ast.File, methodName string) in
 
 			// Check if we're returning a response object
 			for _, result := range ret.Results {
+				// Responses built with models.Accepted are 202 Accepted with a Location header,
+				// and responses built with models.MultiStatus are 207 Multi-Status
+				if s := responseHelperStatus(result); s != 0 {
+					status = s
+					accepted = s == http.StatusAccepted
+					return false
+				}
+
 				// Try to find Status field in composite literals
 				if unary, ok := result.(*ast.UnaryExpr); ok {
 					if cl, ok := unary.X.(*ast.CompositeLit); ok {
@@ -564,7 +1036,71 @@ func (g *OpenAPIGenerator) findStatusInAST(node *ast.File, methodName string) in
 		return false // Stop searching after finding the function
 	})
 
-	return status
+	return status, accepted
+}
+
+// findThrottlingInAST reports whether the handler calls simbaErrors.TooManyRequests.
+func (g *OpenAPIGenerator) findThrottlingInAST(node *ast.File, methodName string) bool {
+	if node == nil {
+		return false
+	}
+
+	var found bool
+	ast.Inspect(node, func(n ast.Node) bool {
+		funcDecl, ok := n.(*ast.FuncDecl)
+		if !ok || funcDecl.Name.Name != methodName {
+			return true
+		}
+
+		ast.Inspect(funcDecl, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return !found
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "TooManyRequests" {
+				if pkgIdent, ok := sel.X.(*ast.Ident); ok && pkgIdent.Name == "simbaErrors" {
+					found = true
+				}
+			}
+			return !found
+		})
+		return false
+	})
+
+	return found
+}
+
+// responseHelperStatus returns the status of the response built by a call to models.Accepted or
+// models.MultiStatus, or 0 if expr isn't such a call.
+func responseHelperStatus(expr ast.Expr) int {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return 0
+	}
+
+	fun := call.Fun
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+
+	var name string
+	switch f := fun.(type) {
+	case *ast.SelectorExpr:
+		name = f.Sel.Name
+	case *ast.Ident:
+		name = f.Name
+	}
+
+	switch name {
+	case "Accepted":
+		return http.StatusAccepted
+	case "MultiStatus":
+		return http.StatusMultiStatus
+	}
+	return 0
 }
 
 // getPackageName extracts the package name for a handler function given its full name.
@@ -588,9 +1124,14 @@ func (g *OpenAPIGenerator) getPackageName(fullName string) string {
 func (g *OpenAPIGenerator) getCommentStrippedFromTags(comment string, methodName string) string {
 	lines := strings.Split(strings.TrimSpace(comment), "\n")
 	result := ""
+	insideExample := false
 
 	for _, line := range lines {
 		if strings.HasPrefix(line, "@") {
+			insideExample = strings.HasPrefix(line, exampleTag)
+			continue
+		}
+		if insideExample {
 			continue
 		}
 		result += line + "\n"
@@ -0,0 +1,10 @@
+package openapiModels
+
+// DiscriminatedBody describes a request body that is one of several types, selected by the
+// value of a discriminator property.
+type DiscriminatedBody struct {
+	// Discriminator is the name of the property selecting the type.
+	Discriminator string
+	// Variants maps the discriminator values to values of their types.
+	Variants map[string]any
+}
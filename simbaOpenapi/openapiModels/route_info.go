@@ -1,5 +1,7 @@
 package openapiModels
 
+import "time"
+
 // RouteInfo stores type information about a route.
 type RouteInfo struct {
 	Method      string
@@ -12,4 +14,37 @@ type RouteInfo struct {
 	Handler     any
 	AuthModel   any
 	AuthHandler any
+
+	// AlternativeResponses are additional media types the route can respond with
+	// for the success status, each documented with its own schema.
+	AlternativeResponses []ResponseContent `exhaustruct:"optional"`
+
+	// Deprecated marks the route as deprecated in addition to the @Deprecated handler comment.
+	Deprecated bool `exhaustruct:"optional"`
+
+	// AliasOf is the method and path of the canonical route, such as "GET /users", if the route
+	// is an alias registered for the same handler.
+	AliasOf string `exhaustruct:"optional"`
+	// DefaultStatus is the status of successful responses with a body if the handler doesn't set one,
+	// overriding the default status of the method.
+	DefaultStatus int `exhaustruct:"optional"`
+
+	// DeprecatedAt is the date the route was deprecated, if known.
+	DeprecatedAt time.Time `exhaustruct:"optional"`
+	// Sunset is the date the route is removed, if known.
+	Sunset time.Time `exhaustruct:"optional"`
+	// RateLimit is the rate limit of the route, documented for API gateways.
+	RateLimit *RateLimit `exhaustruct:"optional"`
+}
+
+// RateLimit is the number of requests a client may send to a route per window.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ResponseContent describes a response body for a single media type.
+type ResponseContent struct {
+	ContentType string
+	Body        any
 }
@@ -0,0 +1,14 @@
+package openapiModels
+
+// Tag holds the metadata of a tag listed in the top-level tags of the OpenAPI documentation.
+type Tag struct {
+	Name         string
+	Description  string        `exhaustruct:"optional"`
+	ExternalDocs *ExternalDocs `exhaustruct:"optional"`
+}
+
+// ExternalDocs links to external documentation.
+type ExternalDocs struct {
+	URL         string
+	Description string `exhaustruct:"optional"`
+}
@@ -0,0 +1,12 @@
+package openapiModels
+
+// VersionedBody describes a request or response body whose type depends on the schema version
+// selected by a request header.
+type VersionedBody struct {
+	// Header is the name of the request header selecting the version.
+	Header string
+	// Default is the version used if the header is missing, or empty if the header is required.
+	Default string
+	// Versions maps the versions to values of their body types.
+	Versions map[string]any
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sillen102/simba/mimetypes"
 	"github.com/sillen102/simba/models"
@@ -23,6 +25,7 @@ type openAPIDoc struct {
 	Info       openapi31.Info       `json:"info"`
 	Paths      openapi31.Paths      `json:"paths"`
 	Components openapi31.Components `json:"components"`
+	Tags       []openapi31.Tag      `json:"tags"`
 }
 
 var validate = validator.New(validator.WithRequiredStructEnabled())
@@ -257,6 +260,210 @@ func TestResponseCode(t *testing.T) {
 	}
 }
 
+func TestAcceptedResponse(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/jobs/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.AcceptedHandler,
+			ReqBody:  simbaTest.RequestBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	responses := doc.Paths.MapOfPathItemValues["/jobs/{id}"].Post.Responses.MapOfResponseOrReferenceValues
+	_, ok := responses["200"]
+	assert.False(t, ok)
+
+	response := responses["202"].Response
+	assert.NotNil(t, response)
+	location := response.Headers["Location"].Header
+	assert.NotNil(t, location)
+	assert.True(t, *location.Required)
+	assert.Equal[any](t, "string", location.Schema["type"])
+	assert.Equal(t, "URL of the status resource of the accepted operation.", *location.Description)
+}
+
+func TestTooManyRequestsResponse(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/exports/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.ThrottledHandler,
+			ReqBody:  simbaTest.RequestBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/jobs/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.AcceptedHandler,
+			ReqBody:  simbaTest.RequestBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	response := doc.Paths.MapOfPathItemValues["/exports/{id}"].Post.Responses.MapOfResponseOrReferenceValues["429"].Response
+	assert.NotNil(t, response)
+	assert.Equal(t, "Too many requests", response.Description)
+	retryAfter := response.Headers["Retry-After"].Header
+	assert.NotNil(t, retryAfter)
+	assert.Equal[any](t, "integer", retryAfter.Schema["type"])
+	assert.Equal(t, "Number of seconds to wait before retrying the request.", *retryAfter.Description)
+
+	_, ok := doc.Paths.MapOfPathItemValues["/jobs/{id}"].Post.Responses.MapOfResponseOrReferenceValues["429"]
+	assert.False(t, ok)
+}
+
+func TestDefaultStatuses(t *testing.T) {
+	t.Parallel()
+
+	noBodyHandler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	route := func(method, path string, handler any, respBody any, defaultStatus int) openapiModels.RouteInfo {
+		return openapiModels.RouteInfo{
+			Method:        method,
+			Path:          path,
+			Accepts:       mimetypes.ApplicationJSON,
+			Produces:      mimetypes.ApplicationJSON,
+			Handler:       handler,
+			ReqBody:       simbaTest.RequestBody{},
+			RespBody:      respBody,
+			Params:        simbaTest.Params{},
+			DefaultStatus: defaultStatus,
+		}
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator(simbaOpenapi.WithDefaultStatuses(map[string]int{
+		http.MethodPost: http.StatusCreated,
+	}))
+	routeInfo := []openapiModels.RouteInfo{
+		route(http.MethodPost, "/method/{id}", simbaTest.ExampleHandler, simbaTest.ResponseBody{}, 0),
+		route(http.MethodPut, "/method/{id}", simbaTest.ExampleHandler, simbaTest.ResponseBody{}, 0),
+		route(http.MethodPost, "/route/{id}", simbaTest.ExampleHandler, simbaTest.ResponseBody{}, http.StatusAccepted),
+		route(http.MethodPost, "/tag/{id}", simbaTest.TagsHandler, simbaTest.ResponseBody{}, http.StatusAccepted),
+		route(http.MethodPost, "/no-body/{id}", noBodyHandler, models.NoBody{}, 0),
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	tests := []struct {
+		name      string
+		operation *openapi31.Operation
+		expected  string
+	}{
+		{
+			name:      "method default",
+			operation: doc.Paths.MapOfPathItemValues["/method/{id}"].Post,
+			expected:  "201",
+		},
+		{
+			name:      "method without default",
+			operation: doc.Paths.MapOfPathItemValues["/method/{id}"].Put,
+			expected:  "200",
+		},
+		{
+			name:      "route default overrides method default",
+			operation: doc.Paths.MapOfPathItemValues["/route/{id}"].Post,
+			expected:  "202",
+		},
+		{
+			name:      "status code tag overrides route default",
+			operation: doc.Paths.MapOfPathItemValues["/tag/{id}"].Post,
+			expected:  "201",
+		},
+		{
+			name:      "no body",
+			operation: doc.Paths.MapOfPathItemValues["/no-body/{id}"].Post,
+			expected:  "204",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var statuses []string
+			for status := range tt.operation.Responses.MapOfResponseOrReferenceValues {
+				if strings.HasPrefix(status, "2") {
+					statuses = append(statuses, status)
+				}
+			}
+			assert.Equal(t, []string{tt.expected}, statuses)
+		})
+	}
+}
+
+func TestMultiStatusResponse(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/users/bulk",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.MultiStatusHandler,
+			ReqBody:  []simbaTest.RequestBody{},
+			RespBody: models.MultiStatusBody[simbaTest.ResponseBody]{},
+			Params:   models.NoParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	responses := doc.Paths.MapOfPathItemValues["/users/bulk"].Post.Responses.MapOfResponseOrReferenceValues
+	_, ok := responses["200"]
+	assert.False(t, ok)
+
+	response := responses["207"].Response
+	assert.NotNil(t, response)
+	ref := response.Content[mimetypes.ApplicationJSON].Schema["$ref"].(string)
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+
+	body := doc.Components.Schemas[name]
+	assert.Contains(t, []string{"results"}, body["required"])
+	results := body["properties"].(map[string]any)["results"].(map[string]any)
+	assert.Equal[any](t, "array", results["type"])
+
+	itemRef := results["items"].(map[string]any)["$ref"].(string)
+	item := doc.Components.Schemas[strings.TrimPrefix(itemRef, "#/components/schemas/")]
+	properties := item["properties"].(map[string]any)
+	for _, property := range []string{"index", "status", "result", "error"} {
+		_, ok := properties[property]
+		assert.True(t, ok, "missing property "+property)
+	}
+}
+
 func TestTags(t *testing.T) {
 	t.Parallel()
 
@@ -621,6 +828,73 @@ func TestCustomError(t *testing.T) {
 	}
 }
 
+func TestErrorSchema(t *testing.T) {
+	t.Parallel()
+
+	type problem struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+	}
+
+	path := "/test/{id}"
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     path,
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  simbaTest.RequestBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	t.Run("documents default error response", func(t *testing.T) {
+		generator := simbaOpenapi.NewOpenAPIGenerator()
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		response := doc.Paths.MapOfPathItemValues[path].Post.Responses.MapOfResponseOrReferenceValues["400"].Response
+		assert.NotNil(t, response)
+		content, ok := response.Content[mimetypes.ApplicationJSON]
+		assert.True(t, ok, "expected application/json error content")
+		assert.Equal(t, "#/components/schemas/SimbaErrorsErrorResponse", content.Schema["$ref"])
+	})
+
+	t.Run("documents configured error schema", func(t *testing.T) {
+		generator := simbaOpenapi.NewOpenAPIGenerator(simbaOpenapi.WithErrorSchema(problem{}, "application/problem+json"))
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		response := doc.Paths.MapOfPathItemValues[path].Post.Responses.MapOfResponseOrReferenceValues["500"].Response
+		assert.NotNil(t, response)
+		_, ok := response.Content["application/problem+json"]
+		assert.True(t, ok, "expected application/problem+json error content")
+		_, ok = response.Content[mimetypes.ApplicationJSON]
+		assert.False(t, ok, "did not expect application/json error content")
+	})
+
+	t.Run("documents configured error examples", func(t *testing.T) {
+		example := map[string]any{"status": float64(http.StatusBadRequest), "message": "invalid request body"}
+		generator := simbaOpenapi.NewOpenAPIGenerator(simbaOpenapi.WithErrorExamples(map[int]any{
+			http.StatusBadRequest: example,
+		}))
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		responses := doc.Paths.MapOfPathItemValues[path].Post.Responses.MapOfResponseOrReferenceValues
+		badRequest := responses["400"].Response.Content[mimetypes.ApplicationJSON]
+		assert.NotNil(t, badRequest.Example)
+		assert.Equal(t, any(example), *badRequest.Example)
+		serverError := responses["500"].Response.Content[mimetypes.ApplicationJSON]
+		assert.Nil(t, serverError.Example)
+	})
+}
+
 func TestDeprecated(t *testing.T) {
 	t.Parallel()
 
@@ -681,6 +955,23 @@ func TestDeprecated(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "route marked deprecated",
+			routeInfo: []openapiModels.RouteInfo{
+				{
+					Method:     http.MethodPost,
+					Path:       path,
+					Accepts:    mimetypes.ApplicationJSON,
+					Produces:   mimetypes.ApplicationJSON,
+					Handler:    simbaTest.NoTagsHandler,
+					ReqBody:    simbaTest.RequestBody{},
+					RespBody:   simbaTest.ResponseBody{},
+					Params:     simbaTest.Params{},
+					Deprecated: true,
+				},
+			},
+			expected: true,
+		},
 		{
 			name: "handler with receiver, tags but no deprecated tag",
 			routeInfo: []openapiModels.RouteInfo{
@@ -990,3 +1281,800 @@ func unmarshalJSON(t *testing.T, schema []byte) openAPIDoc {
 
 	return jsonDoc
 }
+
+func TestMapRequestBody(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/test/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  map[string]any{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	requestBody := doc.Paths.MapOfPathItemValues["/test/{id}"].Post.RequestBody.RequestBody
+	bodySchema := requestBody.Content[mimetypes.ApplicationJSON].Schema
+	assert.Equal(t, "object", bodySchema["type"])
+	assert.NotNil(t, bodySchema["additionalProperties"])
+}
+
+func TestRequestBodyExample(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/test/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.ExampleHandler,
+			ReqBody:  simbaTest.RequestBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	operation := doc.Paths.MapOfPathItemValues["/test/{id}"].Post
+	example := operation.RequestBody.RequestBody.Content[mimetypes.ApplicationJSON].Example
+	assert.Equal(t, any(map[string]any{"name": "Jane Doe", "age": float64(42), "description": "example user"}), *example)
+	assert.Equal(t, "example handler.", *operation.Summary)
+	assert.Equal(t, "A dummy function to test the OpenAPI generation with a request body example.", *operation.Description)
+}
+
+func TestAlternativeResponses(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/test/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  simbaTest.RequestBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   simbaTest.Params{},
+			AlternativeResponses: []openapiModels.ResponseContent{
+				{ContentType: "text/csv", Body: ""},
+			},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	content := doc.Paths.MapOfPathItemValues["/test/{id}"].Post.Responses.MapOfResponseOrReferenceValues["201"].Response.Content
+	assert.Equal(t, 2, len(content))
+	assert.Equal(t, "#/components/schemas/SimbaTestResponseBody", content[mimetypes.ApplicationJSON].Schema["$ref"])
+	assert.Equal(t, "string", content["text/csv"].Schema["type"])
+}
+
+func TestDeepObjectQueryParams(t *testing.T) {
+	t.Parallel()
+
+	type Filter struct {
+		Status string `query:"status"`
+		Limit  int    `query:"limit"`
+	}
+
+	type Params struct {
+		Filter Filter `query:"filter"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/test",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	parameters := doc.Paths.MapOfPathItemValues["/test"].Get.Parameters
+	assert.Len(t, parameters, 1)
+
+	parameter := parameters[0].Parameter
+	assert.Equal(t, "filter", parameter.Name)
+	assert.Equal(t, openapi31.ParameterInQuery, parameter.In)
+	assert.Equal(t, openapi31.ParameterStyleDeepObject, *parameter.Style)
+	assert.True(t, *parameter.Explode)
+}
+
+func TestWildcardPathParam(t *testing.T) {
+	t.Parallel()
+
+	type Params struct {
+		Path string `path:"path" description:"Path of the file"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/files/{path...}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	parameters := doc.Paths.MapOfPathItemValues["/files/{path}"].Get.Parameters
+	assert.Len(t, parameters, 1)
+
+	parameter := parameters[0].Parameter
+	assert.Equal(t, "path", parameter.Name)
+	assert.Equal(t, openapi31.ParameterInPath, parameter.In)
+	assert.Equal(t, "Path of the file. Matches the remainder of the path, including slashes.", *parameter.Description)
+}
+
+func TestDurationParams(t *testing.T) {
+	t.Parallel()
+
+	type Params struct {
+		Timeout  time.Duration  `query:"timeout" default:"30s" example:"1m30s"`
+		Interval *time.Duration `header:"X-Interval"`
+	}
+
+	type Body struct {
+		Delay time.Duration `json:"delay"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/test",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  Body{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	parameters := doc.Paths.MapOfPathItemValues["/test"].Post.Parameters
+	assert.Len(t, parameters, 2)
+	timeout := parameters[0].Parameter.Schema
+	assert.Equal[any](t, "string", timeout["type"])
+	assert.Equal[any](t, "duration", timeout["format"])
+	assert.Equal[any](t, "30s", timeout["default"])
+	assert.Equal[any](t, []any{"1m30s"}, timeout["examples"])
+
+	interval := parameters[1].Parameter.Schema
+	assert.Equal[any](t, []any{"string", "null"}, interval["type"])
+	assert.Equal[any](t, "duration", interval["format"])
+
+	// Durations in bodies are encoded by encoding/json as integer nanoseconds
+	delay := doc.Components.Schemas["SimbaOpenapiTestBody"]["properties"].(map[string]any)["delay"].(map[string]any)
+	assert.Equal[any](t, "integer", delay["type"])
+}
+
+func TestHeaderSliceParams(t *testing.T) {
+	t.Parallel()
+
+	type Params struct {
+		Languages []string `header:"Accept-Language" validate:"dive,min=2"`
+		Limits    []int    `header:"X-Limit"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/test",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  nil,
+			RespBody: simbaTest.ResponseBody{},
+			Params:   Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	parameters := doc.Paths.MapOfPathItemValues["/test"].Get.Parameters
+	assert.Len(t, parameters, 2)
+
+	languages := parameters[0].Parameter
+	assert.Equal(t, "Accept-Language", languages.Name)
+	assert.Equal[any](t, "array", languages.Schema["type"])
+	assert.Equal[any](t, "string", languages.Schema["items"].(map[string]any)["type"])
+
+	limits := parameters[1].Parameter
+	assert.Equal(t, "X-Limit", limits.Name)
+	assert.Equal[any](t, "array", limits.Schema["type"])
+	assert.Equal[any](t, "integer", limits.Schema["items"].(map[string]any)["type"])
+}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+
+	type Price struct {
+		Units, Nanos int64
+	}
+	simbaOpenapi.RegisterDecimal(Price{})
+
+	type Params struct {
+		Min Price  `query:"min" example:"9.99"`
+		Max *Price `query:"max"`
+	}
+
+	type Body struct {
+		Price Price `json:"price"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/test",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  Body{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	parameters := doc.Paths.MapOfPathItemValues["/test"].Post.Parameters
+	assert.Len(t, parameters, 2)
+	for _, parameter := range parameters {
+		assert.Equal[any](t, "#/components/schemas/SimbaOpenapiTestPrice", parameter.Parameter.Schema["$ref"])
+		assert.Nil(t, parameter.Parameter.Style)
+	}
+
+	price := doc.Components.Schemas["SimbaOpenapiTestPrice"]
+	assert.Equal[any](t, "string", price["type"])
+	assert.Equal[any](t, "decimal", price["format"])
+
+	property := doc.Components.Schemas["SimbaOpenapiTestBody"]["properties"].(map[string]any)["price"].(map[string]any)
+	assert.Equal[any](t, "#/components/schemas/SimbaOpenapiTestPrice", property["$ref"])
+}
+
+func TestAliasRoutes(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	route := openapiModels.RouteInfo{
+		Method:   http.MethodGet,
+		Path:     "/users/{id}",
+		Accepts:  mimetypes.ApplicationJSON,
+		Produces: mimetypes.ApplicationJSON,
+		Handler:  simbaTest.NoTagsHandler,
+		ReqBody:  models.NoBody{},
+		RespBody: simbaTest.ResponseBody{},
+		Params:   simbaTest.Params{},
+	}
+	alias := route
+	alias.Path = "/people/{id}"
+	alias.AliasOf = "GET /users/{id}"
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", []openapiModels.RouteInfo{route, alias})
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	canonical := doc.Paths.MapOfPathItemValues["/users/{id}"].Get
+	assert.Equal(t, "no-tags-handler", *canonical.ID)
+	_, ok := canonical.MapOfAnything["x-alias-of"]
+	assert.False(t, ok)
+
+	aliased := doc.Paths.MapOfPathItemValues["/people/{id}"].Get
+	assert.Equal(t, "no-tags-handler-get-people-id", *aliased.ID)
+	assert.Equal[any](t, "GET /users/{id}", aliased.MapOfAnything["x-alias-of"])
+	assert.True(t, strings.HasSuffix(*aliased.Description, "\n\nAlias of GET /users/{id}."))
+}
+
+func TestOperationExtensions(t *testing.T) {
+	t.Parallel()
+
+	route := openapiModels.RouteInfo{
+		Method:       http.MethodGet,
+		Path:         "/users/{id}",
+		Accepts:      mimetypes.ApplicationJSON,
+		Produces:     mimetypes.ApplicationJSON,
+		Handler:      simbaTest.NoTagsHandler,
+		ReqBody:      models.NoBody{},
+		RespBody:     simbaTest.ResponseBody{},
+		Params:       simbaTest.Params{},
+		Deprecated:   true,
+		DeprecatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:       time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		RateLimit:    &openapiModels.RateLimit{Requests: 100, Window: time.Minute},
+	}
+
+	t.Run("emits rate limit and deprecation extensions", func(t *testing.T) {
+		t.Parallel()
+
+		generator := simbaOpenapi.NewOpenAPIGenerator(simbaOpenapi.WithOperationExtensions(true))
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", []openapiModels.RouteInfo{route})
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		operation := doc.Paths.MapOfPathItemValues["/users/{id}"].Get
+		assert.Equal[any](t, map[string]any{"limit": float64(100), "windowSeconds": float64(60)}, operation.MapOfAnything["x-ratelimit"])
+		assert.Equal[any](t, map[string]any{"since": "2025-01-01T00:00:00Z", "sunset": "2025-06-30T00:00:00Z"}, operation.MapOfAnything["x-deprecated"])
+	})
+
+	t.Run("omits extensions by default", func(t *testing.T) {
+		t.Parallel()
+
+		generator := simbaOpenapi.NewOpenAPIGenerator()
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", []openapiModels.RouteInfo{route})
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		operation := doc.Paths.MapOfPathItemValues["/users/{id}"].Get
+		_, ok := operation.MapOfAnything["x-ratelimit"]
+		assert.False(t, ok)
+		_, ok = operation.MapOfAnything["x-deprecated"]
+		assert.False(t, ok)
+	})
+}
+
+func TestRegisteredParameters(t *testing.T) {
+	t.Parallel()
+
+	type PaginationParams struct {
+		Page int `query:"page" default:"1"`
+		Size int `query:"size" default:"10"`
+	}
+
+	type TenantParams struct {
+		Tenant string `header:"X-Tenant-ID"`
+	}
+
+	type ListParams struct {
+		PaginationParams
+		TenantParams
+		Name string `query:"name"`
+	}
+
+	simbaOpenapi.RegisterParameters("Pagination", PaginationParams{})
+	simbaOpenapi.RegisterParameters("Tenant", &TenantParams{})
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/users",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   ListParams{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/orders",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   PaginationParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	components := doc.Components.Parameters
+	assert.Len(t, components, 3)
+	assert.Equal(t, "page", components["PaginationPage"].Parameter.Name)
+	assert.Equal(t, openapi31.ParameterInQuery, components["PaginationSize"].Parameter.In)
+	assert.Equal(t, openapi31.ParameterInHeader, components["TenantXTenantId"].Parameter.In)
+
+	refs := func(parameters []openapi31.ParameterOrReference) []string {
+		var result []string
+		for _, parameter := range parameters {
+			if parameter.Reference != nil {
+				result = append(result, parameter.Reference.Ref)
+			} else {
+				result = append(result, parameter.Parameter.Name)
+			}
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{
+		"#/components/parameters/PaginationPage",
+		"#/components/parameters/PaginationSize",
+		"name",
+		"#/components/parameters/TenantXTenantId",
+	}, refs(doc.Paths.MapOfPathItemValues["/users"].Get.Parameters))
+	assert.Equal(t, []string{
+		"#/components/parameters/PaginationPage",
+		"#/components/parameters/PaginationSize",
+	}, refs(doc.Paths.MapOfPathItemValues["/orders"].Get.Parameters))
+}
+
+func TestDiscriminatedRequestBody(t *testing.T) {
+	t.Parallel()
+
+	type Created struct {
+		Event string `json:"event"`
+		Name  string `json:"name"`
+	}
+
+	type Deleted struct {
+		Event string `json:"event"`
+		ID    int    `json:"id"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/events",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody: openapiModels.DiscriminatedBody{
+				Discriminator: "event",
+				Variants:      map[string]any{"deleted": &Deleted{}, "created": Created{}},
+			},
+			RespBody: models.NoBody{},
+			Params:   models.NoParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	content := doc.Paths.MapOfPathItemValues["/events"].Post.RequestBody.RequestBody.Content[mimetypes.ApplicationJSON]
+	assert.Equal[any](t, []any{
+		map[string]any{"$ref": "#/components/schemas/SimbaOpenapiTestCreated"},
+		map[string]any{"$ref": "#/components/schemas/SimbaOpenapiTestDeleted"},
+	}, content.Schema["oneOf"])
+	assert.Equal[any](t, map[string]any{
+		"propertyName": "event",
+		"mapping": map[string]any{
+			"created": "#/components/schemas/SimbaOpenapiTestCreated",
+			"deleted": "#/components/schemas/SimbaOpenapiTestDeleted",
+		},
+	}, content.Schema["discriminator"])
+	assert.Len(t, doc.Components.Schemas["SimbaOpenapiTestCreated"]["properties"].(map[string]any), 2)
+}
+
+func TestFileResponse(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/files/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationOctetStream,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: models.File{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	responses := doc.Paths.MapOfPathItemValues["/files/{id}"].Get.Responses.MapOfResponseOrReferenceValues
+	content := responses["201"].Response.Content[mimetypes.ApplicationOctetStream]
+	assert.Equal(t, "string", content.Schema["type"])
+	assert.Equal(t, "binary", content.Schema["format"])
+}
+
+func TestMultipartResponse(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/reports/{id}",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.MultipartMixed,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: models.Multipart{},
+			Params:   simbaTest.Params{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	responses := doc.Paths.MapOfPathItemValues["/reports/{id}"].Get.Responses.MapOfResponseOrReferenceValues
+	content := responses["201"].Response.Content[mimetypes.MultipartMixed]
+	assert.Equal(t, "string", content.Schema["type"])
+	assert.Equal(t, "binary", content.Schema["format"])
+}
+
+func TestVersionedBody(t *testing.T) {
+	t.Parallel()
+
+	type userV1 struct {
+		Name string `json:"name"`
+	}
+	type userV2 struct {
+		FirstName string `json:"firstName"`
+	}
+
+	versionedRoute := func(defaultVersion string) []openapiModels.RouteInfo {
+		return []openapiModels.RouteInfo{
+			{
+				Method:   http.MethodPost,
+				Path:     "/users",
+				Accepts:  mimetypes.ApplicationJSON,
+				Produces: mimetypes.ApplicationJSON,
+				Handler:  simbaTest.NoTagsHandler,
+				ReqBody: openapiModels.VersionedBody{
+					Header:   "Api-Version",
+					Default:  defaultVersion,
+					Versions: map[string]any{"1": userV1{}, "2": userV2{}},
+				},
+				RespBody: openapiModels.VersionedBody{
+					Header:   "Api-Version",
+					Default:  defaultVersion,
+					Versions: map[string]any{"1": simbaTest.ResponseBody{}, "2": simbaTest.ResponseBody{}},
+				},
+				Params: models.NoParams{},
+			},
+		}
+	}
+
+	t.Run("documents versions as oneOf and the version header", func(t *testing.T) {
+		t.Parallel()
+
+		generator := simbaOpenapi.NewOpenAPIGenerator()
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", versionedRoute("2"))
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		operation := doc.Paths.MapOfPathItemValues["/users"].Post
+		oneOf := operation.RequestBody.RequestBody.Content[mimetypes.ApplicationJSON].Schema["oneOf"].([]any)
+		assert.Len(t, oneOf, 2)
+
+		// Versions sharing a body type are documented with that type
+		response := operation.Responses.MapOfResponseOrReferenceValues["201"].Response.Content[mimetypes.ApplicationJSON]
+		assert.Contains(t, "ResponseBody", response.Schema["$ref"].(string))
+
+		assert.Len(t, operation.Parameters, 1)
+		header := operation.Parameters[0].Parameter
+		assert.Equal(t, "Api-Version", header.Name)
+		assert.Equal(t, openapi31.ParameterInHeader, header.In)
+		assert.Equal[any](t, "2", header.Schema["default"])
+		assert.Equal[any](t, []any{"1", "2"}, header.Schema["enum"])
+	})
+
+	t.Run("requires the version header without a default", func(t *testing.T) {
+		t.Parallel()
+
+		generator := simbaOpenapi.NewOpenAPIGenerator()
+		schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", versionedRoute(""))
+		assert.NoError(t, err)
+		doc := unmarshalJSON(t, schema)
+
+		header := doc.Paths.MapOfPathItemValues["/users"].Post.Parameters[0].Parameter
+		assert.NotNil(t, header.Required)
+		assert.True(t, *header.Required)
+	})
+}
+
+func TestValidateConditionalRequired(t *testing.T) {
+	t.Parallel()
+
+	type reqBody struct {
+		Street  string `json:"street"`
+		City    string `json:"city" validate:"required_with=Street"`
+		Type    string `json:"type"`
+		Company string `json:"company" validate:"required_if=Type business"`
+		Name    string `json:"name" validate:"required"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[reqBody, models.NoParams]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodPost,
+			Path:     "/test",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  handler,
+			ReqBody:  reqBody{},
+			RespBody: models.NoBody{},
+			Params:   models.NoParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	bodySchema := doc.Components.Schemas["SimbaOpenapiTestReqBody"]
+
+	// Conditionally required fields are not unconditionally required
+	assert.Equal[any](t, []any{"name"}, bodySchema["required"])
+
+	assert.Equal[any](t, map[string]any{"street": []any{"city"}}, bodySchema["dependentRequired"])
+	assert.Equal[any](t, []any{
+		map[string]any{
+			"if": map[string]any{
+				"properties": map[string]any{"type": map[string]any{"const": "business"}},
+				"required":   []any{"type"},
+			},
+			"then": map[string]any{"required": []any{"company"}},
+		},
+	}, bodySchema["allOf"])
+}
+
+func TestTagsMetadata(t *testing.T) {
+	t.Parallel()
+
+	generator := simbaOpenapi.NewOpenAPIGenerator(
+		simbaOpenapi.WithTags(
+			openapiModels.Tag{Name: "Users", Description: "Manage users"},
+			openapiModels.Tag{
+				Name: "Orders",
+				ExternalDocs: &openapiModels.ExternalDocs{
+					URL:         "https://example.com/docs/orders",
+					Description: "Order guide",
+				},
+			},
+		),
+		simbaOpenapi.WithTags(openapiModels.Tag{Name: "Users", Description: "User accounts"}),
+	)
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/users",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   models.NoParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	assert.Len(t, doc.Tags, 2)
+	assert.Equal(t, "Users", doc.Tags[0].Name)
+	assert.Equal(t, "User accounts", *doc.Tags[0].Description)
+	assert.Nil(t, doc.Tags[0].ExternalDocs)
+	assert.Equal(t, "Orders", doc.Tags[1].Name)
+	assert.Nil(t, doc.Tags[1].Description)
+	assert.Equal(t, "https://example.com/docs/orders", doc.Tags[1].ExternalDocs.URL)
+	assert.Equal(t, "Order guide", *doc.Tags[1].ExternalDocs.Description)
+}
+
+func TestResponseEnvelope(t *testing.T) {
+	t.Parallel()
+
+	type envelope struct {
+		Data      any    `json:"data"`
+		Timestamp string `json:"timestamp" format:"date-time"`
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator(simbaOpenapi.WithResponseEnvelope(envelope{}, "data"))
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/users",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  simbaTest.NoTagsHandler,
+			ReqBody:  models.NoBody{},
+			RespBody: simbaTest.ResponseBody{},
+			Params:   models.NoParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	content := doc.Paths.MapOfPathItemValues["/users"].Get.Responses.MapOfResponseOrReferenceValues["201"].Response.Content[mimetypes.ApplicationJSON]
+	properties := content.Schema["properties"].(map[string]any)
+	assert.Equal[any](t, "#/components/schemas/SimbaTestResponseBody", properties["data"].(map[string]any)["$ref"])
+	assert.Equal[any](t, "date-time", properties["timestamp"].(map[string]any)["format"])
+
+	errorContent := doc.Paths.MapOfPathItemValues["/users"].Get.Responses.MapOfResponseOrReferenceValues["500"].Response.Content[mimetypes.ApplicationJSON]
+	_, hasProperties := errorContent.Schema["properties"]
+	assert.False(t, hasProperties)
+}
+
+func TestVisibilityField(t *testing.T) {
+	t.Parallel()
+
+	type respBody struct {
+		ID    int    `json:"id"`
+		Email string `json:"email" description:"Email of the user" visibility:"admin, support"`
+	}
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[respBody], error) {
+		return &models.Response[respBody]{}, nil
+	}
+
+	generator := simbaOpenapi.NewOpenAPIGenerator()
+	routeInfo := []openapiModels.RouteInfo{
+		{
+			Method:   http.MethodGet,
+			Path:     "/test",
+			Accepts:  mimetypes.ApplicationJSON,
+			Produces: mimetypes.ApplicationJSON,
+			Handler:  handler,
+			ReqBody:  models.NoBody{},
+			RespBody: respBody{},
+			Params:   models.NoParams{},
+		},
+	}
+
+	schema, err := generator.GenerateDocumentation(context.Background(), "Test", "1.0.0", routeInfo)
+	assert.NoError(t, err)
+	doc := unmarshalJSON(t, schema)
+
+	properties := doc.Components.Schemas["SimbaOpenapiTestRespBody"]["properties"].(map[string]any)
+	email := properties["email"].(map[string]any)
+	assert.Equal[any](t, []any{"admin", "support"}, email["x-visibility"])
+	assert.Equal[any](t, "Email of the user Only visible to roles: admin, support.", email["description"])
+
+	_, hasVisibility := properties["id"].(map[string]any)["x-visibility"]
+	assert.False(t, hasVisibility)
+}
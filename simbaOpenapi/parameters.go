@@ -0,0 +1,133 @@
+package simbaOpenapi
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/iancoleman/strcase"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+var (
+	parameterSetsMu sync.RWMutex
+	parameterSets   = map[reflect.Type]string{}
+)
+
+// RegisterParameters registers a params struct as a reusable parameter set, documented once under
+// components.parameters. Operations with params that are or embed the struct reference its parameters
+// instead of repeating them. The parameters are named by the set name followed by the parameter name,
+// e.g. PaginationPage for the page query parameter of the Pagination set. Registering a struct again
+// replaces its name.
+//
+//	Example usage:
+//
+//	type PaginationParams struct {
+//		Page int `query:"page" default:"1"`
+//		Size int `query:"size" default:"10"`
+//	}
+//
+//	simbaOpenapi.RegisterParameters("Pagination", PaginationParams{})
+//
+//	type ListUsersParams struct {
+//		PaginationParams
+//		Name string `query:"name"`
+//	}
+func RegisterParameters(name string, params any) {
+	t := reflect.TypeOf(params)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	parameterSetsMu.Lock()
+	defer parameterSetsMu.Unlock()
+	parameterSets[t] = name
+}
+
+// parameterReferences returns the component names of the parameters of registered parameter sets
+// in the params, keyed by parameter location and name.
+func parameterReferences(params any) map[string]string {
+	t := reflect.TypeOf(params)
+	if t == nil {
+		return nil
+	}
+
+	parameterSetsMu.RLock()
+	defer parameterSetsMu.RUnlock()
+	if len(parameterSets) == 0 {
+		return nil
+	}
+
+	references := map[string]string{}
+	collectParameterReferences(t, "", references)
+	return references
+}
+
+// collectParameterReferences adds the parameters of the struct to the references if it belongs to a
+// parameter set, descending into embedded structs to find sets.
+func collectParameterReferences(t reflect.Type, set string, references map[string]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	if name, ok := parameterSets[t]; ok && set == "" {
+		set = name
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous {
+			collectParameterReferences(field.Type, set, references)
+			continue
+		}
+		if set == "" || !field.IsExported() {
+			continue
+		}
+		for _, in := range []string{"path", "query", "header", "cookie"} {
+			if name := field.Tag.Get(in); name != "" {
+				references[parameterKey(in, name)] = set + strcase.ToCamel(name)
+				break
+			}
+		}
+	}
+}
+
+// parameterKey returns the key of a parameter by location and name. Header names are documented in
+// canonical form, so they're matched case-insensitively.
+func parameterKey(in string, name string) string {
+	if in == "header" {
+		name = http.CanonicalHeaderKey(name)
+	}
+	return in + ":" + name
+}
+
+// referenceParameters moves the parameters of parameter sets in the operation to the components,
+// replacing them with references. A parameter already in the components is not replaced.
+func referenceParameters(reflector *openapi31.Reflector, operation *openapi31.Operation, references map[string]string) {
+	if len(references) == 0 {
+		return
+	}
+
+	for i, param := range operation.Parameters {
+		if param.Parameter == nil {
+			continue
+		}
+		name, ok := references[parameterKey(string(param.Parameter.In), param.Parameter.Name)]
+		if !ok {
+			continue
+		}
+
+		components := reflector.SpecEns().ComponentsEns()
+		if _, exists := components.Parameters[name]; !exists {
+			components.WithParametersItem(name, param)
+		}
+		operation.Parameters[i] = openapi31.ParameterOrReference{
+			Reference: &openapi31.Reference{Ref: "#/components/parameters/" + name},
+		}
+	}
+}
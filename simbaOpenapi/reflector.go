@@ -1,27 +1,38 @@
 package simbaOpenapi
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi31"
+
+	"github.com/sillen102/simba/models"
 )
 
 const MIN = "min"
 const MAX = "max"
 
+var timeType = reflect.TypeFor[time.Time]()
+var durationType = reflect.TypeFor[time.Duration]()
+
 // GetReflector creates a new OpenAPI reflector with custom options.
 func GetReflector() (*openapi31.Reflector, error) {
 	r := openapi31.NewReflector()
+	// Inline durations, so parameters can be documented as strings while bodies keep the integer schema
+	r.JSONSchemaReflector().InlineDefinition(time.Duration(0))
 	r.DefaultOptions = append(r.DefaultOptions, jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
 		if !params.Processed {
 			return nil
 		}
 
 		if v, ok := params.Field.Tag.Lookup("validate"); ok {
-			if strings.Contains(v, "required") {
+			if hasValidateRule(v, "required") {
 				setIsRequired(params)
 			}
 
@@ -40,11 +51,312 @@ func GetReflector() (*openapi31.Reflector, error) {
 			}
 		}
 
+		if v, ok := params.Field.Tag.Lookup("visibility"); ok {
+			setVisibility(params, v)
+		}
+
 		return nil
 	}))
+	r.DefaultOptions = append(r.DefaultOptions, jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (bool, error) {
+		if !params.Processed {
+			return documentDurationParam(params) || documentDecimal(params), nil
+		}
+		return false, setConditionalRequired(params)
+	}))
 	return r, nil
 }
 
+// documentDurationParam documents time.Duration parameters as strings in the duration format,
+// such as 1h30m, as they are parsed with time.ParseDuration. Durations in bodies are encoded
+// as integer nanoseconds by encoding/json and keep the default schema.
+func documentDurationParam(params jsonschema.InterceptSchemaParams) bool {
+	if !params.Value.IsValid() {
+		return false
+	}
+	t := params.Value.Type()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != durationType {
+		return false
+	}
+
+	oc, ok := openapi.OperationCtx(params.Context)
+	if !ok {
+		return false
+	}
+	switch oc.ProcessingIn() {
+	case openapi.InPath, openapi.InQuery, openapi.InHeader, openapi.InCookie:
+	default:
+		return false
+	}
+
+	nullable := params.Schema.HasType(jsonschema.Null)
+	params.Schema.WithType(jsonschema.String.Type()).WithFormat("duration")
+	if nullable {
+		params.Schema.AddType(jsonschema.Null)
+	}
+	return true
+}
+
+// setVisibility documents the roles that may see a field restricted with a visibility tag.
+// The roles are listed in the x-visibility extension and appended to the field description,
+// callers without any of the roles receive the response without the field.
+func setVisibility(params jsonschema.InterceptPropParams, v string) {
+	var roles []string
+	for _, role := range strings.Split(v, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	if params.PropertySchema.ExtraProperties == nil {
+		params.PropertySchema.ExtraProperties = map[string]interface{}{}
+	}
+	params.PropertySchema.ExtraProperties["x-visibility"] = roles
+
+	note := "Only visible to roles: " + strings.Join(roles, ", ") + "."
+	if params.PropertySchema.Description != nil && *params.PropertySchema.Description != "" {
+		note = *params.PropertySchema.Description + " " + note
+	}
+	params.PropertySchema.WithDescription(note)
+}
+
+// documentTimeFormat documents time.Time fields in responses as written in the time format, converting
+// RFC 3339 examples to the format. Times in requests are still parsed as RFC 3339 and documented as
+// date-time strings.
+func documentTimeFormat(format models.TimeFormat) func(*jsonschema.ReflectContext) {
+	return jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if !params.Processed {
+			return nil
+		}
+		if oc, ok := openapi.OperationCtx(params.Context); !ok || !oc.IsProcessingResponse() {
+			return nil
+		}
+
+		setTimeFormat(params.PropertySchema, params.Field.Type, format)
+		return nil
+	})
+}
+
+// setTimeFormat updates the schema of times held by the type directly or through slices, arrays and maps.
+func setTimeFormat(schema *jsonschema.Schema, t reflect.Type, format models.TimeFormat) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		setTimeSchema(schema, format)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if schema.Items != nil && schema.Items.SchemaOrBool != nil && schema.Items.SchemaOrBool.TypeObject != nil {
+			setTimeFormat(schema.Items.SchemaOrBool.TypeObject, t.Elem(), format)
+		}
+	case t.Kind() == reflect.Map:
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.TypeObject != nil {
+			setTimeFormat(schema.AdditionalProperties.TypeObject, t.Elem(), format)
+		}
+	}
+}
+
+// setTimeSchema replaces the date-time string schema of a time with the time format.
+func setTimeSchema(schema *jsonschema.Schema, format models.TimeFormat) {
+	simpleType, description := jsonschema.String, "Time formatted with the Go layout "+format.String()+"."
+	switch format {
+	case models.UnixTimeFormat:
+		simpleType, description = jsonschema.Integer, "Unix time in seconds."
+	case models.UnixMilliTimeFormat:
+		simpleType, description = jsonschema.Integer, "Unix time in milliseconds."
+	}
+
+	if schema.Type != nil {
+		if schema.Type.SimpleTypes != nil && *schema.Type.SimpleTypes == jsonschema.String {
+			schema.Type.SimpleTypes = &simpleType
+		}
+		for i, st := range schema.Type.SliceOfSimpleTypeValues {
+			if st == jsonschema.String {
+				schema.Type.SliceOfSimpleTypeValues[i] = simpleType
+			}
+		}
+	}
+	schema.Format = nil
+	if schema.Description == nil {
+		schema.WithDescription(description)
+	}
+
+	for i, example := range schema.Examples {
+		s, ok := example.(string)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			continue
+		}
+		encoded, err := format.FormatJSON(t)
+		if err != nil {
+			continue
+		}
+		var converted any
+		if err = json.Unmarshal(encoded, &converted); err == nil {
+			schema.Examples[i] = converted
+		}
+	}
+}
+
+// hasValidateRule reports whether a validate tag contains the named rule, e.g. "required"
+// in "required,min=1" but not in "required_with=Name".
+func hasValidateRule(v string, rule string) bool {
+	for _, r := range strings.Split(v, ",") {
+		if name, _, _ := strings.Cut(r, "="); name == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// setConditionalRequired documents the required_with and required_if rules of a struct's fields.
+// A field with required_with=A B is required whenever A or B is present, which is expressed with
+// dependentRequired. A field with required_if=A value is required when A equals value, which is
+// expressed with an if/then subschema. Other conditional rules can't be expressed and are ignored.
+func setConditionalRequired(params jsonschema.InterceptSchemaParams) error {
+	if !params.Value.IsValid() {
+		return nil
+	}
+
+	t := params.Value.Type()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || params.Schema.Properties == nil {
+		return nil
+	}
+
+	dependentRequired := map[string][]string{}
+	var conditions []jsonschema.SchemaOrBool
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		v, ok := field.Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+		name, ok := propertyName(field)
+		if !ok {
+			continue
+		}
+
+		for _, rule := range strings.Split(v, ",") {
+			ruleName, ruleParam, _ := strings.Cut(rule, "=")
+			switch ruleName {
+			case "required_with":
+				for _, other := range strings.Fields(ruleParam) {
+					if otherName, ok := structPropertyName(t, other); ok {
+						dependentRequired[otherName] = append(dependentRequired[otherName], name)
+					}
+				}
+			case "required_if":
+				condition, err := requiredIfCondition(t, name, strings.Fields(ruleParam))
+				if err != nil {
+					return err
+				}
+				if condition != nil {
+					conditions = append(conditions, condition.ToSchemaOrBool())
+				}
+			}
+		}
+	}
+
+	if len(dependentRequired) > 0 {
+		if params.Schema.ExtraProperties == nil {
+			params.Schema.ExtraProperties = map[string]interface{}{}
+		}
+		params.Schema.ExtraProperties["dependentRequired"] = dependentRequired
+	}
+	params.Schema.AllOf = append(params.Schema.AllOf, conditions...)
+
+	return nil
+}
+
+// requiredIfCondition builds an if/then subschema requiring name when all the field/value
+// pairs of a required_if rule match. Returns nil if a referenced field is not a property.
+func requiredIfCondition(t reflect.Type, name string, pairs []string) (*jsonschema.Schema, error) {
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("invalid required_if rule for %s", name)
+	}
+
+	condition := &jsonschema.Schema{}
+	for i := 0; i < len(pairs); i += 2 {
+		field, ok := t.FieldByName(pairs[i])
+		if !ok {
+			return nil, nil
+		}
+		otherName, ok := propertyName(field)
+		if !ok {
+			return nil, nil
+		}
+
+		value, err := constValue(field.Type, pairs[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid required_if value for %s: %w", name, err)
+		}
+
+		condition.WithPropertiesItem(otherName, (&jsonschema.Schema{}).WithConst(value).ToSchemaOrBool())
+		condition.Required = append(condition.Required, otherName)
+	}
+
+	then := &jsonschema.Schema{}
+	then.Required = []string{name}
+
+	return (&jsonschema.Schema{}).WithIf(condition.ToSchemaOrBool()).WithThen(then.ToSchemaOrBool()), nil
+}
+
+// constValue converts a validate tag value to the JSON type of the field it is compared with.
+func constValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// structPropertyName returns the JSON property name of the named field of a struct.
+func structPropertyName(t reflect.Type, fieldName string) (string, bool) {
+	field, ok := t.FieldByName(fieldName)
+	if !ok {
+		return "", false
+	}
+	return propertyName(field)
+}
+
+// propertyName returns the JSON property name of a struct field.
+// Returns false for fields that are not serialized.
+func propertyName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
+
 func setIsRequired(params jsonschema.InterceptPropParams) {
 	params.ParentSchema.Required = append(params.ParentSchema.Required, params.Name)
 }
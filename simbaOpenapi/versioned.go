@@ -0,0 +1,81 @@
+package simbaOpenapi
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+)
+
+// resolveVersionedBodies replaces the versioned request and response bodies of a route with the bodies
+// documenting their versions, and returns the params documenting the version header, or nil if the route
+// isn't versioned.
+func resolveVersionedBodies(routeInfo *openapiModels.RouteInfo) (*openapiModels.RouteInfo, any) {
+	reqBody, reqVersioned := routeInfo.ReqBody.(openapiModels.VersionedBody)
+	respBody, respVersioned := routeInfo.RespBody.(openapiModels.VersionedBody)
+	if !reqVersioned && !respVersioned {
+		return routeInfo, nil
+	}
+
+	resolved := *routeInfo
+	var versioned openapiModels.VersionedBody
+	if reqVersioned {
+		resolved.ReqBody = newVersionedBody(reqBody)
+		versioned = reqBody
+	}
+	if respVersioned {
+		resolved.RespBody = newVersionedBody(respBody)
+		versioned = respBody
+	}
+	return &resolved, newVersionHeaderParams(versioned)
+}
+
+// newVersionedBody returns the body documenting the versions: the body type shared by all the versions,
+// or oneOf the distinct body types. Versions without a body are left out.
+func newVersionedBody(body openapiModels.VersionedBody) any {
+	var variants oneOfBody
+	seen := map[reflect.Type]bool{}
+	for _, version := range slices.Sorted(maps.Keys(body.Versions)) {
+		variant := body.Versions[version]
+		switch variant.(type) {
+		case nil, models.NoBody, *models.NoBody:
+			continue
+		}
+		if t := reflect.TypeOf(variant); !seen[t] {
+			seen[t] = true
+			variants = append(variants, variant)
+		}
+	}
+
+	switch len(variants) {
+	case 0:
+		return models.NoBody{}
+	case 1:
+		return variants[0]
+	default:
+		return variants
+	}
+}
+
+// newVersionHeaderParams returns params documenting the header selecting the version, with the versions
+// as its allowed values. The header is required if there is no default version.
+func newVersionHeaderParams(body openapiModels.VersionedBody) any {
+	versions := slices.Sorted(maps.Keys(body.Versions))
+	tag := fmt.Sprintf(`header:%q enum:%q description:%q`, body.Header, strings.Join(versions, ","), "Schema version of the request and response bodies")
+	if body.Default == "" {
+		tag += ` required:"true"`
+	} else {
+		tag += fmt.Sprintf(` default:%q`, body.Default)
+	}
+
+	paramsType := reflect.StructOf([]reflect.StructField{{
+		Name: "Version",
+		Type: reflect.TypeFor[string](),
+		Tag:  reflect.StructTag(tag),
+	}})
+	return reflect.New(paramsType).Elem().Interface()
+}
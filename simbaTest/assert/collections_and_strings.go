@@ -73,6 +73,16 @@ func ContainsOnlyInAnyOrder[T any](t interface {
 	return true
 }
 
+// ElementsMatch verifies that two slices contain exactly the same elements, ignoring their order.
+// It is an alias for [ContainsOnlyInAnyOrder].
+func ElementsMatch[T any](t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, expected, actual []T, msgAndArgs ...any) bool {
+	t.Helper()
+	return ContainsOnlyInAnyOrder(t, expected, actual, msgAndArgs...)
+}
+
 // Contains checks if an item is present in a collection, or if a collection contains another collection.
 func Contains(t interface {
 	Errorf(format string, args ...any)
@@ -447,6 +457,30 @@ func NotEmpty(t interface {
 	return false
 }
 
+// Len verifies that the collection (slice, array, map, string or channel) has the expected length.
+// If not, it formats an error message and reports it through the test interface.
+func Len(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, collection any, length int, msgAndArgs ...any) bool {
+	t.Helper()
+
+	v := reflect.ValueOf(collection)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String, reflect.Chan:
+		if v.Len() == length {
+			return true
+		}
+		message := formatSliceFailureMessage(fmt.Sprintf("Expected length %d, got %d", length, v.Len()), length, collection, msgAndArgs...)
+		t.Errorf("%s", message)
+		return false
+	default:
+		message := formatSliceFailureMessage(fmt.Sprintf("Cannot get length of %T", collection), length, collection, msgAndArgs...)
+		t.Errorf("%s", message)
+		return false
+	}
+}
+
 // formatSliceFailureMessage creates a descriptive failure message for unequal slices.
 func formatSliceFailureMessage(reason string, expected, actual any, msgAndArgs ...any) string {
 	var msg string
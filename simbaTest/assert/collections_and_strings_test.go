@@ -739,3 +739,81 @@ func TestNotEmpty(t *testing.T) {
 		}
 	})
 }
+
+func TestLen(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan int, 2)
+	ch <- 1
+
+	testCases := []struct {
+		name       string
+		value      any
+		length     int
+		message    string
+		shouldPass bool
+	}{
+		// Passing cases
+		{"slice", []int{1, 2, 3}, 3, "", true},
+		{"array", [2]string{"a", "b"}, 2, "", true},
+		{"map", map[string]int{"a": 1}, 1, "", true},
+		{"string", "hello", 5, "", true},
+		{"channel", ch, 1, "", true},
+		{"nil slice", []int(nil), 0, "", true},
+
+		// Failing cases
+		{"wrong slice length", []int{1, 2}, 3, "expected %d errors", false},
+		{"wrong map length", map[string]int{}, 1, "", false},
+		{"nil value", nil, 0, "", false},
+		{"non-collection", 42, 0, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &mockT{}
+			var result bool
+			if tc.message != "" {
+				result = assert.Len(mock, tc.value, tc.length, tc.message, tc.length)
+			} else {
+				result = assert.Len(mock, tc.value, tc.length)
+			}
+
+			if result != tc.shouldPass {
+				t.Errorf("Len(%v, %d) returned %v, expected %v", tc.value, tc.length, result, tc.shouldPass)
+			}
+			if mock.failed == tc.shouldPass {
+				t.Errorf("mockT.failed = %v, expected %v", mock.failed, !tc.shouldPass)
+			}
+		})
+	}
+}
+
+func TestElementsMatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		expected   []string
+		actual     []string
+		shouldPass bool
+	}{
+		{"same order", []string{"a", "b"}, []string{"a", "b"}, true},
+		{"different order", []string{"a", "b", "a"}, []string{"a", "a", "b"}, true},
+		{"different elements", []string{"a", "b"}, []string{"a", "c"}, false},
+		{"different lengths", []string{"a"}, []string{"a", "a"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &mockT{}
+			result := assert.ElementsMatch(mock, tc.expected, tc.actual)
+
+			if result != tc.shouldPass {
+				t.Errorf("ElementsMatch(%v, %v) returned %v, expected %v", tc.expected, tc.actual, result, tc.shouldPass)
+			}
+			if mock.failed == tc.shouldPass {
+				t.Errorf("mockT.failed = %v, expected %v", mock.failed, !tc.shouldPass)
+			}
+		})
+	}
+}
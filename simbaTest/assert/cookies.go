@@ -0,0 +1,150 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+)
+
+// CookiePresent verifies that the response sets the cookie.
+// If not, it formats an error message and reports it through the test interface.
+func CookiePresent(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, w *httptest.ResponseRecorder, name string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if _, ok := findCookie(w, name); !ok {
+		t.Errorf("%s", formatCookieFailureMessage(name, "Expected cookie to be set", msgAndArgs...))
+		return false
+	}
+
+	return true
+}
+
+// CookieEquals verifies that the response sets the cookie with the value.
+// If not, it formats an error message and reports it through the test interface.
+func CookieEquals(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, w *httptest.ResponseRecorder, name string, value string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	cookie, ok := findCookie(w, name)
+	if !ok {
+		t.Errorf("%s", formatCookieFailureMessage(name, "Expected cookie to be set", msgAndArgs...))
+		return false
+	}
+
+	if cookie.Value != value {
+		reason := fmt.Sprintf("Expected value: %q\nActual value:   %q", value, cookie.Value)
+		t.Errorf("%s", formatCookieFailureMessage(name, reason, msgAndArgs...))
+		return false
+	}
+
+	return true
+}
+
+// CookieHttpOnly verifies that the response sets the cookie with the HttpOnly attribute.
+// If not, it formats an error message and reports it through the test interface.
+func CookieHttpOnly(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, w *httptest.ResponseRecorder, name string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	return cookieAttribute(t, w, name, "HttpOnly", func(cookie *http.Cookie) bool { return cookie.HttpOnly }, msgAndArgs...)
+}
+
+// CookieSecure verifies that the response sets the cookie with the Secure attribute.
+// If not, it formats an error message and reports it through the test interface.
+func CookieSecure(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, w *httptest.ResponseRecorder, name string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	return cookieAttribute(t, w, name, "Secure", func(cookie *http.Cookie) bool { return cookie.Secure }, msgAndArgs...)
+}
+
+// CookieSameSite verifies that the response sets the cookie with the SameSite attribute.
+// If not, it formats an error message and reports it through the test interface.
+func CookieSameSite(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, w *httptest.ResponseRecorder, name string, sameSite http.SameSite, msgAndArgs ...any) bool {
+	t.Helper()
+
+	cookie, ok := findCookie(w, name)
+	if !ok {
+		t.Errorf("%s", formatCookieFailureMessage(name, "Expected cookie to be set", msgAndArgs...))
+		return false
+	}
+
+	if cookie.SameSite != sameSite {
+		reason := fmt.Sprintf("Expected SameSite: %s\nActual SameSite:   %s", sameSiteName(sameSite), sameSiteName(cookie.SameSite))
+		t.Errorf("%s", formatCookieFailureMessage(name, reason, msgAndArgs...))
+		return false
+	}
+
+	return true
+}
+
+// cookieAttribute verifies that the response sets the cookie with a boolean attribute.
+func cookieAttribute(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, w *httptest.ResponseRecorder, name string, attribute string, isSet func(*http.Cookie) bool, msgAndArgs ...any) bool {
+	t.Helper()
+
+	cookie, ok := findCookie(w, name)
+	if !ok {
+		t.Errorf("%s", formatCookieFailureMessage(name, "Expected cookie to be set", msgAndArgs...))
+		return false
+	}
+
+	if !isSet(cookie) {
+		t.Errorf("%s", formatCookieFailureMessage(name, fmt.Sprintf("Expected cookie to be %s", attribute), msgAndArgs...))
+		return false
+	}
+
+	return true
+}
+
+// findCookie returns the cookie set by the response with the name. If it's set several times,
+// the last one is returned since it's the one kept by clients.
+func findCookie(w *httptest.ResponseRecorder, name string) (*http.Cookie, bool) {
+	var found *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			found = cookie
+		}
+	}
+	return found, found != nil
+}
+
+// sameSiteName returns the name of the SameSite mode as written in the Set-Cookie header.
+func sameSiteName(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return "unset"
+	}
+}
+
+// formatCookieFailureMessage creates a descriptive failure message for a cookie assertion.
+func formatCookieFailureMessage(name string, reason string, msgAndArgs ...any) string {
+	var msg string
+	if len(msgAndArgs) > 0 {
+		if msgFormat, ok := msgAndArgs[0].(string); ok {
+			msg = formatMessage(msgFormat, msgAndArgs[1:]...) + "\n"
+		}
+	}
+
+	return fmt.Sprintf("%sCookie %q: %s", msg, name, reason)
+}
@@ -0,0 +1,51 @@
+package assert_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestCookies(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "old"})
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", HttpOnly: true, Secure: true, SameSite: http.SameSiteStrictMode})
+	http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark"})
+
+	testCases := []struct {
+		name       string
+		assert     func(t *mockT) bool
+		shouldPass bool
+	}{
+		{"present", func(t *mockT) bool { return assert.CookiePresent(t, w, "session") }, true},
+		{"not present", func(t *mockT) bool { return assert.CookiePresent(t, w, "csrf", "cookie should be %s", "set") }, false},
+		{"equal value of last cookie", func(t *mockT) bool { return assert.CookieEquals(t, w, "session", "abc123") }, true},
+		{"different value", func(t *mockT) bool { return assert.CookieEquals(t, w, "session", "old") }, false},
+		{"value of missing cookie", func(t *mockT) bool { return assert.CookieEquals(t, w, "csrf", "") }, false},
+		{"http only", func(t *mockT) bool { return assert.CookieHttpOnly(t, w, "session") }, true},
+		{"not http only", func(t *mockT) bool { return assert.CookieHttpOnly(t, w, "theme") }, false},
+		{"secure", func(t *mockT) bool { return assert.CookieSecure(t, w, "session") }, true},
+		{"not secure", func(t *mockT) bool { return assert.CookieSecure(t, w, "theme") }, false},
+		{"same site", func(t *mockT) bool { return assert.CookieSameSite(t, w, "session", http.SameSiteStrictMode) }, true},
+		{"different same site", func(t *mockT) bool { return assert.CookieSameSite(t, w, "theme", http.SameSiteLaxMode) }, false},
+		{"same site of missing cookie", func(t *mockT) bool { return assert.CookieSameSite(t, w, "csrf", http.SameSiteLaxMode) }, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &mockT{}
+			result := tc.assert(mock)
+
+			if result != tc.shouldPass {
+				t.Errorf("assertion returned %v, expected %v", result, tc.shouldPass)
+			}
+			if mock.failed == tc.shouldPass {
+				t.Errorf("mockT.failed = %v, expected %v", mock.failed, !tc.shouldPass)
+			}
+		})
+	}
+}
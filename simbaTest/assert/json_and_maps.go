@@ -0,0 +1,106 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONEq verifies that two JSON documents are structurally equal, ignoring key order and whitespace.
+// If not, it formats an error message and reports it through the test interface.
+func JSONEq(t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, expected string, actual []byte, msgAndArgs ...any) bool {
+	t.Helper()
+
+	var expectedValue, actualValue any
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Errorf("%s", formatJSONFailureMessage(fmt.Sprintf("Expected value is not valid JSON: %v", err), expected, string(actual), msgAndArgs...))
+		return false
+	}
+
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		t.Errorf("%s", formatJSONFailureMessage(fmt.Sprintf("Actual value is not valid JSON: %v", err), expected, string(actual), msgAndArgs...))
+		return false
+	}
+
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		t.Errorf("%s", formatJSONFailureMessage("JSON documents are not equal", expected, string(actual), msgAndArgs...))
+		return false
+	}
+
+	return true
+}
+
+// MapContainsSubset verifies that all keys in subset are present in full with equal values.
+// Nested maps are compared recursively so that they only need to contain the keys of the subset.
+// If not, it formats an error message and reports it through the test interface.
+func MapContainsSubset[K comparable, V any](t interface {
+	Errorf(format string, args ...any)
+	Helper()
+}, subset, full map[K]V, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if path, ok := containsSubset(reflect.ValueOf(subset), reflect.ValueOf(full), ""); !ok {
+		t.Errorf("%s", formatSliceFailureMessage(fmt.Sprintf("Map does not contain subset at key '%s'", path), subset, full, msgAndArgs...))
+		return false
+	}
+
+	return true
+}
+
+// containsSubset reports whether full contains subset. If not, it returns the path of the first mismatch.
+func containsSubset(subset, full reflect.Value, path string) (string, bool) {
+	subset = unwrapInterface(subset)
+	full = unwrapInterface(full)
+
+	if subset.Kind() != reflect.Map || full.Kind() != reflect.Map {
+		if !subset.IsValid() || !full.IsValid() {
+			return path, subset.IsValid() == full.IsValid()
+		}
+		return path, reflect.DeepEqual(subset.Interface(), full.Interface())
+	}
+
+	for _, key := range subset.MapKeys() {
+		keyPath := fmt.Sprintf("%v", key.Interface())
+		if path != "" {
+			keyPath = path + "." + keyPath
+		}
+
+		if !key.Type().AssignableTo(full.Type().Key()) {
+			return keyPath, false
+		}
+
+		fullValue := full.MapIndex(key)
+		if !fullValue.IsValid() {
+			return keyPath, false
+		}
+
+		if mismatch, ok := containsSubset(subset.MapIndex(key), fullValue, keyPath); !ok {
+			return mismatch, false
+		}
+	}
+
+	return "", true
+}
+
+// unwrapInterface returns the concrete value held by an interface value.
+func unwrapInterface(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v
+}
+
+// formatJSONFailureMessage creates a descriptive failure message for unequal JSON documents.
+func formatJSONFailureMessage(reason string, expected, actual string, msgAndArgs ...any) string {
+	var msg string
+	if len(msgAndArgs) > 0 {
+		if msgFormat, ok := msgAndArgs[0].(string); ok {
+			msg = formatMessage(msgFormat, msgAndArgs[1:]...) + "\n"
+		}
+	}
+
+	return fmt.Sprintf("%s%s\nExpected: %s\nActual:   %s", msg, reason, expected, actual)
+}
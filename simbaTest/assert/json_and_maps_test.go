@@ -0,0 +1,96 @@
+package assert_test
+
+import (
+	"testing"
+
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestJSONEq(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		expected   string
+		actual     string
+		message    string
+		shouldPass bool
+	}{
+		{"identical documents", `{"a":1,"b":"x"}`, `{"a":1,"b":"x"}`, "", true},
+		{"different key order and whitespace", `{"a":1,"b":[1,2]}`, "{\n  \"b\": [1, 2],\n  \"a\": 1\n}", "", true},
+		{"nested objects", `{"a":{"b":{"c":true}}}`, `{"a":{"b":{"c":true}}}`, "", true},
+		{"different values", `{"a":1}`, `{"a":2}`, "values should match", false},
+		{"different array order", `[1,2]`, `[2,1]`, "", false},
+		{"missing key", `{"a":1,"b":2}`, `{"a":1}`, "", false},
+		{"invalid expected JSON", `{"a":`, `{"a":1}`, "", false},
+		{"invalid actual JSON", `{"a":1}`, `not json`, "response should be %s", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &mockT{}
+			var result bool
+			if tc.message != "" {
+				result = assert.JSONEq(mock, tc.expected, []byte(tc.actual), tc.message, "JSON")
+			} else {
+				result = assert.JSONEq(mock, tc.expected, []byte(tc.actual))
+			}
+
+			if result != tc.shouldPass {
+				t.Errorf("JSONEq(%s, %s) returned %v, expected %v", tc.expected, tc.actual, result, tc.shouldPass)
+			}
+			if mock.failed == tc.shouldPass {
+				t.Errorf("mockT.failed = %v, expected %v", mock.failed, !tc.shouldPass)
+			}
+		})
+	}
+}
+
+func TestMapContainsSubset(t *testing.T) {
+	t.Parallel()
+
+	full := map[string]any{
+		"name": "John",
+		"age":  30,
+		"address": map[string]any{
+			"city": "Stockholm",
+			"zip":  "11122",
+		},
+		"tags": []string{"a", "b"},
+	}
+
+	testCases := []struct {
+		name       string
+		subset     map[string]any
+		message    string
+		shouldPass bool
+	}{
+		{"empty subset", map[string]any{}, "", true},
+		{"top level keys", map[string]any{"name": "John", "age": 30}, "", true},
+		{"nested subset", map[string]any{"address": map[string]any{"city": "Stockholm"}}, "", true},
+		{"slice values", map[string]any{"tags": []string{"a", "b"}}, "", true},
+		{"missing key", map[string]any{"email": "john@example.com"}, "email should be present", false},
+		{"different value", map[string]any{"age": 31}, "", false},
+		{"missing nested key", map[string]any{"address": map[string]any{"street": "Main"}}, "", false},
+		{"nested value is not a map", map[string]any{"name": map[string]any{"first": "John"}}, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &mockT{}
+			var result bool
+			if tc.message != "" {
+				result = assert.MapContainsSubset(mock, tc.subset, full, tc.message)
+			} else {
+				result = assert.MapContainsSubset(mock, tc.subset, full)
+			}
+
+			if result != tc.shouldPass {
+				t.Errorf("MapContainsSubset(%v) returned %v, expected %v", tc.subset, result, tc.shouldPass)
+			}
+			if mock.failed == tc.shouldPass {
+				t.Errorf("mockT.failed = %v, expected %v", mock.failed, !tc.shouldPass)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/swaggest/openapi-go"
 
 	"github.com/sillen102/simba/auth"
@@ -108,6 +110,69 @@ func TagsHandler(_ context.Context, req *models.Request[RequestBody, Params]) (*
 	}, nil
 }
 
+// ExampleHandler A dummy function to test the OpenAPI generation with a request body example.
+// @Example {
+//
+//	"name": "Jane Doe",
+//	"age": 42,
+//	"description": "example user"
+//
+// }
+// @Summary example handler.
+func ExampleHandler(_ context.Context, req *models.Request[RequestBody, Params]) (*models.Response[ResponseBody], error) {
+	return &models.Response[ResponseBody]{
+		Body: ResponseBody{
+			ID:          req.Params.ID,
+			Name:        req.Body.Name,
+			Age:         req.Body.Age,
+			Description: req.Body.Description,
+		},
+	}, nil
+}
+
+// AcceptedHandler A dummy function to test the OpenAPI generation of operations accepted for background processing.
+func AcceptedHandler(_ context.Context, req *models.Request[RequestBody, Params]) (*models.Response[ResponseBody], error) {
+	return models.Accepted("/jobs/"+req.Params.ID.String(), ResponseBody{
+		ID:          req.Params.ID,
+		Name:        req.Body.Name,
+		Age:         req.Body.Age,
+		Description: req.Body.Description,
+	}), nil
+}
+
+// ThrottledHandler A dummy function to test the OpenAPI generation of handlers returning 429 Too Many Requests.
+func ThrottledHandler(_ context.Context, req *models.Request[RequestBody, Params]) (*models.Response[ResponseBody], error) {
+	if req.Body.Age > 100 {
+		return nil, simbaErrors.TooManyRequests(time.Minute, "too many requests")
+	}
+	return &models.Response[ResponseBody]{
+		Body: ResponseBody{
+			ID:          req.Params.ID,
+			Name:        req.Body.Name,
+			Age:         req.Body.Age,
+			Description: req.Body.Description,
+		},
+	}, nil
+}
+
+// MultiStatusHandler A dummy function to test the OpenAPI generation of bulk operations with per-item results.
+func MultiStatusHandler(_ context.Context, req *models.Request[[]RequestBody, models.NoParams]) (*models.Response[models.MultiStatusBody[ResponseBody]], error) {
+	results := make([]models.ItemResult[ResponseBody], 0, len(req.Body))
+	for i, item := range req.Body {
+		if item.Name == "" {
+			results = append(results, models.ItemFailed[ResponseBody](i, http.StatusUnprocessableEntity, "name is required"))
+			continue
+		}
+		results = append(results, models.ItemSucceeded(i, http.StatusCreated, ResponseBody{
+			ID:          uuid.New(),
+			Name:        item.Name,
+			Age:         item.Age,
+			Description: item.Description,
+		}))
+	}
+	return models.MultiStatus(results), nil
+}
+
 // DeprecatedHandler A dummy function to test the OpenAPI generation with deprecated tag.
 // @Deprecated.
 func DeprecatedHandler(ctx context.Context, req *models.Request[RequestBody, Params]) (*models.Response[ResponseBody], error) {
@@ -0,0 +1,133 @@
+package simbaTest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/swaggest/openapi-go/openapi31"
+
+	"github.com/sillen102/simba"
+)
+
+// UpdateOpenAPIGoldenEnv is the environment variable that updates the golden files compared by
+// [AssertOpenAPIGolden] when set to true, like the -update-openapi flag.
+const UpdateOpenAPIGoldenEnv = "SIMBA_UPDATE_OPENAPI"
+
+var updateOpenAPIGolden = flag.Bool("update-openapi", false, "update the OpenAPI golden files instead of comparing them")
+
+// AssertOpenAPIGolden generates the OpenAPI documentation of the application and compares it to the
+// committed golden file, failing the test with a diff when the documentation has drifted, to guard against
+// accidental API contract changes. The golden file is JSON, or YAML if its extension is .yaml or .yml.
+//
+// Run the tests with the -update-openapi flag, or with SIMBA_UPDATE_OPENAPI=true, to write the generated
+// documentation to the golden file instead, and review the change like any other code change. The
+// documentation is generated from the routes registered on the application, so register all routes first.
+//
+//	Example usage:
+//
+//	func TestOpenAPI(t *testing.T) {
+//		app := simba.Default()
+//		registerRoutes(app)
+//		simbaTest.AssertOpenAPIGolden(t, app, "testdata/openapi.json")
+//	}
+func AssertOpenAPIGolden(t testing.TB, app *simba.Application, goldenFile string) bool {
+	t.Helper()
+
+	if !app.Settings.Docs.GenerateOpenAPIDocs {
+		t.Fatalf("OpenAPI documentation generation is disabled for the application")
+		return false
+	}
+
+	if err := app.Router.GenerateOpenAPIDocumentation(t.Context(), app.Settings.Name, app.Settings.Version); err != nil {
+		t.Fatalf("failed to generate OpenAPI documentation: %v", err)
+		return false
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, app.Settings.Docs.OpenAPIFilePath, nil)
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("failed to get OpenAPI documentation from %s: status %d", app.Settings.Docs.OpenAPIFilePath, w.Code)
+		return false
+	}
+
+	generated, err := formatOpenAPIGolden(w.Body.Bytes(), goldenFile)
+	if err != nil {
+		t.Fatalf("failed to format OpenAPI documentation: %v", err)
+		return false
+	}
+
+	if shouldUpdateOpenAPIGolden() {
+		if err = os.MkdirAll(filepath.Dir(goldenFile), 0o755); err == nil {
+			err = os.WriteFile(goldenFile, generated, 0o644)
+		}
+		if err != nil {
+			t.Fatalf("failed to update OpenAPI golden file %s: %v", goldenFile, err)
+			return false
+		}
+		t.Logf("updated OpenAPI golden file %s", goldenFile)
+		return true
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read OpenAPI golden file %s, run the tests with -update-openapi to create it: %v", goldenFile, err)
+		return false
+	}
+
+	if bytes.Equal(golden, generated) {
+		return true
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(golden)),
+		B:        difflib.SplitLines(string(generated)),
+		FromFile: goldenFile,
+		ToFile:   "generated",
+		Context:  3,
+	})
+	if err != nil {
+		diff = err.Error()
+	}
+
+	t.Errorf("OpenAPI documentation does not match the golden file %s, run the tests with -update-openapi "+
+		"to update it if the change is intended:\n%s", goldenFile, diff)
+	return false
+}
+
+// formatOpenAPIGolden formats the documentation for the golden file, as indented JSON or as YAML
+// depending on the file extension.
+func formatOpenAPIGolden(schema []byte, goldenFile string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(goldenFile)) {
+	case ".yaml", ".yml":
+		var spec openapi31.Spec
+		if err := spec.UnmarshalJSON(schema); err != nil {
+			return nil, err
+		}
+		return spec.MarshalYAML()
+	default:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, schema, "", "  "); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+}
+
+func shouldUpdateOpenAPIGolden() bool {
+	if *updateOpenAPIGolden {
+		return true
+	}
+	update, _ := strconv.ParseBool(os.Getenv(UpdateOpenAPIGoldenEnv))
+	return update
+}
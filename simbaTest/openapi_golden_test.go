@@ -0,0 +1,57 @@
+package simbaTest_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+// recordingTB records the errors reported by the assertion instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newGoldenApp(path string) *simba.Application {
+	app := simba.New()
+	app.Router.GET(path, simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[simbaTest.User], error) {
+		return &models.Response[simbaTest.User]{}, nil
+	}))
+	return app
+}
+
+func TestAssertOpenAPIGolden(t *testing.T) {
+	for _, file := range []string{"openapi.json", "openapi.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			goldenFile := filepath.Join(t.TempDir(), "testdata", file)
+
+			t.Setenv(simbaTest.UpdateOpenAPIGoldenEnv, "true")
+			assert.True(t, simbaTest.AssertOpenAPIGolden(t, newGoldenApp("/users"), goldenFile))
+
+			golden, err := os.ReadFile(goldenFile)
+			assert.NoError(t, err)
+			assert.Contains(t, "/users", string(golden))
+
+			t.Setenv(simbaTest.UpdateOpenAPIGoldenEnv, "false")
+			assert.True(t, simbaTest.AssertOpenAPIGolden(t, newGoldenApp("/users"), goldenFile))
+
+			recorder := &recordingTB{TB: t}
+			assert.False(t, simbaTest.AssertOpenAPIGolden(recorder, newGoldenApp("/accounts"), goldenFile))
+			assert.Len(t, recorder.errors, 1)
+			assert.Contains(t, "does not match the golden file", recorder.errors[0])
+			assert.Contains(t, "/users", recorder.errors[0])
+			assert.Contains(t, "/accounts", recorder.errors[0])
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package simbaTest
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+)
+
+// ParamCase is a generated set of parameter values together with the expected binding outcome.
+type ParamCase struct {
+	Name    string
+	Path    map[string]string
+	Query   url.Values
+	Headers http.Header
+	Cookies map[string]string
+	Valid   bool
+
+	// pathNames holds the path parameter names in the order they appear in the route pattern
+	pathNames []string
+}
+
+// paramField describes a single bindable field of a params struct.
+type paramField struct {
+	in           string
+	name         string
+	typ          reflect.Type
+	format       string
+	example      string
+	defaultValue string
+	validated    bool
+}
+
+// GenerateParamCases generates parameter inputs for the Params struct to exercise parameter binding.
+// It produces a baseline case with a valid value for every field, a case per field with a value that
+// cannot be parsed into the field type, and a number of random valid values for fields without
+// validation rules. Values for fields with validation rules or custom text unmarshalers are taken from
+// their example or default tags, so such fields should declare a valid example. The seed makes the
+// generated cases reproducible.
+func GenerateParamCases[Params any](seed int64, iterations int) []ParamCase {
+	fields := collectParamFields(reflect.TypeFor[Params]())
+	random := rand.New(rand.NewSource(seed))
+
+	baseline := make(map[*paramField]string, len(fields))
+	for _, field := range fields {
+		baseline[field] = validParamValue(field, random, false)
+	}
+
+	cases := []ParamCase{newParamCase("valid baseline", fields, baseline, true)}
+
+	for _, field := range fields {
+		invalid, ok := invalidParamValue(field.typ)
+		if !ok {
+			continue
+		}
+		values := copyParamValues(baseline)
+		values[field] = invalid
+		cases = append(cases, newParamCase(fmt.Sprintf("invalid %s %s", field.in, field.name), fields, values, false))
+	}
+
+	for i := 0; i < iterations; i++ {
+		for _, field := range fields {
+			if field.validated || isTextUnmarshalerParam(field.typ) {
+				continue
+			}
+			values := copyParamValues(baseline)
+			values[field] = validParamValue(field, random, true)
+			cases = append(cases, newParamCase(fmt.Sprintf("random %s %s #%d", field.in, field.name, i+1), fields, values, true))
+		}
+	}
+
+	return cases
+}
+
+// FuzzParams runs the cases generated by [GenerateParamCases] through a simba router and asserts that
+// valid cases are accepted and invalid cases are rejected with a 400 Bad Request.
+func FuzzParams[Params any](t *testing.T, seed int64, iterations int) {
+	t.Helper()
+
+	fields := collectParamFields(reflect.TypeFor[Params]())
+	pattern := "/fuzz"
+	for _, field := range fields {
+		if field.in == "path" {
+			pattern += "/{" + field.name + "}"
+		}
+	}
+
+	app := simba.New()
+	app.Router.GET(pattern, simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, Params]) (*models.Response[models.NoBody], error) {
+		return &models.Response[models.NoBody]{}, nil
+	}))
+
+	for _, tc := range GenerateParamCases[Params](seed, iterations) {
+		t.Run(tc.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, tc.Request())
+
+			switch {
+			case tc.Valid && w.Code >= http.StatusBadRequest:
+				t.Errorf("expected valid params to be accepted, got status %d: %s", w.Code, w.Body.String())
+			case !tc.Valid && w.Code != http.StatusBadRequest:
+				t.Errorf("expected invalid params to be rejected with 400, got status %d", w.Code)
+			}
+		})
+	}
+}
+
+// Request builds a GET request carrying the parameters of the case. Path parameters are appended
+// to "/fuzz" in declaration order, matching the route registered by [FuzzParams].
+func (c ParamCase) Request() *http.Request {
+	path := "/fuzz"
+	for _, name := range c.pathNames {
+		path += "/" + url.PathEscape(c.Path[name])
+	}
+	if len(c.Query) > 0 {
+		path += "?" + c.Query.Encode()
+	}
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, values := range c.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	for name, value := range c.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	return req
+}
+
+// collectParamFields returns the bindable fields of a params struct, including embedded structs.
+func collectParamFields(t reflect.Type) []*paramField {
+	var fields []*paramField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectParamFields(field.Type)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, in := range []string{"path", "query", "header", "cookie"} {
+			if name := field.Tag.Get(in); name != "" {
+				typ := field.Type
+				if typ.Kind() == reflect.Pointer {
+					typ = typ.Elem()
+				}
+				if typ.Kind() == reflect.Slice && in != "query" && in != "header" || !isSupportedParamType(typ) {
+					break
+				}
+
+				fields = append(fields, &paramField{
+					in:           in,
+					name:         name,
+					typ:          typ,
+					format:       field.Tag.Get("format"),
+					example:      field.Tag.Get("example"),
+					defaultValue: field.Tag.Get("default"),
+					validated:    field.Tag.Get("validate") != "",
+				})
+				break
+			}
+		}
+	}
+
+	return fields
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	uuidType            = reflect.TypeFor[uuid.UUID]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isSupportedParamType reports whether the parameter binder can parse values of the type.
+func isSupportedParamType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		return isSupportedParamType(t.Elem())
+	}
+
+	if t == timeType || t == uuidType || isDecimalParam(t) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int64, reflect.Bool, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTextUnmarshalerParam reports whether the type is parsed by its own UnmarshalText method.
+func isTextUnmarshalerParam(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t != timeType && t != uuidType && !isDecimalParam(t) && reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isDecimalParam reports whether the type is a decimal registered with [simba.RegisterDecimal].
+func isDecimalParam(t reflect.Type) bool {
+	_, ok := simba.DecimalParser(t)
+	return ok
+}
+
+// validParamValue returns a value that can be parsed into the field type. Unless randomize is set,
+// the example and default tags are preferred when they hold a parsable value.
+func validParamValue(field *paramField, random *rand.Rand, randomize bool) string {
+	if !randomize {
+		for _, candidate := range []string{field.example, field.defaultValue} {
+			if candidate != "" && parsesAs(field.typ, field.format, candidate) {
+				return candidate
+			}
+		}
+	}
+
+	// Custom text unmarshalers define their own format, so there is no value to generate
+	if isTextUnmarshalerParam(field.typ) {
+		return ""
+	}
+
+	typ := field.typ
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		return randomParamValue(typ, field.format, random) + "," + randomParamValue(typ, field.format, random)
+	}
+
+	return randomParamValue(typ, field.format, random)
+}
+
+// randomParamValue returns a random value of the given type formatted as a parameter string.
+func randomParamValue(t reflect.Type, format string, random *rand.Rand) string {
+	switch {
+	case t == timeType:
+		if format == "" {
+			format = time.RFC3339
+		}
+		return time.Unix(random.Int63n(4_000_000_000), 0).UTC().Format(format)
+	case t == durationType:
+		return time.Duration(random.Int63() - random.Int63()).String()
+	case t == uuidType:
+		return uuid.NewSHA1(uuid.NameSpaceOID, []byte(strconv.FormatInt(random.Int63(), 10))).String()
+	case isDecimalParam(t):
+		return strconv.FormatFloat(random.NormFloat64()*1000, 'f', 2, 64)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(random.Int63()-random.Int63(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(random.Intn(2) == 1)
+	case reflect.Float64:
+		return strconv.FormatFloat(random.NormFloat64()*1000, 'f', -1, 64)
+	default:
+		return "value" + strconv.Itoa(random.Intn(1000))
+	}
+}
+
+// invalidParamValue returns a value that cannot be parsed into the type. Types that accept any
+// string, such as strings and custom text unmarshalers, have no invalid value.
+func invalidParamValue(t reflect.Type) (string, bool) {
+	if t.Kind() == reflect.Slice {
+		value, ok := invalidParamValue(t.Elem())
+		return "1," + value, ok
+	}
+
+	switch {
+	case t == timeType:
+		return "not-a-time", true
+	case t == durationType:
+		return "not-a-duration", true
+	case t == uuidType:
+		return "not-a-uuid", true
+	case isDecimalParam(t):
+		return "not-a-decimal", true
+	case isTextUnmarshalerParam(t):
+		return "", false
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return "not-a-number", true
+	case reflect.Bool:
+		return "maybe", true
+	case reflect.Float64:
+		return "1.2.3", true
+	default:
+		return "", false
+	}
+}
+
+// parsesAs reports whether the value can be parsed into the type.
+func parsesAs(t reflect.Type, format string, value string) bool {
+	if t.Kind() == reflect.Slice {
+		for _, v := range strings.Split(value, ",") {
+			if !parsesAs(t.Elem(), format, v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var err error
+	switch {
+	case t == timeType:
+		if format == "" {
+			format = time.RFC3339
+		}
+		_, err = time.Parse(format, value)
+	case t == durationType:
+		_, err = time.ParseDuration(value)
+	case t == uuidType:
+		_, err = uuid.Parse(value)
+	case isDecimalParam(t):
+		parse, _ := simba.DecimalParser(t)
+		_, err = parse(value)
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		err = reflect.New(t).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	case t.Kind() == reflect.Int || t.Kind() == reflect.Int64:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case t.Kind() == reflect.Bool:
+		_, err = strconv.ParseBool(value)
+	case t.Kind() == reflect.Float64:
+		_, err = strconv.ParseFloat(value, 64)
+	}
+
+	return err == nil
+}
+
+func copyParamValues(values map[*paramField]string) map[*paramField]string {
+	result := make(map[*paramField]string, len(values))
+	for field, value := range values {
+		result[field] = value
+	}
+	return result
+}
+
+func newParamCase(name string, fields []*paramField, values map[*paramField]string, valid bool) ParamCase {
+	c := ParamCase{
+		Name:    name,
+		Path:    map[string]string{},
+		Query:   url.Values{},
+		Headers: http.Header{},
+		Cookies: map[string]string{},
+		Valid:   valid,
+
+		pathNames: make([]string, 0),
+	}
+
+	for _, field := range fields {
+		value := values[field]
+		if value == "" && field.in != "path" {
+			continue
+		}
+		switch field.in {
+		case "path":
+			c.Path[field.name] = value
+			c.pathNames = append(c.pathNames, field.name)
+		case "query":
+			c.Query.Set(field.name, value)
+		case "header":
+			c.Headers.Set(field.name, value)
+		case "cookie":
+			c.Cookies[field.name] = value
+		}
+	}
+
+	return c
+}
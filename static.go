@@ -0,0 +1,106 @@
+package simba
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// StaticOptions configures how static files are served by [Router.Static].
+type StaticOptions struct {
+	// IndexFile is served for requests to a directory. Defaults to "index.html"
+	IndexFile string `exhaustruct:"optional"`
+	// Browse enables directory listings for directories without an index file
+	Browse bool `exhaustruct:"optional"`
+	// SPAFallback serves the root index file for paths that do not match a file,
+	// so that client side routing in single page applications works
+	SPAFallback bool `exhaustruct:"optional"`
+	// MaxAge sets the max-age of the Cache-Control header for served files.
+	// Index files are always served with "no-cache" so new deployments are picked up
+	MaxAge time.Duration `exhaustruct:"optional"`
+}
+
+// Static serves files from root below the given prefix. Use [os.DirFS] to serve files from disk
+// or pass an [embed.FS] (optionally narrowed with [fs.Sub]) to serve embedded files.
+// Content types are determined from the file extension. Static files are not included in the
+// OpenAPI documentation.
+func (r *Router) Static(prefix string, root fs.FS, opts StaticOptions) {
+	if opts.IndexFile == "" {
+		opts.IndexFile = "index.html"
+	}
+
+	r.Mount(prefix, &staticHandler{
+		root:       root,
+		opts:       opts,
+		fileServer: http.FileServerFS(root),
+	})
+}
+
+type staticHandler struct {
+	root       fs.FS
+	opts       StaticOptions
+	fileServer http.Handler
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
+			http.StatusMethodNotAllowed,
+			"method not allowed",
+			errors.New("static files only support GET and HEAD"),
+		))
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+
+	info, err := fs.Stat(h.root, name)
+	switch {
+	case err != nil:
+		h.serveFallback(w, r, err)
+	case !info.IsDir():
+		h.serveFile(w, r, name)
+	case h.exists(path.Join(name, h.opts.IndexFile)):
+		h.serveFile(w, r, path.Join(name, h.opts.IndexFile))
+	case h.opts.Browse:
+		h.fileServer.ServeHTTP(w, r)
+	default:
+		h.serveFallback(w, r, fs.ErrNotExist)
+	}
+}
+
+// serveFallback serves the root index file in SPA mode and a 404 Not Found otherwise.
+func (h *staticHandler) serveFallback(w http.ResponseWriter, r *http.Request, err error) {
+	if h.opts.SPAFallback && h.exists(h.opts.IndexFile) {
+		h.serveFile(w, r, h.opts.IndexFile)
+		return
+	}
+
+	simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(http.StatusNotFound, "not found", err))
+}
+
+func (h *staticHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	switch {
+	case path.Base(name) == h.opts.IndexFile:
+		w.Header().Set("Cache-Control", "no-cache")
+	case h.opts.MaxAge > 0:
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.opts.MaxAge.Seconds())))
+	}
+
+	http.ServeFileFS(w, r, h.root, name)
+}
+
+func (h *staticHandler) exists(name string) bool {
+	info, err := fs.Stat(h.root, name)
+	return err == nil && !info.IsDir()
+}
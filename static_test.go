@@ -0,0 +1,83 @@
+package simba_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestRouter_Static(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"index.html":      {Data: []byte("<html>app</html>")},
+		"app.js":          {Data: []byte("console.log('app')")},
+		"docs/index.html": {Data: []byte("<html>docs</html>")},
+		"assets/logo.svg": {Data: []byte("<svg></svg>")},
+	}
+
+	serve := func(opts simba.StaticOptions, method, target string) *httptest.ResponseRecorder {
+		router := simba.New().Router
+		router.Static("/static", files, opts)
+
+		req := httptest.NewRequest(method, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("serves files with content type and caching headers", func(t *testing.T) {
+		w := serve(simba.StaticOptions{MaxAge: time.Hour}, http.MethodGet, "/static/app.js")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/javascript; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "console.log('app')", w.Body.String())
+	})
+
+	t.Run("serves index files for directories", func(t *testing.T) {
+		w := serve(simba.StaticOptions{}, http.MethodGet, "/static/docs/")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "<html>docs</html>", w.Body.String())
+	})
+
+	t.Run("returns not found for directories without index when browsing is disabled", func(t *testing.T) {
+		w := serve(simba.StaticOptions{}, http.MethodGet, "/static/assets/")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("lists directories when browsing is enabled", func(t *testing.T) {
+		w := serve(simba.StaticOptions{Browse: true}, http.MethodGet, "/static/assets/")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, "logo.svg", w.Body.String())
+	})
+
+	t.Run("returns not found for missing files", func(t *testing.T) {
+		w := serve(simba.StaticOptions{}, http.MethodGet, "/static/users/1")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("falls back to index in SPA mode", func(t *testing.T) {
+		w := serve(simba.StaticOptions{SPAFallback: true}, http.MethodGet, "/static/users/1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<html>app</html>", w.Body.String())
+	})
+
+	t.Run("rejects unsafe methods", func(t *testing.T) {
+		w := serve(simba.StaticOptions{}, http.MethodPost, "/static/app.js")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,85 @@
+package simba
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/sillen102/simba/models"
+)
+
+var (
+	// ErrHeadersSent is returned by SetHeaders once the streamed response has started, since headers
+	// can no longer be changed after the status line has been written.
+	ErrHeadersSent = errors.New("response headers already sent")
+
+	// ErrNotStreaming is returned by SetHeaders when the context does not belong to a streaming handler.
+	// Other handlers set headers on the returned Response.
+	ErrNotStreaming = errors.New("context does not belong to a streaming handler")
+)
+
+// streamHeadersKey is the context key of the headers of a streamed response.
+type streamHeadersKey struct{}
+
+// streamHeaders guards the headers of a streamed response until the response has started.
+type streamHeaders struct {
+	mu     sync.Mutex
+	header http.Header
+	sent   bool
+}
+
+// withStreamHeaders returns a context through which the handler can set the headers of the response until
+// it has started.
+func withStreamHeaders(ctx context.Context, w http.ResponseWriter) (context.Context, *streamHeaders) {
+	headers := &streamHeaders{mu: sync.Mutex{}, header: w.Header(), sent: false}
+	return context.WithValue(ctx, streamHeadersKey{}, headers), headers
+}
+
+// send marks the headers as sent and runs writeHeader, which writes the status line, while holding the lock
+// so headers set concurrently are either sent or rejected.
+func (h *streamHeaders) send(writeHeader func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sent = true
+	writeHeader()
+}
+
+// SetHeaders sets headers of the response of a streaming handler, such as [NDJSONHandler] or [JSONArrayHandler],
+// from the context passed to the handler. Headers must be set before the first record is emitted, since that
+// writes the status line; afterwards SetHeaders returns ErrHeadersSent and the headers are not applied. Headers
+// set before the handler returns an error are sent with the error response.
+//
+//	Example usage:
+//
+//	func(ctx context.Context, req *simba.Request[simba.NoBody, simba.NoParams], emit func(User) error) error {
+//		total, err := users.Count(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		if err := simba.SetHeaders(ctx, models.TotalCount(total)); err != nil {
+//			return err
+//		}
+//		for user := range users.All(ctx) {
+//			if err := emit(user); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	}
+func SetHeaders(ctx context.Context, headers ...models.ResponseHeader) error {
+	h, ok := ctx.Value(streamHeadersKey{}).(*streamHeaders)
+	if !ok {
+		return ErrNotStreaming
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sent {
+		return ErrHeadersSent
+	}
+	for _, header := range headers {
+		header.Apply(h.header)
+	}
+	return nil
+}
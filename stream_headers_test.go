@@ -0,0 +1,130 @@
+package simba_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaTest"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+func TestResponseSetHeaders(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[[]simbaTest.User], error) {
+		users := []simbaTest.User{{ID: 1, Name: "John", Role: "admin"}}
+		resp := &models.Response[[]simbaTest.User]{Body: users}
+		return resp.SetHeaders(
+			models.TotalCount(42),
+			models.RateLimit{Limit: 100, Remaining: 99, Reset: 1500 * time.Millisecond},
+			models.ResponseHeaderFunc(func(header http.Header) {
+				header.Set("X-Page", "1")
+			}),
+		), nil
+	}
+
+	app := simba.New()
+	app.Router.GET("/users", simba.JsonHandler(handler))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "42", w.Header().Get(models.TotalCountHeader))
+	assert.Equal(t, "100", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "99", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "1", w.Header().Get("X-Page"))
+}
+
+func TestSetHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("headers set before the first record are sent", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], emit func(simbaTest.User) error) error {
+			if err := simba.SetHeaders(ctx, models.TotalCount(2)); err != nil {
+				return err
+			}
+			for i := range 2 {
+				if err := emit(simbaTest.User{ID: i, Name: "user", Role: "admin"}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.NDJSONHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(models.TotalCountHeader))
+	})
+
+	t.Run("headers set after the first element are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		var setErr error
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], emit func(simbaTest.User) error) error {
+			if err := emit(simbaTest.User{ID: 1, Name: "user", Role: "admin"}); err != nil {
+				return err
+			}
+			setErr = simba.SetHeaders(ctx, models.TotalCount(1))
+			return nil
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.JSONArrayHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, errors.Is(setErr, simba.ErrHeadersSent))
+		assert.Equal(t, "", w.Header().Get(models.TotalCountHeader))
+		assert.Equal(t, `[{"id":1,"name":"user","role":"admin"}]`, w.Body.String())
+	})
+
+	t.Run("headers are sent with an error before the first element", func(t *testing.T) {
+		t.Parallel()
+
+		handler := func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], emit func(simbaTest.User) error) error {
+			if err := simba.SetHeaders(ctx, models.RateLimit{Limit: 10, Remaining: 0, Reset: time.Minute}); err != nil {
+				return err
+			}
+			return simbaErrors.NewSimbaError(http.StatusTooManyRequests, "too many exports", nil)
+		}
+
+		app := simba.New()
+		app.Router.GET("/export", simba.JSONArrayHandler(handler))
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+	})
+
+	t.Run("context of a non-streaming handler", func(t *testing.T) {
+		t.Parallel()
+
+		err := simba.SetHeaders(context.Background(), models.TotalCount(1))
+		assert.True(t, errors.Is(err, simba.ErrNotStreaming))
+	})
+}
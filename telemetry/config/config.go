@@ -9,6 +9,9 @@ type TelemetryConfig struct {
 	ServiceName    string
 	ServiceVersion string
 	Environment    string
+	// RedactPaths are path patterns, such as /reset/{token}, whose matching request paths are
+	// recorded in spans and metrics as the pattern instead of the path
+	RedactPaths []string
 }
 
 type TracingConfig struct {
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// ClientCancellation marks the current span when the client disconnects before the request completes.
+// Cancelled requests are recorded as an event and attribute rather than an error.
+func ClientCancellation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if simbaContext.IsClientCancelled(r.Context()) {
+			span := trace.SpanFromContext(r.Context())
+			span.SetAttributes(
+				attribute.Bool("http.request.cancelled", true),
+				attribute.String("http.request.cancel_reason", simbaContext.ClientCancelledReason),
+			)
+			span.AddEvent(simbaContext.ClientCancelledReason)
+		}
+	})
+}
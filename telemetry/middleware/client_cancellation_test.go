@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClientCancellation(t *testing.T) {
+	t.Parallel()
+
+	serve := func(cancelRequest bool) sdktrace.ReadOnlySpan {
+		recorder := tracetest.NewSpanRecorder()
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ctx, span := tracerProvider.Tracer("test").Start(ctx, "request")
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cancelRequest {
+				cancel()
+			}
+		})
+
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", nil)
+		ClientCancellation(handler).ServeHTTP(httptest.NewRecorder(), req)
+		span.End()
+
+		return recorder.Ended()[0]
+	}
+
+	t.Run("marks cancelled requests", func(t *testing.T) {
+		span := serve(true)
+
+		if len(span.Events()) != 1 || span.Events()[0].Name != "client_cancelled" {
+			t.Fatalf("events = %v, want client_cancelled event", span.Events())
+		}
+		if span.Status().Code.String() != "Unset" {
+			t.Fatalf("status = %v, want Unset", span.Status().Code)
+		}
+	})
+
+	t.Run("leaves completed requests untouched", func(t *testing.T) {
+		span := serve(false)
+
+		if len(span.Events()) != 0 || len(span.Attributes()) != 0 {
+			t.Fatalf("expected no events or attributes, got %v %v", span.Events(), span.Attributes())
+		}
+	})
+}
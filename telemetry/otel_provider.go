@@ -10,15 +10,21 @@ import (
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/simbaContext"
 	"github.com/sillen102/simba/telemetry/config"
 	telemetryMiddleware "github.com/sillen102/simba/telemetry/middleware"
 )
 
+// StatusClientClosedRequest is the non-standard status code recorded in metrics for requests
+// cancelled by the client before a response was written.
+const StatusClientClosedRequest = 499
+
 // OtelTelemetryProvider implements simba.TelemetryProvider using OpenTelemetry SDK
 // (wraps a full OTel Provider instance for tracing/metrics).
 type OtelTelemetryProvider struct {
 	provider        *Provider
 	telemetryConfig *config.TelemetryConfig
+	redactor        pathRedactor
 }
 
 func NewOtelTelemetryProvider(ctx context.Context, cfg *config.TelemetryConfig) (simba.TelemetryProvider, error) {
@@ -29,7 +35,7 @@ func NewOtelTelemetryProvider(ctx context.Context, cfg *config.TelemetryConfig)
 	if err != nil {
 		return nil, err
 	}
-	return &OtelTelemetryProvider{provider: prov, telemetryConfig: cfg}, nil
+	return &OtelTelemetryProvider{provider: prov, telemetryConfig: cfg, redactor: newPathRedactor(cfg.RedactPaths)}, nil
 }
 
 // TracingMiddleware injects OTel tracing handler.
@@ -38,9 +44,9 @@ func (o *OtelTelemetryProvider) TracingMiddleware() func(http.Handler) http.Hand
 		if o.provider == nil || !o.telemetryConfig.Enabled || !o.telemetryConfig.Tracing.Enabled {
 			return next
 		}
-		return otelhttp.NewHandler(telemetryMiddleware.TraceIDFromOTel(next), "simba.http.server",
+		return o.redactor.redactURL(otelhttp.NewHandler(restoreURL(telemetryMiddleware.ClientCancellation(telemetryMiddleware.TraceIDFromOTel(next))), "simba.http.server",
 			otelhttp.WithTracerProvider(o.provider.TracerProvider()),
-		)
+		))
 	}
 }
 
@@ -60,6 +66,10 @@ func (o *OtelTelemetryProvider) MetricsMiddleware() func(http.Handler) http.Hand
 			"http.server.request.count",
 			metric.WithDescription("Total number of HTTP requests"),
 		)
+		cancelledCount, _ := meter.Int64Counter(
+			"http.server.request.cancelled",
+			metric.WithDescription("Total number of HTTP requests cancelled by the client"),
+		)
 		responseSize, _ := meter.Int64Histogram(
 			"http.server.response.size",
 			metric.WithDescription("Size of HTTP response in bytes"),
@@ -71,13 +81,25 @@ func (o *OtelTelemetryProvider) MetricsMiddleware() func(http.Handler) http.Hand
 				ResponseWriter: w,
 				statusCode:     http.StatusOK,
 				bytesWritten:   0,
+				wroteHeader:    false,
 			}
 			next.ServeHTTP(wrappedWriter, r)
 			duration := float64(time.Since(start).Milliseconds())
+			statusCode := wrappedWriter.statusCode
+			route, _ := o.redactor.redact(r.URL.Path)
+			// Only requests abandoned before a response was written are cancelled, a written response was handled
+			if !wrappedWriter.wroteHeader && simbaContext.IsClientCancelled(r.Context()) {
+				statusCode = StatusClientClosedRequest
+				cancelledCount.Add(r.Context(), 1, metric.WithAttributes(
+					attribute.String("http.method", r.Method),
+					attribute.String("http.route", route),
+					attribute.String("reason", simbaContext.ClientCancelledReason),
+				))
+			}
 			attrs := []attribute.KeyValue{
 				attribute.String("http.method", r.Method),
-				attribute.String("http.route", r.URL.Path),
-				attribute.Int("http.status_code", wrappedWriter.statusCode),
+				attribute.String("http.route", route),
+				attribute.Int("http.status_code", statusCode),
 			}
 			requestDuration.Record(r.Context(), duration, metric.WithAttributes(attrs...))
 			requestCount.Add(r.Context(), 1, metric.WithAttributes(attrs...))
@@ -93,14 +115,17 @@ type metricsResponseWriter struct {
 	http.ResponseWriter
 	statusCode   int
 	bytesWritten int64
+	wroteHeader  bool
 }
 
 func (w *metricsResponseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
+	w.wroteHeader = true
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
 func (w *metricsResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
 	n, err := w.ResponseWriter.Write(b)
 	w.bytesWritten += int64(n)
 	return n, err
@@ -113,6 +138,14 @@ func (w *metricsResponseWriter) Flush() {
 	}
 }
 
+// Push implements http.Pusher if the underlying ResponseWriter does.
+func (w *metricsResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
 // Shutdown delegates to the underlying Otel Provider shutdown.
 func (o *OtelTelemetryProvider) Shutdown(ctx context.Context) error {
 	if o.provider != nil {
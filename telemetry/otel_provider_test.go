@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/sillen102/simba/telemetry/config"
+)
+
+func TestOtelTelemetryProvider_MetricsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	// serve handles a request that the client abandons once the handler is done, returning the recorded
+	// status code and whether the request was counted as cancelled
+	serve := func(t *testing.T, handler http.HandlerFunc) (int64, bool) {
+		t.Helper()
+
+		reader := sdkmetric.NewManualReader()
+		cfg := &config.TelemetryConfig{Enabled: true, Metrics: config.MetricsConfig{Enabled: true}}
+		provider := &OtelTelemetryProvider{
+			provider:        &Provider{meterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), settings: cfg},
+			telemetryConfig: cfg,
+			redactor:        newPathRedactor(nil),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", nil)
+		provider.MetricsMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+			cancel()
+		})).ServeHTTP(httptest.NewRecorder(), req)
+
+		var metrics metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &metrics); err != nil {
+			t.Fatalf("collect metrics: %v", err)
+		}
+
+		var status int64
+		cancelled := false
+		for _, scope := range metrics.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				switch m.Name {
+				case "http.server.request.count":
+					point := m.Data.(metricdata.Sum[int64]).DataPoints[0]
+					value, _ := point.Attributes.Value(attribute.Key("http.status_code"))
+					status = value.AsInt64()
+				case "http.server.request.cancelled":
+					cancelled = true
+				}
+			}
+		}
+		return status, cancelled
+	}
+
+	t.Run("request cancelled before a response is written", func(t *testing.T) {
+		t.Parallel()
+
+		status, cancelled := serve(t, func(w http.ResponseWriter, r *http.Request) {})
+
+		if status != StatusClientClosedRequest || !cancelled {
+			t.Fatalf("status = %d, cancelled = %v, want %d and cancelled", status, cancelled, StatusClientClosedRequest)
+		}
+	})
+
+	t.Run("request cancelled after a response is written", func(t *testing.T) {
+		t.Parallel()
+
+		status, cancelled := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("done"))
+		})
+
+		if status != http.StatusOK || cancelled {
+			t.Fatalf("status = %d, cancelled = %v, want %d and not cancelled", status, cancelled, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// pathRedactor replaces request paths matching a redaction pattern with the pattern itself,
+// such as /reset/abc123 with /reset/{token}, before they are recorded in telemetry.
+type pathRedactor struct {
+	patterns []redactPattern
+}
+
+// redactPattern is a path pattern split into segments. A {name} segment matches any single
+// segment and a trailing {name...} segment matches the remainder of the path.
+type redactPattern struct {
+	pattern  string
+	segments []string
+}
+
+func newPathRedactor(patterns []string) pathRedactor {
+	redactor := pathRedactor{patterns: make([]redactPattern, 0, len(patterns))}
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		redactor.patterns = append(redactor.patterns, redactPattern{
+			pattern:  pattern,
+			segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		})
+	}
+	return redactor
+}
+
+// redact returns the first pattern matching the path, or the path itself if none match.
+func (p pathRedactor) redact(path string) (string, bool) {
+	if len(p.patterns) == 0 {
+		return path, false
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, pattern := range p.patterns {
+		if pattern.matches(segments) {
+			return pattern.pattern, true
+		}
+	}
+	return path, false
+}
+
+func (p redactPattern) matches(segments []string) bool {
+	for i, segment := range p.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}") {
+			return i == len(p.segments)-1
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return len(segments) == len(p.segments)
+}
+
+type originalURLKey struct{}
+
+// originalURL is the URL of a request before it was redacted.
+type originalURL struct {
+	url        *url.URL
+	requestURI string
+}
+
+// redactURL hides the path of requests matching a redaction pattern from the tracing handler, which
+// records the request URL in span attributes. The query is dropped as well, as it can't be checked
+// for sensitive data. The original URL is restored for the application by restoreURL.
+func (p pathRedactor) redactURL(next http.Handler) http.Handler {
+	if len(p.patterns) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, redacted := p.redact(r.URL.Path)
+		if !redacted {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), originalURLKey{}, originalURL{url: r.URL, requestURI: r.RequestURI})
+		r = r.WithContext(ctx)
+		r.URL = &url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host, Path: path}
+		r.RequestURI = path
+		next.ServeHTTP(w, r)
+	})
+}
+
+// restoreURL restores the request URL hidden by redactURL.
+func restoreURL(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if original, ok := r.Context().Value(originalURLKey{}).(originalURL); ok {
+			r = r.WithContext(r.Context())
+			r.URL = original.url
+			r.RequestURI = original.requestURI
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPathRedactor(t *testing.T) {
+	t.Parallel()
+
+	redactor := newPathRedactor([]string{"/reset/{token}", "/files/{path...}", "/users/{id}/keys/{key}"})
+
+	tests := []struct {
+		path     string
+		expected string
+		redacted bool
+	}{
+		{path: "/reset/abc123", expected: "/reset/{token}", redacted: true},
+		{path: "/reset/abc123/", expected: "/reset/{token}", redacted: true},
+		{path: "/reset", expected: "/reset", redacted: false},
+		{path: "/reset/abc/confirm", expected: "/reset/abc/confirm", redacted: false},
+		{path: "/files/docs/secret.pdf", expected: "/files/{path...}", redacted: true},
+		{path: "/users/1/keys/sk_live", expected: "/users/{id}/keys/{key}", redacted: true},
+		{path: "/users/1", expected: "/users/1", redacted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			path, redacted := redactor.redact(tt.path)
+			if path != tt.expected || redacted != tt.redacted {
+				t.Errorf("expected %s (%t), got %s (%t)", tt.expected, tt.redacted, path, redacted)
+			}
+		})
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	redactor := newPathRedactor([]string{"/reset/{token}"})
+
+	var handledURI string
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handledURI = r.URL.RequestURI()
+	})
+	handler := redactor.redactURL(otelhttp.NewHandler(restoreURL(app), "test", otelhttp.WithTracerProvider(tracerProvider)))
+
+	req := httptest.NewRequest(http.MethodGet, "/reset/abc123?email=john@example.com", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if handledURI != "/reset/abc123?email=john@example.com" {
+		t.Errorf("expected the application to see the original URL, got %s", handledURI)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	var urlPath string
+	for _, attr := range spans[0].Attributes() {
+		if value := attr.Value.Emit(); value != "" && (strings.Contains(value, "abc123") || strings.Contains(value, "john@example.com")) {
+			t.Errorf("expected attribute %s to be redacted, got %s", attr.Key, value)
+		}
+		if attr.Key == "url.path" {
+			urlPath = attr.Value.AsString()
+		}
+	}
+	if urlPath != "/reset/{token}" {
+		t.Errorf("expected url.path /reset/{token}, got %s", urlPath)
+	}
+}
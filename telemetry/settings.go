@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/telemetry/config"
+)
+
+// ConfigFromSettings builds the telemetry configuration from the Simba settings, so telemetry can be
+// configured through environment variables (see [settings.FromEnv]). The service name and version
+// default to the application name and version.
+func ConfigFromSettings(s *settings.Simba) *config.TelemetryConfig {
+	serviceName := s.Telemetry.ServiceName
+	if serviceName == "" {
+		serviceName = s.Application.Name
+	}
+	serviceVersion := s.Telemetry.ServiceVersion
+	if serviceVersion == "" {
+		serviceVersion = s.Application.Version
+	}
+
+	return &config.TelemetryConfig{
+		Enabled:        s.Telemetry.Enabled,
+		ServiceName:    serviceName,
+		ServiceVersion: serviceVersion,
+		Environment:    s.Telemetry.Environment,
+		RedactPaths:    s.Telemetry.RedactPaths,
+		Tracing: config.TracingConfig{
+			Enabled:      s.Tracing.Enabled,
+			Exporter:     s.Tracing.Exporter,
+			Endpoint:     s.Tracing.Endpoint,
+			Insecure:     s.Tracing.Insecure,
+			SamplingRate: s.Tracing.SamplingRate,
+		},
+		Metrics: config.MetricsConfig{
+			Enabled:        s.Metrics.Enabled,
+			Exporter:       s.Metrics.Exporter,
+			Endpoint:       s.Metrics.Endpoint,
+			Insecure:       s.Metrics.Insecure,
+			ExportInterval: s.Metrics.ExportInterval,
+		},
+	}
+}
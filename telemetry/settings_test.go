@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/sillen102/simba/settings"
+)
+
+func TestConfigFromSettings(t *testing.T) {
+	s, err := settings.Load(
+		settings.WithApplicationName("users"),
+		settings.WithApplicationVersion("1.2.3"),
+		settings.FromEnv(),
+		settings.WithEnvGetter(func(key string) string {
+			return map[string]string{
+				"SIMBA_TELEMETRY_ENABLED":     "true",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4317",
+			}[key]
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to load settings: %v", err)
+	}
+
+	cfg := ConfigFromSettings(s)
+	if !cfg.Enabled {
+		t.Error("expected telemetry to be enabled")
+	}
+	if cfg.ServiceName != "users" || cfg.ServiceVersion != "1.2.3" {
+		t.Errorf("expected service users 1.2.3, got %s %s", cfg.ServiceName, cfg.ServiceVersion)
+	}
+	if cfg.Tracing.Endpoint != "collector:4317" || cfg.Metrics.Endpoint != "collector:4317" {
+		t.Errorf("expected endpoints collector:4317, got %s and %s", cfg.Tracing.Endpoint, cfg.Metrics.Endpoint)
+	}
+	if !cfg.Tracing.Insecure || !cfg.Metrics.Insecure {
+		t.Error("expected insecure exporters for an http endpoint")
+	}
+}
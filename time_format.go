@@ -0,0 +1,299 @@
+package simba
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/sillen102/simba/models"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	interfaceType  = reflect.TypeFor[any]()
+	formattedTypes sync.Map
+
+	// timeFields searches types for times and empty interfaces, which may hold times, in the exported fields of
+	// the structs that can be converted.
+	timeFields = &typeSearch{
+		cache: sync.Map{},
+		match: func(t reflect.Type) bool {
+			return t == timeType || t.Kind() == reflect.Interface && t.NumMethod() == 0
+		},
+		skip: func(t reflect.Type) bool {
+			return isCustomMarshaler(t) || t.Kind() == reflect.Struct && !convertibleStruct(t)
+		},
+		field: func(field reflect.StructField) (bool, bool) {
+			return false, field.IsExported()
+		},
+	}
+)
+
+// applyTimeFormat returns the body with its time.Time values replaced by their encoding in the time format
+// of the request settings. The body is returned as is if the format is the encoding/json default or its type
+// holds no times. Values held in interface fields are formatted as well, while types that implement
+// json.Marshaler or encoding.TextMarshaler, non-empty interfaces and structs embedding unexported structs
+// or types other than structs are left as is.
+func applyTimeFormat(ctx context.Context, body any) (any, error) {
+	format := getConfigurationFromContext(ctx).TimeFormat
+	if format.IsDefault() {
+		return body, nil
+	}
+
+	value := reflect.ValueOf(body)
+	if !value.IsValid() || !hasTimeFields(value.Type()) {
+		return body, nil
+	}
+
+	formatted, err := convertTimes(value, formattedTypeFor(value.Type(), nil), format)
+	if err != nil {
+		return nil, err
+	}
+	return formatted.Interface(), nil
+}
+
+// hasTimeFields reports whether values of the type can hold times, including through interface fields.
+func hasTimeFields(t reflect.Type) bool {
+	return timeFields.has(t)
+}
+
+// formattedTypeFor returns the type with times replaced by [json.RawMessage]. Recursive references
+// are replaced by interface types, as types created at runtime can't refer to themselves.
+func formattedTypeFor(t reflect.Type, visiting map[reflect.Type]bool) reflect.Type {
+	if !hasTimeFields(t) {
+		return t
+	}
+	if t == timeType {
+		return rawMessageType
+	}
+	if visiting[t] {
+		return interfaceType
+	}
+
+	// Only cache complete results, types inside a cycle refer to the interface type
+	topLevel := visiting == nil
+	if topLevel {
+		if cached, ok := formattedTypes.Load(t); ok {
+			return cached.(reflect.Type)
+		}
+		visiting = map[reflect.Type]bool{}
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var result reflect.Type
+	switch t.Kind() {
+	case reflect.Interface:
+		result = t
+	case reflect.Pointer:
+		result = reflect.PointerTo(formattedTypeFor(t.Elem(), visiting))
+	case reflect.Slice:
+		result = reflect.SliceOf(formattedTypeFor(t.Elem(), visiting))
+	case reflect.Array:
+		result = reflect.ArrayOf(t.Len(), formattedTypeFor(t.Elem(), visiting))
+	case reflect.Map:
+		result = reflect.MapOf(t.Key(), formattedTypeFor(t.Elem(), visiting))
+	default:
+		fields := make([]reflect.StructField, 0, t.NumField())
+		for _, field := range exportedFields(t) {
+			fieldType := formattedTypeFor(field.Type, visiting)
+			if field.Anonymous {
+				fieldType = embeddableType(fieldType)
+			}
+			fields = append(fields, reflect.StructField{
+				Name:      field.Name,
+				Type:      fieldType,
+				Tag:       field.Tag,
+				Anonymous: field.Anonymous,
+			})
+		}
+		result = reflect.StructOf(fields)
+	}
+
+	if topLevel {
+		formattedTypes.Store(t, result)
+	}
+	return result
+}
+
+// convertTimes copies the value into the formatted type, encoding the times in the format.
+func convertTimes(value reflect.Value, formattedType reflect.Type, format models.TimeFormat) (reflect.Value, error) {
+	if value.Type() == formattedType && !hasTimeFields(formattedType) {
+		return value, nil
+	}
+
+	switch {
+	case formattedType == rawMessageType:
+		encoded, err := format.FormatJSON(value.Interface().(time.Time))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(json.RawMessage(encoded)), nil
+	case formattedType.Kind() == reflect.Interface:
+		// Nil values are left as nil interfaces, so omitempty behaves as for the original value
+		if value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer || value.Kind() == reflect.Slice || value.Kind() == reflect.Map {
+			if value.IsNil() {
+				return reflect.Zero(formattedType), nil
+			}
+		}
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		converted, err := convertTimes(value, formattedTypeFor(value.Type(), nil), format)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.New(formattedType).Elem()
+		result.Set(converted)
+		return result, nil
+	}
+
+	switch formattedType.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return reflect.Zero(formattedType), nil
+		}
+		elem, err := convertTimes(value.Elem(), formattedType.Elem(), format)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.New(formattedType.Elem())
+		result.Elem().Set(elem)
+		return result, nil
+	case reflect.Slice:
+		if value.IsNil() {
+			return reflect.Zero(formattedType), nil
+		}
+		result := reflect.MakeSlice(formattedType, value.Len(), value.Len())
+		for i := range value.Len() {
+			elem, err := convertTimes(value.Index(i), formattedType.Elem(), format)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.Index(i).Set(elem)
+		}
+		return result, nil
+	case reflect.Array:
+		result := reflect.New(formattedType).Elem()
+		for i := range value.Len() {
+			elem, err := convertTimes(value.Index(i), formattedType.Elem(), format)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.Index(i).Set(elem)
+		}
+		return result, nil
+	case reflect.Map:
+		if value.IsNil() {
+			return reflect.Zero(formattedType), nil
+		}
+		result := reflect.MakeMapWithSize(formattedType, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			elem, err := convertTimes(iter.Value(), formattedType.Elem(), format)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.SetMapIndex(iter.Key(), elem)
+		}
+		return result, nil
+	default:
+		result := reflect.New(formattedType).Elem()
+		for i, field := range exportedFields(value.Type()) {
+			elem, err := convertTimes(value.FieldByIndex(field.Index), formattedType.Field(i).Type, format)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.Field(i).Set(elem)
+		}
+		return result, nil
+	}
+}
+
+// exportedFields returns the exported fields of the struct.
+func exportedFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := range t.NumField() {
+		if field := t.Field(i); field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// convertibleStruct reports whether the struct can be recreated with [reflect.StructOf] without changing
+// its JSON encoding, which isn't the case if it embeds unexported structs whose fields are promoted, or
+// types other than structs, which may have methods that types created at runtime can't embed.
+func convertibleStruct(t reflect.Type) bool {
+	return convertibleEmbedding(t, map[reflect.Type]bool{})
+}
+
+func convertibleEmbedding(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		switch {
+		case !field.Anonymous:
+		case !isStruct(field.Type):
+			if field.IsExported() {
+				return false
+			}
+		case !field.IsExported():
+			return false
+		default:
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if !convertibleEmbedding(embedded, visiting) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// embeddableTypes caches the copies of embedded structs without their methods.
+var embeddableTypes sync.Map
+
+// embeddableType returns the type of an embedded field of a convertible struct in a form [reflect.StructOf]
+// can embed, which doesn't include structs with methods unless they are the first field. Named structs are
+// copied without their methods, which doesn't change their JSON encoding as types that encode themselves
+// aren't converted.
+func embeddableType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return reflect.PointerTo(embeddableType(t.Elem()))
+	}
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return t
+	}
+	if cached, ok := embeddableTypes.Load(t); ok {
+		return cached.(reflect.Type)
+	}
+
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for _, field := range exportedFields(t) {
+		fieldType := field.Type
+		if field.Anonymous {
+			fieldType = embeddableType(fieldType)
+		}
+		fields = append(fields, reflect.StructField{
+			Name:      field.Name,
+			Type:      fieldType,
+			Tag:       field.Tag,
+			Anonymous: field.Anonymous,
+		})
+	}
+
+	result := reflect.StructOf(fields)
+	embeddableTypes.Store(t, result)
+	return result
+}
@@ -0,0 +1,136 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/settings"
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type timeFormatEvent struct {
+	Name      string               `json:"name"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt *time.Time           `json:"updatedAt,omitempty"`
+	History   []time.Time          `json:"history"`
+	Meta      any                  `json:"meta,omitempty"`
+	Children  []timeFormatEvent    `json:"children,omitempty"`
+	Next      *timeFormatEvent     `json:"next,omitempty"`
+	Labels    map[string]time.Time `json:"labels,omitempty"`
+}
+
+// TimeFormatRevision has a method, which types created at runtime can't embed after other fields.
+type TimeFormatRevision struct {
+	Revision int `json:"revision"`
+}
+
+func (r TimeFormatRevision) String() string {
+	return "r" + strconv.Itoa(r.Revision)
+}
+
+type timeFormatNote struct {
+	At time.Time `json:"at"`
+	TimeFormatRevision
+}
+
+func TestTimeFormat(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	event := timeFormatEvent{
+		Name:      "release",
+		CreatedAt: createdAt,
+		History:   []time.Time{createdAt},
+		Meta:      map[string]any{"at": createdAt},
+		Children:  []timeFormatEvent{{Name: "child", CreatedAt: createdAt, History: nil}},
+	}
+
+	newApp := func(format models.TimeFormat) *simba.Application {
+		app := simba.New(settings.WithTimeFormat(format))
+		app.Router.GET("/events", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[timeFormatEvent], error) {
+			return &models.Response[timeFormatEvent]{Body: event}, nil
+		}))
+		app.Router.GET("/fail", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[timeFormatEvent], error) {
+			return nil, simbaErrors.NewSimbaError(http.StatusConflict, "conflict", nil)
+		}))
+		app.Router.GET("/notes", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[timeFormatNote], error) {
+			return &models.Response[timeFormatNote]{Body: timeFormatNote{At: createdAt, TimeFormatRevision: TimeFormatRevision{Revision: 3}}}, nil
+		}))
+		return app
+	}
+
+	get := func(app *simba.Application, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	tests := []struct {
+		format   models.TimeFormat
+		expected string
+	}{
+		{
+			format:   models.RFC3339TimeFormat,
+			expected: `{"name":"release","createdAt":"2024-03-01T12:30:00Z","history":["2024-03-01T12:30:00Z"],"meta":{"at":"2024-03-01T12:30:00Z"},"children":[{"name":"child","createdAt":"2024-03-01T12:30:00Z","history":null}]}`,
+		},
+		{
+			format:   models.UnixTimeFormat,
+			expected: `{"name":"release","createdAt":1709296200,"history":[1709296200],"meta":{"at":1709296200},"children":[{"name":"child","createdAt":1709296200,"history":null}]}`,
+		},
+		{
+			format:   models.UnixMilliTimeFormat,
+			expected: `{"name":"release","createdAt":1709296200000,"history":[1709296200000],"meta":{"at":1709296200000},"children":[{"name":"child","createdAt":1709296200000,"history":null}]}`,
+		},
+		{
+			format:   "2006-01-02",
+			expected: `{"name":"release","createdAt":"2024-03-01","history":["2024-03-01"],"meta":{"at":"2024-03-01"},"children":[{"name":"child","createdAt":"2024-03-01","history":null}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			t.Parallel()
+
+			w := get(newApp(tt.format), "/events")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected+"\n", w.Body.String())
+		})
+	}
+
+	t.Run("embedded type with methods", func(t *testing.T) {
+		t.Parallel()
+
+		w := get(newApp(models.UnixTimeFormat), "/notes")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"at":1709296200,"revision":3}`+"\n", w.Body.String())
+	})
+
+	t.Run("error responses", func(t *testing.T) {
+		t.Parallel()
+
+		w := get(newApp(models.UnixTimeFormat), "/fail")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.True(t, strings.HasPrefix(w.Body.String(), `{"timestamp":1`))
+	})
+
+	t.Run("documented in OpenAPI", func(t *testing.T) {
+		t.Parallel()
+
+		app := newApp(models.UnixMilliTimeFormat)
+		assert.NoError(t, app.Router.GenerateOpenAPIDocumentation(context.Background(), "Test", "1.0.0"))
+
+		w := get(app, "/openapi.json")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, "Unix time in milliseconds.", w.Body.String())
+		assert.False(t, strings.Contains(w.Body.String(), "date-time"))
+	})
+}
@@ -0,0 +1,76 @@
+package simba
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeSearch searches types for struct fields, such as fields with a tag, or types matching themselves,
+// through pointers, slices, arrays, map values and nested structs, caching the result by type.
+type typeSearch struct {
+	cache sync.Map
+
+	// match reports whether a type matches itself, such as time.Time, or is nil if no type does
+	match func(t reflect.Type) bool
+	// skip reports whether a type isn't searched, such as types encoding themselves, or nil to search all types
+	skip func(t reflect.Type) bool
+	// field reports whether a struct field matches, and whether its type is searched if it doesn't
+	field func(field reflect.StructField) (match, search bool)
+}
+
+// has reports whether the type matches or has a matching field, including in nested types.
+func (s *typeSearch) has(t reflect.Type) bool {
+	return s.search(t, nil)
+}
+
+func (s *typeSearch) search(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if cached, ok := s.cache.Load(t); ok {
+		return cached.(bool)
+	}
+	if visiting[t] {
+		return false
+	}
+
+	// Only cache complete results, types inside a cycle are evaluated before the cycle is resolved
+	topLevel := visiting == nil
+	if topLevel {
+		visiting = map[reflect.Type]bool{}
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	result := false
+	switch {
+	case s.match != nil && s.match(t):
+		result = true
+	case s.skip != nil && s.skip(t):
+	case t.Kind() == reflect.Pointer, t.Kind() == reflect.Slice, t.Kind() == reflect.Array, t.Kind() == reflect.Map:
+		result = s.search(t.Elem(), visiting)
+	case t.Kind() == reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if match, search := s.field(field); match || search && s.search(field.Type, visiting) {
+				result = true
+				break
+			}
+		}
+	}
+
+	if topLevel {
+		s.cache.Store(t, result)
+	}
+	return result
+}
+
+// tagSearch returns a search for fields with the struct tag.
+func tagSearch(tag string, skip func(t reflect.Type) bool) *typeSearch {
+	return &typeSearch{
+		cache: sync.Map{},
+		match: nil,
+		skip:  skip,
+		field: func(field reflect.StructField) (bool, bool) {
+			_, ok := field.Tag.Lookup(tag)
+			return ok, true
+		},
+	}
+}
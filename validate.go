@@ -0,0 +1,69 @@
+package simba
+
+import (
+	"reflect"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/validation"
+)
+
+// ValidateRequest validates the params and body of the request with the same rules handlers apply after
+// binding them, returning the validation errors of the params followed by those of the body. It returns
+// nil if the request is valid. This is useful in unit tests and in flows that build requests themselves.
+//
+//	Example usage:
+//
+//	req := &models.Request[CreateUser, UserParams]{Body: CreateUser{Name: ""}}
+//	errs := simba.ValidateRequest(req) // [{Field: "name", Err: "name is a required field", Code: "required"}]
+//
+// Rules scoped to HTTP methods, such as required_on=POST, pass since the request has no method. Use
+// [ValidateRequestForMethod] to validate them.
+func ValidateRequest[RequestBody, Params any](req *models.Request[RequestBody, Params]) []validation.ValidationError {
+	return ValidateRequestForMethod("", req)
+}
+
+// ValidateRequestForMethod validates the request like [ValidateRequest] as if it had the HTTP method, which
+// the rules scoped to methods, such as required_on=POST PUT and excluded_on=PATCH, apply to.
+//
+//	Example usage:
+//
+//	req := &models.Request[UserRequest, UserParams]{Body: UserRequest{}}
+//	errs := simba.ValidateRequestForMethod(http.MethodPost, req) // name is required on POST
+func ValidateRequestForMethod[RequestBody, Params any](method string, req *models.Request[RequestBody, Params]) []validation.ValidationError {
+	if req == nil {
+		return nil
+	}
+
+	validationErrors := paramErrors(validateModel(req.Params, method, false, ""))
+	return append(validationErrors, validateModel(req.Body, method, false, "")...)
+}
+
+// paramErrors removes the JSON Pointers from the validation errors of params, which are not in the request body.
+func paramErrors(validationErrors []validation.ValidationError) []validation.ValidationError {
+	for i := range validationErrors {
+		validationErrors[i].Pointer = ""
+	}
+	return validationErrors
+}
+
+// validateModel validates the struct tags of a params or body model, which may be a pointer, for a request
+// with the HTTP method, stopping at the first error if failFast is set, with the messages in the locale, or
+// English if empty. Models without struct tags to validate, such as maps and nil pointers, are valid.
+func validateModel(model any, method string, failFast bool, locale string) []validation.ValidationError {
+	v := reflect.ValueOf(model)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if failFast {
+		return validation.ValidateStructFailFastForMethod(v.Interface(), method, locale)
+	}
+	return validation.ValidateStructForMethod(v.Interface(), method, locale)
+}
@@ -0,0 +1,75 @@
+package simba_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+	"github.com/sillen102/simba/validation"
+)
+
+func TestValidateRequest(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		Tenant string `header:"X-Tenant" validate:"required"`
+	}
+	type body struct {
+		Name string `json:"name" validate:"required"`
+		Age  int    `json:"age" validate:"min=0"`
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		t.Parallel()
+
+		req := &models.Request[body, params]{Params: params{Tenant: "acme"}, Body: body{Name: "John", Age: 42}}
+		assert.Len(t, simba.ValidateRequest(req), 0)
+	})
+
+	t.Run("params and body errors", func(t *testing.T) {
+		t.Parallel()
+
+		req := &models.Request[body, params]{Body: body{Age: -1}}
+		assert.Equal(t, []validation.ValidationError{
+			{Field: "Tenant", Err: "Tenant is a required field", Code: "required"},
+			{Field: "name", Err: "name is a required field", Code: "required", Pointer: "/name"},
+			{Field: "age", Err: "age must be 0 or greater", Code: "min", Pointer: "/age"},
+		}, simba.ValidateRequest(req))
+	})
+
+	t.Run("pointer body", func(t *testing.T) {
+		t.Parallel()
+
+		req := &models.Request[*body, models.NoParams]{Body: &body{}}
+		errs := simba.ValidateRequest(req)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "name", errs[0].Field)
+
+		assert.Len(t, simba.ValidateRequest(&models.Request[*body, models.NoParams]{}), 0)
+	})
+
+	t.Run("bodies without struct tags", func(t *testing.T) {
+		t.Parallel()
+
+		req := &models.Request[map[string]any, models.NoParams]{Body: map[string]any{"name": ""}}
+		assert.Len(t, simba.ValidateRequest(req), 0)
+		assert.Len(t, simba.ValidateRequest[models.NoBody, models.NoParams](nil), 0)
+	})
+
+	t.Run("method-scoped rules", func(t *testing.T) {
+		t.Parallel()
+
+		type scoped struct {
+			Email string `json:"email" validate:"required_on=POST,omitempty,email"`
+		}
+
+		req := &models.Request[scoped, models.NoParams]{Body: scoped{}}
+		assert.Equal(t, []validation.ValidationError{
+			{Field: "email", Err: "email is a required field", Code: "required_on", Pointer: "/email"},
+		}, simba.ValidateRequestForMethod(http.MethodPost, req))
+		assert.Len(t, simba.ValidateRequestForMethod(http.MethodPatch, req), 0)
+		assert.Len(t, simba.ValidateRequest(req), 0)
+	})
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// methodKey is the context key of the HTTP method of the request a struct is validated for.
+type methodKey struct{}
+
+// methodContext returns a context holding the HTTP method the method-scoped rules apply to.
+func methodContext(method string) context.Context {
+	return context.WithValue(context.Background(), methodKey{}, strings.ToUpper(method))
+}
+
+// methodRules are the validation rules scoped to HTTP methods, with the translation keys of the rules they
+// mirror, which every locale translates.
+var methodRules = []struct {
+	tag         string
+	fn          validator.FuncCtx
+	translation string
+}{
+	{tag: "required_on", fn: requiredOn, translation: "required"},
+	{tag: "excluded_on", fn: excludedOn, translation: "excluded_if"},
+}
+
+// registerMethodRules registers the method-scoped rules with the validator and their messages with the
+// translators.
+func registerMethodRules() error {
+	for _, rule := range methodRules {
+		if err := validate.RegisterValidationCtx(rule.tag, rule.fn, true); err != nil {
+			return err
+		}
+		for _, translator := range translators {
+			err := validate.RegisterTranslation(rule.tag, translator, noTranslations, translateAs(rule.translation))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// noTranslations registers no translations, since the method-scoped rules reuse those of the rules they mirror.
+func noTranslations(ut.Translator) error {
+	return nil
+}
+
+// translateAs returns a translation function translating errors with the message of another rule, in English
+// if the locale doesn't translate it.
+func translateAs(key string) validator.TranslationFunc {
+	return func(translator ut.Translator, fe validator.FieldError) string {
+		if msg, err := translator.T(key, fe.Field()); err == nil {
+			return msg
+		}
+		if msg, err := trans.T(key, fe.Field()); err == nil {
+			return msg
+		}
+		return fe.Error()
+	}
+}
+
+// requiredOn is the required_on rule, such as required_on=POST PUT, which requires the field like required
+// when the request has one of the methods, and passes otherwise.
+func requiredOn(ctx context.Context, fl validator.FieldLevel) bool {
+	return !methodMatches(ctx, fl.Param()) || hasValue(fl)
+}
+
+// excludedOn is the excluded_on rule, such as excluded_on=PATCH, which requires the field to be empty when
+// the request has one of the methods, and passes otherwise.
+func excludedOn(ctx context.Context, fl validator.FieldLevel) bool {
+	return !methodMatches(ctx, fl.Param()) || !hasValue(fl)
+}
+
+// methodMatches reports whether the method in the context is one of the space separated methods. Structs
+// validated without a method match none.
+func methodMatches(ctx context.Context, methods string) bool {
+	method, _ := ctx.Value(methodKey{}).(string)
+	return method != "" && slices.Contains(strings.Fields(strings.ToUpper(methods)), method)
+}
+
+// hasValue reports whether the field has a value the way the required rule does: nil pointers, slices and maps
+// and zero values have none, while a pointer to a zero value does.
+func hasValue(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface, reflect.Chan, reflect.Func:
+		return !field.IsNil()
+	default:
+		return isPointerField(fl) || !field.IsZero()
+	}
+}
+
+// isPointerField reports whether the field is declared as a pointer, which the validator has dereferenced if
+// it isn't nil.
+func isPointerField(fl validator.FieldLevel) bool {
+	parent := reflect.Indirect(fl.Parent())
+	if parent.Kind() != reflect.Struct {
+		return false
+	}
+	field, ok := parent.Type().FieldByName(fl.StructFieldName())
+	return ok && field.Type.Kind() == reflect.Pointer
+}
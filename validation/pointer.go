@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pointerEscaper escapes reference tokens of JSON Pointers as defined by RFC 6901.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// JSONPointer returns the RFC 6901 JSON Pointer to the value at the reference tokens, such as
+// /items/2/email for the tokens items, 2 and email. The tokens are escaped.
+func JSONPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+// namespacePointer converts the namespace of a validation error in a model of type t, such as
+// CreateOrder.items[2].email, to the JSON Pointer to the invalid value in the JSON document of the model,
+// such as /items/2/email. The namespace is read along the type, since field names and map keys can
+// contain the separators of the namespace, and fields of embedded structs are promoted like in JSON.
+func namespacePointer(t reflect.Type, namespace string) string {
+	t = indirectType(t)
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		namespace = strings.TrimPrefix(namespace, t.Name()+".")
+	}
+
+	var tokens []string
+	for namespace != "" {
+		t = indirectType(t)
+		switch t.Kind() {
+		case reflect.Struct:
+			field, name, ok := namespaceField(t, namespace)
+			if !ok {
+				return JSONPointer(append(tokens, namespace)...)
+			}
+			if !field.Anonymous || jsonName(field) != "" {
+				tokens = append(tokens, name)
+			}
+			namespace = strings.TrimPrefix(namespace[len(name):], ".")
+			t = field.Type
+		case reflect.Slice, reflect.Array:
+			end := strings.IndexByte(namespace, ']')
+			if !strings.HasPrefix(namespace, "[") || end < 0 {
+				return JSONPointer(append(tokens, namespace)...)
+			}
+			if _, err := strconv.Atoi(namespace[1:end]); err != nil {
+				return JSONPointer(append(tokens, namespace)...)
+			}
+			tokens = append(tokens, namespace[1:end])
+			namespace = strings.TrimPrefix(namespace[end+1:], ".")
+			t = t.Elem()
+		case reflect.Map:
+			end := mapKeyEnd(namespace)
+			if !strings.HasPrefix(namespace, "[") || end < 0 {
+				return JSONPointer(append(tokens, namespace)...)
+			}
+			tokens = append(tokens, namespace[1:end])
+			namespace = strings.TrimPrefix(namespace[end+1:], ".")
+			t = t.Elem()
+		default:
+			return JSONPointer(append(tokens, namespace)...)
+		}
+	}
+
+	return JSONPointer(tokens...)
+}
+
+// namespaceField returns the field of the struct the namespace starts with and its name in the namespace,
+// preferring the longest name when names are prefixes of each other.
+func namespaceField(t reflect.Type, namespace string) (reflect.StructField, string, bool) {
+	var (
+		match reflect.StructField
+		name  string
+	)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		candidate := fieldName(field)
+		rest, ok := strings.CutPrefix(namespace, candidate)
+		if !ok || len(candidate) <= len(name) {
+			continue
+		}
+		if rest == "" || rest[0] == '.' || rest[0] == '[' {
+			match, name = field, candidate
+		}
+	}
+	return match, name, name != ""
+}
+
+// mapKeyEnd returns the index of the bracket closing the map key the namespace starts with, or -1.
+// Keys can contain brackets, so the closing bracket is the first one followed by a separator or the end.
+func mapKeyEnd(namespace string) int {
+	for i := 1; i < len(namespace); i++ {
+		if namespace[i] != ']' {
+			continue
+		}
+		if i == len(namespace)-1 || namespace[i+1] == '.' || namespace[i+1] == '[' {
+			return i
+		}
+	}
+	return -1
+}
+
+// jsonName returns the name of a field in the json tag, or an empty string if the tag doesn't name it.
+func jsonName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
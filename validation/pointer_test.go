@@ -0,0 +1,103 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/sillen102/simba/simbaTest/assert"
+	"github.com/sillen102/simba/validation"
+)
+
+type PointerAudit struct {
+	CreatedBy string `json:"createdBy" validate:"required"`
+}
+
+func TestValidationErrorPointers(t *testing.T) {
+	t.Parallel()
+
+	type address struct {
+		Street string `json:"street" validate:"required"`
+	}
+
+	type item struct {
+		Email   string   `json:"email" validate:"email"`
+		Address *address `json:"address"`
+	}
+
+	type order struct {
+		PointerAudit
+		Items      []item             `json:"items" validate:"dive"`
+		Recipients map[string]address `json:"recipients" validate:"dive"`
+		Path       string             `json:"a/b~c" validate:"required"`
+		Note       string             `validate:"required"`
+		NoteText   string             `validate:"required"`
+	}
+
+	tests := []struct {
+		name     string
+		request  any
+		expected string
+	}{
+		{
+			name:     "nested array element",
+			request:  order{Items: []item{{Email: "a@b.c"}, {Email: "a@b.c"}, {Email: "invalid"}}},
+			expected: "/items/2/email",
+		},
+		{
+			name:     "nested pointer struct",
+			request:  &order{Items: []item{{Email: "a@b.c", Address: &address{}}}},
+			expected: "/items/0/address/street",
+		},
+		{
+			name:     "map value with separators in the key",
+			request:  order{Recipients: map[string]address{"home.main[1]": {}}},
+			expected: "/recipients/home.main[1]/street",
+		},
+		{
+			name:     "escaped field name",
+			request:  order{},
+			expected: "/a~1b~0c",
+		},
+		{
+			name:     "field promoted from embedded struct",
+			request:  order{},
+			expected: "/createdBy",
+		},
+		{
+			name:     "field sharing a prefix with another field",
+			request:  order{},
+			expected: "/NoteText",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var pointers []string
+			for _, e := range validation.ValidateStruct(tt.request) {
+				pointers = append(pointers, e.Pointer)
+			}
+			assert.Contains(t, tt.expected, pointers)
+		})
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", validation.JSONPointer())
+	assert.Equal(t, "/items/2/email", validation.JSONPointer("items", "2", "email"))
+	assert.Equal(t, "/a~1b/~0c", validation.JSONPointer("a/b", "~c"))
+}
+
+func TestPointers(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []validation.PointerValidationError{
+		{Pointer: "/name", Code: "required", Detail: "name is a required field"},
+		{Parameter: "id", Code: "invalid", Detail: "invalid int parameter value: abc"},
+	}, validation.Pointers([]validation.ValidationError{
+		{Field: "name", Err: "name is a required field", Code: "required", Pointer: "/name"},
+		{Field: "id", Err: "invalid int parameter value: abc"},
+	}))
+}
@@ -1,20 +1,96 @@
 package validation
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
 	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/zh"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	de_translations "github.com/go-playground/validator/v10/translations/de"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	it_translations "github.com/go-playground/validator/v10/translations/it"
+	ja_translations "github.com/go-playground/validator/v10/translations/ja"
+	nl_translations "github.com/go-playground/validator/v10/translations/nl"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	ru_translations "github.com/go-playground/validator/v10/translations/ru"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
 )
 
 type ValidationError struct {
 	Field string `json:"field"`
 	Err   string `json:"error"`
+	// Code is the validation rule that failed, such as required or email
+	Code string `json:"-" exhaustruct:"optional"`
+	// Pointer is the JSON Pointer (RFC 6901) to the invalid value in the request body, such as /items/2/email,
+	// or empty for errors that are not in the body, such as params
+	Pointer string `json:"-" exhaustruct:"optional"`
+}
+
+// StructuredValidationError is a validation error with a machine-readable code,
+// written when the structured validation error format is selected.
+type StructuredValidationError struct {
+	Field   string `json:"field" xml:"field"`
+	Code    string `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
+}
+
+// Structured converts validation errors to structured validation errors.
+// Errors without a code get the code invalid.
+func Structured(errs []ValidationError) []StructuredValidationError {
+	structured := make([]StructuredValidationError, len(errs))
+	for i, e := range errs {
+		code := e.Code
+		if code == "" {
+			code = "invalid"
+		}
+		structured[i] = StructuredValidationError{Field: e.Field, Code: code, Message: e.Err}
+	}
+	return structured
+}
+
+// PointerValidationError is a validation error located by a JSON Pointer, written when the pointer validation
+// error format is selected. Errors in the request body have a pointer to the invalid value, such as
+// /items/2/email, and errors in params name the parameter instead, like the errors of RFC 9457 problem details.
+type PointerValidationError struct {
+	Pointer   string `json:"pointer,omitempty" xml:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty" xml:"parameter,omitempty"`
+	Code      string `json:"code" xml:"code"`
+	Detail    string `json:"detail" xml:"detail"`
+}
+
+// Pointers converts validation errors to validation errors located by JSON Pointers.
+// Errors without a code get the code invalid.
+func Pointers(errs []ValidationError) []PointerValidationError {
+	pointers := make([]PointerValidationError, len(errs))
+	for i, e := range errs {
+		code := e.Code
+		if code == "" {
+			code = "invalid"
+		}
+		pointers[i] = PointerValidationError{Pointer: e.Pointer, Code: code, Detail: e.Err}
+		if e.Pointer == "" {
+			pointers[i].Parameter = e.Field
+		}
+	}
+	return pointers
 }
 
 func (e ValidationError) Error() string {
@@ -25,25 +101,92 @@ var (
 	uni      *ut.UniversalTranslator
 	trans    ut.Translator
 	validate *validator.Validate
+
+	// translators holds the translators of validation messages by lowercase locale, such as pt_br
+	translators = map[string]ut.Translator{}
 )
 
+// translations are the locales validation messages are translated to, besides English.
+var translations = []struct {
+	locale   locales.Translator
+	register func(v *validator.Validate, trans ut.Translator) error
+}{
+	{locale: de.New(), register: de_translations.RegisterDefaultTranslations},
+	{locale: es.New(), register: es_translations.RegisterDefaultTranslations},
+	{locale: fr.New(), register: fr_translations.RegisterDefaultTranslations},
+	{locale: it.New(), register: it_translations.RegisterDefaultTranslations},
+	{locale: ja.New(), register: ja_translations.RegisterDefaultTranslations},
+	{locale: nl.New(), register: nl_translations.RegisterDefaultTranslations},
+	{locale: pt.New(), register: pt_translations.RegisterDefaultTranslations},
+	{locale: pt_BR.New(), register: pt_BR_translations.RegisterDefaultTranslations},
+	{locale: ru.New(), register: ru_translations.RegisterDefaultTranslations},
+	{locale: zh.New(), register: zh_translations.RegisterDefaultTranslations},
+}
+
 func init() {
 	enLocale := en.New()
 	uni = ut.New(enLocale, enLocale)
 	trans, _ = uni.GetTranslator("en")
 
 	validate = validator.New(validator.WithRequiredStructEnabled())
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "" || name == "-" {
-			return fld.Name
-		}
-		return name
-	})
+	validate.RegisterTagNameFunc(fieldName)
 	err := en_translations.RegisterDefaultTranslations(validate, trans)
 	if err != nil {
 		panic("failed to register default translations for validator: " + err.Error())
 	}
+	translators["en"] = trans
+
+	for _, translation := range translations {
+		if err := uni.AddTranslator(translation.locale, false); err != nil {
+			panic("failed to add translator for validator: " + err.Error())
+		}
+		translator, _ := uni.GetTranslator(translation.locale.Locale())
+		if err := translation.register(validate, translator); err != nil {
+			panic("failed to register " + translation.locale.Locale() + " translations for validator: " + err.Error())
+		}
+		translators[strings.ToLower(translation.locale.Locale())] = translator
+	}
+
+	if err := registerMethodRules(); err != nil {
+		panic("failed to register method-scoped validation rules: " + err.Error())
+	}
+}
+
+// translator returns the translator of validation messages for a locale, such as de or pt-BR, falling back
+// to the base language of the locale and then to English.
+func translator(locale string) ut.Translator {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "-", "_"))
+	if translator, ok := translators[locale]; ok {
+		return translator
+	}
+	base, _, _ := strings.Cut(locale, "_")
+	if translator, ok := translators[base]; ok {
+		return translator
+	}
+	return trans
+}
+
+// TranslatedLocales returns the locales validation messages are translated to. Messages in other locales
+// are in English.
+func TranslatedLocales() []string {
+	translated := []string{"en"}
+	for _, translation := range translations {
+		translated = append(translated, strings.ReplaceAll(translation.locale.Locale(), "_", "-"))
+	}
+	return translated
+}
+
+// fieldName returns the name of a field in validation errors: its JSON name, or its form name for models
+// decoded from form values, so errors are keyed the same way regardless of the content type of the request.
+// Fields without either name are reported by their Go name.
+func fieldName(fld reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(fld.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return fld.Name
 }
 
 // Validator returns the validator instance for the application.
@@ -57,26 +200,106 @@ func Validator() *validator.Validate {
 // will return a slice of ValidationErrors containing the validation errors for
 // each field.
 func ValidateStruct(request any) []ValidationError {
+	return ValidateStructInLocale(request, "en")
+}
+
+// ValidateStructInLocale validates the request like ValidateStruct, with the error messages translated to
+// the locale, such as de or pt-BR. See [TranslatedLocales] for the supported locales, messages in other
+// locales are in English. Field names are not translated.
+func ValidateStructInLocale(request any, locale string) []ValidationError {
+	return ValidateStructForMethod(request, "", locale)
+}
+
+// ValidateStructForMethod validates the request like [ValidateStructInLocale] for a request with the HTTP
+// method, which the method-scoped rules apply to. The required_on rule, such as required_on=POST PUT, requires
+// the field like required for the listed methods, and excluded_on, such as excluded_on=PATCH, requires it to be
+// empty for them. Both pass for other methods, so a request struct can be shared by endpoints of several methods:
+//
+//	type UserRequest struct {
+//		Name  string `json:"name" validate:"required_on=POST PUT,omitempty,min=2"`
+//		ID    string `json:"id" validate:"excluded_on=POST"`
+//	}
+//
+// Without a method, such as with [ValidateStructInLocale], the method-scoped rules pass.
+func ValidateStructForMethod(request any, method, locale string) []ValidationError {
+	if request == nil {
+		return nil
+	}
+
+	err := validate.StructCtx(methodContext(method), request)
+	return toValidationErrors(err, reflect.TypeOf(request), translator(locale))
+}
+
+// ValidateStructFailFast validates the request like ValidateStruct, but stops at the first top-level
+// field that is invalid and returns only its first validation error. The fields after it, including
+// nested structs and slices, are not validated, which saves work for large requests.
+func ValidateStructFailFast(request any) []ValidationError {
+	return ValidateStructFailFastInLocale(request, "en")
+}
+
+// ValidateStructFailFastInLocale validates the request like ValidateStructFailFast, with the error messages
+// translated to the locale like [ValidateStructInLocale].
+func ValidateStructFailFastInLocale(request any, locale string) []ValidationError {
+	return ValidateStructFailFastForMethod(request, "", locale)
+}
+
+// ValidateStructFailFastForMethod validates the request like [ValidateStructFailFastInLocale] for a request
+// with the HTTP method, which the method-scoped rules apply to like [ValidateStructForMethod].
+func ValidateStructFailFastForMethod(request any, method, locale string) []ValidationError {
 	if request == nil {
 		return nil
 	}
 
-	err := validate.Struct(request)
+	t := reflect.TypeOf(request)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ValidateStructForMethod(request, method, locale)
+	}
+
+	ctx := methodContext(method)
+
+	// Namespaces of the validator start with the name of the struct type, if it has one
+	prefix := t.Name()
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for i := range t.NumField() {
+		field := []byte(prefix + t.Field(i).Name)
+		err := validate.StructFilteredCtx(ctx, request, func(ns []byte) bool {
+			rest, ok := bytes.CutPrefix(ns, field)
+			return !ok || len(rest) > 0 && rest[0] != '.' && rest[0] != '['
+		})
+		if validationErrors := toValidationErrors(err, t, translator(locale)); len(validationErrors) > 0 {
+			return validationErrors[:1]
+		}
+	}
+
+	return nil
+}
+
+// toValidationErrors converts an error returned by the validator for a model of type t to validation errors
+// with the messages translated by the translator.
+func toValidationErrors(err error, t reflect.Type, translator ut.Translator) []ValidationError {
 	if err == nil {
 		return nil
 	}
 
 	var validationErrors validator.ValidationErrors
 	if !errors.As(err, &validationErrors) {
-		return []ValidationError{{Field: "unknown", Err: "validation failed"}}
+		return []ValidationError{{Field: "unknown", Err: "validation failed", Code: "invalid"}}
 	}
 
 	if len(validationErrors) > 0 {
 		validationErrorsData := make([]ValidationError, len(validationErrors))
 		for i, e := range validationErrors {
 			validationErrorsData[i] = ValidationError{
-				Field: e.Field(),
-				Err:   e.Translate(trans),
+				Field:   e.Field(),
+				Err:     e.Translate(translator),
+				Code:    e.Tag(),
+				Pointer: namespacePointer(t, e.Namespace()),
 			}
 		}
 		return validationErrorsData
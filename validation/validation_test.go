@@ -74,3 +74,282 @@ func TestValidateStruct_PointerInputUsesJsonTagFieldName(t *testing.T) {
 	assert.Equal(t, "email", errors[0].Field)
 	assert.NotEqual(t, "", errors[0].Err)
 }
+
+func TestValidateStruct_UsesFormTagForFieldName(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		FirstName string `form:"first_name" validate:"required"`
+	}
+
+	errors := validation.ValidateStruct(request{})
+
+	assert.NotNil(t, errors)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "first_name", errors[0].Field)
+	assert.Equal(t, "first_name is a required field", errors[0].Err)
+}
+
+func TestValidateStruct_PointerInputUsesFormTagFieldName(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Email string `form:"email,omitempty" validate:"required,email"`
+	}
+
+	errors := validation.ValidateStruct(&request{Email: "invalid"})
+
+	assert.NotNil(t, errors)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "email", errors[0].Field)
+	assert.Equal(t, "email must be a valid email address", errors[0].Err)
+}
+
+func TestValidateStruct_FormAndJsonTagsProduceSameErrors(t *testing.T) {
+	t.Parallel()
+
+	type jsonRequest struct {
+		Name string `json:"name" validate:"required"`
+		Age  int    `json:"age" validate:"gte=18"`
+	}
+
+	type formRequest struct {
+		Name string `form:"name" validate:"required"`
+		Age  int    `form:"age" validate:"gte=18"`
+	}
+
+	type sharedRequest struct {
+		Name string `json:"name" form:"full_name" validate:"required"`
+		Age  int    `json:"age" form:"-" validate:"gte=18"`
+	}
+
+	expected := validation.ValidateStruct(jsonRequest{Age: 12})
+	assert.Len(t, expected, 2)
+	assert.Equal(t, expected, validation.ValidateStruct(formRequest{Age: 12}))
+	assert.Equal(t, expected, validation.ValidateStruct(sharedRequest{Age: 12}))
+}
+
+func TestValidateStruct_IgnoredFormTagFallsBackToStructFieldName(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		FirstName string `form:"-" validate:"required"`
+	}
+
+	errors := validation.ValidateStruct(request{})
+
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "FirstName", errors[0].Field)
+}
+
+func TestValidateStructInLocale_TranslatesMessages(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{locale: "de", expected: "name ist ein Pflichtfeld"},
+		{locale: "pt-BR", expected: "name é um campo obrigatório"},
+		{locale: "fr-CA", expected: "name est un champ obligatoire"},
+		{locale: "sv", expected: "name is a required field"},
+		{locale: "", expected: "name is a required field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			t.Parallel()
+
+			errors := validation.ValidateStructInLocale(request{}, tt.locale)
+			assert.Len(t, errors, 1)
+			assert.Equal(t, "name", errors[0].Field)
+			assert.Equal(t, tt.expected, errors[0].Err)
+			assert.Equal(t, "required", errors[0].Code)
+
+			errors = validation.ValidateStructFailFastInLocale(request{}, tt.locale)
+			assert.Len(t, errors, 1)
+			assert.Equal(t, tt.expected, errors[0].Err)
+		})
+	}
+}
+
+func TestTranslatedLocales(t *testing.T) {
+	t.Parallel()
+
+	locales := validation.TranslatedLocales()
+	assert.Contains(t, "en", locales)
+	assert.Contains(t, "pt-BR", locales)
+}
+
+func TestValidateStructFailFast_ReturnsFirstError(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		SKU      string `json:"sku" validate:"required"`
+		Quantity int    `json:"quantity" validate:"min=1"`
+	}
+
+	type request struct {
+		Customer   string `json:"customer" validate:"required"`
+		Items      []item `json:"items" validate:"dive"`
+		ItemsCount int    `json:"items_count" validate:"min=1"`
+	}
+
+	tests := []struct {
+		name     string
+		request  any
+		expected []validation.ValidationError
+	}{
+		{
+			name:    "valid request",
+			request: request{Customer: "acme", Items: []item{{SKU: "a", Quantity: 1}}, ItemsCount: 1},
+		},
+		{
+			name:    "first top-level field",
+			request: request{Items: []item{{Quantity: 0}}},
+			expected: []validation.ValidationError{
+				{Field: "customer", Err: "customer is a required field", Code: "required", Pointer: "/customer"},
+			},
+		},
+		{
+			name:    "nested field",
+			request: &request{Customer: "acme", Items: []item{{SKU: "a", Quantity: 1}, {Quantity: 0}}},
+			expected: []validation.ValidationError{
+				{Field: "sku", Err: "sku is a required field", Code: "required", Pointer: "/items/1/sku"},
+			},
+		},
+		{
+			name:    "field sharing a prefix with a previous field",
+			request: request{Customer: "acme", Items: []item{{SKU: "a", Quantity: 1}}},
+			expected: []validation.ValidationError{
+				{Field: "items_count", Err: "items_count must be 1 or greater", Code: "min", Pointer: "/items_count"},
+			},
+		},
+		{
+			name: "anonymous struct",
+			request: struct {
+				Name, Email string `validate:"required"`
+			}{},
+			expected: []validation.ValidationError{
+				{Field: "Name", Err: "Name is a required field", Code: "required", Pointer: "/Name"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, validation.ValidateStructFailFast(tt.request))
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected[0], validation.ValidateStruct(tt.request)[0])
+			}
+		})
+	}
+}
+
+func TestValidateStructForMethod(t *testing.T) {
+	t.Parallel()
+
+	type address struct {
+		City string `json:"city" validate:"required_on=POST"`
+	}
+
+	type request struct {
+		ID       string   `json:"id" validate:"excluded_on=POST"`
+		Name     string   `json:"name" validate:"required_on=POST PUT,omitempty,min=2"`
+		Nickname *string  `json:"nickname" validate:"required_on=PUT"`
+		Address  *address `json:"address"`
+	}
+
+	empty := ""
+
+	tests := []struct {
+		name     string
+		method   string
+		request  any
+		expected []validation.ValidationError
+	}{
+		{
+			name:    "required on method",
+			method:  "POST",
+			request: request{},
+			expected: []validation.ValidationError{
+				{Field: "name", Err: "name is a required field", Code: "required_on", Pointer: "/name"},
+			},
+		},
+		{
+			name:    "optional on other method",
+			method:  "PATCH",
+			request: request{ID: "1"},
+		},
+		{
+			name:    "rules after omitempty apply on other method",
+			method:  "PATCH",
+			request: request{Name: "a"},
+			expected: []validation.ValidationError{
+				{Field: "name", Err: "name must be at least 2 characters in length", Code: "min", Pointer: "/name"},
+			},
+		},
+		{
+			name:    "lowercase method",
+			method:  "put",
+			request: &request{Name: "Jane"},
+			expected: []validation.ValidationError{
+				{Field: "nickname", Err: "nickname is a required field", Code: "required_on", Pointer: "/nickname"},
+			},
+		},
+		{
+			name:    "pointer to zero value is a value",
+			method:  "PUT",
+			request: request{Name: "Jane", Nickname: &empty},
+		},
+		{
+			name:    "excluded on method",
+			method:  "POST",
+			request: request{ID: "1", Name: "Jane"},
+			expected: []validation.ValidationError{
+				{Field: "id", Err: "id is an excluded field", Code: "excluded_on", Pointer: "/id"},
+			},
+		},
+		{
+			name:    "nested struct",
+			method:  "POST",
+			request: request{Name: "Jane", Address: &address{}},
+			expected: []validation.ValidationError{
+				{Field: "city", Err: "city is a required field", Code: "required_on", Pointer: "/address/city"},
+			},
+		},
+		{
+			name:    "without method",
+			request: request{ID: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, validation.ValidateStructForMethod(tt.request, tt.method, "en"))
+
+			failFast := validation.ValidateStructFailFastForMethod(tt.request, tt.method, "en")
+			if len(tt.expected) == 0 {
+				assert.Len(t, failFast, 0)
+			} else {
+				assert.Equal(t, tt.expected[:1], failFast)
+			}
+		})
+	}
+
+	t.Run("translated messages", func(t *testing.T) {
+		t.Parallel()
+
+		errs := validation.ValidateStructForMethod(request{ID: "1"}, "POST", "de")
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "name ist ein Pflichtfeld", errs[1].Err)
+	})
+}
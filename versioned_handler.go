@@ -0,0 +1,149 @@
+package simba
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sillen102/simba/simbaErrors"
+	"github.com/sillen102/simba/simbaOpenapi/openapiModels"
+	"github.com/sillen102/simba/validation"
+)
+
+// versionedHandler dispatches requests to the handler of the schema version selected by a request header.
+type versionedHandler struct {
+	header         string
+	defaultVersion string
+	versions       map[string]Handler
+}
+
+// VersionedHandler handles a Request with the handler registered for the schema version in the header,
+// so request and response shapes can evolve without registering a new endpoint. Each version is a regular
+// handler, such as a [JsonHandler], with its own request and response types, and the versions can share the
+// business logic by mapping their types to and from a common model.
+//
+// Requests without the header are handled by the default version. A missing header without a default version,
+// or an unknown version, is rejected with 400 Bad Request. Responses echo the version in the header and vary
+// on it. The request and response bodies are documented in OpenAPI as oneOf the versions, along with the
+// header. The summary, description and params are taken from the default version, or the first version
+// if there is no default.
+//
+//	Example usage:
+//
+//	func createUserV1(ctx context.Context, req *simba.Request[UserV1, simba.NoParams]) (*simba.Response[UserResponseV1], error) {
+//		user, err := users.Create(ctx, req.Body.Name, "")
+//		// ...
+//	}
+//
+//	func createUserV2(ctx context.Context, req *simba.Request[UserV2, simba.NoParams]) (*simba.Response[UserResponseV2], error) {
+//		user, err := users.Create(ctx, req.Body.FirstName+" "+req.Body.LastName, req.Body.Email)
+//		// ...
+//	}
+//
+// Register the handler:
+//
+//	Mux.POST("/users", simba.VersionedHandler("Api-Version", "2", map[string]simba.Handler{
+//		"1": simba.JsonHandler(createUserV1),
+//		"2": simba.JsonHandler(createUserV2),
+//	}))
+func VersionedHandler(header string, defaultVersion string, versions map[string]Handler) Handler {
+	if len(versions) == 0 {
+		panic("versioned handler must have at least one version")
+	}
+	if _, ok := versions[defaultVersion]; defaultVersion != "" && !ok {
+		panic(fmt.Sprintf("default version %q of versioned handler must be one of its versions", defaultVersion))
+	}
+
+	return versionedHandler{
+		header:         http.CanonicalHeaderKey(header),
+		defaultVersion: defaultVersion,
+		versions:       maps.Clone(versions),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface for versionedHandler.
+func (h versionedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", h.header)
+
+	version := strings.TrimSpace(r.Header.Get(h.header))
+	if version == "" {
+		version = h.defaultVersion
+	}
+
+	handler, ok := h.versions[version]
+	if !ok {
+		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
+			http.StatusBadRequest,
+			"request validation failed",
+			nil,
+		).WithDetails([]validation.ValidationError{{
+			Field: h.header,
+			Err:   fmt.Sprintf("%s must be one of %s", h.header, strings.Join(h.values(), ", ")),
+			Code:  "oneof",
+		}}))
+		return
+	}
+
+	w.Header().Set(h.header, version)
+	handler.ServeHTTP(w, r)
+}
+
+// values returns the versions in sorted order.
+func (h versionedHandler) values() []string {
+	return slices.Sorted(maps.Keys(h.versions))
+}
+
+// primary returns the handler of the default version, or the first version if there is no default.
+func (h versionedHandler) primary() Handler {
+	if h.defaultVersion != "" {
+		return h.versions[h.defaultVersion]
+	}
+	return h.versions[h.values()[0]]
+}
+
+// openAPIBody describes the bodies of the versions for the OpenAPI documentation.
+func (h versionedHandler) openAPIBody(body func(Handler) any) openapiModels.VersionedBody {
+	versions := make(map[string]any, len(h.versions))
+	for version, handler := range h.versions {
+		versions[version] = body(handler)
+	}
+	return openapiModels.VersionedBody{
+		Header:   h.header,
+		Default:  h.defaultVersion,
+		Versions: versions,
+	}
+}
+
+func (h versionedHandler) GetRequestBody() any {
+	return h.openAPIBody(Handler.GetRequestBody)
+}
+
+func (h versionedHandler) GetResponseBody() any {
+	return h.openAPIBody(Handler.GetResponseBody)
+}
+
+func (h versionedHandler) GetParams() any {
+	return h.primary().GetParams()
+}
+
+func (h versionedHandler) GetAccepts() string {
+	return h.primary().GetAccepts()
+}
+
+func (h versionedHandler) GetProduces() string {
+	return h.primary().GetProduces()
+}
+
+func (h versionedHandler) GetHandler() any {
+	return h.primary().GetHandler()
+}
+
+func (h versionedHandler) GetAuthModel() any {
+	return h.primary().GetAuthModel()
+}
+
+func (h versionedHandler) GetAuthHandler() any {
+	return h.primary().GetAuthHandler()
+}
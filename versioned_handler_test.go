@@ -0,0 +1,132 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type userV1 struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type userV2 struct {
+	FirstName string `json:"firstName" validate:"required"`
+	LastName  string `json:"lastName" validate:"required"`
+}
+
+type userResponseV1 struct {
+	Name string `json:"name"`
+}
+
+type userResponseV2 struct {
+	FullName string `json:"fullName"`
+}
+
+func TestVersionedHandler(t *testing.T) {
+	t.Parallel()
+
+	createUser := func(name string) string {
+		return strings.ToUpper(name)
+	}
+
+	v1 := func(ctx context.Context, req *models.Request[userV1, models.NoParams]) (*models.Response[userResponseV1], error) {
+		return &models.Response[userResponseV1]{Body: userResponseV1{Name: createUser(req.Body.Name)}}, nil
+	}
+	v2 := func(ctx context.Context, req *models.Request[userV2, models.NoParams]) (*models.Response[userResponseV2], error) {
+		return &models.Response[userResponseV2]{Body: userResponseV2{FullName: createUser(req.Body.FirstName + " " + req.Body.LastName)}}, nil
+	}
+
+	versions := map[string]simba.Handler{
+		"1": simba.JsonHandler(v1),
+		"2": simba.JsonHandler(v2),
+	}
+
+	tests := []struct {
+		name           string
+		defaultVersion string
+		version        string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedHeader string
+	}{
+		{
+			name:           "dispatches to the version in the header",
+			defaultVersion: "2",
+			version:        "1",
+			body:           `{"name":"john doe"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"JOHN DOE"}`,
+			expectedHeader: "1",
+		},
+		{
+			name:           "uses the default version without a header",
+			defaultVersion: "2",
+			body:           `{"firstName":"john","lastName":"doe"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"fullName":"JOHN DOE"}`,
+			expectedHeader: "2",
+		},
+		{
+			name:           "validates the body of the selected version",
+			defaultVersion: "2",
+			version:        "2",
+			body:           `{"name":"john doe"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `firstName is a required field`,
+			expectedHeader: "2",
+		},
+		{
+			name:           "rejects unknown versions",
+			defaultVersion: "2",
+			version:        "3",
+			body:           `{"name":"john doe"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `Api-Version must be one of 1, 2`,
+		},
+		{
+			name:           "rejects a missing header without a default version",
+			body:           `{"name":"john doe"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `Api-Version must be one of 1, 2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := simba.New()
+			app.Router.POST("/users", simba.VersionedHandler("api-version", tt.defaultVersion, versions))
+
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.version != "" {
+				req.Header.Set("Api-Version", tt.version)
+			}
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, tt.expectedBody, w.Body.String())
+			assert.Equal(t, tt.expectedHeader, w.Header().Get("Api-Version"))
+			assert.Equal(t, "Api-Version", w.Header().Get("Vary"))
+		})
+	}
+
+	t.Run("panics on unknown default version", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+		simba.VersionedHandler("Api-Version", "3", versions)
+	})
+}
@@ -0,0 +1,269 @@
+package simba
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// visibilityTag restricts a response field to callers with one of the listed roles,
+// e.g. `visibility:"admin,support"`.
+const visibilityTag = "visibility"
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+	// visibilityFields searches types for fields with a visibility tag, which types that encode themselves
+	// can't have.
+	visibilityFields = tagSearch(visibilityTag, isCustomMarshaler)
+
+	// visibleTypes caches the types with the fields a set of roles may not see removed. The roles are limited to
+	// those named in the visibility tags of the type, so there are at most as many types as combinations of them.
+	visibleTypes sync.Map
+
+	// visibilityRoles caches the roles named in the visibility tags of types.
+	visibilityRoles sync.Map
+
+	errUnsupportedVisibilityType = errors.New("visibility tags are not supported on the type")
+)
+
+type visibleTypeKey struct {
+	t     reflect.Type
+	roles string
+}
+
+// applyVisibility returns the body with the fields the roles in the context may not see removed.
+// The body is returned as is if its type has no fields with a visibility tag. Values held in
+// interface fields and types that implement json.Marshaler or encoding.TextMarshaler are not inspected.
+func applyVisibility(ctx context.Context, body any) (any, error) {
+	value := reflect.ValueOf(body)
+	if !value.IsValid() || !hasVisibilityTags(value.Type()) {
+		return body, nil
+	}
+
+	// Roles that no tag names don't change the visible fields, and would otherwise create a type per caller
+	tagged := taggedRoles(value.Type())
+	roles := slices.DeleteFunc(slices.Clone(simbaContext.GetRoles(ctx)), func(role string) bool {
+		_, found := slices.BinarySearch(tagged, role)
+		return !found
+	})
+	sort.Strings(roles)
+	roles = slices.Compact(roles)
+
+	visibleType, err := visibleTypeFor(value.Type(), roles, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertVisible(value, visibleType, roles).Interface(), nil
+}
+
+// hasVisibilityTags reports whether the type has fields with a visibility tag, including nested types.
+func hasVisibilityTags(t reflect.Type) bool {
+	return visibilityFields.has(t)
+}
+
+// taggedRoles returns the sorted roles named in the visibility tags of the type, including nested types.
+func taggedRoles(t reflect.Type) []string {
+	if cached, ok := visibilityRoles.Load(t); ok {
+		return cached.([]string)
+	}
+
+	named := map[string]bool{}
+	collectTaggedRoles(t, named, map[reflect.Type]bool{})
+	roles := slices.Sorted(maps.Keys(named))
+	visibilityRoles.Store(t, roles)
+	return roles
+}
+
+func collectTaggedRoles(t reflect.Type, roles map[string]bool, visited map[reflect.Type]bool) {
+	if visited[t] || !hasVisibilityTags(t) {
+		return
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		collectTaggedRoles(t.Elem(), roles, visited)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if allowed, ok := field.Tag.Lookup(visibilityTag); ok {
+				for role := range strings.SplitSeq(allowed, ",") {
+					roles[strings.TrimSpace(role)] = true
+				}
+			}
+			collectTaggedRoles(field.Type, roles, visited)
+		}
+	}
+}
+
+// visibleTypeFor returns the type with the fields the roles may not see removed.
+func visibleTypeFor(t reflect.Type, roles []string, visiting map[reflect.Type]bool) (reflect.Type, error) {
+	if !hasVisibilityTags(t) {
+		return t, nil
+	}
+
+	key := visibleTypeKey{t: t, roles: strings.Join(roles, ",")}
+	if cached, ok := visibleTypes.Load(key); ok {
+		return cached.(reflect.Type), nil
+	}
+
+	if visiting[t] {
+		return nil, fmt.Errorf("%w: %s is recursive", errUnsupportedVisibilityType, t)
+	}
+	if visiting == nil {
+		visiting = map[reflect.Type]bool{}
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var result reflect.Type
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := visibleTypeFor(t.Elem(), roles, visiting)
+		if err != nil {
+			return nil, err
+		}
+		result = reflect.PointerTo(elem)
+	case reflect.Slice:
+		elem, err := visibleTypeFor(t.Elem(), roles, visiting)
+		if err != nil {
+			return nil, err
+		}
+		result = reflect.SliceOf(elem)
+	case reflect.Array:
+		elem, err := visibleTypeFor(t.Elem(), roles, visiting)
+		if err != nil {
+			return nil, err
+		}
+		result = reflect.ArrayOf(t.Len(), elem)
+	case reflect.Map:
+		elem, err := visibleTypeFor(t.Elem(), roles, visiting)
+		if err != nil {
+			return nil, err
+		}
+		result = reflect.MapOf(t.Key(), elem)
+	default:
+		if !convertibleStruct(t) {
+			return nil, fmt.Errorf("%w: %s embeds unexported structs or types other than structs", errUnsupportedVisibilityType, t)
+		}
+		fields := make([]reflect.StructField, 0, t.NumField())
+		for _, field := range visibleFields(t, roles) {
+			fieldType, err := visibleTypeFor(field.Type, roles, visiting)
+			if err != nil {
+				return nil, err
+			}
+			if field.Anonymous {
+				fieldType = embeddableType(fieldType)
+			}
+
+			fields = append(fields, reflect.StructField{
+				Name:      field.Name,
+				Type:      fieldType,
+				Tag:       field.Tag,
+				Anonymous: field.Anonymous,
+			})
+		}
+		result = reflect.StructOf(fields)
+	}
+
+	visibleTypes.Store(key, result)
+	return result, nil
+}
+
+// visibleFields returns the fields of the struct that are encoded and that the roles may see.
+func visibleFields(t reflect.Type, roles []string) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if allowed, ok := field.Tag.Lookup(visibilityTag); ok && !hasAnyRole(allowed, roles) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// convertVisible copies the value into the visible type, leaving out the removed fields.
+func convertVisible(value reflect.Value, visibleType reflect.Type, roles []string) reflect.Value {
+	if value.Type() == visibleType {
+		return value
+	}
+
+	switch visibleType.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return reflect.Zero(visibleType)
+		}
+		result := reflect.New(visibleType.Elem())
+		result.Elem().Set(convertVisible(value.Elem(), visibleType.Elem(), roles))
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return reflect.Zero(visibleType)
+		}
+		result := reflect.MakeSlice(visibleType, value.Len(), value.Len())
+		for i := range value.Len() {
+			result.Index(i).Set(convertVisible(value.Index(i), visibleType.Elem(), roles))
+		}
+		return result
+	case reflect.Array:
+		result := reflect.New(visibleType).Elem()
+		for i := range value.Len() {
+			result.Index(i).Set(convertVisible(value.Index(i), visibleType.Elem(), roles))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return reflect.Zero(visibleType)
+		}
+		result := reflect.MakeMapWithSize(visibleType, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), convertVisible(iter.Value(), visibleType.Elem(), roles))
+		}
+		return result
+	default:
+		result := reflect.New(visibleType).Elem()
+		for i, field := range visibleFields(value.Type(), roles) {
+			result.Field(i).Set(convertVisible(value.FieldByIndex(field.Index), visibleType.Field(i).Type, roles))
+		}
+		return result
+	}
+}
+
+func hasAnyRole(allowed string, roles []string) bool {
+	for role := range strings.SplitSeq(allowed, ",") {
+		if _, found := slices.BinarySearch(roles, strings.TrimSpace(role)); found {
+			return true
+		}
+	}
+	return false
+}
+
+func isCustomMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
@@ -0,0 +1,211 @@
+package simba_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaTest/assert"
+)
+
+type VisibilityAudit struct {
+	CreatedBy string `json:"createdBy"`
+	Reason    string `json:"reason,omitempty" visibility:"admin"`
+}
+
+type visibilityUser struct {
+	VisibilityAudit
+	ID       int                        `json:"id"`
+	Email    string                     `json:"email" visibility:"admin, support"`
+	Salary   int                        `json:"salary,omitempty" visibility:"admin"`
+	Manager  *visibilityManager         `json:"manager,omitempty"`
+	Accounts []visibilityAccount        `json:"accounts"`
+	Labels   map[string]visibilityLabel `json:"labels,omitempty"`
+}
+
+type visibilityManager struct {
+	ID    int    `json:"id"`
+	Email string `json:"email" visibility:"admin,support"`
+}
+
+type visibilityAccount struct {
+	Number  string `json:"number"`
+	Balance int    `json:"balance" visibility:"admin"`
+}
+
+type visibilityLabel struct {
+	Value string `json:"value"`
+	Owner string `json:"owner" visibility:"support"`
+}
+
+type visibilityNode struct {
+	Name     string           `json:"name"`
+	Secret   string           `json:"secret" visibility:"admin"`
+	Children []visibilityNode `json:"children"`
+}
+
+// VisibilityRevision has a method, which types created at runtime can't embed after other fields.
+type VisibilityRevision struct {
+	Revision int `json:"revision"`
+}
+
+func (r VisibilityRevision) String() string {
+	return "r" + strconv.Itoa(r.Revision)
+}
+
+type visibilityNote struct {
+	Title  string `json:"title"`
+	Author string `json:"author" visibility:"admin"`
+	VisibilityRevision
+}
+
+type visibilityPrincipal struct {
+	roles []string
+}
+
+func (p visibilityPrincipal) Roles() []string {
+	return p.roles
+}
+
+func visibilityUserBody() visibilityUser {
+	return visibilityUser{
+		VisibilityAudit: VisibilityAudit{CreatedBy: "system", Reason: "import"},
+		ID:              1,
+		Email:           "john@example.com",
+		Salary:          100,
+		Manager:         &visibilityManager{ID: 2, Email: "jane@example.com"},
+		Accounts:        []visibilityAccount{{Number: "123", Balance: 10}},
+		Labels:          map[string]visibilityLabel{"team": {Value: "core", Owner: "jane"}},
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	t.Parallel()
+
+	withRoles := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if roles := r.Header.Get("X-Roles"); roles != "" {
+				r = r.WithContext(simbaContext.WithRoles(r.Context(), strings.Split(roles, ",")...))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	app := simba.New()
+	app.Router.Use(withRoles)
+	app.Router.GET("/users", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[visibilityUser], error) {
+		return &models.Response[visibilityUser]{Body: visibilityUserBody()}, nil
+	}))
+	app.Router.GET("/nodes", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[visibilityNode], error) {
+		return &models.Response[visibilityNode]{Body: visibilityNode{Name: "root", Secret: "secret"}}, nil
+	}))
+	app.Router.GET("/notes", simba.JsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams]) (*models.Response[visibilityNote], error) {
+		return &models.Response[visibilityNote]{Body: visibilityNote{Title: "todo", Author: "john", VisibilityRevision: VisibilityRevision{Revision: 3}}}, nil
+	}))
+	app.Router.GET("/export", simba.NDJSONHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], emit func(visibilityAccount) error) error {
+		return emit(visibilityAccount{Number: "123", Balance: 10})
+	}))
+
+	authHandler := auth.BearerAuth[visibilityPrincipal](
+		func(ctx context.Context, token string) (visibilityPrincipal, error) {
+			return visibilityPrincipal{roles: strings.Split(token, ",")}, nil
+		},
+		auth.BearerAuthConfig{Name: "bearer", Format: "jwt", Description: "Bearer token"},
+	)
+	app.Router.GET("/me", simba.AuthJsonHandler(func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], principal visibilityPrincipal) (*models.Response[visibilityUser], error) {
+		return &models.Response[visibilityUser]{Body: visibilityUserBody()}, nil
+	}, authHandler))
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		value    string
+		expected string
+	}{
+		{
+			name:     "no roles",
+			path:     "/users",
+			expected: `{"createdBy":"system","id":1,"manager":{"id":2},"accounts":[{"number":"123"}],"labels":{"team":{"value":"core"}}}`,
+		},
+		{
+			name:     "support role",
+			path:     "/users",
+			header:   "X-Roles",
+			value:    "support",
+			expected: `{"createdBy":"system","id":1,"email":"john@example.com","manager":{"id":2,"email":"jane@example.com"},"accounts":[{"number":"123"}],"labels":{"team":{"value":"core","owner":"jane"}}}`,
+		},
+		{
+			name:     "admin role",
+			path:     "/users",
+			header:   "X-Roles",
+			value:    "admin",
+			expected: `{"createdBy":"system","reason":"import","id":1,"email":"john@example.com","salary":100,"manager":{"id":2,"email":"jane@example.com"},"accounts":[{"number":"123","balance":10}],"labels":{"team":{"value":"core"}}}`,
+		},
+		{
+			name:     "roles not named in tags",
+			path:     "/users",
+			header:   "X-Roles",
+			value:    "auditor,admin,admin",
+			expected: `{"createdBy":"system","reason":"import","id":1,"email":"john@example.com","salary":100,"manager":{"id":2,"email":"jane@example.com"},"accounts":[{"number":"123","balance":10}],"labels":{"team":{"value":"core"}}}`,
+		},
+		{
+			name:     "roles from auth model",
+			path:     "/me",
+			header:   "Authorization",
+			value:    "Bearer support",
+			expected: `{"createdBy":"system","id":1,"email":"john@example.com","manager":{"id":2,"email":"jane@example.com"},"accounts":[{"number":"123"}],"labels":{"team":{"value":"core","owner":"jane"}}}`,
+		},
+		{
+			name:     "embedded type with methods",
+			path:     "/notes",
+			expected: `{"title":"todo","revision":3}`,
+		},
+		{
+			name:     "embedded type with methods for admin",
+			path:     "/notes",
+			header:   "X-Roles",
+			value:    "admin",
+			expected: `{"title":"todo","author":"john","revision":3}`,
+		},
+		{
+			name:     "ndjson records",
+			path:     "/export",
+			expected: `{"number":"123"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			app.Router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected+"\n", w.Body.String())
+		})
+	}
+
+	t.Run("recursive type fails closed", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/nodes", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.False(t, strings.Contains(w.Body.String(), "secret"))
+	})
+}
@@ -0,0 +1,150 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what happens to broadcast messages sent to a connection beyond its [BroadcastLimit].
+type OverflowPolicy int
+
+const (
+	// DropExcess drops the messages beyond the limit.
+	DropExcess OverflowPolicy = iota
+	// CoalesceExcess keeps only the latest message beyond the limit and sends it as soon as the limit allows,
+	// which suits messages that replace each other, such as state snapshots or presence counts.
+	CoalesceExcess
+)
+
+// BroadcastLimit limits the rate of messages broadcast to each connection of a group, to protect slow clients
+// and bandwidth. The limit is a token bucket: a connection is sent up to Burst messages at once, refilled
+// at MessagesPerSecond. A MessagesPerSecond of 0 or less means no limit.
+type BroadcastLimit struct {
+	MessagesPerSecond float64
+	// Burst is the number of messages that can be sent at once, at least 1
+	Burst int `exhaustruct:"optional"`
+	// Overflow is the policy for messages beyond the limit, dropping them by default
+	Overflow OverflowPolicy `exhaustruct:"optional"`
+}
+
+// enabled reports whether the limit restricts broadcasts.
+func (l BroadcastLimit) enabled() bool {
+	return l.MessagesPerSecond > 0
+}
+
+// BroadcastStats holds the number of broadcast messages sent, dropped and coalesced by a registry.
+// A coalesced message is one that was replaced by a later message before it could be sent.
+type BroadcastStats struct {
+	Sent      int64 `json:"sent"`
+	Dropped   int64 `json:"dropped"`
+	Coalesced int64 `json:"coalesced"`
+}
+
+// broadcastCounters counts the outcome of broadcast messages.
+type broadcastCounters struct {
+	sent      atomic.Int64
+	dropped   atomic.Int64
+	coalesced atomic.Int64
+}
+
+// broadcastLimiter is the token bucket of a connection in a group.
+type broadcastLimiter struct {
+	mu       sync.Mutex
+	limit    BroadcastLimit
+	counters *broadcastCounters
+	tokens   float64
+	last     time.Time
+	pending  *string     `exhaustruct:"optional"`
+	timer    *time.Timer `exhaustruct:"optional"`
+	stopped  bool        `exhaustruct:"optional"`
+}
+
+func newBroadcastLimiter(limit BroadcastLimit, counters *broadcastCounters) *broadcastLimiter {
+	limit.Burst = max(limit.Burst, 1)
+	return &broadcastLimiter{
+		mu:       sync.Mutex{},
+		limit:    limit,
+		counters: counters,
+		tokens:   float64(limit.Burst),
+		last:     time.Now(),
+	}
+}
+
+// allow reports whether msg can be sent to conn now. Otherwise the message is dropped or, when coalescing,
+// kept to be sent once a token is available, replacing the message kept before.
+func (l *broadcastLimiter) allow(ctx context.Context, conn *Connection, msg string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	if l.pending == nil && l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+
+	if l.limit.Overflow != CoalesceExcess || l.stopped {
+		l.counters.dropped.Add(1)
+		return false
+	}
+
+	if l.pending != nil {
+		l.counters.coalesced.Add(1)
+	}
+	l.pending = &msg
+	if l.timer == nil {
+		ctx = context.WithoutCancel(ctx)
+		l.timer = time.AfterFunc(l.wait(), func() { l.flush(ctx, conn) })
+	}
+	return false
+}
+
+// flush sends the pending message once a token is available.
+func (l *broadcastLimiter) flush(ctx context.Context, conn *Connection) {
+	l.mu.Lock()
+	l.timer = nil
+	if l.stopped || l.pending == nil {
+		l.mu.Unlock()
+		return
+	}
+
+	l.refill()
+	if l.tokens < 1 {
+		l.timer = time.AfterFunc(l.wait(), func() { l.flush(ctx, conn) })
+		l.mu.Unlock()
+		return
+	}
+	l.tokens--
+	msg := *l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if err := conn.WriteText(ctx, msg); err == nil {
+		l.counters.sent.Add(1)
+	}
+}
+
+// stop discards the pending message, for connections that left the group.
+func (l *broadcastLimiter) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+	l.pending = nil
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+}
+
+// refill adds the tokens accumulated since the last refill. The caller must hold the lock.
+func (l *broadcastLimiter) refill() {
+	now := time.Now()
+	l.tokens = min(float64(l.limit.Burst), l.tokens+now.Sub(l.last).Seconds()*l.limit.MessagesPerSecond)
+	l.last = now
+}
+
+// wait returns the time until a token is available. The caller must hold the lock.
+func (l *broadcastLimiter) wait() time.Duration {
+	return time.Duration((1 - l.tokens) / l.limit.MessagesPerSecond * float64(time.Second))
+}
@@ -0,0 +1,121 @@
+package websocket_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+	simbawebsocket "github.com/sillen102/simba/websocket"
+
+	"github.com/coder/websocket"
+)
+
+func TestConnectionRegistry_BroadcastLimit(t *testing.T) {
+	t.Parallel()
+
+	// connect joins a client to the room of a new server and returns the client.
+	connect := func(t *testing.T, registry *simbawebsocket.ConnectionRegistry) *websocket.Conn {
+		t.Helper()
+		joined := make(chan struct{}, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						registry.Join("room", conn)
+						joined <- struct{}{}
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return nil
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						registry.Remove(connID)
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		client, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = client.CloseNow() })
+		<-joined
+		return client
+	}
+
+	broadcast := func(t *testing.T, registry *simbawebsocket.ConnectionRegistry, count int) {
+		t.Helper()
+		for i := 1; i <= count; i++ {
+			assert.NoError(t, registry.BroadcastToGroup(context.Background(), "room", strconv.Itoa(i)))
+		}
+	}
+
+	read := func(t *testing.T, client *websocket.Conn) string {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, msg, err := client.Read(ctx)
+		assert.NoError(t, err)
+		return string(msg)
+	}
+
+	t.Run("drops messages beyond the limit", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		registry.SetBroadcastLimit(simbawebsocket.BroadcastLimit{MessagesPerSecond: 1, Burst: 2})
+		client := connect(t, registry)
+
+		broadcast(t, registry, 5)
+
+		assert.Equal(t, "1", read(t, client))
+		assert.Equal(t, "2", read(t, client))
+		assert.Equal(t, simbawebsocket.BroadcastStats{Sent: 2, Dropped: 3, Coalesced: 0}, registry.BroadcastStats())
+	})
+
+	t.Run("coalesces messages beyond the limit into the latest", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		registry.SetGroupBroadcastLimit("room", simbawebsocket.BroadcastLimit{
+			MessagesPerSecond: 20,
+			Overflow:          simbawebsocket.CoalesceExcess,
+		})
+		client := connect(t, registry)
+
+		broadcast(t, registry, 4)
+
+		assert.Equal(t, "1", read(t, client))
+		assert.Equal(t, "4", read(t, client))
+		stats := registry.BroadcastStats()
+		assert.Equal(t, int64(2), stats.Coalesced)
+		assert.Equal(t, int64(0), stats.Dropped)
+	})
+
+	t.Run("group limit overrides the registry limit", func(t *testing.T) {
+		t.Parallel()
+
+		limit := simbawebsocket.BroadcastLimit{MessagesPerSecond: 1}
+		registry := simbawebsocket.NewConnectionRegistry()
+		registry.SetBroadcastLimit(limit)
+		registry.SetGroupBroadcastLimit("room", simbawebsocket.BroadcastLimit{})
+		client := connect(t, registry)
+
+		assert.Equal(t, limit, registry.GroupBroadcastLimit("other"))
+		assert.Equal(t, simbawebsocket.BroadcastLimit{}, registry.GroupBroadcastLimit("room"))
+
+		broadcast(t, registry, 3)
+
+		for _, expected := range []string{"1", "2", "3"} {
+			assert.Equal(t, expected, read(t, client))
+		}
+		assert.Equal(t, simbawebsocket.BroadcastStats{Sent: 3, Dropped: 0, Coalesced: 0}, registry.BroadcastStats())
+	})
+}
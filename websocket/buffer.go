@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+)
+
+// readBufferSizeOption implements HandlerOption for the read buffer size of upgraded connections.
+type readBufferSizeOption struct {
+	size int
+}
+
+func (o readBufferSizeOption) apply(handler any) {
+	if v, ok := handler.(interface{ setReadBufferSize(int) }); ok {
+		v.setReadBufferSize(o.size)
+	}
+}
+
+// WithReadBufferSize sets the size in bytes of the buffer used to read from each upgraded connection.
+// A larger buffer reads big messages in fewer system calls, at the cost of memory held by every open
+// connection for its whole lifetime. A size of 0 or less keeps the 4 KB buffer of net/http.
+func WithReadBufferSize(size int) HandlerOption {
+	return readBufferSizeOption{size: size}
+}
+
+// writeBufferSizeOption implements HandlerOption for the write buffer size of upgraded connections.
+type writeBufferSizeOption struct {
+	size int
+}
+
+func (o writeBufferSizeOption) apply(handler any) {
+	if v, ok := handler.(interface{ setWriteBufferSize(int) }); ok {
+		v.setWriteBufferSize(o.size)
+	}
+}
+
+// WithWriteBufferSize sets the size in bytes of the buffer used to write to each upgraded connection.
+// Messages that fit in the buffer are written in a single system call, so a larger buffer suits handlers
+// sending big messages, while a smaller one saves memory on servers with many idle connections.
+// A size of 0 or less keeps the 4 KB buffer of net/http.
+func WithWriteBufferSize(size int) HandlerOption {
+	return writeBufferSizeOption{size: size}
+}
+
+// bufferSizes holds the buffer sizes of upgraded connections, 0 meaning the net/http default.
+type bufferSizes struct {
+	read  int
+	write int
+}
+
+// wrap returns w with its Hijack method replacing the connection buffers with ones of the configured
+// sizes, or w itself when no size is configured.
+func (b bufferSizes) wrap(w http.ResponseWriter) http.ResponseWriter {
+	if b.read <= 0 && b.write <= 0 {
+		return w
+	}
+	return &bufferedResponseWriter{ResponseWriter: w, sizes: b}
+}
+
+// bufferedResponseWriter hijacks connections with buffers of the configured sizes.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	sizes bufferSizes
+}
+
+// Hijack implements http.Hijacker.
+func (w *bufferedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, writer := brw.Reader, brw.Writer
+	if w.sizes.read > 0 {
+		// Keep the bytes the client sent after the handshake, which net/http has already read. They are
+		// peeked into the new buffer, since the websocket library only carries over buffered bytes when it
+		// resets the reader to read from the connection.
+		buffered, _ := reader.Peek(reader.Buffered())
+		buffered = bytes.Clone(buffered)
+		reader = bufio.NewReaderSize(io.MultiReader(bytes.NewReader(buffered), conn), max(w.sizes.read, len(buffered)))
+		if _, err := reader.Peek(len(buffered)); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+	}
+	if w.sizes.write > 0 {
+		if err := writer.Flush(); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+		writer = bufio.NewWriterSize(conn, w.sizes.write)
+	}
+	return conn, bufio.NewReadWriter(reader, writer), nil
+}
+
+// Unwrap returns the underlying http.ResponseWriter, for use with http.ResponseController.
+func (w *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -2,25 +2,33 @@ package websocket
 
 import (
 	"context"
+	"net/http"
 )
 
 // Callbacks defines lifecycle callbacks for a WebSocket connection.
 type Callbacks[Params any] struct {
+	// BeforeUpgrade is called before the HTTP connection is upgraded to WebSocket.
+	// Return an error to reject the handshake with a regular HTTP error response.
+	// Use a simbaErrors.SimbaError (or an error implementing StatusCodeProvider)
+	// to control the status, e.g. 403 or 429; other errors result in a 500.
+	BeforeUpgrade func(ctx context.Context, r *http.Request, params Params) error
+
 	// OnConnect is called after WebSocket upgrade succeeds.
 	// Return an error to reject the connection.
 	OnConnect func(ctx context.Context, conn *Connection, params Params) error
 
 	// OnMessage is called for each incoming message (required).
+	// Fragmented WebSocket frames are reassembled, so data is always a complete message.
 	// Return an error to trigger OnError or close the connection.
 	OnMessage func(ctx context.Context, conn *Connection, data []byte) error
 
 	// OnDisconnect is called when the connection closes.
 	// The err parameter is nil for clean close, non-nil otherwise.
-	// Guaranteed to run via defer, making it safe for cleanup.
+	// Guaranteed to run via defer, even if another callback panicked, making it safe for cleanup.
 	OnDisconnect func(ctx context.Context, connID string, params Params, err error)
 
-	// OnError is called when OnMessage returns an error.
-	// Return true to continue, false to close the connection.
+	// OnError is called when OnMessage returns an error or panics, the panic being
+	// converted to a *PanicError. Return true to continue, false to close the connection.
 	// If not provided, errors close the connection.
 	OnError func(ctx context.Context, conn *Connection, err error) bool
 }
@@ -28,21 +36,29 @@ type Callbacks[Params any] struct {
 // AuthCallbacks defines lifecycle callbacks for an authenticated WebSocket connection.
 // Same as Callbacks but includes the authenticated user model in each callback.
 type AuthCallbacks[Params, AuthModel any] struct {
+	// BeforeUpgrade is called after authentication but before the HTTP connection
+	// is upgraded to WebSocket. Return an error to reject the handshake with a
+	// regular HTTP error response.
+	// Use a simbaErrors.SimbaError (or an error implementing StatusCodeProvider)
+	// to control the status, e.g. 403 or 429; other errors result in a 500.
+	BeforeUpgrade func(ctx context.Context, r *http.Request, params Params, auth AuthModel) error
+
 	// OnConnect is called after WebSocket upgrade succeeds.
 	// Return an error to reject the connection.
 	OnConnect func(ctx context.Context, conn *Connection, params Params, auth AuthModel) error
 
 	// OnMessage is called for each incoming message (required).
+	// Fragmented WebSocket frames are reassembled, so data is always a complete message.
 	// Return an error to trigger OnError or close the connection.
 	OnMessage func(ctx context.Context, conn *Connection, data []byte, auth AuthModel) error
 
 	// OnDisconnect is called when the connection closes.
 	// The err parameter is nil for clean close, non-nil otherwise.
-	// Guaranteed to run via defer, making it safe for cleanup.
+	// Guaranteed to run via defer, even if another callback panicked, making it safe for cleanup.
 	OnDisconnect func(ctx context.Context, connID string, params Params, auth AuthModel, err error)
 
-	// OnError is called when OnMessage returns an error.
-	// Return true to continue, false to close the connection.
+	// OnError is called when OnMessage returns an error or panics, the panic being
+	// converted to a *PanicError. Return true to continue, false to close the connection.
 	// If not provided, errors close the connection.
 	OnError func(ctx context.Context, conn *Connection, err error) bool
 }
@@ -4,8 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/coder/websocket"
+	"github.com/google/uuid"
 )
 
 // Connection represents an active WebSocket connection.
@@ -17,7 +23,102 @@ type Connection struct {
 	// Use this to track connections in external registries.
 	ID string
 
-	conn *websocket.Conn
+	conn        *websocket.Conn
+	remoteAddr  string
+	header      http.Header
+	connectedAt time.Time `exhaustruct:"optional"`
+
+	mu     sync.RWMutex   `exhaustruct:"optional"`
+	values map[string]any `exhaustruct:"optional"`
+}
+
+// newConnection wraps an upgraded connection, capturing the client's remote address
+// and handshake headers from the upgrade request.
+func newConnection(conn *websocket.Conn, r *http.Request) *Connection {
+	return &Connection{
+		ID:          uuid.New().String(),
+		conn:        conn,
+		remoteAddr:  r.RemoteAddr,
+		header:      r.Header.Clone(),
+		connectedAt: time.Now(),
+		mu:          sync.RWMutex{},
+		values:      nil,
+	}
+}
+
+// RemoteAddr returns the network address of the client that opened the connection,
+// as reported by the upgrade request.
+func (c *Connection) RemoteAddr() string {
+	return c.remoteAddr
+}
+
+// Header returns a copy of the headers sent with the WebSocket handshake request.
+// Modifying the returned header does not affect the connection.
+func (c *Connection) Header() http.Header {
+	return c.header.Clone()
+}
+
+// ConnectedAt returns when the connection was upgraded.
+func (c *Connection) ConnectedAt() time.Time {
+	return c.connectedAt
+}
+
+// info describes the connection for a registry snapshot, without its groups.
+func (c *Connection) info() ConnectionInfo {
+	c.mu.RLock()
+	keys := slices.Sorted(maps.Keys(c.values))
+	c.mu.RUnlock()
+	if keys == nil {
+		keys = []string{}
+	}
+
+	return ConnectionInfo{
+		ID:          c.ID,
+		RemoteAddr:  c.remoteAddr,
+		UserAgent:   c.header.Get("User-Agent"),
+		Origin:      c.header.Get("Origin"),
+		ConnectedAt: c.connectedAt,
+		Groups:      nil,
+		ValueKeys:   keys,
+	}
+}
+
+// connectionContextKey is the context key under which the active Connection is stored.
+type connectionContextKey struct{}
+
+// ConnectionFromContext returns the Connection the callback is running for.
+// It is available in all callbacks, including OnDisconnect, so values stored on
+// the connection can be read for cleanup. Returns nil if ctx does not belong to a connection.
+func ConnectionFromContext(ctx context.Context) *Connection {
+	conn, _ := ctx.Value(connectionContextKey{}).(*Connection)
+	return conn
+}
+
+// Set stores a value on the connection under the given key (thread-safe).
+// Values live as long as the connection and can be used to keep per-connection
+// state such as subscriptions or user preferences.
+func (c *Connection) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[string]any)
+	}
+	c.values[key] = value
+}
+
+// Get returns the value stored on the connection under the given key (thread-safe).
+func (c *Connection) Get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.values[key]
+	return value, ok
+}
+
+// Delete removes the value stored on the connection under the given key (thread-safe).
+func (c *Connection) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
 }
 
 // WriteText sends a text message to the client (thread-safe).
@@ -44,3 +145,20 @@ func (c *Connection) WriteJSON(ctx context.Context, v any) error {
 func (c *Connection) Close() error {
 	return c.conn.CloseNow()
 }
+
+// closeGoingAway closes the connection with status 1001 (going away), performing the close handshake.
+// The connection is closed immediately if the context is done before the handshake completes.
+func (c *Connection) closeGoingAway(ctx context.Context, reason string) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = c.conn.Close(websocket.StatusGoingAway, reason)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		_ = c.conn.CloseNow()
+		<-done
+	}
+}
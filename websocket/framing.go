@@ -0,0 +1,97 @@
+package websocket
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/coder/websocket"
+)
+
+// FrameHeaderSize is the size in bytes of the big-endian length prefix of a frame.
+const FrameHeaderSize = 4
+
+// ErrFrameTooLarge is returned when a frame exceeds the maximum size.
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// AppendFrame appends payload to dst as a length-prefixed frame and returns the extended buffer.
+func AppendFrame(dst []byte, payload []byte) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(payload)))
+	return append(dst, payload...)
+}
+
+// WriteFrames sends the payloads as length-prefixed frames in a single binary message (thread-safe).
+// Each frame is a 4-byte big-endian length followed by the payload, so protocols such as
+// protobuf streams can send several messages at once and split them with a FrameReader.
+func (c *Connection) WriteFrames(ctx context.Context, payloads ...[]byte) error {
+	size := 0
+	for _, payload := range payloads {
+		if uint64(len(payload)) > math.MaxUint32 {
+			return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, len(payload))
+		}
+		size += FrameHeaderSize + len(payload)
+	}
+
+	data := make([]byte, 0, size)
+	for _, payload := range payloads {
+		data = AppendFrame(data, payload)
+	}
+
+	return c.conn.Write(ctx, websocket.MessageBinary, data)
+}
+
+// FrameReader reassembles length-prefixed frames from a stream of messages.
+// Frames may be split across messages or several frames may arrive in one message;
+// the reader buffers partial frames until they are complete.
+// A FrameReader is not safe for concurrent use; use one per connection,
+// e.g. stored with Connection.Set.
+type FrameReader struct {
+	maxFrameSize int
+	buf          []byte
+}
+
+// NewFrameReader creates a FrameReader that rejects frames larger than maxFrameSize bytes.
+// A maxFrameSize of 0 or less means no limit.
+func NewFrameReader(maxFrameSize int) *FrameReader {
+	return &FrameReader{
+		maxFrameSize: maxFrameSize,
+		buf:          nil,
+	}
+}
+
+// Feed adds the data of a received message and returns the frames that are now complete.
+// Returns ErrFrameTooLarge if a frame header announces a frame above the maximum size;
+// the reader is reset in that case since the stream can no longer be trusted.
+func (r *FrameReader) Feed(data []byte) ([][]byte, error) {
+	r.buf = append(r.buf, data...)
+
+	var frames [][]byte
+	for len(r.buf) >= FrameHeaderSize {
+		length := int(binary.BigEndian.Uint32(r.buf))
+		if r.maxFrameSize > 0 && length > r.maxFrameSize {
+			r.buf = nil
+			return frames, fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrFrameTooLarge, length, r.maxFrameSize)
+		}
+		if len(r.buf) < FrameHeaderSize+length {
+			break
+		}
+
+		frame := make([]byte, length)
+		copy(frame, r.buf[FrameHeaderSize:FrameHeaderSize+length])
+		frames = append(frames, frame)
+		r.buf = r.buf[FrameHeaderSize+length:]
+	}
+
+	if len(r.buf) == 0 {
+		r.buf = nil
+	}
+
+	return frames, nil
+}
+
+// Buffered returns the number of bytes of incomplete frames held by the reader.
+func (r *FrameReader) Buffered() int {
+	return len(r.buf)
+}
@@ -0,0 +1,96 @@
+package websocket_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+	simbawebsocket "github.com/sillen102/simba/websocket"
+
+	"github.com/coder/websocket"
+)
+
+func TestFrameReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("splits multiple frames in one message", func(t *testing.T) {
+		t.Parallel()
+
+		data := simbawebsocket.AppendFrame(nil, []byte("one"))
+		data = simbawebsocket.AppendFrame(data, []byte{})
+		data = simbawebsocket.AppendFrame(data, []byte("three"))
+
+		frames, err := simbawebsocket.NewFrameReader(0).Feed(data)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("one"), {}, []byte("three")}, frames)
+	})
+
+	t.Run("reassembles frames split across messages", func(t *testing.T) {
+		t.Parallel()
+
+		data := simbawebsocket.AppendFrame(nil, []byte("hello world"))
+		reader := simbawebsocket.NewFrameReader(0)
+
+		frames, err := reader.Feed(data[:2])
+		assert.NoError(t, err)
+		assert.Len(t, frames, 0)
+
+		frames, err = reader.Feed(data[2:8])
+		assert.NoError(t, err)
+		assert.Len(t, frames, 0)
+		assert.Equal(t, 8, reader.Buffered())
+
+		frames, err = reader.Feed(data[8:])
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("hello world")}, frames)
+		assert.Equal(t, 0, reader.Buffered())
+	})
+
+	t.Run("rejects frames above the limit", func(t *testing.T) {
+		t.Parallel()
+
+		reader := simbawebsocket.NewFrameReader(4)
+		_, err := reader.Feed(simbawebsocket.AppendFrame(nil, []byte("too large")))
+		assert.True(t, errors.Is(err, simbawebsocket.ErrFrameTooLarge))
+		assert.Equal(t, 0, reader.Buffered())
+	})
+}
+
+func TestConnection_WriteFrames(t *testing.T) {
+	t.Parallel()
+
+	handler := simbawebsocket.Handler(
+		func() simbawebsocket.Callbacks[models.NoParams] {
+			return simbawebsocket.Callbacks[models.NoParams]{
+				OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+					return conn.WriteFrames(ctx, []byte("first"), []byte("second"))
+				},
+				OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+					return nil
+				},
+			}
+		},
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+	assert.NoError(t, err)
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msgType, data, err := conn.Read(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.MessageBinary, msgType)
+
+	frames, err := simbawebsocket.NewFrameReader(0).Feed(data)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("first"), []byte("second")}, frames)
+}
@@ -2,7 +2,9 @@ package websocket
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/sillen102/simba"
 	"github.com/sillen102/simba/auth"
@@ -11,7 +13,6 @@ import (
 	"github.com/sillen102/simba/simbaErrors"
 
 	"github.com/coder/websocket"
-	"github.com/google/uuid"
 )
 
 // Middleware wraps a context to enrich it before callback invocations.
@@ -40,16 +41,181 @@ func WithMiddleware(middleware ...Middleware) HandlerOption {
 	return middlewareOption{middleware: middleware}
 }
 
+// HandshakeHook extracts values from the handshake request and stores them in the context.
+// The returned context is used for all callbacks of the connection, including OnDisconnect.
+// Return an error to reject the handshake with a regular HTTP error response.
+type HandshakeHook func(ctx context.Context, r *http.Request) (context.Context, error)
+
+// handshakeOption implements HandlerOption for handshake hooks.
+type handshakeOption struct {
+	hooks []HandshakeHook
+}
+
+func (h handshakeOption) apply(handler any) {
+	if v, ok := handler.(interface{ setHandshake([]HandshakeHook) }); ok {
+		v.setHandshake(h.hooks)
+	}
+}
+
+// WithHandshake adds hooks that run once per connection during the handshake, after the
+// params have been parsed and before BeforeUpgrade. Values they store in the context, e.g.
+// a tenant taken from the subdomain or the selected locale, are seen by every callback.
+func WithHandshake(hooks ...HandshakeHook) HandlerOption {
+	return handshakeOption{hooks: hooks}
+}
+
+// applyHandshake runs the handshake hooks in order, returning the connection context.
+func applyHandshake(ctx context.Context, r *http.Request, hooks []HandshakeHook) (context.Context, error) {
+	for _, hook := range hooks {
+		var err error
+		ctx, err = hook(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// disconnectContext returns the base context for OnDisconnect. It is detached from the
+// connection context, which may be cancelled, but keeps the values stored by handshake hooks.
+func disconnectContext(ctx context.Context, hooks []HandshakeHook) context.Context {
+	if len(hooks) == 0 {
+		return context.Background()
+	}
+	return context.WithoutCancel(ctx)
+}
+
+// compressionOption implements HandlerOption for permessage-deflate compression.
+type compressionOption struct {
+	threshold int
+}
+
+func (c compressionOption) apply(handler any) {
+	if v, ok := handler.(interface{ setCompression(compressionOption) }); ok {
+		v.setCompression(c)
+	}
+}
+
+// WithCompression enables the permessage-deflate extension for the WebSocket handler.
+// Compression is negotiated during the handshake and only used if the client supports it.
+// Messages smaller than threshold bytes are sent uncompressed; a threshold of 0 uses the default.
+func WithCompression(threshold int) HandlerOption {
+	return compressionOption{threshold: threshold}
+}
+
+// ErrMessageTimeout is passed to OnError when an OnMessage invocation exceeds the
+// timeout configured with WithMessageTimeout.
+var ErrMessageTimeout = errors.New("message handler timed out")
+
+// messageTimeoutOption implements HandlerOption for per-message deadlines.
+type messageTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (m messageTimeoutOption) apply(handler any) {
+	if v, ok := handler.(interface{ setMessageTimeout(time.Duration) }); ok {
+		v.setMessageTimeout(m.timeout)
+	}
+}
+
+// WithMessageTimeout gives each OnMessage invocation a context with the given deadline.
+// Cancellation is cooperative: OnMessage runs on the read loop of the connection, so a handler
+// that ignores its context still delays the next message until it returns. If the handler fails
+// after the deadline has passed, the error wraps ErrMessageTimeout and is passed to OnError (or
+// closes the connection), while a handler that succeeds anyway isn't reported.
+// A timeout of 0 or less means no deadline.
+func WithMessageTimeout(timeout time.Duration) HandlerOption {
+	return messageTimeoutOption{timeout: timeout}
+}
+
+// withMessageTimeout runs fn with a context bounded by timeout, reporting ErrMessageTimeout
+// if fn fails after the deadline was exceeded.
+func withMessageTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.Join(ErrMessageTimeout, err)
+	}
+	return err
+}
+
+// acceptOptions returns the options used to upgrade the HTTP connection.
+func acceptOptions(compression *compressionOption) *websocket.AcceptOptions {
+	options := &websocket.AcceptOptions{
+		Subprotocols:         nil,
+		InsecureSkipVerify:   true, // Match gobwas behavior (no origin check)
+		OriginPatterns:       nil,
+		CompressionMode:      websocket.CompressionDisabled,
+		CompressionThreshold: 0,
+		OnPingReceived:       nil,
+		OnPongReceived:       nil,
+	}
+	if compression != nil {
+		options.CompressionMode = websocket.CompressionNoContextTakeover
+		options.CompressionThreshold = compression.threshold
+	}
+	return options
+}
+
 // CallbackHandlerFunc handles WebSocket connections with callbacks.
 type CallbackHandlerFunc[Params any] struct {
-	callbacks  Callbacks[Params]
-	middleware []Middleware `exhaustruct:"optional"`
+	callbacks      Callbacks[Params]
+	middleware     []Middleware       `exhaustruct:"optional"`
+	handshake      []HandshakeHook    `exhaustruct:"optional"`
+	compression    *compressionOption `exhaustruct:"optional"`
+	connections    connectionCounter  `exhaustruct:"optional"`
+	messageTimeout time.Duration      `exhaustruct:"optional"`
+	buffers        bufferSizes        `exhaustruct:"optional"`
 }
 
 func (h *CallbackHandlerFunc[Params]) setMiddleware(middleware []Middleware) {
 	h.middleware = middleware
 }
 
+func (h *CallbackHandlerFunc[Params]) setHandshake(hooks []HandshakeHook) {
+	h.handshake = hooks
+}
+
+func (h *CallbackHandlerFunc[Params]) setCompression(compression compressionOption) {
+	h.compression = &compression
+}
+
+func (h *CallbackHandlerFunc[Params]) setMaxConnections(limit int64) {
+	h.connections.limit = limit
+}
+
+func (h *CallbackHandlerFunc[Params]) setConnectionStats(stats *connectionCounters) {
+	h.connections.stats = stats
+}
+
+func (h *CallbackHandlerFunc[Params]) setMessageTimeout(timeout time.Duration) {
+	h.messageTimeout = timeout
+}
+
+func (h *CallbackHandlerFunc[Params]) setReadBufferSize(size int) {
+	h.buffers.read = size
+}
+
+func (h *CallbackHandlerFunc[Params]) setWriteBufferSize(size int) {
+	h.buffers.write = size
+}
+
+// ActiveConnections returns the number of connections currently open on the handler.
+func (h *CallbackHandlerFunc[Params]) ActiveConnections() int64 {
+	return h.connections.active.Load()
+}
+
+// RejectedConnections returns the number of upgrades the handler rejected because of its connection limit.
+func (h *CallbackHandlerFunc[Params]) RejectedConnections() int64 {
+	return h.connections.rejected.Load()
+}
+
 // Handler creates a handler that uses callbacks for WebSocket lifecycle events.
 //
 // Example usage:
@@ -100,16 +266,30 @@ func (h *CallbackHandlerFunc[Params]) ServeHTTP(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Store values from the handshake request in the connection context
+	ctx, err = applyHandshake(ctx, r, h.handshake)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	// Allow the handshake to be rejected with a proper HTTP response
+	if h.callbacks.BeforeUpgrade != nil {
+		if err := h.callbacks.BeforeUpgrade(h.applyMiddleware(ctx), r, params); err != nil {
+			simbaErrors.WriteError(w, r, err)
+			return
+		}
+	}
+
+	// Reject the upgrade if the handler has reached its connection limit
+	if !h.connections.acquire() {
+		simbaErrors.WriteError(w, r, errTooManyConnections)
+		return
+	}
+	defer h.connections.release()
+
 	// Upgrade the HTTP connection to WebSocket
-	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
-		Subprotocols:         nil,
-		InsecureSkipVerify:   true, // Match gobwas behavior (no origin check)
-		OriginPatterns:       nil,
-		CompressionMode:      0,
-		CompressionThreshold: 0,
-		OnPingReceived:       nil,
-		OnPongReceived:       nil,
-	})
+	conn, err := websocket.Accept(h.buffers.wrap(w), r, acceptOptions(h.compression))
 	if err != nil {
 		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
 			http.StatusBadRequest,
@@ -128,37 +308,42 @@ func (h *CallbackHandlerFunc[Params]) ServeHTTP(w http.ResponseWriter, r *http.R
 
 	// Handle the connection synchronously - the HTTP server runs each
 	// request in its own goroutine, so blocking here is correct
-	h.handleConnection(ctx, conn, params)
+	h.handleConnection(ctx, conn, r, params)
 }
 
 // handleConnection manages the lifecycle of a WebSocket connection.
-func (h *CallbackHandlerFunc[Params]) handleConnection(ctx context.Context, conn *websocket.Conn, params Params) {
+func (h *CallbackHandlerFunc[Params]) handleConnection(ctx context.Context, conn *websocket.Conn, r *http.Request, params Params) {
 	// Create a connection wrapper with unique ID
-	wsConn := &Connection{
-		ID:   uuid.New().String(),
-		conn: conn,
-	}
+	wsConn := newConnection(conn, r)
 
 	// Add connectionID to context (persistent for entire connection)
 	ctx = context.WithValue(ctx, simbaContext.ConnectionIDKey, wsConn.ID)
+	ctx = context.WithValue(ctx, connectionContextKey{}, wsConn)
 
 	// Always cleanup
 	var handlerErr error
 	defer func() {
+		closeAfterPanic(conn, handlerErr)
 		_ = conn.CloseNow()
 		if h.callbacks.OnDisconnect != nil {
-			// Use background context for cleanup as connection context may be cancelled
+			// Use a detached context for cleanup as connection context may be cancelled
 			// Apply middleware for OnDisconnect
-			disconnectCtx := h.applyMiddleware(context.Background())
+			disconnectCtx := h.applyMiddleware(disconnectContext(ctx, h.handshake))
 			disconnectCtx = context.WithValue(disconnectCtx, simbaContext.ConnectionIDKey, wsConn.ID)
-			h.callbacks.OnDisconnect(disconnectCtx, wsConn.ID, params, handlerErr)
+			disconnectCtx = context.WithValue(disconnectCtx, connectionContextKey{}, wsConn)
+			recoverDisconnect(disconnectCtx, func() {
+				h.callbacks.OnDisconnect(disconnectCtx, wsConn.ID, params, handlerErr)
+			})
 		}
 	}()
 
 	// Call OnConnect with middleware
 	if h.callbacks.OnConnect != nil {
 		connectCtx := h.applyMiddleware(ctx)
-		if err := h.callbacks.OnConnect(connectCtx, wsConn, params); err != nil {
+		err := recoverCallback(connectCtx, func() error {
+			return h.callbacks.OnConnect(connectCtx, wsConn, params)
+		})
+		if err != nil {
 			handlerErr = err
 			return
 		}
@@ -181,7 +366,8 @@ func (h *CallbackHandlerFunc[Params]) handleConnection(ctx context.Context, conn
 			// Other errors
 			if h.callbacks.OnError != nil {
 				errorCtx := h.applyMiddleware(ctx)
-				if h.callbacks.OnError(errorCtx, wsConn, err) {
+				var resume bool
+				if resume, err = h.onError(errorCtx, wsConn, err); resume {
 					continue
 				}
 			}
@@ -191,11 +377,17 @@ func (h *CallbackHandlerFunc[Params]) handleConnection(ctx context.Context, conn
 
 		// Call OnMessage with middleware (fresh context per message)
 		messageCtx := h.applyMiddleware(ctx)
-		if err := h.callbacks.OnMessage(messageCtx, wsConn, msg); err != nil {
+		err = withMessageTimeout(messageCtx, h.messageTimeout, func(ctx context.Context) error {
+			return recoverCallback(ctx, func() error {
+				return h.callbacks.OnMessage(ctx, wsConn, msg)
+			})
+		})
+		if err != nil {
 			// Check if OnError wants to continue
 			if h.callbacks.OnError != nil {
 				errorCtx := h.applyMiddleware(ctx)
-				if h.callbacks.OnError(errorCtx, wsConn, err) {
+				var resume bool
+				if resume, err = h.onError(errorCtx, wsConn, err); resume {
 					continue
 				}
 			}
@@ -205,6 +397,20 @@ func (h *CallbackHandlerFunc[Params]) handleConnection(ctx context.Context, conn
 	}
 }
 
+// onError calls the OnError callback and reports whether to keep the connection open. If the
+// callback panics, the connection is closed and the panic is joined to err.
+func (h *CallbackHandlerFunc[Params]) onError(ctx context.Context, conn *Connection, err error) (bool, error) {
+	var resume bool
+	panicErr := recoverCallback(ctx, func() error {
+		resume = h.callbacks.OnError(ctx, conn, err)
+		return nil
+	})
+	if panicErr != nil {
+		return false, errors.Join(err, panicErr)
+	}
+	return resume, err
+}
+
 // applyMiddleware applies the middleware chain to the context.
 func (h *CallbackHandlerFunc[Params]) applyMiddleware(ctx context.Context) context.Context {
 	for _, mw := range h.middleware {
@@ -248,15 +454,58 @@ func (h *CallbackHandlerFunc[Params]) GetAuthHandler() any {
 
 // AuthCallbackHandlerFunc handles authenticated WebSocket connections with callbacks.
 type AuthCallbackHandlerFunc[Params, AuthModel any] struct {
-	callbacks   AuthCallbacks[Params, AuthModel]
-	authHandler auth.Handler[AuthModel]
-	middleware  []Middleware `exhaustruct:"optional"`
+	callbacks      AuthCallbacks[Params, AuthModel]
+	authHandler    auth.Handler[AuthModel]
+	middleware     []Middleware       `exhaustruct:"optional"`
+	handshake      []HandshakeHook    `exhaustruct:"optional"`
+	compression    *compressionOption `exhaustruct:"optional"`
+	connections    connectionCounter  `exhaustruct:"optional"`
+	messageTimeout time.Duration      `exhaustruct:"optional"`
+	buffers        bufferSizes        `exhaustruct:"optional"`
 }
 
 func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setMiddleware(middleware []Middleware) {
 	h.middleware = middleware
 }
 
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setHandshake(hooks []HandshakeHook) {
+	h.handshake = hooks
+}
+
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setCompression(compression compressionOption) {
+	h.compression = &compression
+}
+
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setMaxConnections(limit int64) {
+	h.connections.limit = limit
+}
+
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setConnectionStats(stats *connectionCounters) {
+	h.connections.stats = stats
+}
+
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setMessageTimeout(timeout time.Duration) {
+	h.messageTimeout = timeout
+}
+
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setReadBufferSize(size int) {
+	h.buffers.read = size
+}
+
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) setWriteBufferSize(size int) {
+	h.buffers.write = size
+}
+
+// ActiveConnections returns the number of connections currently open on the handler.
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) ActiveConnections() int64 {
+	return h.connections.active.Load()
+}
+
+// RejectedConnections returns the number of upgrades the handler rejected because of its connection limit.
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) RejectedConnections() int64 {
+	return h.connections.rejected.Load()
+}
+
 // AuthHandler creates an authenticated handler that uses callbacks for WebSocket lifecycle events.
 //
 // Example usage:
@@ -322,6 +571,7 @@ func (h *AuthCallbackHandlerFunc[Params, AuthModel]) ServeHTTP(w http.ResponseWr
 		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(statusCode, errorMessage, err))
 		return
 	}
+	ctx = simbaContext.WithPrincipal(ctx, authModel)
 
 	// Parse and validate params before upgrading connection
 	params, err := simba.ParseAndValidateParams[Params](r)
@@ -330,16 +580,30 @@ func (h *AuthCallbackHandlerFunc[Params, AuthModel]) ServeHTTP(w http.ResponseWr
 		return
 	}
 
+	// Store values from the handshake request in the connection context
+	ctx, err = applyHandshake(ctx, r, h.handshake)
+	if err != nil {
+		simbaErrors.WriteError(w, r, err)
+		return
+	}
+
+	// Allow the handshake to be rejected with a proper HTTP response
+	if h.callbacks.BeforeUpgrade != nil {
+		if err := h.callbacks.BeforeUpgrade(h.applyMiddleware(ctx), r, params, authModel); err != nil {
+			simbaErrors.WriteError(w, r, err)
+			return
+		}
+	}
+
+	// Reject the upgrade if the handler has reached its connection limit
+	if !h.connections.acquire() {
+		simbaErrors.WriteError(w, r, errTooManyConnections)
+		return
+	}
+	defer h.connections.release()
+
 	// Upgrade the HTTP connection to WebSocket
-	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
-		Subprotocols:         nil,
-		InsecureSkipVerify:   true, // Match gobwas behavior (no origin check)
-		OriginPatterns:       nil,
-		CompressionMode:      0,
-		CompressionThreshold: 0,
-		OnPingReceived:       nil,
-		OnPongReceived:       nil,
-	})
+	conn, err := websocket.Accept(h.buffers.wrap(w), r, acceptOptions(h.compression))
 	if err != nil {
 		simbaErrors.WriteError(w, r, simbaErrors.NewSimbaError(
 			http.StatusBadRequest,
@@ -358,37 +622,43 @@ func (h *AuthCallbackHandlerFunc[Params, AuthModel]) ServeHTTP(w http.ResponseWr
 
 	// Handle the connection synchronously - the HTTP server runs each
 	// request in its own goroutine, so blocking here is correct
-	h.handleConnection(ctx, conn, params, authModel)
+	h.handleConnection(ctx, conn, r, params, authModel)
 }
 
 // handleConnection manages the lifecycle of an authenticated WebSocket connection.
-func (h *AuthCallbackHandlerFunc[Params, AuthModel]) handleConnection(ctx context.Context, conn *websocket.Conn, params Params, auth AuthModel) {
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) handleConnection(ctx context.Context, conn *websocket.Conn, r *http.Request, params Params, auth AuthModel) {
 	// Create a connection wrapper with unique ID
-	wsConn := &Connection{
-		ID:   uuid.New().String(),
-		conn: conn,
-	}
+	wsConn := newConnection(conn, r)
 
 	// Add connectionID to context (persistent for entire connection)
 	ctx = context.WithValue(ctx, simbaContext.ConnectionIDKey, wsConn.ID)
+	ctx = context.WithValue(ctx, connectionContextKey{}, wsConn)
 
 	// Always cleanup
 	var handlerErr error
 	defer func() {
+		closeAfterPanic(conn, handlerErr)
 		_ = conn.CloseNow()
 		if h.callbacks.OnDisconnect != nil {
-			// Use background context for cleanup as connection context may be cancelled
+			// Use a detached context for cleanup as connection context may be cancelled
 			// Apply middleware for OnDisconnect
-			disconnectCtx := h.applyMiddleware(context.Background())
+			disconnectCtx := h.applyMiddleware(disconnectContext(ctx, h.handshake))
 			disconnectCtx = context.WithValue(disconnectCtx, simbaContext.ConnectionIDKey, wsConn.ID)
-			h.callbacks.OnDisconnect(disconnectCtx, wsConn.ID, params, auth, handlerErr)
+			disconnectCtx = context.WithValue(disconnectCtx, connectionContextKey{}, wsConn)
+			disconnectCtx = simbaContext.WithPrincipal(disconnectCtx, auth)
+			recoverDisconnect(disconnectCtx, func() {
+				h.callbacks.OnDisconnect(disconnectCtx, wsConn.ID, params, auth, handlerErr)
+			})
 		}
 	}()
 
 	// Call OnConnect with middleware
 	if h.callbacks.OnConnect != nil {
 		connectCtx := h.applyMiddleware(ctx)
-		if err := h.callbacks.OnConnect(connectCtx, wsConn, params, auth); err != nil {
+		err := recoverCallback(connectCtx, func() error {
+			return h.callbacks.OnConnect(connectCtx, wsConn, params, auth)
+		})
+		if err != nil {
 			handlerErr = err
 			return
 		}
@@ -411,7 +681,8 @@ func (h *AuthCallbackHandlerFunc[Params, AuthModel]) handleConnection(ctx contex
 			// Other errors
 			if h.callbacks.OnError != nil {
 				errorCtx := h.applyMiddleware(ctx)
-				if h.callbacks.OnError(errorCtx, wsConn, err) {
+				var resume bool
+				if resume, err = h.onError(errorCtx, wsConn, err); resume {
 					continue
 				}
 			}
@@ -421,11 +692,17 @@ func (h *AuthCallbackHandlerFunc[Params, AuthModel]) handleConnection(ctx contex
 
 		// Call OnMessage with middleware (fresh context per message)
 		messageCtx := h.applyMiddleware(ctx)
-		if err := h.callbacks.OnMessage(messageCtx, wsConn, msg, auth); err != nil {
+		err = withMessageTimeout(messageCtx, h.messageTimeout, func(ctx context.Context) error {
+			return recoverCallback(ctx, func() error {
+				return h.callbacks.OnMessage(ctx, wsConn, msg, auth)
+			})
+		})
+		if err != nil {
 			// Check if OnError wants to continue
 			if h.callbacks.OnError != nil {
 				errorCtx := h.applyMiddleware(ctx)
-				if h.callbacks.OnError(errorCtx, wsConn, err) {
+				var resume bool
+				if resume, err = h.onError(errorCtx, wsConn, err); resume {
 					continue
 				}
 			}
@@ -435,6 +712,20 @@ func (h *AuthCallbackHandlerFunc[Params, AuthModel]) handleConnection(ctx contex
 	}
 }
 
+// onError calls the OnError callback and reports whether to keep the connection open. If the
+// callback panics, the connection is closed and the panic is joined to err.
+func (h *AuthCallbackHandlerFunc[Params, AuthModel]) onError(ctx context.Context, conn *Connection, err error) (bool, error) {
+	var resume bool
+	panicErr := recoverCallback(ctx, func() error {
+		resume = h.callbacks.OnError(ctx, conn, err)
+		return nil
+	})
+	if panicErr != nil {
+		return false, errors.Join(err, panicErr)
+	}
+	return resume, err
+}
+
 // applyMiddleware applies the middleware chain to the context.
 func (h *AuthCallbackHandlerFunc[Params, AuthModel]) applyMiddleware(ctx context.Context) context.Context {
 	for _, mw := range h.middleware {
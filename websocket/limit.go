@@ -0,0 +1,111 @@
+package websocket
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/sillen102/simba/simbaErrors"
+)
+
+// ConnectionCounter is implemented by WebSocket handlers and reports the number of
+// connections currently open on the handler and of upgrades rejected by its connection
+// limit. It can be used to export the counts as metrics, e.g. with OpenTelemetry
+// observable instruments.
+type ConnectionCounter interface {
+	ActiveConnections() int64
+	RejectedConnections() int64
+}
+
+// ConnectionStats holds the number of connections open on the handlers reporting to a registry with
+// [WithConnectionStats], and of the upgrades they rejected because of their connection limit.
+type ConnectionStats struct {
+	Active   int64 `json:"active"`
+	Rejected int64 `json:"rejected"`
+}
+
+// connectionCounters counts the connections of the handlers reporting to a registry.
+type connectionCounters struct {
+	active   atomic.Int64
+	rejected atomic.Int64
+}
+
+// errTooManyConnections is returned when a handler has reached its connection limit.
+var errTooManyConnections = simbaErrors.NewSimbaError(
+	http.StatusServiceUnavailable,
+	"too many connections",
+	nil,
+)
+
+// maxConnectionsOption implements HandlerOption for limiting concurrent connections.
+type maxConnectionsOption struct {
+	limit int64
+}
+
+func (m maxConnectionsOption) apply(handler any) {
+	if v, ok := handler.(interface{ setMaxConnections(int64) }); ok {
+		v.setMaxConnections(m.limit)
+	}
+}
+
+// WithMaxConnections limits the number of concurrent connections on the WebSocket handler.
+// Upgrades beyond the limit are rejected with 503 Service Unavailable before switching protocols.
+// A limit of 0 or less means no limit.
+func WithMaxConnections(n int) HandlerOption {
+	return maxConnectionsOption{limit: int64(n)}
+}
+
+// connectionStatsOption implements HandlerOption for reporting connection counts to a registry.
+type connectionStatsOption struct {
+	registry *ConnectionRegistry
+}
+
+func (c connectionStatsOption) apply(handler any) {
+	if v, ok := handler.(interface{ setConnectionStats(*connectionCounters) }); ok && c.registry != nil {
+		v.setConnectionStats(&c.registry.connectionStats)
+	}
+}
+
+// WithConnectionStats reports the connections open on the WebSocket handler, and the upgrades rejected
+// by its connection limit, to the registry, which sums them over all handlers reporting to it in
+// [ConnectionRegistry.ConnectionStats]. Connections are counted whether or not they are added to the
+// registry.
+func WithConnectionStats(registry *ConnectionRegistry) HandlerOption {
+	return connectionStatsOption{registry: registry}
+}
+
+// connectionCounter tracks the connections open on a handler and enforces its limit.
+type connectionCounter struct {
+	limit    int64
+	active   atomic.Int64
+	rejected atomic.Int64
+	// stats are the counters of the registry the handler reports to, if any
+	stats *connectionCounters
+}
+
+// acquire reserves a connection slot. Returns false if the limit has been reached.
+func (c *connectionCounter) acquire() bool {
+	for {
+		active := c.active.Load()
+		if c.limit > 0 && active >= c.limit {
+			c.rejected.Add(1)
+			if c.stats != nil {
+				c.stats.rejected.Add(1)
+			}
+			return false
+		}
+		if c.active.CompareAndSwap(active, active+1) {
+			if c.stats != nil {
+				c.stats.active.Add(1)
+			}
+			return true
+		}
+	}
+}
+
+// release frees a connection slot reserved by acquire.
+func (c *connectionCounter) release() {
+	c.active.Add(-1)
+	if c.stats != nil {
+		c.stats.active.Add(-1)
+	}
+}
@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/coder/websocket"
+	"github.com/sillen102/simba/logging"
+	"github.com/sillen102/simba/simbaContext"
+)
+
+// ErrCallbackPanic is matched by the error a panicking callback is converted to.
+var ErrCallbackPanic = errors.New("callback panicked")
+
+// PanicError is the error a panic in OnConnect, OnMessage or OnError is converted to. It is passed to
+// OnError (for OnMessage) and OnDisconnect, and matches ErrCallbackPanic as well as the panic value
+// when that is an error.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrCallbackPanic, e.Value)
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrCallbackPanic, err}
+	}
+	return []error{ErrCallbackPanic}
+}
+
+// recoverCallback calls fn, converting a panic into a *PanicError that is logged and returned.
+func recoverCallback(ctx context.Context, fn func() error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr := &PanicError{Value: value, Stack: debug.Stack()}
+			logPanic(ctx, panicErr)
+			err = panicErr
+		}
+	}()
+	return fn()
+}
+
+// recoverDisconnect calls the OnDisconnect callback fn, logging a panic instead of letting it
+// escape the connection goroutine.
+func recoverDisconnect(ctx context.Context, fn func()) {
+	defer func() {
+		if value := recover(); value != nil {
+			logPanic(ctx, &PanicError{Value: value, Stack: debug.Stack()})
+		}
+	}()
+	fn()
+}
+
+// logPanic logs a recovered panic with the connection it happened on.
+func logPanic(ctx context.Context, err *PanicError) {
+	logging.From(ctx).Error("recovered from panic in WebSocket callback",
+		"error", fmt.Sprint(err.Value),
+		"stacktrace", string(err.Stack),
+		"connectionId", ctx.Value(simbaContext.ConnectionIDKey),
+	)
+}
+
+// closeAfterPanic closes the connection with an internal error status if err was caused by a panic,
+// so the client is told why the connection ends.
+func closeAfterPanic(conn *websocket.Conn, err error) {
+	if errors.Is(err, ErrCallbackPanic) {
+		_ = conn.Close(websocket.StatusInternalError, "internal error")
+	}
+}
@@ -0,0 +1,366 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrConnectionNotFound is returned when sending to a connection that is not registered,
+// typically because it has already disconnected.
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// ConnectionRegistry keeps track of the active connections of a single instance
+// and organizes them into named groups (e.g. chat rooms or topics).
+// All methods are safe for concurrent use, so group membership can be changed
+// from any goroutine (e.g. a pub/sub consumer), not only from the connection's callbacks.
+type ConnectionRegistry struct {
+	mu          sync.RWMutex
+	connections map[string]*Connection
+	groups      map[string]map[string]*Connection
+
+	broadcastLimit BroadcastLimit                   `exhaustruct:"optional"`
+	groupLimits    map[string]BroadcastLimit        `exhaustruct:"optional"`
+	limiters       map[limiterKey]*broadcastLimiter `exhaustruct:"optional"`
+	broadcasts     broadcastCounters                `exhaustruct:"optional"`
+
+	connectionStats connectionCounters `exhaustruct:"optional"`
+}
+
+// limiterKey identifies the broadcast limiter of a connection in a group.
+type limiterKey struct {
+	group  string
+	connID string
+}
+
+// NewConnectionRegistry creates an empty connection registry.
+func NewConnectionRegistry() *ConnectionRegistry {
+	return &ConnectionRegistry{
+		mu:          sync.RWMutex{},
+		connections: make(map[string]*Connection),
+		groups:      make(map[string]map[string]*Connection),
+	}
+}
+
+// Add registers a connection. It is typically called from OnConnect.
+func (r *ConnectionRegistry) Add(conn *Connection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.connections[conn.ID] = conn
+}
+
+// Remove unregisters a connection and removes it from all groups.
+// It is typically called from OnDisconnect.
+func (r *ConnectionRegistry) Remove(connID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.connections, connID)
+	for group := range r.groups {
+		r.leave(group, connID)
+	}
+}
+
+// Get returns the connection with the given ID.
+func (r *ConnectionRegistry) Get(connID string) (*Connection, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	conn, ok := r.connections[connID]
+	return conn, ok
+}
+
+// SendText sends a text message to the connection with the given ID.
+// Returns ErrConnectionNotFound if the connection is not registered.
+func (r *ConnectionRegistry) SendText(ctx context.Context, connID string, msg string) error {
+	conn, ok := r.Get(connID)
+	if !ok {
+		return ErrConnectionNotFound
+	}
+	return conn.WriteText(ctx, msg)
+}
+
+// SendBinary sends a binary message to the connection with the given ID.
+// Returns ErrConnectionNotFound if the connection is not registered.
+func (r *ConnectionRegistry) SendBinary(ctx context.Context, connID string, data []byte) error {
+	conn, ok := r.Get(connID)
+	if !ok {
+		return ErrConnectionNotFound
+	}
+	return conn.WriteBinary(ctx, data)
+}
+
+// SendJSON marshals v to JSON and sends it to the connection with the given ID.
+// Returns ErrConnectionNotFound if the connection is not registered.
+func (r *ConnectionRegistry) SendJSON(ctx context.Context, connID string, v any) error {
+	conn, ok := r.Get(connID)
+	if !ok {
+		return ErrConnectionNotFound
+	}
+	return conn.WriteJSON(ctx, v)
+}
+
+// Shutdown drains the registered connections, typically when the server is shutting down so clients
+// can reconnect to another instance. If msg is not nil it is sent as JSON to every connection, after
+// which every connection is closed with status 1001 (going away). Connections are drained concurrently
+// and closed immediately once the context is done. Errors sending the message are returned joined.
+func (r *ConnectionRegistry) Shutdown(ctx context.Context, msg any) error {
+	r.mu.RLock()
+	connections := make([]*Connection, 0, len(r.connections))
+	for _, conn := range r.connections {
+		connections = append(connections, conn)
+	}
+	r.mu.RUnlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, conn := range connections {
+		wg.Go(func() {
+			if msg != nil {
+				if err := conn.WriteJSON(ctx, msg); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("connection %s: %w", conn.ID, err))
+					mu.Unlock()
+				}
+			}
+			conn.closeGoingAway(ctx, "server shutting down")
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// ShutdownHook returns a hook that calls [ConnectionRegistry.Shutdown] with msg, for use with
+// simba's Application.RegisterShutdownHook:
+//
+//	app.RegisterShutdownHook(registry.ShutdownHook(map[string]string{"type": "server_restarting"}))
+func (r *ConnectionRegistry) ShutdownHook(msg any) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return r.Shutdown(ctx, msg)
+	}
+}
+
+// Count returns the number of registered connections.
+func (r *ConnectionRegistry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.connections)
+}
+
+// Join adds a connection to a group. The connection is registered if it isn't already.
+func (r *ConnectionRegistry) Join(group string, conn *Connection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.connections[conn.ID] = conn
+	r.join(group, conn)
+}
+
+// JoinByID adds a registered connection to a group by its ID.
+// Returns false if no connection with the ID is registered.
+func (r *ConnectionRegistry) JoinByID(group string, connID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conn, ok := r.connections[connID]
+	if !ok {
+		return false
+	}
+	r.join(group, conn)
+	return true
+}
+
+// Leave removes a connection from a group.
+func (r *ConnectionRegistry) Leave(group string, connID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leave(group, connID)
+}
+
+// MoveToGroup atomically moves a registered connection from one group to another,
+// so concurrent readers never observe it in both or neither group.
+// Returns false if no connection with the ID is registered.
+func (r *ConnectionRegistry) MoveToGroup(connID string, from string, to string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conn, ok := r.connections[connID]
+	if !ok {
+		return false
+	}
+	r.leave(from, connID)
+	r.join(to, conn)
+	return true
+}
+
+// join adds a connection to a group. The caller must hold the write lock.
+func (r *ConnectionRegistry) join(group string, conn *Connection) {
+	members, ok := r.groups[group]
+	if !ok {
+		members = make(map[string]*Connection)
+		r.groups[group] = members
+	}
+	members[conn.ID] = conn
+}
+
+// leave removes a connection from a group. The caller must hold the write lock.
+func (r *ConnectionRegistry) leave(group string, connID string) {
+	members, ok := r.groups[group]
+	if !ok {
+		return
+	}
+	delete(members, connID)
+	if len(members) == 0 {
+		delete(r.groups, group)
+	}
+
+	key := limiterKey{group: group, connID: connID}
+	if limiter, ok := r.limiters[key]; ok {
+		limiter.stop()
+		delete(r.limiters, key)
+	}
+}
+
+// GroupCount returns the number of connections in a group.
+func (r *ConnectionRegistry) GroupCount(group string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.groups[group])
+}
+
+// GroupMembers returns a snapshot of the connections in a group.
+// The order of the returned connections is unspecified.
+func (r *ConnectionRegistry) GroupMembers(group string) []*Connection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := make([]*Connection, 0, len(r.groups[group]))
+	for _, conn := range r.groups[group] {
+		members = append(members, conn)
+	}
+	return members
+}
+
+// ForEachInGroup calls fn for each connection in a group until fn returns false.
+// It iterates over a snapshot of the group, so fn may safely call other registry
+// methods, such as Leave.
+func (r *ConnectionRegistry) ForEachInGroup(group string, fn func(conn *Connection) bool) {
+	for _, conn := range r.GroupMembers(group) {
+		if !fn(conn) {
+			return
+		}
+	}
+}
+
+// BroadcastToGroup sends a text message to every connection in a group.
+// Delivery continues if sending to a connection fails; all errors are returned joined.
+// Messages beyond the [BroadcastLimit] of the group are dropped or coalesced without an error.
+func (r *ConnectionRegistry) BroadcastToGroup(ctx context.Context, group string, msg string) error {
+	var errs []error
+	for conn, limiter := range r.broadcastTargets(group) {
+		if limiter != nil && !limiter.allow(ctx, conn, msg) {
+			continue
+		}
+		if err := conn.WriteText(ctx, msg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.broadcasts.sent.Add(1)
+	}
+	return errors.Join(errs...)
+}
+
+// SetBroadcastLimit sets the rate limit of messages broadcast to each connection of the groups without
+// a limit of their own. A zero limit removes it.
+func (r *ConnectionRegistry) SetBroadcastLimit(limit BroadcastLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.broadcastLimit = limit
+	r.resetLimiters(func(group string) bool {
+		_, ok := r.groupLimits[group]
+		return !ok
+	})
+}
+
+// SetGroupBroadcastLimit sets the rate limit of messages broadcast to each connection of a group, overriding
+// the limit set with [ConnectionRegistry.SetBroadcastLimit]. A zero limit disables limiting for the group.
+func (r *ConnectionRegistry) SetGroupBroadcastLimit(group string, limit BroadcastLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.groupLimits == nil {
+		r.groupLimits = make(map[string]BroadcastLimit)
+	}
+	r.groupLimits[group] = limit
+	r.resetLimiters(func(g string) bool { return g == group })
+}
+
+// GroupBroadcastLimit returns the rate limit of messages broadcast to each connection of a group.
+func (r *ConnectionRegistry) GroupBroadcastLimit(group string) BroadcastLimit {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.groupLimit(group)
+}
+
+// BroadcastStats returns the number of messages broadcast to groups that were sent, dropped and coalesced.
+func (r *ConnectionRegistry) BroadcastStats() BroadcastStats {
+	return BroadcastStats{
+		Sent:      r.broadcasts.sent.Load(),
+		Dropped:   r.broadcasts.dropped.Load(),
+		Coalesced: r.broadcasts.coalesced.Load(),
+	}
+}
+
+// ConnectionStats returns the number of connections open on the handlers reporting to the registry with
+// [WithConnectionStats], and of the upgrades they rejected because of their connection limit.
+func (r *ConnectionRegistry) ConnectionStats() ConnectionStats {
+	return ConnectionStats{
+		Active:   r.connectionStats.active.Load(),
+		Rejected: r.connectionStats.rejected.Load(),
+	}
+}
+
+// groupLimit returns the broadcast limit of a group. The caller must hold the lock.
+func (r *ConnectionRegistry) groupLimit(group string) BroadcastLimit {
+	if limit, ok := r.groupLimits[group]; ok {
+		return limit
+	}
+	return r.broadcastLimit
+}
+
+// resetLimiters discards the limiters of the groups matched by match, so they are recreated with the
+// current limit. The caller must hold the write lock.
+func (r *ConnectionRegistry) resetLimiters(match func(group string) bool) {
+	for key, limiter := range r.limiters {
+		if match(key.group) {
+			limiter.stop()
+			delete(r.limiters, key)
+		}
+	}
+}
+
+// broadcastTargets returns a snapshot of the connections in a group with their broadcast limiter,
+// which is nil if the group has no limit.
+func (r *ConnectionRegistry) broadcastTargets(group string) map[*Connection]*broadcastLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limit := r.groupLimit(group)
+	targets := make(map[*Connection]*broadcastLimiter, len(r.groups[group]))
+	for connID, conn := range r.groups[group] {
+		if !limit.enabled() {
+			targets[conn] = nil
+			continue
+		}
+
+		key := limiterKey{group: group, connID: connID}
+		limiter, ok := r.limiters[key]
+		if !ok {
+			if r.limiters == nil {
+				r.limiters = make(map[limiterKey]*broadcastLimiter)
+			}
+			limiter = newBroadcastLimiter(limit, &r.broadcasts)
+			r.limiters[key] = limiter
+		}
+		targets[conn] = limiter
+	}
+	return targets
+}
@@ -0,0 +1,391 @@
+package websocket_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/models"
+	"github.com/sillen102/simba/simbaTest/assert"
+	simbawebsocket "github.com/sillen102/simba/websocket"
+
+	"github.com/coder/websocket"
+)
+
+func TestConnectionRegistry(t *testing.T) {
+	t.Parallel()
+
+	memberIDs := func(members []*simbawebsocket.Connection) []string {
+		ids := make([]string, 0, len(members))
+		for _, conn := range members {
+			ids = append(ids, conn.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	t.Run("group members", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		registry.Join("room", &simbawebsocket.Connection{ID: "a"})
+		registry.Join("room", &simbawebsocket.Connection{ID: "b"})
+		registry.Join("other", &simbawebsocket.Connection{ID: "c"})
+
+		assert.Equal(t, 3, registry.Count())
+		assert.Equal(t, 2, registry.GroupCount("room"))
+		assert.Equal(t, []string{"a", "b"}, memberIDs(registry.GroupMembers("room")))
+		assert.Len(t, registry.GroupMembers("missing"), 0)
+
+		registry.Leave("room", "a")
+		assert.Equal(t, []string{"b"}, memberIDs(registry.GroupMembers("room")))
+
+		registry.Remove("b")
+		assert.Equal(t, 0, registry.GroupCount("room"))
+		_, ok := registry.Get("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("for each in group", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		registry.Join("room", &simbawebsocket.Connection{ID: "a"})
+		registry.Join("room", &simbawebsocket.Connection{ID: "b"})
+
+		var visited []string
+		registry.ForEachInGroup("room", func(conn *simbawebsocket.Connection) bool {
+			visited = append(visited, conn.ID)
+			registry.Leave("room", conn.ID)
+			return true
+		})
+		sort.Strings(visited)
+		assert.Equal(t, []string{"a", "b"}, visited)
+		assert.Equal(t, 0, registry.GroupCount("room"))
+
+		registry.Join("room", &simbawebsocket.Connection{ID: "a"})
+		registry.Join("room", &simbawebsocket.Connection{ID: "b"})
+
+		calls := 0
+		registry.ForEachInGroup("room", func(conn *simbawebsocket.Connection) bool {
+			calls++
+			return false
+		})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("join and move by ID", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		registry.Add(&simbawebsocket.Connection{ID: "a"})
+
+		assert.True(t, registry.JoinByID("lobby", "a"))
+		assert.False(t, registry.JoinByID("lobby", "missing"))
+
+		assert.True(t, registry.MoveToGroup("a", "lobby", "room"))
+		assert.Equal(t, 0, registry.GroupCount("lobby"))
+		assert.Equal(t, []string{"a"}, memberIDs(registry.GroupMembers("room")))
+		assert.False(t, registry.MoveToGroup("missing", "room", "lobby"))
+	})
+
+	t.Run("move between groups from many goroutines", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		for i := range 50 {
+			registry.Join("a", &simbawebsocket.Connection{ID: fmt.Sprintf("conn-%d", i)})
+		}
+
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Go(func() {
+				connID := fmt.Sprintf("conn-%d", i)
+				for range 100 {
+					registry.MoveToGroup(connID, "a", "b")
+					registry.MoveToGroup(connID, "b", "a")
+				}
+			})
+			wg.Go(func() {
+				for range 100 {
+					registry.ForEachInGroup("b", func(conn *simbawebsocket.Connection) bool {
+						return conn.ID != ""
+					})
+				}
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, 50, registry.GroupCount("a"))
+		assert.Equal(t, 0, registry.GroupCount("b"))
+	})
+
+	t.Run("broadcast to group", func(t *testing.T) {
+		t.Parallel()
+
+		registry := simbawebsocket.NewConnectionRegistry()
+		joined := make(chan struct{}, 2)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						registry.Join("room", conn)
+						joined <- struct{}{}
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return nil
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						registry.Remove(connID)
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		clients := make([]*websocket.Conn, 0, 2)
+		for range 2 {
+			conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+			assert.NoError(t, err)
+			defer conn.CloseNow()
+			clients = append(clients, conn)
+			<-joined
+		}
+
+		err := registry.BroadcastToGroup(context.Background(), "room", "hello")
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		for _, client := range clients {
+			_, msg, err := client.Read(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, "hello", string(msg))
+		}
+	})
+}
+
+func TestConnectionRegistry_Send(t *testing.T) {
+	t.Parallel()
+
+	registry := simbawebsocket.NewConnectionRegistry()
+	connected := make(chan string, 1)
+
+	handler := simbawebsocket.Handler(
+		func() simbawebsocket.Callbacks[models.NoParams] {
+			return simbawebsocket.Callbacks[models.NoParams]{
+				OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+					registry.Add(conn)
+					connected <- conn.ID
+					return nil
+				},
+				OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+					return nil
+				},
+				OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+					registry.Remove(connID)
+				},
+			}
+		},
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+	assert.NoError(t, err)
+	defer client.CloseNow()
+
+	connID := <-connected
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, registry.SendText(ctx, connID, "text"))
+	msgType, msg, err := client.Read(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.MessageText, msgType)
+	assert.Equal(t, "text", string(msg))
+
+	assert.NoError(t, registry.SendBinary(ctx, connID, []byte{1, 2}))
+	msgType, msg, err = client.Read(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.MessageBinary, msgType)
+	assert.Equal(t, []byte{1, 2}, msg)
+
+	assert.NoError(t, registry.SendJSON(ctx, connID, map[string]string{"hello": "world"}))
+	_, msg, err = client.Read(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"hello":"world"}`, string(msg))
+
+	err = registry.SendText(ctx, "missing", "text")
+	assert.True(t, errors.Is(err, simbawebsocket.ErrConnectionNotFound))
+}
+
+func TestConnectionRegistry_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	registry := simbawebsocket.NewConnectionRegistry()
+	connected := make(chan string, 2)
+
+	handler := simbawebsocket.Handler(
+		func() simbawebsocket.Callbacks[models.NoParams] {
+			return simbawebsocket.Callbacks[models.NoParams]{
+				OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+					registry.Add(conn)
+					connected <- conn.ID
+					return nil
+				},
+				OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+					return nil
+				},
+				OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+					registry.Remove(connID)
+				},
+			}
+		},
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	clients := make([]*websocket.Conn, 0, 2)
+	for range 2 {
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+		clients = append(clients, conn)
+		<-connected
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The clients must read for the close handshake to complete, so they read while the registry drains
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Go(func() {
+			_, msg, err := client.Read(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"type":"server_restarting"}`, string(msg))
+
+			_, _, err = client.Read(ctx)
+			assert.Equal(t, websocket.StatusGoingAway, websocket.CloseStatus(err))
+		})
+	}
+
+	hook := registry.ShutdownHook(map[string]string{"type": "server_restarting"})
+	assert.NoError(t, hook(ctx))
+	wg.Wait()
+}
+
+func TestConnectionRegistry_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	registry := simbawebsocket.NewConnectionRegistry()
+	connected := make(chan struct{})
+
+	handler := simbawebsocket.Handler(
+		func() simbawebsocket.Callbacks[models.NoParams] {
+			return simbawebsocket.Callbacks[models.NoParams]{
+				OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+					conn.Set("user", "secret")
+					registry.Join("room", conn)
+					registry.Join("lobby", conn)
+					close(connected)
+					return nil
+				},
+				OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+					return nil
+				},
+			}
+		},
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], &websocket.DialOptions{
+		HTTPHeader: http.Header{"User-Agent": {"snapshot-test"}},
+	})
+	assert.NoError(t, err)
+	defer conn.CloseNow()
+	<-connected
+
+	registry.Join("room", &simbawebsocket.Connection{ID: "other"})
+
+	authHandler := auth.BearerAuth(
+		func(ctx context.Context, token string) (WSAuthModel, error) {
+			if token == "admin-token" {
+				return WSAuthModel{UserID: 1, Username: "admin"}, nil
+			}
+			return WSAuthModel{}, fmt.Errorf("invalid token")
+		},
+		auth.BearerAuthConfig{Name: "BearerAuth", Format: "JWT", Description: "Admin auth"},
+	)
+
+	app := simba.New()
+	app.Router.GET("/admin/websocket", simbawebsocket.SnapshotHandler(registry, authHandler))
+
+	t.Run("snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		snapshot := registry.Snapshot()
+		assert.Equal(t, 2, snapshot.Count)
+		// Connection IDs are UUIDs, which sort before "other"
+		info, other := snapshot.Connections[0], snapshot.Connections[1]
+		assert.Equal(t, map[string][]string{"lobby": {info.ID}, "room": {info.ID, "other"}}, snapshot.Groups)
+
+		assert.Equal(t, "other", other.ID)
+		assert.Equal(t, []string{"room"}, other.Groups)
+		assert.Equal(t, []string{}, other.ValueKeys)
+
+		assert.Equal(t, "snapshot-test", info.UserAgent)
+		assert.True(t, info.RemoteAddr != "")
+		assert.False(t, info.ConnectedAt.IsZero())
+		assert.Equal(t, []string{"lobby", "room"}, info.Groups)
+		assert.Equal(t, []string{"user"}, info.ValueKeys)
+	})
+
+	t.Run("handler responds with the snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/websocket", nil)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var snapshot simbawebsocket.RegistrySnapshot
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+		assert.Equal(t, 2, snapshot.Count)
+		assert.Equal(t, []string{"user"}, snapshot.Connections[0].ValueKeys)
+		assert.False(t, strings.Contains(w.Body.String(), "secret"))
+	})
+
+	t.Run("handler requires authentication", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/websocket", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
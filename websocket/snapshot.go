@@ -0,0 +1,122 @@
+package websocket
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sillen102/simba"
+	"github.com/sillen102/simba/auth"
+	"github.com/sillen102/simba/models"
+)
+
+// RegistrySnapshot is the state of a [ConnectionRegistry] at a point in time, for debugging.
+type RegistrySnapshot struct {
+	// Count is the number of registered connections
+	Count int `json:"count"`
+	// Connections are the registered connections, sorted by ID
+	Connections []ConnectionInfo `json:"connections"`
+	// Groups maps each group to the sorted IDs of its members
+	Groups map[string][]string `json:"groups"`
+	// Broadcasts are the outcomes of the messages broadcast to groups
+	Broadcasts BroadcastStats `json:"broadcasts"`
+	// Limits are the connections open on the handlers reporting to the registry and the upgrades they rejected
+	Limits ConnectionStats `json:"limits"`
+}
+
+// ConnectionInfo describes a registered connection. Values stored on the connection are listed by key only,
+// since they may hold sensitive or unserializable data, and only non-sensitive handshake headers are included.
+type ConnectionInfo struct {
+	// ID is the ID of the connection
+	ID string `json:"id"`
+	// RemoteAddr is the network address of the client
+	RemoteAddr string `json:"remoteAddr"`
+	// UserAgent is the User-Agent header of the handshake request
+	UserAgent string `json:"userAgent,omitempty"`
+	// Origin is the Origin header of the handshake request
+	Origin string `json:"origin,omitempty"`
+	// ConnectedAt is when the connection was upgraded
+	ConnectedAt time.Time `json:"connectedAt"`
+	// Groups are the sorted groups the connection is a member of
+	Groups []string `json:"groups"`
+	// ValueKeys are the sorted keys of the values stored on the connection
+	ValueKeys []string `json:"valueKeys"`
+}
+
+// Snapshot returns the current state of the registry: its connections, their group memberships and the
+// broadcast stats. The snapshot is a copy and does not change as connections come and go.
+func (r *ConnectionRegistry) Snapshot() RegistrySnapshot {
+	r.mu.RLock()
+	connections := slices.Collect(maps.Values(r.connections))
+	memberships := make(map[string][]string, len(r.connections))
+	groups := make(map[string][]string, len(r.groups))
+	for group, members := range r.groups {
+		groups[group] = slices.Sorted(maps.Keys(members))
+		for connID := range members {
+			memberships[connID] = append(memberships[connID], group)
+		}
+	}
+	r.mu.RUnlock()
+
+	infos := make([]ConnectionInfo, 0, len(connections))
+	for _, conn := range connections {
+		info := conn.info()
+		info.Groups = memberships[conn.ID]
+		if info.Groups == nil {
+			info.Groups = []string{}
+		}
+		slices.Sort(info.Groups)
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b ConnectionInfo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return RegistrySnapshot{
+		Count:       len(infos),
+		Connections: infos,
+		Groups:      groups,
+		Broadcasts:  r.BroadcastStats(),
+		Limits:      r.ConnectionStats(),
+	}
+}
+
+// SnapshotHandler returns a handler that responds with the [ConnectionRegistry.Snapshot] of the registry as JSON,
+// for debugging real-time deployments. The snapshot exposes connection IDs and client addresses, so the handler
+// requires authentication with authHandler; restrict it further in authHandler, e.g. to admins.
+//
+//	Example usage:
+//
+//	app.Router.GET("/admin/websocket", websocket.SnapshotHandler(registry, adminAuth))
+func SnapshotHandler[AuthModel any](registry *ConnectionRegistry, authHandler auth.Handler[AuthModel]) simba.Handler {
+	return snapshotHandler{
+		Handler: simba.AuthJsonHandler(
+			func(ctx context.Context, req *models.Request[models.NoBody, models.NoParams], authModel AuthModel) (*models.Response[RegistrySnapshot], error) {
+				return snapshotResponse(registry), nil
+			},
+			authHandler,
+		),
+	}
+}
+
+// snapshotHandler is the handler returned by SnapshotHandler. It documents the route through snapshotResponse
+// rather than the anonymous function it serves requests with.
+type snapshotHandler struct {
+	simba.Handler
+}
+
+func (h snapshotHandler) GetHandler() any {
+	return snapshotResponse
+}
+
+// snapshotResponse responds with the snapshot of the registry.
+// The snapshot handler is documented through this function.
+// @ID websocketRegistrySnapshot
+// @Tag WebSocket
+// @Summary WebSocket registry snapshot
+// @Description Returns the registered WebSocket connections, their group memberships and the broadcast stats.
+func snapshotResponse(registry *ConnectionRegistry) *models.Response[RegistrySnapshot] {
+	return &models.Response[RegistrySnapshot]{Body: registry.Snapshot()}
+}
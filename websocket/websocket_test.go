@@ -1,18 +1,25 @@
 package websocket_test
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/sillen102/simba"
 	"github.com/sillen102/simba/auth"
 	"github.com/sillen102/simba/models"
 	"github.com/sillen102/simba/simbaContext"
+	"github.com/sillen102/simba/simbaErrors"
 	"github.com/sillen102/simba/simbaTest/assert"
 	simbawebsocket "github.com/sillen102/simba/websocket"
 
@@ -578,7 +585,7 @@ func TestAuthHandler_Authentication(t *testing.T) {
 	t.Run("authenticated connection succeeds with valid token", func(t *testing.T) {
 		t.Parallel()
 
-		var authReceived atomic.Value
+		var authReceived, principalReceived atomic.Value
 		done := make(chan struct{})
 
 		handler := simbawebsocket.AuthHandler(
@@ -586,6 +593,9 @@ func TestAuthHandler_Authentication(t *testing.T) {
 				return simbawebsocket.AuthCallbacks[models.NoParams, WSAuthModel]{
 					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams, auth WSAuthModel) error {
 						authReceived.Store(auth)
+						if principal, ok := simba.PrincipalFromContext[WSAuthModel](ctx); ok {
+							principalReceived.Store(principal)
+						}
 						close(done)
 						return nil
 					},
@@ -612,6 +622,7 @@ func TestAuthHandler_Authentication(t *testing.T) {
 
 		assert.Equal(t, 123, authReceived.Load().(WSAuthModel).UserID)
 		assert.Equal(t, "testuser", authReceived.Load().(WSAuthModel).Username)
+		assert.Equal(t, 123, principalReceived.Load().(WSAuthModel).UserID)
 	})
 
 	t.Run("connection rejected with invalid token", func(t *testing.T) {
@@ -880,6 +891,250 @@ func TestHandler_ErrorRecovery(t *testing.T) {
 	})
 }
 
+func TestHandler_PanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	t.Run("panic in OnMessage closes the connection and reaches OnDisconnect", func(t *testing.T) {
+		t.Parallel()
+
+		disconnectErr := make(chan error, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						panic(errBoom)
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						disconnectErr <- err
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		err = conn.Write(context.Background(), websocket.MessageText, []byte("test"))
+		assert.NoError(t, err)
+
+		_, _, err = conn.Read(context.Background())
+		assert.Equal(t, websocket.StatusInternalError, websocket.CloseStatus(err))
+
+		err = <-disconnectErr
+		assert.True(t, errors.Is(err, simbawebsocket.ErrCallbackPanic))
+		assert.True(t, errors.Is(err, errBoom))
+
+		var panicErr *simbawebsocket.PanicError
+		assert.True(t, errors.As(err, &panicErr))
+		assert.Equal[any](t, errBoom, panicErr.Value)
+		assert.True(t, len(panicErr.Stack) > 0)
+	})
+
+	t.Run("panic in OnMessage is passed to OnError which can continue", func(t *testing.T) {
+		t.Parallel()
+
+		errorReceived := make(chan error, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						if string(data) == "panic" {
+							panic("boom")
+						}
+						return conn.WriteText(ctx, "Echo: "+string(data))
+					},
+					OnError: func(ctx context.Context, conn *simbawebsocket.Connection, err error) bool {
+						errorReceived <- err
+						return true
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		err = conn.Write(context.Background(), websocket.MessageText, []byte("panic"))
+		assert.NoError(t, err)
+
+		err = <-errorReceived
+		assert.True(t, errors.Is(err, simbawebsocket.ErrCallbackPanic))
+		assert.Equal(t, "callback panicked: boom", err.Error())
+
+		err = conn.Write(context.Background(), websocket.MessageText, []byte("hello"))
+		assert.NoError(t, err)
+
+		_, data, err := conn.Read(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "Echo: hello", string(data))
+	})
+
+	t.Run("panic in OnConnect closes the connection and reaches OnDisconnect", func(t *testing.T) {
+		t.Parallel()
+
+		disconnectErr := make(chan error, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						panic("boom")
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return nil
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						disconnectErr <- err
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		_, _, err = conn.Read(context.Background())
+		assert.Equal(t, websocket.StatusInternalError, websocket.CloseStatus(err))
+		assert.True(t, errors.Is(<-disconnectErr, simbawebsocket.ErrCallbackPanic))
+	})
+
+	t.Run("panic in OnError closes the connection", func(t *testing.T) {
+		t.Parallel()
+
+		disconnectErr := make(chan error, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return errBoom
+					},
+					OnError: func(ctx context.Context, conn *simbawebsocket.Connection, err error) bool {
+						panic("error handler failed")
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						disconnectErr <- err
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		err = conn.Write(context.Background(), websocket.MessageText, []byte("test"))
+		assert.NoError(t, err)
+
+		_, _, err = conn.Read(context.Background())
+		assert.Equal(t, websocket.StatusInternalError, websocket.CloseStatus(err))
+
+		err = <-disconnectErr
+		assert.True(t, errors.Is(err, errBoom))
+		assert.True(t, errors.Is(err, simbawebsocket.ErrCallbackPanic))
+	})
+
+	t.Run("panic in OnDisconnect does not affect other connections", func(t *testing.T) {
+		t.Parallel()
+
+		disconnected := make(chan struct{}, 2)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return conn.WriteText(ctx, "Echo: "+string(data))
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						disconnected <- struct{}{}
+						panic("boom")
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		for range 2 {
+			conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+			assert.NoError(t, err)
+
+			err = conn.Write(context.Background(), websocket.MessageText, []byte("hello"))
+			assert.NoError(t, err)
+
+			_, data, err := conn.Read(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "Echo: hello", string(data))
+
+			assert.NoError(t, conn.Close(websocket.StatusNormalClosure, ""))
+		}
+
+		<-disconnected
+		<-disconnected
+	})
+
+	t.Run("panic in authenticated OnMessage reaches OnDisconnect", func(t *testing.T) {
+		t.Parallel()
+
+		disconnectErr := make(chan error, 1)
+
+		handler := simbawebsocket.AuthHandler(
+			func() simbawebsocket.AuthCallbacks[models.NoParams, WSAuthModel] {
+				return simbawebsocket.AuthCallbacks[models.NoParams, WSAuthModel]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte, auth WSAuthModel) error {
+						panic("boom")
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, auth WSAuthModel, err error) {
+						disconnectErr <- err
+					},
+				}
+			},
+			auth.BearerAuth(
+				func(ctx context.Context, token string) (WSAuthModel, error) {
+					return WSAuthModel{UserID: 1, Username: "user"}, nil
+				},
+				auth.BearerAuthConfig{Name: "BearerAuth", Format: "JWT", Description: "Test bearer auth"},
+			),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], &websocket.DialOptions{
+			HTTPHeader: http.Header{"Authorization": {"Bearer token"}},
+		})
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		err = conn.Write(context.Background(), websocket.MessageText, []byte("test"))
+		assert.NoError(t, err)
+
+		_, _, err = conn.Read(context.Background())
+		assert.Equal(t, websocket.StatusInternalError, websocket.CloseStatus(err))
+		assert.True(t, errors.Is(<-disconnectErr, simbawebsocket.ErrCallbackPanic))
+	})
+}
+
 func TestHandler_ThreadSafety(t *testing.T) {
 	t.Parallel()
 
@@ -1157,3 +1412,660 @@ func TestHandler_Middleware(t *testing.T) {
 		assert.Equal(t, "trace-2", traceIDs[2])
 	})
 }
+
+func TestHandler_ConnectionValues(t *testing.T) {
+	t.Parallel()
+
+	t.Run("values set in callbacks are available in OnDisconnect", func(t *testing.T) {
+		t.Parallel()
+
+		messageDone := make(chan struct{})
+		disconnectDone := make(chan struct{})
+		var subscriptions atomic.Value
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						conn.Set("subscriptions", []string{})
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						value, ok := conn.Get("subscriptions")
+						assert.True(t, ok)
+						conn.Set("subscriptions", append(value.([]string), string(data)))
+						close(messageDone)
+						return nil
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						conn := simbawebsocket.ConnectionFromContext(ctx)
+						assert.NotNil(t, conn)
+						assert.Equal(t, connID, conn.ID)
+						value, _ := conn.Get("subscriptions")
+						subscriptions.Store(value)
+						close(disconnectDone)
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+
+		err = conn.Write(context.Background(), websocket.MessageText, []byte("news"))
+		assert.NoError(t, err)
+		<-messageDone
+
+		conn.CloseNow()
+		<-disconnectDone
+
+		assert.Equal(t, []string{"news"}, subscriptions.Load().([]string))
+	})
+
+	t.Run("get and delete values", func(t *testing.T) {
+		t.Parallel()
+
+		var conn simbawebsocket.Connection
+		_, ok := conn.Get("missing")
+		assert.False(t, ok)
+
+		conn.Set("key", 1)
+		value, ok := conn.Get("key")
+		assert.True(t, ok)
+		assert.Equal(t, 1, value)
+
+		conn.Delete("key")
+		_, ok = conn.Get("key")
+		assert.False(t, ok)
+	})
+
+	t.Run("context without connection", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, simbawebsocket.ConnectionFromContext(context.Background()))
+	})
+}
+
+func TestHandler_BeforeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects handshake with status and JSON body", func(t *testing.T) {
+		t.Parallel()
+
+		var connected atomic.Bool
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					BeforeUpgrade: func(ctx context.Context, r *http.Request, params models.NoParams) error {
+						return simbaErrors.NewSimbaError(http.StatusTooManyRequests, "too many connections", nil)
+					},
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						connected.Store(true)
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return nil
+					},
+				}
+			},
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Contains(t, "too many connections", w.Body.String())
+		assert.False(t, connected.Load())
+	})
+
+	t.Run("allows handshake when nil is returned", func(t *testing.T) {
+		t.Parallel()
+
+		done := make(chan struct{})
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					BeforeUpgrade: func(ctx context.Context, r *http.Request, params models.NoParams) error {
+						return nil
+					},
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						close(done)
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return nil
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		<-done
+	})
+
+	t.Run("auth handler receives authenticated model", func(t *testing.T) {
+		t.Parallel()
+
+		authHandler := auth.BearerAuth(
+			func(ctx context.Context, token string) (WSAuthModel, error) {
+				return WSAuthModel{UserID: 1, Username: token}, nil
+			},
+			auth.BearerAuthConfig{
+				Name:        "BearerAuth",
+				Format:      "JWT",
+				Description: "Test bearer auth",
+			},
+		)
+
+		handler := simbawebsocket.AuthHandler(
+			func() simbawebsocket.AuthCallbacks[models.NoParams, WSAuthModel] {
+				return simbawebsocket.AuthCallbacks[models.NoParams, WSAuthModel]{
+					BeforeUpgrade: func(ctx context.Context, r *http.Request, params models.NoParams, user WSAuthModel) error {
+						if user.Username != "admin" {
+							return simbaErrors.NewSimbaError(http.StatusForbidden, "forbidden", nil)
+						}
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte, user WSAuthModel) error {
+						return nil
+					},
+				}
+			},
+			authHandler,
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("Authorization", "Bearer guest")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, "forbidden", w.Body.String())
+	})
+}
+
+func TestHandler_Handshake(t *testing.T) {
+	t.Parallel()
+
+	type tenantKey struct{}
+
+	tenantFromHost := func(ctx context.Context, r *http.Request) (context.Context, error) {
+		tenant, _, found := strings.Cut(r.Host, ".")
+		if !found {
+			return nil, simbaErrors.NewSimbaError(http.StatusBadRequest, "missing tenant", nil)
+		}
+		return context.WithValue(ctx, tenantKey{}, tenant), nil
+	}
+
+	t.Run("values are propagated to all callbacks", func(t *testing.T) {
+		t.Parallel()
+
+		tenants := make(chan any, 4)
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					BeforeUpgrade: func(ctx context.Context, r *http.Request, params models.NoParams) error {
+						tenants <- ctx.Value(tenantKey{})
+						return nil
+					},
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						tenants <- ctx.Value(tenantKey{})
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						tenants <- ctx.Value(tenantKey{})
+						return nil
+					},
+					OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+						tenants <- ctx.Value(tenantKey{})
+					},
+				}
+			},
+			simbawebsocket.WithHandshake(tenantFromHost),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], &websocket.DialOptions{
+			Host: "acme.example.com",
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, conn.Write(context.Background(), websocket.MessageText, []byte("hello")))
+		assert.Equal[any](t, "acme", <-tenants)
+		assert.Equal[any](t, "acme", <-tenants)
+		assert.Equal[any](t, "acme", <-tenants)
+
+		assert.NoError(t, conn.Close(websocket.StatusNormalClosure, ""))
+		assert.Equal[any](t, "acme", <-tenants)
+	})
+
+	t.Run("error rejects handshake", func(t *testing.T) {
+		t.Parallel()
+
+		var connected atomic.Bool
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+						connected.Store(true)
+						return nil
+					},
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						return nil
+					},
+				}
+			},
+			simbawebsocket.WithHandshake(tenantFromHost),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Host = "localhost"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, "missing tenant", w.Body.String())
+		assert.False(t, connected.Load())
+	})
+}
+
+func TestHandler_Compression(t *testing.T) {
+	t.Parallel()
+
+	echoCallbacks := func() simbawebsocket.Callbacks[models.NoParams] {
+		return simbawebsocket.Callbacks[models.NoParams]{
+			OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+				return conn.WriteText(ctx, string(data))
+			},
+		}
+	}
+
+	dialOptions := func() *websocket.DialOptions {
+		return &websocket.DialOptions{CompressionMode: websocket.CompressionNoContextTakeover}
+	}
+
+	t.Run("negotiates permessage-deflate when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(simbawebsocket.Handler(echoCallbacks, simbawebsocket.WithCompression(256)))
+		defer server.Close()
+
+		conn, resp, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], dialOptions())
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		assert.Contains(t, "permessage-deflate", resp.Header.Get("Sec-WebSocket-Extensions"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		message := strings.Repeat(`{"message":"hello"}`, 1000)
+		err = conn.Write(ctx, websocket.MessageText, []byte(message))
+		assert.NoError(t, err)
+
+		_, data, err := conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, message, string(data))
+	})
+
+	t.Run("compression is disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(simbawebsocket.Handler(echoCallbacks))
+		defer server.Close()
+
+		conn, resp, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], dialOptions())
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		assert.Equal(t, "", resp.Header.Get("Sec-WebSocket-Extensions"))
+	})
+}
+
+func TestHandler_BufferSizes(t *testing.T) {
+	t.Parallel()
+
+	echoCallbacks := func() simbawebsocket.Callbacks[models.NoParams] {
+		return simbawebsocket.Callbacks[models.NoParams]{
+			OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+				return conn.WriteText(ctx, string(data))
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options []simbawebsocket.HandlerOption
+	}{
+		{
+			name:    "small buffers",
+			options: []simbawebsocket.HandlerOption{simbawebsocket.WithReadBufferSize(64), simbawebsocket.WithWriteBufferSize(64)},
+		},
+		{
+			name:    "large buffers",
+			options: []simbawebsocket.HandlerOption{simbawebsocket.WithReadBufferSize(64 << 10), simbawebsocket.WithWriteBufferSize(64 << 10)},
+		},
+		{
+			name:    "read buffer only",
+			options: []simbawebsocket.HandlerOption{simbawebsocket.WithReadBufferSize(128)},
+		},
+		{
+			name:    "write buffer only",
+			options: []simbawebsocket.HandlerOption{simbawebsocket.WithWriteBufferSize(128)},
+		},
+		{
+			name:    "zero keeps the default",
+			options: []simbawebsocket.HandlerOption{simbawebsocket.WithReadBufferSize(0), simbawebsocket.WithWriteBufferSize(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(simbawebsocket.Handler(echoCallbacks, tt.options...))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			conn, _, err := websocket.Dial(ctx, "ws"+server.URL[4:], nil)
+			assert.NoError(t, err)
+			defer conn.CloseNow()
+
+			for _, message := range []string{"hello", strings.Repeat("x", 20_000)} {
+				err = conn.Write(ctx, websocket.MessageText, []byte(message))
+				assert.NoError(t, err)
+
+				_, data, err := conn.Read(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, message, string(data))
+			}
+		})
+	}
+
+	t.Run("frame sent with the upgrade request", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(simbawebsocket.Handler(echoCallbacks, simbawebsocket.WithReadBufferSize(64)))
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+		assert.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+		// A masked text frame with a zero mask key, written along with the handshake
+		frame := append([]byte{0x81, 0x80 | 5, 0, 0, 0, 0}, "hello"...)
+		upgrade := "GET / HTTP/1.1\r\n" +
+			"Host: " + server.Listener.Addr().String() + "\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Version: 13\r\n" +
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+		_, err = conn.Write(append([]byte(upgrade), frame...))
+		assert.NoError(t, err)
+
+		reader := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(reader, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+		echo := make([]byte, 7)
+		_, err = io.ReadFull(reader, echo)
+		assert.NoError(t, err)
+		assert.Equal(t, append([]byte{0x81, 5}, "hello"...), echo)
+	})
+}
+
+func TestHandler_ConnectionRequestInfo(t *testing.T) {
+	t.Parallel()
+
+	type requestInfo struct {
+		remoteAddr string
+		userAgent  string
+	}
+
+	connectInfo := make(chan requestInfo, 1)
+	disconnectInfo := make(chan requestInfo, 1)
+
+	handler := simbawebsocket.Handler(
+		func() simbawebsocket.Callbacks[models.NoParams] {
+			return simbawebsocket.Callbacks[models.NoParams]{
+				OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+					conn.Header().Set("User-Agent", "modified")
+					connectInfo <- requestInfo{conn.RemoteAddr(), conn.Header().Get("User-Agent")}
+					return nil
+				},
+				OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+					return nil
+				},
+				OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+					conn := simbawebsocket.ConnectionFromContext(ctx)
+					disconnectInfo <- requestInfo{conn.RemoteAddr(), conn.Header().Get("User-Agent")}
+				},
+			}
+		},
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], &websocket.DialOptions{
+		HTTPHeader: http.Header{"User-Agent": {"test-client"}},
+	})
+	assert.NoError(t, err)
+
+	info := <-connectInfo
+	assert.Contains(t, "127.0.0.1:", info.remoteAddr)
+	assert.Equal(t, "test-client", info.userAgent)
+
+	conn.CloseNow()
+
+	info = <-disconnectInfo
+	assert.Contains(t, "127.0.0.1:", info.remoteAddr)
+	assert.Equal(t, "test-client", info.userAgent)
+}
+
+func TestHandler_MaxConnections(t *testing.T) {
+	t.Parallel()
+
+	registry := simbawebsocket.NewConnectionRegistry()
+	connected := make(chan struct{}, 2)
+	disconnected := make(chan struct{}, 2)
+
+	handler := simbawebsocket.Handler(
+		func() simbawebsocket.Callbacks[models.NoParams] {
+			return simbawebsocket.Callbacks[models.NoParams]{
+				OnConnect: func(ctx context.Context, conn *simbawebsocket.Connection, params models.NoParams) error {
+					connected <- struct{}{}
+					return nil
+				},
+				OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+					return nil
+				},
+				OnDisconnect: func(ctx context.Context, connID string, params models.NoParams, err error) {
+					disconnected <- struct{}{}
+				},
+			}
+		},
+		simbawebsocket.WithMaxConnections(2),
+		simbawebsocket.WithConnectionStats(registry),
+	)
+	counter := handler.(simbawebsocket.ConnectionCounter)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn1, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+	assert.NoError(t, err)
+	<-connected
+
+	conn2, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+	assert.NoError(t, err)
+	defer conn2.CloseNow()
+	<-connected
+
+	assert.Equal(t, int64(2), counter.ActiveConnections())
+
+	_, resp, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int64(1), counter.RejectedConnections())
+	assert.Equal(t, simbawebsocket.ConnectionStats{Active: 2, Rejected: 1}, registry.ConnectionStats())
+
+	conn1.CloseNow()
+	<-disconnected
+
+	// The slot is released once the connection handler has returned
+	deadline := time.Now().Add(5 * time.Second)
+	for counter.ActiveConnections() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(1), counter.ActiveConnections())
+	assert.Equal(t, simbawebsocket.ConnectionStats{Active: 1, Rejected: 1}, registry.ConnectionStats())
+
+	conn3, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+	assert.NoError(t, err)
+	defer conn3.CloseNow()
+	<-connected
+}
+
+func TestHandler_MessageTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("slow handler is cancelled and reported to OnError", func(t *testing.T) {
+		t.Parallel()
+
+		errs := make(chan error, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						if string(data) == "fast" {
+							return conn.WriteText(ctx, "done")
+						}
+						<-ctx.Done()
+						return ctx.Err()
+					},
+					OnError: func(ctx context.Context, conn *simbawebsocket.Connection, err error) bool {
+						errs <- err
+						return true
+					},
+				}
+			},
+			simbawebsocket.WithMessageTimeout(50*time.Millisecond),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		assert.NoError(t, conn.Write(ctx, websocket.MessageText, []byte("slow")))
+
+		select {
+		case err := <-errs:
+			assert.True(t, errors.Is(err, simbawebsocket.ErrMessageTimeout))
+			assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for OnError")
+		}
+
+		// The read loop continues after the timed out message
+		assert.NoError(t, conn.Write(ctx, websocket.MessageText, []byte("fast")))
+		_, msg, err := conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "done", string(msg))
+	})
+
+	t.Run("no deadline by default", func(t *testing.T) {
+		t.Parallel()
+
+		hasDeadline := make(chan bool, 1)
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						_, ok := ctx.Deadline()
+						hasDeadline <- ok
+						return nil
+					},
+				}
+			},
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		assert.NoError(t, conn.Write(context.Background(), websocket.MessageText, []byte("hello")))
+		assert.False(t, <-hasDeadline)
+	})
+
+	t.Run("handler succeeding after the deadline is not reported", func(t *testing.T) {
+		t.Parallel()
+
+		var reported atomic.Bool
+
+		handler := simbawebsocket.Handler(
+			func() simbawebsocket.Callbacks[models.NoParams] {
+				return simbawebsocket.Callbacks[models.NoParams]{
+					OnMessage: func(ctx context.Context, conn *simbawebsocket.Connection, data []byte) error {
+						if string(data) == "slow" {
+							<-ctx.Done()
+							return nil
+						}
+						return conn.WriteText(context.Background(), "done")
+					},
+					OnError: func(ctx context.Context, conn *simbawebsocket.Connection, err error) bool {
+						reported.Store(true)
+						return true
+					},
+				}
+			},
+			simbawebsocket.WithMessageTimeout(10*time.Millisecond),
+		)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, _, err := websocket.Dial(context.Background(), "ws"+server.URL[4:], nil)
+		assert.NoError(t, err)
+		defer conn.CloseNow()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Messages are handled in order, so the reply to the second one follows the slow first one
+		assert.NoError(t, conn.Write(ctx, websocket.MessageText, []byte("slow")))
+		assert.NoError(t, conn.Write(ctx, websocket.MessageText, []byte("fast")))
+		_, msg, err := conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "done", string(msg))
+		assert.False(t, reported.Load())
+	})
+}